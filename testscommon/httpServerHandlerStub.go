@@ -1,11 +1,17 @@
 package testscommon
 
-import "context"
+import (
+	"context"
+	"net"
+)
 
 // HttpServerStub -
 type HttpServerStub struct {
-	ListenAndServeCalled func() error
-	ShutdownCalled       func(ctx context.Context) error
+	ListenAndServeCalled    func() error
+	ListenAndServeTLSCalled func(certFile string, keyFile string) error
+	ShutdownCalled          func(ctx context.Context) error
+	ServeCalled             func(listener net.Listener) error
+	ServeTLSCalled          func(listener net.Listener, certFile string, keyFile string) error
 }
 
 // ListenAndServe -
@@ -17,6 +23,33 @@ func (h *HttpServerStub) ListenAndServe() error {
 	return nil
 }
 
+// ListenAndServeTLS -
+func (h *HttpServerStub) ListenAndServeTLS(certFile string, keyFile string) error {
+	if h.ListenAndServeTLSCalled != nil {
+		return h.ListenAndServeTLSCalled(certFile, keyFile)
+	}
+
+	return nil
+}
+
+// Serve -
+func (h *HttpServerStub) Serve(listener net.Listener) error {
+	if h.ServeCalled != nil {
+		return h.ServeCalled(listener)
+	}
+
+	return nil
+}
+
+// ServeTLS -
+func (h *HttpServerStub) ServeTLS(listener net.Listener, certFile string, keyFile string) error {
+	if h.ServeTLSCalled != nil {
+		return h.ServeTLSCalled(listener, certFile, keyFile)
+	}
+
+	return nil
+}
+
 //Shutdown -
 func (h *HttpServerStub) Shutdown(ctx context.Context) error {
 	if h.ShutdownCalled != nil {
@@ -0,0 +1,22 @@
+package testscommon
+
+import "github.com/subrahamanyam341/andes-communication/websocket/data"
+
+// MessageSignerStub -
+type MessageSignerStub struct {
+	SignCalled func(message *data.WsMessage) error
+}
+
+// Sign -
+func (m *MessageSignerStub) Sign(message *data.WsMessage) error {
+	if m.SignCalled != nil {
+		return m.SignCalled(message)
+	}
+
+	return nil
+}
+
+// IsInterfaceNil -
+func (m *MessageSignerStub) IsInterfaceNil() bool {
+	return m == nil
+}
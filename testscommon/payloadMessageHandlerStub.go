@@ -0,0 +1,17 @@
+package testscommon
+
+import "github.com/subrahamanyam341/andes-communication/websocket/data"
+
+// PayloadMessageHandlerStub -
+type PayloadMessageHandlerStub struct {
+	PayloadHandlerStub
+	ProcessPayloadMessageCalled func(message *data.WsMessage) error
+}
+
+// ProcessPayloadMessage -
+func (stub *PayloadMessageHandlerStub) ProcessPayloadMessage(message *data.WsMessage) error {
+	if stub.ProcessPayloadMessageCalled != nil {
+		return stub.ProcessPayloadMessageCalled(message)
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+package testscommon
+
+// PayloadSenderStub -
+type PayloadSenderStub struct {
+	SendCalled func(payload []byte, topic string) error
+}
+
+// Send -
+func (ps *PayloadSenderStub) Send(payload []byte, topic string) error {
+	if ps.SendCalled != nil {
+		return ps.SendCalled(payload, topic)
+	}
+
+	return nil
+}
+
+// IsInterfaceNil -
+func (ps *PayloadSenderStub) IsInterfaceNil() bool {
+	return ps == nil
+}
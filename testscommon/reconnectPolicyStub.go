@@ -0,0 +1,22 @@
+package testscommon
+
+import "time"
+
+// ReconnectPolicyStub -
+type ReconnectPolicyStub struct {
+	NextDelayCalled func(attempt int) (time.Duration, bool)
+}
+
+// NextDelay -
+func (stub *ReconnectPolicyStub) NextDelay(attempt int) (time.Duration, bool) {
+	if stub.NextDelayCalled != nil {
+		return stub.NextDelayCalled(attempt)
+	}
+
+	return time.Second, true
+}
+
+// IsInterfaceNil -
+func (stub *ReconnectPolicyStub) IsInterfaceNil() bool {
+	return stub == nil
+}
@@ -0,0 +1,87 @@
+package relay
+
+import (
+	"context"
+
+	"github.com/subrahamanyam341/andes-communication/websocket"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+)
+
+// HostStub -
+type HostStub struct {
+	SendCalled                func(payload []byte, topic string) error
+	SendAsyncCalled           func(payload []byte, topic string) (websocket.AckFuture, error)
+	SendMessageToQuorumCalled func(message *data.OutgoingMessage, quorum int) error
+	RequestCalled             func(ctx context.Context, topic string, payload []byte) ([]byte, error)
+	SetPayloadHandlerCalled   func(handler websocket.PayloadHandler) error
+	SetRequestHandlerCalled   func(handler websocket.RequestHandler) error
+	CloseCalled               func() error
+}
+
+// Send -
+func (h *HostStub) Send(payload []byte, topic string) error {
+	if h.SendCalled != nil {
+		return h.SendCalled(payload, topic)
+	}
+
+	return nil
+}
+
+// SendAsync -
+func (h *HostStub) SendAsync(payload []byte, topic string) (websocket.AckFuture, error) {
+	if h.SendAsyncCalled != nil {
+		return h.SendAsyncCalled(payload, topic)
+	}
+
+	return nil, nil
+}
+
+// SendMessageToQuorum -
+func (h *HostStub) SendMessageToQuorum(message *data.OutgoingMessage, quorum int) error {
+	if h.SendMessageToQuorumCalled != nil {
+		return h.SendMessageToQuorumCalled(message, quorum)
+	}
+
+	return nil
+}
+
+// Request -
+func (h *HostStub) Request(ctx context.Context, topic string, payload []byte) ([]byte, error) {
+	if h.RequestCalled != nil {
+		return h.RequestCalled(ctx, topic, payload)
+	}
+
+	return nil, nil
+}
+
+// SetPayloadHandler -
+func (h *HostStub) SetPayloadHandler(handler websocket.PayloadHandler) error {
+	if h.SetPayloadHandlerCalled != nil {
+		return h.SetPayloadHandlerCalled(handler)
+	}
+
+	return nil
+}
+
+// SetRequestHandler -
+func (h *HostStub) SetRequestHandler(handler websocket.RequestHandler) error {
+	if h.SetRequestHandlerCalled != nil {
+		return h.SetRequestHandlerCalled(handler)
+	}
+
+	return nil
+}
+
+// Close -
+func (h *HostStub) Close() error {
+	if h.CloseCalled != nil {
+		return h.CloseCalled()
+	}
+
+	return nil
+}
+
+// IsInterfaceNil -
+func (h *HostStub) IsInterfaceNil() bool {
+	return h == nil
+}
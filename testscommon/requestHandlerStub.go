@@ -0,0 +1,20 @@
+package testscommon
+
+// RequestHandlerStub -
+type RequestHandlerStub struct {
+	ProcessRequestCalled func(payload []byte, topic string, version uint32) ([]byte, error)
+}
+
+// ProcessRequest -
+func (r *RequestHandlerStub) ProcessRequest(payload []byte, topic string, version uint32) ([]byte, error) {
+	if r.ProcessRequestCalled != nil {
+		return r.ProcessRequestCalled(payload, topic, version)
+	}
+
+	return nil, nil
+}
+
+// IsInterfaceNil -
+func (r *RequestHandlerStub) IsInterfaceNil() bool {
+	return r == nil
+}
@@ -0,0 +1,26 @@
+package testscommon
+
+// SubscriptionsHandlerStub -
+type SubscriptionsHandlerStub struct {
+	SubscribeCalled   func(connectionID string, topics []string)
+	UnsubscribeCalled func(connectionID string, topics []string)
+}
+
+// Subscribe -
+func (s *SubscriptionsHandlerStub) Subscribe(connectionID string, topics []string) {
+	if s.SubscribeCalled != nil {
+		s.SubscribeCalled(connectionID, topics)
+	}
+}
+
+// Unsubscribe -
+func (s *SubscriptionsHandlerStub) Unsubscribe(connectionID string, topics []string) {
+	if s.UnsubscribeCalled != nil {
+		s.UnsubscribeCalled(connectionID, topics)
+	}
+}
+
+// IsInterfaceNil -
+func (s *SubscriptionsHandlerStub) IsInterfaceNil() bool {
+	return s == nil
+}
@@ -0,0 +1,123 @@
+package testscommon
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// TLSCertificateFiles holds the paths of the generated test certificates
+type TLSCertificateFiles struct {
+	CACertificateFile     string
+	ServerCertificateFile string
+	ServerKeyFile         string
+	ClientCertificateFile string
+	ClientKeyFile         string
+}
+
+// GenerateTLSCertificateFiles will generate, in the provided directory, a self-signed CA together with a server
+// certificate valid for localhost and a client certificate, both of them signed by the CA
+func GenerateTLSCertificateFiles(directory string) (*TLSCertificateFiles, error) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+	}
+	caBytes, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		return nil, err
+	}
+
+	files := &TLSCertificateFiles{
+		CACertificateFile:     filepath.Join(directory, "ca.pem"),
+		ServerCertificateFile: filepath.Join(directory, "server.pem"),
+		ServerKeyFile:         filepath.Join(directory, "server.key"),
+		ClientCertificateFile: filepath.Join(directory, "client.pem"),
+		ClientKeyFile:         filepath.Join(directory, "client.key"),
+	}
+	err = writePemFile(files.CACertificateFile, "CERTIFICATE", caBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	serverTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	err = generateSignedCertificate(serverTemplate, caTemplate, caKey, files.ServerCertificateFile, files.ServerKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	clientTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: "test client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	err = generateSignedCertificate(clientTemplate, caTemplate, caKey, files.ClientCertificateFile, files.ClientKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+func generateSignedCertificate(
+	template *x509.Certificate,
+	caTemplate *x509.Certificate,
+	caKey *ecdsa.PrivateKey,
+	certificateFile string,
+	keyFile string,
+) error {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+
+	certificateBytes, err := x509.CreateCertificate(rand.Reader, template, caTemplate, &key.PublicKey, caKey)
+	if err != nil {
+		return err
+	}
+	err = writePemFile(certificateFile, "CERTIFICATE", certificateBytes)
+	if err != nil {
+		return err
+	}
+
+	keyBytes, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return err
+	}
+
+	return writePemFile(keyFile, "EC PRIVATE KEY", keyBytes)
+}
+
+func writePemFile(path string, blockType string, bytes []byte) error {
+	pemBytes := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: bytes})
+
+	return os.WriteFile(path, pemBytes, 0600)
+}
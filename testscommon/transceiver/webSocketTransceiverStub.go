@@ -1,15 +1,27 @@
 package transceiver
 
 import (
+	"context"
+
 	"github.com/subrahamanyam341/andes-communication/websocket"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
 )
 
 // WebSocketTransceiverStub -
 type WebSocketTransceiverStub struct {
 	SendCalled              func(payload []byte, topic string, conn websocket.WSConClient) error
+	SendAsyncCalled         func(payload []byte, topic string, conn websocket.WSConClient) (websocket.AckFuture, error)
+	SendMessageCalled       func(message *data.OutgoingMessage, conn websocket.WSConClient) error
+	SendMessageAsyncCalled  func(message *data.OutgoingMessage, conn websocket.WSConClient) (websocket.AckFuture, error)
+	SubscribeCalled         func(topics []string, conn websocket.WSConClient) error
+	UnsubscribeCalled       func(topics []string, conn websocket.WSConClient) error
+	RequestCalled           func(ctx context.Context, payload []byte, topic string, conn websocket.WSConClient) ([]byte, error)
+	SetRequestHandlerCalled func(handler websocket.RequestHandler) error
 	CloseCalled             func() error
 	SetPayloadHandlerCalled func(handler websocket.PayloadHandler) error
 	ListenCalled            func(conn websocket.WSConClient) (closed bool)
+	SetPayloadVersionCalled func(version uint32)
+	GetNumPendingAcksCalled func() int
 }
 
 // Send -
@@ -20,6 +32,62 @@ func (w *WebSocketTransceiverStub) Send(payload []byte, topic string, conn webso
 	return nil
 }
 
+// SendAsync -
+func (w *WebSocketTransceiverStub) SendAsync(payload []byte, topic string, conn websocket.WSConClient) (websocket.AckFuture, error) {
+	if w.SendAsyncCalled != nil {
+		return w.SendAsyncCalled(payload, topic, conn)
+	}
+	return nil, nil
+}
+
+// SendMessage -
+func (w *WebSocketTransceiverStub) SendMessage(message *data.OutgoingMessage, conn websocket.WSConClient) error {
+	if w.SendMessageCalled != nil {
+		return w.SendMessageCalled(message, conn)
+	}
+	return w.Send(message.Payload, message.Topic, conn)
+}
+
+// SendMessageAsync -
+func (w *WebSocketTransceiverStub) SendMessageAsync(message *data.OutgoingMessage, conn websocket.WSConClient) (websocket.AckFuture, error) {
+	if w.SendMessageAsyncCalled != nil {
+		return w.SendMessageAsyncCalled(message, conn)
+	}
+	return w.SendAsync(message.Payload, message.Topic, conn)
+}
+
+// Subscribe -
+func (w *WebSocketTransceiverStub) Subscribe(topics []string, conn websocket.WSConClient) error {
+	if w.SubscribeCalled != nil {
+		return w.SubscribeCalled(topics, conn)
+	}
+	return nil
+}
+
+// Unsubscribe -
+func (w *WebSocketTransceiverStub) Unsubscribe(topics []string, conn websocket.WSConClient) error {
+	if w.UnsubscribeCalled != nil {
+		return w.UnsubscribeCalled(topics, conn)
+	}
+	return nil
+}
+
+// Request -
+func (w *WebSocketTransceiverStub) Request(ctx context.Context, payload []byte, topic string, conn websocket.WSConClient) ([]byte, error) {
+	if w.RequestCalled != nil {
+		return w.RequestCalled(ctx, payload, topic, conn)
+	}
+	return nil, nil
+}
+
+// SetRequestHandler -
+func (w *WebSocketTransceiverStub) SetRequestHandler(handler websocket.RequestHandler) error {
+	if w.SetRequestHandlerCalled != nil {
+		return w.SetRequestHandlerCalled(handler)
+	}
+	return nil
+}
+
 // Close -
 func (w *WebSocketTransceiverStub) Close() error {
 	if w.CloseCalled != nil {
@@ -44,3 +112,18 @@ func (w *WebSocketTransceiverStub) Listen(conn websocket.WSConClient) (closed bo
 	}
 	return false
 }
+
+// SetPayloadVersion -
+func (w *WebSocketTransceiverStub) SetPayloadVersion(version uint32) {
+	if w.SetPayloadVersionCalled != nil {
+		w.SetPayloadVersionCalled(version)
+	}
+}
+
+// GetNumPendingAcks -
+func (w *WebSocketTransceiverStub) GetNumPendingAcks() int {
+	if w.GetNumPendingAcksCalled != nil {
+		return w.GetNumPendingAcksCalled()
+	}
+	return 0
+}
@@ -0,0 +1,82 @@
+package authentication
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+)
+
+type bearerTokenAuthenticator struct {
+	tokens [][]byte
+}
+
+// NewBearerTokenAuthenticator will create a new handshake authenticator that accepts any of the provided bearer tokens
+func NewBearerTokenAuthenticator(tokens []string) (*bearerTokenAuthenticator, error) {
+	if len(tokens) == 0 {
+		return nil, data.ErrEmptyBearerToken
+	}
+
+	tokensBytes := make([][]byte, 0, len(tokens))
+	for _, token := range tokens {
+		if len(token) == 0 {
+			return nil, data.ErrEmptyBearerToken
+		}
+		tokensBytes = append(tokensBytes, []byte(token))
+	}
+
+	return &bearerTokenAuthenticator{
+		tokens: tokensBytes,
+	}, nil
+}
+
+// Authenticate will check that the request holds one of the accepted bearer tokens
+func (bta *bearerTokenAuthenticator) Authenticate(request *http.Request) error {
+	authorization := request.Header.Get(data.AuthorizationHeader)
+	if !strings.HasPrefix(authorization, data.BearerTokenPrefix) {
+		return data.ErrMissingCredentials
+	}
+
+	receivedToken := []byte(strings.TrimPrefix(authorization, data.BearerTokenPrefix))
+	for _, token := range bta.tokens {
+		if subtle.ConstantTimeCompare(token, receivedToken) == 1 {
+			return nil
+		}
+	}
+
+	return data.ErrInvalidCredentials
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (bta *bearerTokenAuthenticator) IsInterfaceNil() bool {
+	return bta == nil
+}
+
+type bearerTokenCredentialsProvider struct {
+	token string
+}
+
+// NewBearerTokenCredentialsProvider will create a new credentials provider that sends the provided bearer token
+func NewBearerTokenCredentialsProvider(token string) (*bearerTokenCredentialsProvider, error) {
+	if len(token) == 0 {
+		return nil, data.ErrEmptyBearerToken
+	}
+
+	return &bearerTokenCredentialsProvider{
+		token: token,
+	}, nil
+}
+
+// CreateCredentials will return the headers holding the bearer token, the same for any host and route
+func (btp *bearerTokenCredentialsProvider) CreateCredentials(_ string, _ string) (http.Header, error) {
+	header := http.Header{}
+	header.Set(data.AuthorizationHeader, data.BearerTokenPrefix+btp.token)
+
+	return header, nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (btp *bearerTokenCredentialsProvider) IsInterfaceNil() bool {
+	return btp == nil
+}
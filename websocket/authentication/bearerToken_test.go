@@ -0,0 +1,86 @@
+package authentication
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+)
+
+const (
+	testHost  = "localhost:8080"
+	testRoute = "/save"
+)
+
+func createRequestWithHeader(header http.Header) *http.Request {
+	request, _ := http.NewRequest(http.MethodGet, "http://"+testHost+testRoute, nil)
+	if header != nil {
+		request.Header = header
+	}
+
+	return request
+}
+
+func TestNewBearerTokenAuthenticator(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no tokens, should return error", func(t *testing.T) {
+		authenticator, err := NewBearerTokenAuthenticator(nil)
+		require.Nil(t, authenticator)
+		require.Equal(t, data.ErrEmptyBearerToken, err)
+	})
+
+	t.Run("empty token, should return error", func(t *testing.T) {
+		authenticator, err := NewBearerTokenAuthenticator([]string{"token", ""})
+		require.Nil(t, authenticator)
+		require.Equal(t, data.ErrEmptyBearerToken, err)
+	})
+
+	t.Run("should work", func(t *testing.T) {
+		authenticator, err := NewBearerTokenAuthenticator([]string{"token"})
+		require.Nil(t, err)
+		require.False(t, authenticator.IsInterfaceNil())
+	})
+}
+
+func TestBearerTokenAuthenticator_Authenticate(t *testing.T) {
+	t.Parallel()
+
+	authenticator, _ := NewBearerTokenAuthenticator([]string{"token1", "token2"})
+
+	t.Run("missing header, should return error", func(t *testing.T) {
+		err := authenticator.Authenticate(createRequestWithHeader(nil))
+		require.Equal(t, data.ErrMissingCredentials, err)
+	})
+
+	t.Run("wrong token, should return error", func(t *testing.T) {
+		provider, _ := NewBearerTokenCredentialsProvider("token3")
+		header, _ := provider.CreateCredentials(testHost, testRoute)
+		err := authenticator.Authenticate(createRequestWithHeader(header))
+		require.Equal(t, data.ErrInvalidCredentials, err)
+	})
+
+	t.Run("accepted token should work", func(t *testing.T) {
+		provider, _ := NewBearerTokenCredentialsProvider("token2")
+		header, _ := provider.CreateCredentials(testHost, testRoute)
+		err := authenticator.Authenticate(createRequestWithHeader(header))
+		require.Nil(t, err)
+	})
+}
+
+func TestNewBearerTokenCredentialsProvider(t *testing.T) {
+	t.Parallel()
+
+	provider, err := NewBearerTokenCredentialsProvider("")
+	require.Nil(t, provider)
+	require.Equal(t, data.ErrEmptyBearerToken, err)
+
+	provider, err = NewBearerTokenCredentialsProvider("token")
+	require.Nil(t, err)
+	require.False(t, provider.IsInterfaceNil())
+
+	header, err := provider.CreateCredentials(testHost, testRoute)
+	require.Nil(t, err)
+	require.Equal(t, "Bearer token", header.Get(data.AuthorizationHeader))
+}
@@ -0,0 +1,20 @@
+package authentication
+
+import "net/http"
+
+type disabledAuthenticator struct{}
+
+// NewDisabledAuthenticator will create a new handshake authenticator that accepts every request
+func NewDisabledAuthenticator() *disabledAuthenticator {
+	return &disabledAuthenticator{}
+}
+
+// Authenticate will accept the request
+func (da *disabledAuthenticator) Authenticate(_ *http.Request) error {
+	return nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (da *disabledAuthenticator) IsInterfaceNil() bool {
+	return da == nil
+}
@@ -0,0 +1,143 @@
+package authentication
+
+import (
+	"container/heap"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+)
+
+const nonceSize = 16
+
+// freshnessChecker rejects credentials with a timestamp outside the accepted window and credentials that reuse a
+// nonce that was already seen inside that window, so a captured handshake cannot be replayed
+type freshnessChecker struct {
+	maxDrift      time.Duration
+	mutNonces     sync.Mutex
+	usedNonces    map[string]struct{}
+	nonceExpiries nonceExpiryHeap
+}
+
+// nonceExpiry holds the time after which a used nonce is forgotten
+type nonceExpiry struct {
+	nonce  string
+	expiry time.Time
+}
+
+// nonceExpiryHeap orders the used nonces by their expiry, so the expired ones are removed without scanning them all
+type nonceExpiryHeap []nonceExpiry
+
+// Len returns the number of used nonces
+func (h nonceExpiryHeap) Len() int {
+	return len(h)
+}
+
+// Less returns true if the nonce at index i expires before the one at index j
+func (h nonceExpiryHeap) Less(i, j int) bool {
+	return h[i].expiry.Before(h[j].expiry)
+}
+
+// Swap swaps the nonces at the provided indexes
+func (h nonceExpiryHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+}
+
+// Push adds a nonce, to be called only through heap.Push
+func (h *nonceExpiryHeap) Push(x interface{}) {
+	*h = append(*h, x.(nonceExpiry))
+}
+
+// Pop removes the last nonce, to be called only through heap.Pop
+func (h *nonceExpiryHeap) Pop() interface{} {
+	old := *h
+	last := old[len(old)-1]
+	*h = old[:len(old)-1]
+
+	return last
+}
+
+func newFreshnessChecker(maxDriftInSec int) (*freshnessChecker, error) {
+	if maxDriftInSec <= 0 {
+		return nil, data.ErrZeroValueMaxTimestampDrift
+	}
+
+	return &freshnessChecker{
+		maxDrift:   time.Duration(maxDriftInSec) * time.Second,
+		usedNonces: make(map[string]struct{}),
+	}, nil
+}
+
+func (fc *freshnessChecker) checkAndStore(timestamp string, nonce string) error {
+	unixTimestamp, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return data.ErrInvalidCredentials
+	}
+
+	now := time.Now()
+	drift := now.Sub(time.Unix(unixTimestamp, 0))
+	if drift > fc.maxDrift || drift < -fc.maxDrift {
+		return data.ErrCredentialsExpired
+	}
+
+	fc.mutNonces.Lock()
+	defer fc.mutNonces.Unlock()
+
+	fc.removeExpiredNonces(now)
+
+	_, found := fc.usedNonces[nonce]
+	if found {
+		return data.ErrNonceAlreadyUsed
+	}
+	// a nonce is kept until its timestamp can no longer pass the drift check
+	fc.usedNonces[nonce] = struct{}{}
+	heap.Push(&fc.nonceExpiries, nonceExpiry{
+		nonce:  nonce,
+		expiry: time.Unix(unixTimestamp, 0).Add(fc.maxDrift),
+	})
+
+	return nil
+}
+
+// removeExpiredNonces removes the nonces expired at the provided time, the first to expire being the first in the heap
+func (fc *freshnessChecker) removeExpiredNonces(now time.Time) {
+	for fc.nonceExpiries.Len() > 0 && now.After(fc.nonceExpiries[0].expiry) {
+		expired := heap.Pop(&fc.nonceExpiries).(nonceExpiry)
+		delete(fc.usedNonces, expired.nonce)
+	}
+}
+
+func extractTimestampAndNonce(request *http.Request) (string, string, error) {
+	timestamp := request.Header.Get(data.AuthTimestampHeader)
+	nonce := request.Header.Get(data.AuthNonceHeader)
+	if len(timestamp) == 0 || len(nonce) == 0 {
+		return "", "", data.ErrMissingCredentials
+	}
+
+	return timestamp, nonce, nil
+}
+
+func createTimestampAndNonce() (string, string, error) {
+	nonce := make([]byte, nonceSize)
+	_, err := rand.Read(nonce)
+	if err != nil {
+		return "", "", err
+	}
+
+	return strconv.FormatInt(time.Now().Unix(), 10), hex.EncodeToString(nonce), nil
+}
+
+// createMessageToSign binds the credentials to the host and the route they were created for, so they cannot be used
+// to open a connection to another server sharing the same keys
+func createMessageToSign(timestamp string, nonce string, host string, route string) []byte {
+	// an empty route is dialed as the root one
+	if len(route) == 0 {
+		route = "/"
+	}
+
+	return []byte(timestamp + "-" + nonce + "-" + host + route)
+}
@@ -0,0 +1,71 @@
+package authentication
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+)
+
+func TestNewFreshnessChecker(t *testing.T) {
+	t.Parallel()
+
+	checker, err := newFreshnessChecker(0)
+	require.Nil(t, checker)
+	require.Equal(t, data.ErrZeroValueMaxTimestampDrift, err)
+
+	checker, err = newFreshnessChecker(10)
+	require.NotNil(t, checker)
+	require.Nil(t, err)
+}
+
+func TestFreshnessChecker_CheckAndStore(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().Unix()
+
+	t.Run("invalid timestamp, should return error", func(t *testing.T) {
+		checker, _ := newFreshnessChecker(10)
+		err := checker.checkAndStore("invalid", "nonce")
+		require.Equal(t, data.ErrInvalidCredentials, err)
+	})
+	t.Run("timestamp outside the window, should return error", func(t *testing.T) {
+		checker, _ := newFreshnessChecker(10)
+		err := checker.checkAndStore(strconv.FormatInt(now-20, 10), "nonce")
+		require.Equal(t, data.ErrCredentialsExpired, err)
+		err = checker.checkAndStore(strconv.FormatInt(now+20, 10), "nonce")
+		require.Equal(t, data.ErrCredentialsExpired, err)
+	})
+	t.Run("reused nonce, should return error", func(t *testing.T) {
+		checker, _ := newFreshnessChecker(10)
+		err := checker.checkAndStore(strconv.FormatInt(now, 10), "nonce")
+		require.Nil(t, err)
+		err = checker.checkAndStore(strconv.FormatInt(now-1, 10), "nonce")
+		require.Equal(t, data.ErrNonceAlreadyUsed, err)
+	})
+}
+
+func TestFreshnessChecker_RemoveExpiredNonces(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().Unix()
+	checker, _ := newFreshnessChecker(10)
+	// the nonces are not received in the order of their timestamps
+	require.Nil(t, checker.checkAndStore(strconv.FormatInt(now, 10), "nonce0"))
+	require.Nil(t, checker.checkAndStore(strconv.FormatInt(now-5, 10), "nonce1"))
+	require.Nil(t, checker.checkAndStore(strconv.FormatInt(now+5, 10), "nonce2"))
+	require.Nil(t, checker.checkAndStore(strconv.FormatInt(now-2, 10), "nonce3"))
+
+	checker.removeExpiredNonces(time.Unix(now+9, 0))
+	require.Equal(t, map[string]struct{}{"nonce0": {}, "nonce2": {}}, checker.usedNonces)
+	require.Equal(t, 2, checker.nonceExpiries.Len())
+
+	checker.removeExpiredNonces(time.Unix(now+11, 0))
+	require.Equal(t, map[string]struct{}{"nonce2": {}}, checker.usedNonces)
+
+	checker.removeExpiredNonces(time.Unix(now+16, 0))
+	require.Empty(t, checker.usedNonces)
+	require.Zero(t, checker.nonceExpiries.Len())
+}
@@ -0,0 +1,111 @@
+package authentication
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+)
+
+// ArgsHMACAuthenticator holds the arguments needed for creating a HMAC handshake authenticator
+type ArgsHMACAuthenticator struct {
+	Secret                 []byte
+	MaxTimestampDriftInSec int
+}
+
+type hmacAuthenticator struct {
+	secret           []byte
+	freshnessChecker *freshnessChecker
+}
+
+// NewHMACAuthenticator will create a new handshake authenticator that accepts requests holding a timestamp and a nonce
+// signed with the shared secret
+func NewHMACAuthenticator(args ArgsHMACAuthenticator) (*hmacAuthenticator, error) {
+	if len(args.Secret) == 0 {
+		return nil, data.ErrEmptyHMACSecret
+	}
+
+	checker, err := newFreshnessChecker(args.MaxTimestampDriftInSec)
+	if err != nil {
+		return nil, err
+	}
+
+	return &hmacAuthenticator{
+		secret:           args.Secret,
+		freshnessChecker: checker,
+	}, nil
+}
+
+// Authenticate will check that the request holds a fresh timestamp and nonce pair signed with the shared secret for
+// the host and the route of the request
+func (ha *hmacAuthenticator) Authenticate(request *http.Request) error {
+	timestamp, nonce, err := extractTimestampAndNonce(request)
+	if err != nil {
+		return err
+	}
+
+	receivedMAC, err := hex.DecodeString(request.Header.Get(data.AuthSignatureHeader))
+	if err != nil || len(receivedMAC) == 0 {
+		return data.ErrInvalidCredentials
+	}
+
+	expectedMAC := computeMAC(ha.secret, createMessageToSign(timestamp, nonce, request.Host, request.URL.Path))
+	if !hmac.Equal(expectedMAC, receivedMAC) {
+		return data.ErrInvalidCredentials
+	}
+
+	return ha.freshnessChecker.checkAndStore(timestamp, nonce)
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (ha *hmacAuthenticator) IsInterfaceNil() bool {
+	return ha == nil
+}
+
+type hmacCredentialsProvider struct {
+	secret []byte
+}
+
+// NewHMACCredentialsProvider will create a new credentials provider that signs a fresh timestamp and nonce with the
+// shared secret on every handshake
+func NewHMACCredentialsProvider(secret []byte) (*hmacCredentialsProvider, error) {
+	if len(secret) == 0 {
+		return nil, data.ErrEmptyHMACSecret
+	}
+
+	return &hmacCredentialsProvider{
+		secret: secret,
+	}, nil
+}
+
+// CreateCredentials will return the headers holding the timestamp, the nonce and their HMAC for the provided host and
+// route
+func (hp *hmacCredentialsProvider) CreateCredentials(host string, route string) (http.Header, error) {
+	timestamp, nonce, err := createTimestampAndNonce()
+	if err != nil {
+		return nil, err
+	}
+
+	mac := computeMAC(hp.secret, createMessageToSign(timestamp, nonce, host, route))
+
+	header := http.Header{}
+	header.Set(data.AuthTimestampHeader, timestamp)
+	header.Set(data.AuthNonceHeader, nonce)
+	header.Set(data.AuthSignatureHeader, hex.EncodeToString(mac))
+
+	return header, nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (hp *hmacCredentialsProvider) IsInterfaceNil() bool {
+	return hp == nil
+}
+
+func computeMAC(secret []byte, message []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	_, _ = mac.Write(message)
+
+	return mac.Sum(nil)
+}
@@ -0,0 +1,131 @@
+package authentication
+
+import (
+	"encoding/hex"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+)
+
+func createHMACArgs() ArgsHMACAuthenticator {
+	return ArgsHMACAuthenticator{
+		Secret:                 []byte("secret"),
+		MaxTimestampDriftInSec: 10,
+	}
+}
+
+func TestNewHMACAuthenticator(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty secret, should return error", func(t *testing.T) {
+		args := createHMACArgs()
+		args.Secret = nil
+		authenticator, err := NewHMACAuthenticator(args)
+		require.Nil(t, authenticator)
+		require.Equal(t, data.ErrEmptyHMACSecret, err)
+	})
+
+	t.Run("zero timestamp drift, should return error", func(t *testing.T) {
+		args := createHMACArgs()
+		args.MaxTimestampDriftInSec = 0
+		authenticator, err := NewHMACAuthenticator(args)
+		require.Nil(t, authenticator)
+		require.Equal(t, data.ErrZeroValueMaxTimestampDrift, err)
+	})
+
+	t.Run("should work", func(t *testing.T) {
+		authenticator, err := NewHMACAuthenticator(createHMACArgs())
+		require.Nil(t, err)
+		require.False(t, authenticator.IsInterfaceNil())
+	})
+}
+
+func TestHMACAuthenticator_Authenticate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("missing credentials, should return error", func(t *testing.T) {
+		authenticator, _ := NewHMACAuthenticator(createHMACArgs())
+		err := authenticator.Authenticate(createRequestWithHeader(nil))
+		require.Equal(t, data.ErrMissingCredentials, err)
+	})
+
+	t.Run("different secret, should return error", func(t *testing.T) {
+		authenticator, _ := NewHMACAuthenticator(createHMACArgs())
+		provider, _ := NewHMACCredentialsProvider([]byte("other secret"))
+		header, _ := provider.CreateCredentials(testHost, testRoute)
+		err := authenticator.Authenticate(createRequestWithHeader(header))
+		require.Equal(t, data.ErrInvalidCredentials, err)
+	})
+
+	t.Run("tampered nonce, should return error", func(t *testing.T) {
+		authenticator, _ := NewHMACAuthenticator(createHMACArgs())
+		provider, _ := NewHMACCredentialsProvider([]byte("secret"))
+		header, _ := provider.CreateCredentials(testHost, testRoute)
+		header.Set(data.AuthNonceHeader, "tampered")
+		err := authenticator.Authenticate(createRequestWithHeader(header))
+		require.Equal(t, data.ErrInvalidCredentials, err)
+	})
+
+	t.Run("credentials for another host, should return error", func(t *testing.T) {
+		authenticator, _ := NewHMACAuthenticator(createHMACArgs())
+		provider, _ := NewHMACCredentialsProvider([]byte("secret"))
+		header, _ := provider.CreateCredentials("localhost:8081", testRoute)
+		err := authenticator.Authenticate(createRequestWithHeader(header))
+		require.Equal(t, data.ErrInvalidCredentials, err)
+	})
+
+	t.Run("credentials for another route, should return error", func(t *testing.T) {
+		authenticator, _ := NewHMACAuthenticator(createHMACArgs())
+		provider, _ := NewHMACCredentialsProvider([]byte("secret"))
+		header, _ := provider.CreateCredentials(testHost, "/other")
+		err := authenticator.Authenticate(createRequestWithHeader(header))
+		require.Equal(t, data.ErrInvalidCredentials, err)
+	})
+
+	t.Run("expired timestamp, should return error", func(t *testing.T) {
+		authenticator, _ := NewHMACAuthenticator(createHMACArgs())
+		timestamp := strconv.FormatInt(time.Now().Add(-time.Minute).Unix(), 10)
+		header := createRequestWithHeader(nil).Header
+		header.Set(data.AuthTimestampHeader, timestamp)
+		header.Set(data.AuthNonceHeader, "nonce")
+		header.Set(data.AuthSignatureHeader, hex.EncodeToString(computeMAC([]byte("secret"), createMessageToSign(timestamp, "nonce", testHost, testRoute))))
+		err := authenticator.Authenticate(createRequestWithHeader(header))
+		require.Equal(t, data.ErrCredentialsExpired, err)
+	})
+
+	t.Run("replayed credentials, should return error", func(t *testing.T) {
+		authenticator, _ := NewHMACAuthenticator(createHMACArgs())
+		provider, _ := NewHMACCredentialsProvider([]byte("secret"))
+		header, _ := provider.CreateCredentials(testHost, testRoute)
+		err := authenticator.Authenticate(createRequestWithHeader(header))
+		require.Nil(t, err)
+
+		err = authenticator.Authenticate(createRequestWithHeader(header))
+		require.Equal(t, data.ErrNonceAlreadyUsed, err)
+	})
+
+	t.Run("should work", func(t *testing.T) {
+		authenticator, _ := NewHMACAuthenticator(createHMACArgs())
+		provider, _ := NewHMACCredentialsProvider([]byte("secret"))
+		for i := 0; i < 10; i++ {
+			header, _ := provider.CreateCredentials(testHost, testRoute)
+			err := authenticator.Authenticate(createRequestWithHeader(header))
+			require.Nil(t, err)
+		}
+	})
+}
+
+func TestNewHMACCredentialsProvider(t *testing.T) {
+	t.Parallel()
+
+	provider, err := NewHMACCredentialsProvider(nil)
+	require.Nil(t, provider)
+	require.Equal(t, data.ErrEmptyHMACSecret, err)
+
+	provider, err = NewHMACCredentialsProvider([]byte("secret"))
+	require.Nil(t, err)
+	require.False(t, provider.IsInterfaceNil())
+}
@@ -0,0 +1,165 @@
+package authentication
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	"github.com/subrahamanyam341/andes-core-16/core/check"
+	crypto "github.com/subrahamanyam341/andes-crypto-123"
+)
+
+// ArgsSignatureAuthenticator holds the arguments needed for creating a signature handshake authenticator
+type ArgsSignatureAuthenticator struct {
+	Signer                 crypto.SingleSigner
+	KeyGenerator           crypto.KeyGenerator
+	TrustedPublicKeys      [][]byte
+	MaxTimestampDriftInSec int
+}
+
+type signatureAuthenticator struct {
+	signer            crypto.SingleSigner
+	keyGenerator      crypto.KeyGenerator
+	trustedPublicKeys map[string]struct{}
+	freshnessChecker  *freshnessChecker
+}
+
+// NewSignatureAuthenticator will create a new handshake authenticator that accepts requests holding a timestamp and a
+// nonce signed by one of the trusted node keys
+func NewSignatureAuthenticator(args ArgsSignatureAuthenticator) (*signatureAuthenticator, error) {
+	if check.IfNil(args.Signer) {
+		return nil, data.ErrNilSingleSigner
+	}
+	if check.IfNil(args.KeyGenerator) {
+		return nil, data.ErrNilKeyGenerator
+	}
+	if len(args.TrustedPublicKeys) == 0 {
+		return nil, data.ErrEmptyTrustedPublicKeys
+	}
+
+	checker, err := newFreshnessChecker(args.MaxTimestampDriftInSec)
+	if err != nil {
+		return nil, err
+	}
+
+	trustedPublicKeys := make(map[string]struct{}, len(args.TrustedPublicKeys))
+	for _, publicKey := range args.TrustedPublicKeys {
+		trustedPublicKeys[string(publicKey)] = struct{}{}
+	}
+
+	return &signatureAuthenticator{
+		signer:            args.Signer,
+		keyGenerator:      args.KeyGenerator,
+		trustedPublicKeys: trustedPublicKeys,
+		freshnessChecker:  checker,
+	}, nil
+}
+
+// Authenticate will check that the request holds a fresh timestamp and nonce pair signed by a trusted key for the host
+// and the route of the request
+func (sa *signatureAuthenticator) Authenticate(request *http.Request) error {
+	timestamp, nonce, err := extractTimestampAndNonce(request)
+	if err != nil {
+		return err
+	}
+
+	publicKeyBytes, err := hex.DecodeString(request.Header.Get(data.AuthPublicKeyHeader))
+	if err != nil || len(publicKeyBytes) == 0 {
+		return data.ErrMissingCredentials
+	}
+	_, isTrusted := sa.trustedPublicKeys[string(publicKeyBytes)]
+	if !isTrusted {
+		return data.ErrUntrustedPublicKey
+	}
+
+	signature, err := hex.DecodeString(request.Header.Get(data.AuthSignatureHeader))
+	if err != nil || len(signature) == 0 {
+		return data.ErrInvalidCredentials
+	}
+
+	publicKey, err := sa.keyGenerator.PublicKeyFromByteArray(publicKeyBytes)
+	if err != nil {
+		return data.ErrInvalidCredentials
+	}
+
+	err = sa.signer.Verify(publicKey, createHashToSign(timestamp, nonce, request.Host, request.URL.Path), signature)
+	if err != nil {
+		return data.ErrInvalidCredentials
+	}
+
+	return sa.freshnessChecker.checkAndStore(timestamp, nonce)
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (sa *signatureAuthenticator) IsInterfaceNil() bool {
+	return sa == nil
+}
+
+// ArgsSignatureCredentialsProvider holds the arguments needed for creating a signature credentials provider
+type ArgsSignatureCredentialsProvider struct {
+	Signer     crypto.SingleSigner
+	PrivateKey crypto.PrivateKey
+}
+
+type signatureCredentialsProvider struct {
+	signer         crypto.SingleSigner
+	privateKey     crypto.PrivateKey
+	publicKeyBytes []byte
+}
+
+// NewSignatureCredentialsProvider will create a new credentials provider that signs a fresh timestamp and nonce with
+// the node key on every handshake
+func NewSignatureCredentialsProvider(args ArgsSignatureCredentialsProvider) (*signatureCredentialsProvider, error) {
+	if check.IfNil(args.Signer) {
+		return nil, data.ErrNilSingleSigner
+	}
+	if check.IfNil(args.PrivateKey) {
+		return nil, data.ErrNilPrivateKey
+	}
+
+	publicKeyBytes, err := args.PrivateKey.GeneratePublic().ToByteArray()
+	if err != nil {
+		return nil, err
+	}
+
+	return &signatureCredentialsProvider{
+		signer:         args.Signer,
+		privateKey:     args.PrivateKey,
+		publicKeyBytes: publicKeyBytes,
+	}, nil
+}
+
+// CreateCredentials will return the headers holding the timestamp, the nonce, their signature for the provided host
+// and route and the public key
+func (sp *signatureCredentialsProvider) CreateCredentials(host string, route string) (http.Header, error) {
+	timestamp, nonce, err := createTimestampAndNonce()
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := sp.signer.Sign(sp.privateKey, createHashToSign(timestamp, nonce, host, route))
+	if err != nil {
+		return nil, err
+	}
+
+	header := http.Header{}
+	header.Set(data.AuthTimestampHeader, timestamp)
+	header.Set(data.AuthNonceHeader, nonce)
+	header.Set(data.AuthPublicKeyHeader, hex.EncodeToString(sp.publicKeyBytes))
+	header.Set(data.AuthSignatureHeader, hex.EncodeToString(signature))
+
+	return header, nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (sp *signatureCredentialsProvider) IsInterfaceNil() bool {
+	return sp == nil
+}
+
+// createHashToSign hashes the message to sign, as the secp256k1 signer signs only the first 32 bytes of its input
+func createHashToSign(timestamp string, nonce string, host string, route string) []byte {
+	hash := sha256.Sum256(createMessageToSign(timestamp, nonce, host, route))
+
+	return hash[:]
+}
@@ -0,0 +1,161 @@
+package authentication
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	crypto "github.com/subrahamanyam341/andes-crypto-123"
+	"github.com/subrahamanyam341/andes-crypto-123/signing"
+	"github.com/subrahamanyam341/andes-crypto-123/signing/secp256k1"
+	"github.com/subrahamanyam341/andes-crypto-123/signing/secp256k1/singlesig"
+)
+
+var keyGen = signing.NewKeyGenerator(secp256k1.NewSecp256k1())
+
+func createSignatureArgs(trustedKeys ...crypto.PrivateKey) ArgsSignatureAuthenticator {
+	trustedPublicKeys := make([][]byte, 0, len(trustedKeys))
+	for _, privateKey := range trustedKeys {
+		publicKeyBytes, _ := privateKey.GeneratePublic().ToByteArray()
+		trustedPublicKeys = append(trustedPublicKeys, publicKeyBytes)
+	}
+
+	return ArgsSignatureAuthenticator{
+		Signer:                 &singlesig.Secp256k1Signer{},
+		KeyGenerator:           keyGen,
+		TrustedPublicKeys:      trustedPublicKeys,
+		MaxTimestampDriftInSec: 10,
+	}
+}
+
+func createSignatureProvider(privateKey crypto.PrivateKey) *signatureCredentialsProvider {
+	provider, _ := NewSignatureCredentialsProvider(ArgsSignatureCredentialsProvider{
+		Signer:     &singlesig.Secp256k1Signer{},
+		PrivateKey: privateKey,
+	})
+
+	return provider
+}
+
+func TestNewSignatureAuthenticator(t *testing.T) {
+	t.Parallel()
+
+	privateKey, _ := keyGen.GeneratePair()
+
+	t.Run("nil signer, should return error", func(t *testing.T) {
+		args := createSignatureArgs(privateKey)
+		args.Signer = nil
+		authenticator, err := NewSignatureAuthenticator(args)
+		require.Nil(t, authenticator)
+		require.Equal(t, data.ErrNilSingleSigner, err)
+	})
+
+	t.Run("nil key generator, should return error", func(t *testing.T) {
+		args := createSignatureArgs(privateKey)
+		args.KeyGenerator = nil
+		authenticator, err := NewSignatureAuthenticator(args)
+		require.Nil(t, authenticator)
+		require.Equal(t, data.ErrNilKeyGenerator, err)
+	})
+
+	t.Run("no trusted keys, should return error", func(t *testing.T) {
+		args := createSignatureArgs()
+		authenticator, err := NewSignatureAuthenticator(args)
+		require.Nil(t, authenticator)
+		require.Equal(t, data.ErrEmptyTrustedPublicKeys, err)
+	})
+
+	t.Run("zero timestamp drift, should return error", func(t *testing.T) {
+		args := createSignatureArgs(privateKey)
+		args.MaxTimestampDriftInSec = 0
+		authenticator, err := NewSignatureAuthenticator(args)
+		require.Nil(t, authenticator)
+		require.Equal(t, data.ErrZeroValueMaxTimestampDrift, err)
+	})
+
+	t.Run("should work", func(t *testing.T) {
+		authenticator, err := NewSignatureAuthenticator(createSignatureArgs(privateKey))
+		require.Nil(t, err)
+		require.False(t, authenticator.IsInterfaceNil())
+	})
+}
+
+func TestSignatureAuthenticator_Authenticate(t *testing.T) {
+	t.Parallel()
+
+	trustedKey, _ := keyGen.GeneratePair()
+	untrustedKey, _ := keyGen.GeneratePair()
+
+	t.Run("untrusted key, should return error", func(t *testing.T) {
+		authenticator, _ := NewSignatureAuthenticator(createSignatureArgs(trustedKey))
+		header, _ := createSignatureProvider(untrustedKey).CreateCredentials(testHost, testRoute)
+		err := authenticator.Authenticate(createRequestWithHeader(header))
+		require.Equal(t, data.ErrUntrustedPublicKey, err)
+	})
+
+	t.Run("signature of another key, should return error", func(t *testing.T) {
+		authenticator, _ := NewSignatureAuthenticator(createSignatureArgs(trustedKey))
+		header, _ := createSignatureProvider(untrustedKey).CreateCredentials(testHost, testRoute)
+		trustedPublicKey, _ := trustedKey.GeneratePublic().ToByteArray()
+		header.Set(data.AuthPublicKeyHeader, hex.EncodeToString(trustedPublicKey))
+		err := authenticator.Authenticate(createRequestWithHeader(header))
+		require.Equal(t, data.ErrInvalidCredentials, err)
+	})
+
+	t.Run("tampered nonce, should return error", func(t *testing.T) {
+		authenticator, _ := NewSignatureAuthenticator(createSignatureArgs(trustedKey))
+		header, _ := createSignatureProvider(trustedKey).CreateCredentials(testHost, testRoute)
+		header.Set(data.AuthNonceHeader, header.Get(data.AuthNonceHeader)+"00")
+		err := authenticator.Authenticate(createRequestWithHeader(header))
+		require.Equal(t, data.ErrInvalidCredentials, err)
+	})
+
+	t.Run("credentials for another host, should return error", func(t *testing.T) {
+		authenticator, _ := NewSignatureAuthenticator(createSignatureArgs(trustedKey))
+		header, _ := createSignatureProvider(trustedKey).CreateCredentials("localhost:8081", testRoute)
+		err := authenticator.Authenticate(createRequestWithHeader(header))
+		require.Equal(t, data.ErrInvalidCredentials, err)
+	})
+
+	t.Run("credentials for another route, should return error", func(t *testing.T) {
+		authenticator, _ := NewSignatureAuthenticator(createSignatureArgs(trustedKey))
+		header, _ := createSignatureProvider(trustedKey).CreateCredentials(testHost, "/other")
+		err := authenticator.Authenticate(createRequestWithHeader(header))
+		require.Equal(t, data.ErrInvalidCredentials, err)
+	})
+
+	t.Run("replayed credentials, should return error", func(t *testing.T) {
+		authenticator, _ := NewSignatureAuthenticator(createSignatureArgs(trustedKey))
+		header, _ := createSignatureProvider(trustedKey).CreateCredentials(testHost, testRoute)
+		err := authenticator.Authenticate(createRequestWithHeader(header))
+		require.Nil(t, err)
+
+		err = authenticator.Authenticate(createRequestWithHeader(header))
+		require.Equal(t, data.ErrNonceAlreadyUsed, err)
+	})
+
+	t.Run("should work", func(t *testing.T) {
+		authenticator, _ := NewSignatureAuthenticator(createSignatureArgs(trustedKey, untrustedKey))
+		header, _ := createSignatureProvider(trustedKey).CreateCredentials(testHost, testRoute)
+		err := authenticator.Authenticate(createRequestWithHeader(header))
+		require.Nil(t, err)
+	})
+}
+
+func TestNewSignatureCredentialsProvider(t *testing.T) {
+	t.Parallel()
+
+	privateKey, _ := keyGen.GeneratePair()
+
+	provider, err := NewSignatureCredentialsProvider(ArgsSignatureCredentialsProvider{PrivateKey: privateKey})
+	require.Nil(t, provider)
+	require.Equal(t, data.ErrNilSingleSigner, err)
+
+	provider, err = NewSignatureCredentialsProvider(ArgsSignatureCredentialsProvider{Signer: &singlesig.Secp256k1Signer{}})
+	require.Nil(t, provider)
+	require.Equal(t, data.ErrNilPrivateKey, err)
+
+	provider = createSignatureProvider(privateKey)
+	require.False(t, provider.IsInterfaceNil())
+}
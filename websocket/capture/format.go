@@ -0,0 +1,51 @@
+package capture
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+)
+
+const (
+	// fileMagic is written at the beginning of every capture file, the last byte being the format version
+	fileMagic = "WSCAP\x01"
+	// every record is prefixed by its length
+	lengthPrefixSize = 4
+	// timestamp + version + topic length
+	recordHeaderSize = 8 + 4 + 4
+	// maxRecordSize protects the reader against corrupted length prefixes
+	maxRecordSize = 1 << 30
+)
+
+func encodeRecord(message *data.CapturedMessage) []byte {
+	recordSize := recordHeaderSize + len(message.Topic) + len(message.Payload)
+	buff := make([]byte, lengthPrefixSize+recordHeaderSize, lengthPrefixSize+recordSize)
+
+	binary.BigEndian.PutUint32(buff[:lengthPrefixSize], uint32(recordSize))
+	record := buff[lengthPrefixSize:]
+	binary.BigEndian.PutUint64(record[:8], uint64(message.Timestamp))
+	binary.BigEndian.PutUint32(record[8:12], message.Version)
+	binary.BigEndian.PutUint32(record[12:recordHeaderSize], uint32(len(message.Topic)))
+
+	buff = append(buff, message.Topic...)
+	return append(buff, message.Payload...)
+}
+
+func decodeRecord(record []byte) (*data.CapturedMessage, error) {
+	if len(record) < recordHeaderSize {
+		return nil, fmt.Errorf("%w: record too short", data.ErrInvalidCaptureFile)
+	}
+
+	topicLength := int(binary.BigEndian.Uint32(record[12:recordHeaderSize]))
+	if len(record) < recordHeaderSize+topicLength {
+		return nil, fmt.Errorf("%w: topic exceeds the record", data.ErrInvalidCaptureFile)
+	}
+
+	return &data.CapturedMessage{
+		Timestamp: int64(binary.BigEndian.Uint64(record[:8])),
+		Version:   binary.BigEndian.Uint32(record[8:12]),
+		Topic:     string(record[recordHeaderSize : recordHeaderSize+topicLength]),
+		Payload:   record[recordHeaderSize+topicLength:],
+	}, nil
+}
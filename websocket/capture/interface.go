@@ -0,0 +1,17 @@
+package capture
+
+import "github.com/subrahamanyam341/andes-communication/websocket/data"
+
+// Reader defines what a component that reads the messages of a capture file should be able to do
+type Reader interface {
+	Next() (*data.CapturedMessage, error)
+	Close() error
+	IsInterfaceNil() bool
+}
+
+// PayloadSender defines what a component that the captured payloads are replayed through should be able to do. A
+// factory.FullDuplexHost is such a component
+type PayloadSender interface {
+	Send(payload []byte, topic string) error
+	IsInterfaceNil() bool
+}
@@ -0,0 +1,80 @@
+package capture
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+)
+
+// reader reads, in order, the messages stored in a capture file
+type reader struct {
+	file   *os.File
+	reader *bufio.Reader
+}
+
+// NewReader will open the capture file at the provided path
+func NewReader(path string) (*reader, error) {
+	if path == "" {
+		return nil, data.ErrEmptyCapturePath
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &reader{
+		file:   file,
+		reader: bufio.NewReader(file),
+	}
+
+	magic := make([]byte, len(fileMagic))
+	_, err = io.ReadFull(r.reader, magic)
+	if err != nil || string(magic) != fileMagic {
+		_ = file.Close()
+		return nil, fmt.Errorf("%w: %s", data.ErrInvalidCaptureFile, path)
+	}
+
+	return r, nil
+}
+
+// Next returns the next message of the capture file or io.EOF after the last one
+func (r *reader) Next() (*data.CapturedMessage, error) {
+	prefix := make([]byte, lengthPrefixSize)
+	_, err := io.ReadFull(r.reader, prefix)
+	if errors.Is(err, io.EOF) {
+		return nil, io.EOF
+	}
+	if err != nil {
+		// the recorder was stopped while writing the last record
+		return nil, fmt.Errorf("%w: truncated record length", data.ErrInvalidCaptureFile)
+	}
+
+	recordSize := binary.BigEndian.Uint32(prefix)
+	if recordSize > maxRecordSize {
+		return nil, fmt.Errorf("%w: record of %d bytes", data.ErrInvalidCaptureFile, recordSize)
+	}
+
+	record := make([]byte, recordSize)
+	_, err = io.ReadFull(r.reader, record)
+	if err != nil {
+		return nil, fmt.Errorf("%w: truncated record", data.ErrInvalidCaptureFile)
+	}
+
+	return decodeRecord(record)
+}
+
+// Close will close the capture file
+func (r *reader) Close() error {
+	return r.file.Close()
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (r *reader) IsInterfaceNil() bool {
+	return r == nil
+}
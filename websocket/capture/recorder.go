@@ -0,0 +1,134 @@
+package capture
+
+import (
+	"bufio"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/subrahamanyam341/andes-communication/websocket"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	"github.com/subrahamanyam341/andes-core-16/core"
+	"github.com/subrahamanyam341/andes-core-16/core/check"
+)
+
+// ArgsRecorder holds the arguments needed for creating a recorder
+type ArgsRecorder struct {
+	Path    string
+	Handler websocket.PayloadHandler
+	Log     core.Logger
+}
+
+// recorder is a payload handler that appends every received payload to a capture file before handing it to the
+// wrapped handler, so the stream that made a handler fail can be replayed
+type recorder struct {
+	mut     sync.Mutex
+	file    *os.File
+	writer  *bufio.Writer
+	handler websocket.PayloadHandler
+	log     core.Logger
+}
+
+// NewRecorder will create a recorder that appends the received payloads to the capture file at the provided path. A new
+// file is created if it does not exist
+func NewRecorder(args ArgsRecorder) (*recorder, error) {
+	if args.Path == "" {
+		return nil, data.ErrEmptyCapturePath
+	}
+	if check.IfNil(args.Log) {
+		return nil, core.ErrNilLogger
+	}
+
+	handler := args.Handler
+	if check.IfNil(handler) {
+		handler = websocket.NewNilPayloadHandler()
+	}
+
+	file, err := os.OpenFile(args.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &recorder{
+		file:    file,
+		writer:  bufio.NewWriter(file),
+		handler: handler,
+		log:     args.Log,
+	}
+
+	err = r.writeMagicIfEmpty()
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (r *recorder) writeMagicIfEmpty() error {
+	info, err := r.file.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Size() > 0 {
+		return nil
+	}
+
+	_, err = r.writer.WriteString(fileMagic)
+	if err != nil {
+		return err
+	}
+
+	return r.writer.Flush()
+}
+
+// ProcessPayload will append the payload to the capture file and will hand it to the wrapped handler. The record is
+// flushed before calling the handler, so it is not lost if the handler crashes the process
+func (r *recorder) ProcessPayload(payload []byte, topic string, version uint32) error {
+	err := r.record(&data.CapturedMessage{
+		Timestamp: time.Now().UnixNano(),
+		Topic:     topic,
+		Version:   version,
+		Payload:   payload,
+	})
+	if err != nil {
+		r.log.Warn("recorder.ProcessPayload: cannot record the payload", "topic", topic, "error", err)
+	}
+
+	return r.handler.ProcessPayload(payload, topic, version)
+}
+
+func (r *recorder) record(message *data.CapturedMessage) error {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	_, err := r.writer.Write(encodeRecord(message))
+	if err != nil {
+		return err
+	}
+
+	return r.writer.Flush()
+}
+
+// Close will close the capture file and the wrapped handler
+func (r *recorder) Close() error {
+	r.mut.Lock()
+	errFlush := r.writer.Flush()
+	errClose := r.file.Close()
+	r.mut.Unlock()
+
+	err := r.handler.Close()
+	if err != nil {
+		return err
+	}
+	if errFlush != nil {
+		return errFlush
+	}
+
+	return errClose
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (r *recorder) IsInterfaceNil() bool {
+	return r == nil
+}
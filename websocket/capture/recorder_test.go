@@ -0,0 +1,143 @@
+package capture
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/testscommon"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	"github.com/subrahamanyam341/andes-core-16/core"
+)
+
+func createRecorderArgs(t *testing.T) ArgsRecorder {
+	return ArgsRecorder{
+		Path: filepath.Join(t.TempDir(), "stream.capture"),
+		Log:  &testscommon.LoggerMock{},
+	}
+}
+
+func readAll(t *testing.T, path string) []*data.CapturedMessage {
+	captureReader, err := NewReader(path)
+	require.Nil(t, err)
+	defer func() {
+		_ = captureReader.Close()
+	}()
+
+	messages := make([]*data.CapturedMessage, 0)
+	for {
+		message, errNext := captureReader.Next()
+		if errors.Is(errNext, io.EOF) {
+			return messages
+		}
+		require.Nil(t, errNext)
+		messages = append(messages, message)
+	}
+}
+
+func TestNewRecorder(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty path, should return error", func(t *testing.T) {
+		args := createRecorderArgs(t)
+		args.Path = ""
+		r, err := NewRecorder(args)
+		require.Nil(t, r)
+		require.Equal(t, data.ErrEmptyCapturePath, err)
+	})
+	t.Run("nil logger, should return error", func(t *testing.T) {
+		args := createRecorderArgs(t)
+		args.Log = nil
+		r, err := NewRecorder(args)
+		require.Nil(t, r)
+		require.Equal(t, core.ErrNilLogger, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		r, err := NewRecorder(createRecorderArgs(t))
+		require.Nil(t, err)
+		require.False(t, r.IsInterfaceNil())
+		require.Nil(t, r.Close())
+	})
+}
+
+func TestRecorder_ShouldRecordAndForwardThePayloads(t *testing.T) {
+	t.Parallel()
+
+	args := createRecorderArgs(t)
+	forwarded := make([]string, 0)
+	handlerClosed := false
+	args.Handler = &testscommon.PayloadHandlerStub{
+		ProcessPayloadCalled: func(payload []byte, topic string, _ uint32) error {
+			// the payload is already on disk when the handler is called
+			messages := readAll(t, args.Path)
+			require.Equal(t, payload, messages[len(messages)-1].Payload)
+
+			forwarded = append(forwarded, topic+":"+string(payload))
+			return nil
+		},
+		CloseCalled: func() error {
+			handlerClosed = true
+			return nil
+		},
+	}
+
+	r, _ := NewRecorder(args)
+	require.Nil(t, r.ProcessPayload([]byte("first"), "blocks", 1))
+	require.Nil(t, r.ProcessPayload([]byte{}, "", 2))
+	require.Nil(t, r.Close())
+	require.True(t, handlerClosed)
+
+	// a new recorder appends to the same file
+	args.Handler = nil
+	r, _ = NewRecorder(args)
+	require.Nil(t, r.ProcessPayload([]byte("third"), "accounts", 3))
+	require.Nil(t, r.Close())
+
+	require.Equal(t, []string{"blocks:first", ":"}, forwarded)
+	messages := readAll(t, args.Path)
+	require.Len(t, messages, 3)
+	require.Equal(t, "blocks", messages[0].Topic)
+	require.Equal(t, uint32(1), messages[0].Version)
+	require.Equal(t, []byte("first"), messages[0].Payload)
+	require.Equal(t, "", messages[1].Topic)
+	require.Empty(t, messages[1].Payload)
+	require.Equal(t, "accounts", messages[2].Topic)
+	require.Equal(t, uint32(3), messages[2].Version)
+	require.LessOrEqual(t, messages[0].Timestamp, messages[2].Timestamp)
+}
+
+func TestReader_InvalidFiles(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty path, should return error", func(t *testing.T) {
+		captureReader, err := NewReader("")
+		require.Nil(t, captureReader)
+		require.Equal(t, data.ErrEmptyCapturePath, err)
+	})
+	t.Run("not a capture file, should return error", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "other")
+		_ = os.WriteFile(path, []byte("something else"), 0644)
+
+		captureReader, err := NewReader(path)
+		require.Nil(t, captureReader)
+		require.True(t, errors.Is(err, data.ErrInvalidCaptureFile))
+	})
+	t.Run("truncated record, should return error", func(t *testing.T) {
+		args := createRecorderArgs(t)
+		r, _ := NewRecorder(args)
+		_ = r.ProcessPayload([]byte("payload"), "blocks", 1)
+		_ = r.Close()
+
+		content, _ := os.ReadFile(args.Path)
+		_ = os.WriteFile(args.Path, content[:len(content)-2], 0644)
+
+		captureReader, err := NewReader(args.Path)
+		require.Nil(t, err)
+		_, err = captureReader.Next()
+		require.True(t, errors.Is(err, data.ErrInvalidCaptureFile))
+		_ = captureReader.Close()
+	})
+}
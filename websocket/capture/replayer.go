@@ -0,0 +1,135 @@
+package capture
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	"github.com/subrahamanyam341/andes-core-16/core"
+	"github.com/subrahamanyam341/andes-core-16/core/check"
+)
+
+const defaultRetryDuration = time.Second
+
+// ArgsReplayer holds the arguments needed for creating a replayer
+type ArgsReplayer struct {
+	Reader        Reader
+	Sender        PayloadSender
+	Speed         float64
+	RetryDuration time.Duration
+	Log           core.Logger
+}
+
+// replayer pushes the messages of a capture file through a payload sender, keeping their order and, optionally, the
+// original pace. The payload versions are not replayed, the sender stamps its own version
+type replayer struct {
+	reader        Reader
+	sender        PayloadSender
+	speed         float64
+	retryDuration time.Duration
+	log           core.Logger
+}
+
+// NewReplayer will create a replayer. A speed of 1 keeps the original pace, 2 replays twice as fast and 0 replays the
+// messages as fast as they are sent
+func NewReplayer(args ArgsReplayer) (*replayer, error) {
+	if check.IfNil(args.Reader) {
+		return nil, data.ErrNilCaptureReader
+	}
+	if check.IfNil(args.Sender) {
+		return nil, data.ErrNilPayloadSender
+	}
+	if check.IfNil(args.Log) {
+		return nil, core.ErrNilLogger
+	}
+	if args.Speed < 0 {
+		return nil, data.ErrInvalidReplaySpeed
+	}
+
+	retryDuration := args.RetryDuration
+	if retryDuration == 0 {
+		retryDuration = defaultRetryDuration
+	}
+
+	return &replayer{
+		reader:        args.Reader,
+		sender:        args.Sender,
+		speed:         args.Speed,
+		retryDuration: retryDuration,
+		log:           args.Log,
+	}, nil
+}
+
+// Replay will send all the messages of the capture file, in order, and returns the number of sent messages. A message
+// that cannot be sent is retried until it succeeds or the context is done
+func (r *replayer) Replay(ctx context.Context) (int, error) {
+	numSent := 0
+	previousTimestamp := int64(0)
+	for {
+		message, err := r.reader.Next()
+		if errors.Is(err, io.EOF) {
+			return numSent, nil
+		}
+		if err != nil {
+			return numSent, err
+		}
+
+		if numSent > 0 {
+			err = r.waitOriginalGap(ctx, message.Timestamp-previousTimestamp)
+			if err != nil {
+				return numSent, err
+			}
+		}
+		previousTimestamp = message.Timestamp
+
+		err = r.sendUntilSucceeds(ctx, message)
+		if err != nil {
+			return numSent, err
+		}
+		numSent++
+	}
+}
+
+func (r *replayer) waitOriginalGap(ctx context.Context, gapInNanoseconds int64) error {
+	if r.speed == 0 || gapInNanoseconds <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(time.Duration(float64(gapInNanoseconds) / r.speed))
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *replayer) sendUntilSucceeds(ctx context.Context, message *data.CapturedMessage) error {
+	timer := time.NewTimer(r.retryDuration)
+	defer timer.Stop()
+
+	for {
+		err := r.sender.Send(message.Payload, message.Topic)
+		if err == nil {
+			return nil
+		}
+
+		r.log.Debug("replayer.sendUntilSucceeds: cannot send the message, retrying", "topic", message.Topic, "error", err)
+		timer.Reset(r.retryDuration)
+
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (r *replayer) IsInterfaceNil() bool {
+	return r == nil
+}
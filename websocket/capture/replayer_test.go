@@ -0,0 +1,185 @@
+package capture
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/testscommon"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	"github.com/subrahamanyam341/andes-core-16/core"
+)
+
+type sliceReader struct {
+	messages []*data.CapturedMessage
+}
+
+func (sr *sliceReader) Next() (*data.CapturedMessage, error) {
+	if len(sr.messages) == 0 {
+		return nil, errEndOfSlice
+	}
+
+	message := sr.messages[0]
+	sr.messages = sr.messages[1:]
+	return message, nil
+}
+
+func (sr *sliceReader) Close() error {
+	return nil
+}
+
+func (sr *sliceReader) IsInterfaceNil() bool {
+	return sr == nil
+}
+
+var errEndOfSlice = errors.New("end of slice")
+
+func createReplayerArgs(t *testing.T, messages ...*data.CapturedMessage) ArgsReplayer {
+	path := createRecorderArgs(t).Path
+	r, _ := NewRecorder(ArgsRecorder{Path: path, Log: &testscommon.LoggerMock{}})
+	for _, message := range messages {
+		_ = r.record(message)
+	}
+	_ = r.Close()
+
+	captureReader, err := NewReader(path)
+	require.Nil(t, err)
+	t.Cleanup(func() {
+		_ = captureReader.Close()
+	})
+
+	return ArgsReplayer{
+		Reader:        captureReader,
+		Sender:        &testscommon.PayloadSenderStub{},
+		RetryDuration: 10 * time.Millisecond,
+		Log:           &testscommon.LoggerMock{},
+	}
+}
+
+func TestNewReplayer(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil reader, should return error", func(t *testing.T) {
+		args := createReplayerArgs(t)
+		args.Reader = nil
+		r, err := NewReplayer(args)
+		require.Nil(t, r)
+		require.Equal(t, data.ErrNilCaptureReader, err)
+	})
+	t.Run("nil sender, should return error", func(t *testing.T) {
+		args := createReplayerArgs(t)
+		args.Sender = nil
+		r, err := NewReplayer(args)
+		require.Nil(t, r)
+		require.Equal(t, data.ErrNilPayloadSender, err)
+	})
+	t.Run("nil logger, should return error", func(t *testing.T) {
+		args := createReplayerArgs(t)
+		args.Log = nil
+		r, err := NewReplayer(args)
+		require.Nil(t, r)
+		require.Equal(t, core.ErrNilLogger, err)
+	})
+	t.Run("negative speed, should return error", func(t *testing.T) {
+		args := createReplayerArgs(t)
+		args.Speed = -1
+		r, err := NewReplayer(args)
+		require.Nil(t, r)
+		require.Equal(t, data.ErrInvalidReplaySpeed, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		r, err := NewReplayer(createReplayerArgs(t))
+		require.Nil(t, err)
+		require.False(t, r.IsInterfaceNil())
+	})
+}
+
+func TestReplayer_ReplayShouldKeepTheOrderAndRetry(t *testing.T) {
+	t.Parallel()
+
+	args := createReplayerArgs(t,
+		&data.CapturedMessage{Timestamp: 1, Topic: "blocks", Payload: []byte("first")},
+		&data.CapturedMessage{Timestamp: 2, Topic: "accounts", Payload: []byte("second")},
+	)
+	sent := make([]string, 0)
+	numFailures := 0
+	args.Sender = &testscommon.PayloadSenderStub{
+		SendCalled: func(payload []byte, topic string) error {
+			if numFailures < 2 {
+				numFailures++
+				return errors.New("no clients connected")
+			}
+			sent = append(sent, topic+":"+string(payload))
+			return nil
+		},
+	}
+
+	r, _ := NewReplayer(args)
+	numSent, err := r.Replay(context.Background())
+	require.Nil(t, err)
+	require.Equal(t, 2, numSent)
+	require.Equal(t, []string{"blocks:first", "accounts:second"}, sent)
+}
+
+func TestReplayer_ReplayShouldKeepThePace(t *testing.T) {
+	t.Parallel()
+
+	gap := 400 * time.Millisecond
+	messages := []*data.CapturedMessage{
+		{Timestamp: 0, Topic: "blocks"},
+		{Timestamp: int64(gap), Topic: "blocks"},
+		{Timestamp: int64(2 * gap), Topic: "blocks"},
+	}
+
+	replayDuration := func(speed float64) time.Duration {
+		args := createReplayerArgs(t, messages...)
+		args.Speed = speed
+		r, _ := NewReplayer(args)
+
+		start := time.Now()
+		numSent, err := r.Replay(context.Background())
+		require.Nil(t, err)
+		require.Equal(t, len(messages), numSent)
+
+		return time.Since(start)
+	}
+
+	require.GreaterOrEqual(t, replayDuration(1), 2*gap)
+	accelerated := replayDuration(4)
+	require.GreaterOrEqual(t, accelerated, gap/2)
+	require.Less(t, accelerated, 2*gap)
+	require.Less(t, replayDuration(0), gap/2)
+}
+
+func TestReplayer_ReplayShouldStopWhenTheContextIsDone(t *testing.T) {
+	t.Parallel()
+
+	args := createReplayerArgs(t, &data.CapturedMessage{Topic: "blocks"})
+	args.Sender = &testscommon.PayloadSenderStub{
+		SendCalled: func(payload []byte, topic string) error {
+			return errors.New("no clients connected")
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	r, _ := NewReplayer(args)
+	numSent, err := r.Replay(ctx)
+	require.Equal(t, context.DeadlineExceeded, err)
+	require.Zero(t, numSent)
+}
+
+func TestReplayer_ReplayShouldReturnTheReaderError(t *testing.T) {
+	t.Parallel()
+
+	args := createReplayerArgs(t)
+	args.Reader = &sliceReader{messages: []*data.CapturedMessage{{Topic: "blocks"}}}
+
+	r, _ := NewReplayer(args)
+	numSent, err := r.Replay(context.Background())
+	require.Equal(t, errEndOfSlice, err)
+	require.Equal(t, 1, numSent)
+}
@@ -1,15 +1,26 @@
 package client
 
 import (
+	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
+	"net/http"
 	"net/url"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/subrahamanyam341/andes-communication/websocket"
+	"github.com/subrahamanyam341/andes-communication/websocket/codec"
+	"github.com/subrahamanyam341/andes-communication/websocket/compression"
 	"github.com/subrahamanyam341/andes-communication/websocket/connection"
 	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	"github.com/subrahamanyam341/andes-communication/websocket/memory"
+	"github.com/subrahamanyam341/andes-communication/websocket/queue"
+	"github.com/subrahamanyam341/andes-communication/websocket/reconnect"
 	"github.com/subrahamanyam341/andes-communication/websocket/transceiver"
+	"github.com/subrahamanyam341/andes-communication/websocket/versioning"
 	"github.com/subrahamanyam341/andes-core-16/core"
 	"github.com/subrahamanyam341/andes-core-16/core/check"
 	"github.com/subrahamanyam341/andes-core-16/core/closing"
@@ -19,6 +30,7 @@ import (
 type ArgsWebSocketClient struct {
 	RetryDurationInSeconds     int
 	AckTimeoutInSeconds        int
+	AckWindowSize              int
 	WithAcknowledge            bool
 	BlockingAckOnError         bool
 	DropMessagesIfNoConnection bool
@@ -26,16 +38,58 @@ type ArgsWebSocketClient struct {
 	PayloadConverter           websocket.PayloadConverter
 	Log                        core.Logger
 	PayloadVersion             uint32
+	MinPayloadVersion          uint32
+	TLSConfig                  *tls.Config
+	CredentialsProvider        websocket.CredentialsProvider
+	OutboundQueue              websocket.OutboundQueue
+	SubscribedTopics           []string
+	EnablePerMessageDeflate    bool
+	PayloadCompression         string
+	CompressionThreshold       int
+	PingIntervalInSeconds      int
+	PongTimeoutInSeconds       int
+	ReconnectPolicy            websocket.ReconnectPolicy
+	RoutePath                  string
+	ReadBufferSize             int
+	WriteBufferSize            int
+	MaxMessageSize             int64
+	WriteTimeoutInSeconds      int
+	HandshakeTimeoutInSeconds  int
+	URLs                       []string
+	EndpointSelection          string
+	Codec                      string
+	Metrics                    websocket.MetricsHandler
+	Transport                  string
+	MemoryTransport            data.MemoryTransportConfig
+	ChunkSizeInBytes           int
+	MaxReassemblySizeInBytes   int
+	ReassemblyTimeoutInSeconds int
+	MessageSigner              websocket.MessageSigner
+	MessageVerifier            websocket.MessageVerifier
+	MaxConcurrentRequests      int
 }
 
 type client struct {
-	url                        string
+	endpoints                  *endpointSelector
 	retryDuration              time.Duration
+	reconnectPolicy            websocket.ReconnectPolicy
 	safeCloser                 core.SafeCloser
 	log                        core.Logger
 	wsConn                     websocket.WSConClient
 	transceiver                Transceiver
+	payloadConverter           CompressingPayloadConverter
+	payloadCompression         string
+	codec                      string
+	payloadVersion             uint32
+	minPayloadVersion          uint32
+	metrics                    websocket.MetricsHandler
+	queueSender                QueueSender
 	dropMessagesIfNoConnection bool
+	mutSubscriptions           sync.RWMutex
+	subscribed                 bool
+	subscriptions              map[string]struct{}
+	mutStateHandler            sync.RWMutex
+	stateHandler               func(state data.ConnectionState)
 }
 
 // NewWebSocketClient will create a new instance of WebSocket client
@@ -45,30 +99,91 @@ func NewWebSocketClient(args ArgsWebSocketClient) (*client, error) {
 		return nil, err
 	}
 
+	if args.Codec == "" {
+		args.Codec = data.ProtobufCodec
+	}
+	codecPayloadConverter, err := codec.NewPayloadConverter(args.Codec, args.PayloadConverter)
+	if err != nil {
+		return nil, err
+	}
+
+	payloadConverter, err := compression.NewCompressingPayloadConverter(compression.ArgsCompressingPayloadConverter{
+		PayloadConverter: codecPayloadConverter,
+		Algorithm:        args.PayloadCompression,
+		ThresholdInBytes: args.CompressionThreshold,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	metrics := args.Metrics
+	if check.IfNil(metrics) {
+		metrics = websocket.NewNilMetricsHandler()
+	}
+
 	argsTransceiver := transceiver.ArgsTransceiver{
-		PayloadConverter:   args.PayloadConverter,
-		Log:                args.Log,
-		RetryDurationInSec: args.RetryDurationInSeconds,
-		AckTimeoutInSec:    args.AckTimeoutInSeconds,
-		BlockingAckOnError: args.BlockingAckOnError,
-		WithAcknowledge:    args.WithAcknowledge,
-		PayloadVersion:     args.PayloadVersion,
+		PayloadConverter:      payloadConverter,
+		Log:                   args.Log,
+		RetryDurationInSec:    args.RetryDurationInSeconds,
+		AckTimeoutInSec:       args.AckTimeoutInSeconds,
+		BlockingAckOnError:    args.BlockingAckOnError,
+		WithAcknowledge:       args.WithAcknowledge,
+		PayloadVersion:        args.PayloadVersion,
+		MinPayloadVersion:     args.MinPayloadVersion,
+		AckWindowSize:         args.AckWindowSize,
+		TextFrames:            codec.UsesTextFrames(args.Codec),
+		Metrics:               metrics,
+		ChunkSizeInBytes:      args.ChunkSizeInBytes,
+		MaxReassemblySize:     args.MaxReassemblySizeInBytes,
+		ReassemblyTimeout:     time.Duration(args.ReassemblyTimeoutInSeconds) * time.Second,
+		MessageSigner:         args.MessageSigner,
+		MessageVerifier:       args.MessageVerifier,
+		MaxConcurrentRequests: args.MaxConcurrentRequests,
 	}
 	wsTransceiver, err := transceiver.NewTransceiver(argsTransceiver)
 	if err != nil {
 		return nil, err
 	}
 
-	wsUrl := url.URL{Scheme: "ws", Host: args.URL, Path: data.WSRoute}
+	reconnectPolicy := args.ReconnectPolicy
+	if check.IfNil(reconnectPolicy) {
+		reconnectPolicy, err = reconnect.NewFixedPolicy(time.Duration(args.RetryDurationInSeconds)*time.Second, 0)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	wsClient := &client{
-		url:                        wsUrl.String(),
-		wsConn:                     connection.NewWSConnClient(),
+		endpoints:                  newEndpointSelector(createEndpointURLs(args), args.EndpointSelection),
+		wsConn:                     createConnection(args),
 		retryDuration:              time.Duration(args.RetryDurationInSeconds) * time.Second,
+		reconnectPolicy:            reconnectPolicy,
 		safeCloser:                 closing.NewSafeChanCloser(),
 		transceiver:                wsTransceiver,
+		payloadConverter:           payloadConverter,
+		payloadCompression:         args.PayloadCompression,
+		codec:                      args.Codec,
+		payloadVersion:             args.PayloadVersion,
+		minPayloadVersion:          args.MinPayloadVersion,
+		metrics:                    metrics,
 		log:                        args.Log,
 		dropMessagesIfNoConnection: args.DropMessagesIfNoConnection,
+		subscriptions:              make(map[string]struct{}),
+	}
+	if len(args.SubscribedTopics) > 0 {
+		wsClient.addSubscriptions(args.SubscribedTopics)
+	}
+
+	if !check.IfNil(args.OutboundQueue) {
+		wsClient.queueSender, err = queue.NewQueueSender(queue.ArgsQueueSender{
+			Queue:              args.OutboundQueue,
+			SendHandler:        wsClient.sendQueued,
+			Log:                args.Log,
+			RetryDurationInSec: args.RetryDurationInSeconds,
+		})
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	wsClient.start()
@@ -83,26 +198,166 @@ func checkArgs(args ArgsWebSocketClient) error {
 	if check.IfNil(args.PayloadConverter) {
 		return data.ErrNilPayloadConverter
 	}
-	if args.URL == "" {
+	if args.URL == "" && len(args.URLs) == 0 {
 		return data.ErrEmptyUrl
 	}
+	for _, endpoint := range args.URLs {
+		if endpoint == "" {
+			return data.ErrEmptyUrl
+		}
+	}
 	if args.RetryDurationInSeconds == 0 {
 		return data.ErrZeroValueRetryDuration
 	}
-	return nil
+	if (args.PingIntervalInSeconds > 0) != (args.PongTimeoutInSeconds > 0) {
+		return data.ErrInvalidKeepAliveConfig
+	}
+	if args.RoutePath != "" && !strings.HasPrefix(args.RoutePath, "/") {
+		return data.ErrInvalidRoutePath
+	}
+	if args.ReadBufferSize < 0 || args.WriteBufferSize < 0 {
+		return data.ErrInvalidBufferSize
+	}
+	if args.MaxMessageSize < 0 {
+		return data.ErrInvalidMaxMessageSize
+	}
+	if args.WriteTimeoutInSeconds < 0 || args.HandshakeTimeoutInSeconds < 0 {
+		return data.ErrInvalidTimeout
+	}
+	err := codec.CheckCodec(args.Codec)
+	if err != nil {
+		return err
+	}
+	err = versioning.CheckRange(args.MinPayloadVersion, args.PayloadVersion)
+	if err != nil {
+		return err
+	}
+	err = memory.CheckTransport(args.Transport, args.MemoryTransport)
+	if err != nil {
+		return err
+	}
+	if memory.IsMemoryTransport(args.Transport) && args.TLSConfig != nil {
+		return fmt.Errorf("%w: TLS", data.ErrNotSupportedByMemoryTransport)
+	}
+	return checkEndpointSelection(args.EndpointSelection)
+}
+
+// createConnection returns the connection dialed by the client: a websocket connection or, for the memory transport,
+// an in-memory connection to a server of the same process
+func createConnection(args ArgsWebSocketClient) websocket.WSConClient {
+	subprotocols := []string{codec.CodecToSubprotocol(args.Codec)}
+	if memory.IsMemoryTransport(args.Transport) {
+		return memory.NewConn(memory.ArgsConn{
+			CredentialsProvider: args.CredentialsProvider,
+			HandshakeHeader:     createHandshakeHeader(args),
+			Subprotocols:        subprotocols,
+			Config:              args.MemoryTransport,
+		})
+	}
+
+	return connection.NewWSConnClientWithArgs(connection.ArgsWSConnClient{
+		TLSConfig:           args.TLSConfig,
+		CredentialsProvider: args.CredentialsProvider,
+		HandshakeHeader:     createHandshakeHeader(args),
+		EnableCompression:   args.EnablePerMessageDeflate,
+		PingInterval:        time.Duration(args.PingIntervalInSeconds) * time.Second,
+		PongTimeout:         time.Duration(args.PongTimeoutInSeconds) * time.Second,
+		ReadBufferSize:      args.ReadBufferSize,
+		WriteBufferSize:     args.WriteBufferSize,
+		HandshakeTimeout:    time.Duration(args.HandshakeTimeoutInSeconds) * time.Second,
+		MaxMessageSize:      args.MaxMessageSize,
+		WriteTimeout:        time.Duration(args.WriteTimeoutInSeconds) * time.Second,
+		Subprotocols:        subprotocols,
+	})
+}
+
+// createHandshakeHeader returns the header advertising the payload compression algorithms and the payload versions
+// supported by this client
+func createHandshakeHeader(args ArgsWebSocketClient) http.Header {
+	header := compression.CreateHandshakeHeader()
+	for key, values := range versioning.CreateHandshakeHeader(args.MinPayloadVersion, args.PayloadVersion) {
+		header[key] = values
+	}
+
+	return header
+}
+
+// createEndpointURLs returns the websocket urls of the endpoints, the URL argument, if set, being the first one
+func createEndpointURLs(args ArgsWebSocketClient) []string {
+	routePath := args.RoutePath
+	if routePath == "" {
+		routePath = data.WSRoute
+	}
+	scheme := "ws"
+	if args.TLSConfig != nil {
+		scheme = "wss"
+	}
+
+	hosts := getEndpointHosts(args)
+	urls := make([]string, 0, len(hosts))
+	for _, host := range hosts {
+		wsUrl := url.URL{Scheme: scheme, Host: host, Path: routePath}
+		urls = append(urls, wsUrl.String())
+	}
+
+	return urls
+}
+
+func getEndpointHosts(args ArgsWebSocketClient) []string {
+	hosts := make([]string, 0, len(args.URLs)+1)
+	if args.URL != "" {
+		hosts = append(hosts, args.URL)
+	}
+
+	return append(hosts, args.URLs...)
 }
 
 func (c *client) start() {
 	go func() {
 		timer := time.NewTimer(c.retryDuration)
 		defer timer.Stop()
+		failedAttempts := 0
+		connecting := false
+		connectedBefore := false
 		for {
-			err := c.wsConn.OpenConnection(c.url)
-			if err != nil && !errors.Is(err, data.ErrConnectionAlreadyOpen) {
-				c.log.Warn(fmt.Sprintf("c.openConnection(), retrying in %v...", c.retryDuration), "error", err)
+			if !connecting && !c.wsConn.IsOpen() {
+				connecting = true
+				c.endpoints.reconnecting()
+				c.notifyStateChange(data.ConnectionConnecting)
 			}
 
-			timer.Reset(c.retryDuration)
+			delay := c.retryDuration
+			err := c.openConnection()
+			switch {
+			case err == nil && c.isClosed():
+				// the client was closed while the connection was being opened
+				_ = c.wsConn.Close()
+				return
+			case err == nil:
+				failedAttempts = 0
+				connecting = false
+				if connectedBefore {
+					c.metrics.Reconnected()
+				}
+				connectedBefore = true
+				c.negotiatePayloadCompression()
+				c.sendSubscriptions()
+				c.notifyStateChange(data.ConnectionOpen)
+			case errors.Is(err, data.ErrConnectionAlreadyOpen):
+			default:
+				failedAttempts++
+				c.endpoints.attemptFailed()
+				var shouldRetry bool
+				delay, shouldRetry = c.reconnectPolicy.NextDelay(failedAttempts)
+				if !shouldRetry {
+					c.log.Error("c.openConnection(), giving up", "attempts", failedAttempts, "error", err)
+					c.notifyStateChange(data.ConnectionFailed)
+					return
+				}
+				c.log.Warn(fmt.Sprintf("c.openConnection(), retrying in %v...", delay), "next url", c.endpoints.current(), "error", err)
+			}
+
+			timer.Reset(delay)
 
 			select {
 			case <-timer.C:
@@ -120,6 +375,9 @@ func (c *client) start() {
 			if closed {
 				err := c.wsConn.Close()
 				c.log.Debug("try to close the connection", "close error", err)
+				if err == nil {
+					c.notifyStateChange(c.getClosedState())
+				}
 			}
 
 			timer.Reset(c.retryDuration)
@@ -133,28 +391,240 @@ func (c *client) start() {
 	}()
 }
 
-// Send will send the provided payload from args
+// Send will send the provided payload from args. If an outbound queue is set, the payload is persisted and sent asynchronously
 func (c *client) Send(payload []byte, topic string) error {
+	if c.queueSender != nil {
+		return c.queueSender.Send(payload, topic)
+	}
+
 	dropMessage := c.dropMessagesIfNoConnection && !c.wsConn.IsOpen()
 	if dropMessage {
 		return nil
 	}
 
+	return c.sendNow(payload, topic)
+}
+
+// SendAsync will send the provided payload without waiting for its acknowledgement. If an outbound queue is set, the
+// returned future completes as soon as the payload is persisted
+func (c *client) SendAsync(payload []byte, topic string) (websocket.AckFuture, error) {
+	if c.queueSender != nil {
+		return transceiver.NewCompletedAckFuture(nil), c.queueSender.Send(payload, topic)
+	}
+
+	return c.sendMessageAsync(&data.OutgoingMessage{Payload: payload, Topic: topic})
+}
+
+func (c *client) sendMessageAsync(message *data.OutgoingMessage) (websocket.AckFuture, error) {
+	dropMessage := c.dropMessagesIfNoConnection && !c.wsConn.IsOpen()
+	if dropMessage {
+		return transceiver.NewCompletedAckFuture(nil), nil
+	}
+
+	return c.transceiver.SendMessageAsync(message, c.wsConn)
+}
+
+func (c *client) sendNow(payload []byte, topic string) error {
 	return c.transceiver.Send(payload, topic, c.wsConn)
 }
 
+// sendQueued sends a message of the outbound queue, which is retried with the same ID until it is sent
+func (c *client) sendQueued(message *data.QueuedMessage) error {
+	return c.transceiver.SendMessage(message.ToOutgoingMessage(), c.wsConn)
+}
+
+// Subscribe will ask the server to send only the payloads of the subscribed topics. The subscriptions are kept and
+// sent again on every reconnection
+func (c *client) Subscribe(topics ...string) error {
+	c.addSubscriptions(topics)
+	if !c.wsConn.IsOpen() {
+		return nil
+	}
+
+	return c.transceiver.Subscribe(topics, c.wsConn)
+}
+
+// Unsubscribe will ask the server to stop sending the payloads of the provided topics
+func (c *client) Unsubscribe(topics ...string) error {
+	c.mutSubscriptions.Lock()
+	for _, topic := range topics {
+		delete(c.subscriptions, topic)
+	}
+	c.mutSubscriptions.Unlock()
+
+	if !c.wsConn.IsOpen() {
+		return nil
+	}
+
+	return c.transceiver.Unsubscribe(topics, c.wsConn)
+}
+
+func (c *client) isClosed() bool {
+	select {
+	case <-c.safeCloser.ChanClose():
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *client) openConnection() error {
+	err := c.wsConn.OpenConnection(c.endpoints.current())
+	if err != nil {
+		return err
+	}
+
+	err = c.checkNegotiatedCodec()
+	if err != nil {
+		_ = c.wsConn.Close()
+		return err
+	}
+
+	err = c.negotiatePayloadVersion()
+	if err != nil {
+		_ = c.wsConn.Close()
+		return err
+	}
+
+	return nil
+}
+
+// checkNegotiatedCodec returns an error if the server does not speak the codec of this client
+func (c *client) checkNegotiatedCodec() error {
+	provider, ok := c.wsConn.(handshakeResponseHeaderProvider)
+	if !ok {
+		return nil
+	}
+
+	negotiatedCodec := codec.GetNegotiatedCodec(provider.GetHandshakeResponseHeader())
+	if negotiatedCodec != c.codec {
+		return fmt.Errorf("%w: requested %s, negotiated %s", data.ErrCodecNotAcceptedByPeer, c.codec, negotiatedCodec)
+	}
+
+	return nil
+}
+
+// negotiatePayloadVersion sets the payload version of the connection to the highest one supported by both peers. The
+// server closes the connection on its own if there is none, the returned error only telling why
+func (c *client) negotiatePayloadVersion() error {
+	if c.minPayloadVersion == 0 {
+		return nil
+	}
+	provider, ok := c.wsConn.(handshakeResponseHeaderProvider)
+	if !ok {
+		return nil
+	}
+
+	version, err := versioning.Negotiate(c.minPayloadVersion, c.payloadVersion, provider.GetHandshakeResponseHeader())
+	if err != nil {
+		return err
+	}
+
+	c.transceiver.SetPayloadVersion(version)
+	c.log.Debug("payload version negotiated", "version", version)
+
+	return nil
+}
+
+func (c *client) negotiatePayloadCompression() {
+	provider, ok := c.wsConn.(handshakeResponseHeaderProvider)
+	if !ok {
+		return
+	}
+
+	enabled := compression.IsAlgorithmSupportedByPeer(provider.GetHandshakeResponseHeader(), c.payloadCompression)
+	c.payloadConverter.SetCompressionEnabled(enabled)
+	c.log.Debug("payload compression negotiated", "algorithm", c.payloadCompression, "enabled", enabled)
+}
+
+// OnStateChange will set the handler called on every connection state transition: connecting, open, closed or
+// unresponsive and failed, once the reconnect policy gave up
+func (c *client) OnStateChange(handler func(state data.ConnectionState)) {
+	c.mutStateHandler.Lock()
+	c.stateHandler = handler
+	c.mutStateHandler.Unlock()
+}
+
+func (c *client) notifyStateChange(state data.ConnectionState) {
+	c.log.Debug("connection state changed", "url", c.endpoints.current(), "state", state.String())
+
+	c.mutStateHandler.RLock()
+	handler := c.stateHandler
+	c.mutStateHandler.RUnlock()
+
+	if handler != nil {
+		handler(state)
+	}
+}
+
+func (c *client) getClosedState() data.ConnectionState {
+	provider, ok := c.wsConn.(unresponsivenessProvider)
+	if ok && provider.IsUnresponsive() {
+		return data.ConnectionUnresponsive
+	}
+
+	return data.ConnectionClosed
+}
+
+func (c *client) addSubscriptions(topics []string) {
+	c.mutSubscriptions.Lock()
+	defer c.mutSubscriptions.Unlock()
+
+	c.subscribed = true
+	for _, topic := range topics {
+		c.subscriptions[topic] = struct{}{}
+	}
+}
+
+func (c *client) sendSubscriptions() {
+	c.mutSubscriptions.RLock()
+	if !c.subscribed {
+		c.mutSubscriptions.RUnlock()
+		return
+	}
+	topics := make([]string, 0, len(c.subscriptions))
+	for topic := range c.subscriptions {
+		topics = append(topics, topic)
+	}
+	c.mutSubscriptions.RUnlock()
+
+	err := c.transceiver.Subscribe(topics, c.wsConn)
+	if err != nil {
+		c.log.Warn("c.sendSubscriptions() cannot send the subscriptions", "error", err)
+	}
+}
+
+// Request will send the provided payload to the server and will wait for its response until the context is done
+func (c *client) Request(ctx context.Context, topic string, payload []byte) ([]byte, error) {
+	return c.transceiver.Request(ctx, payload, topic, c.wsConn)
+}
+
 // SetPayloadHandler set the payload handler
 func (c *client) SetPayloadHandler(handler websocket.PayloadHandler) error {
 	return c.transceiver.SetPayloadHandler(handler)
 }
 
+// SetRequestHandler set the request handler
+func (c *client) SetRequestHandler(handler websocket.RequestHandler) error {
+	return c.transceiver.SetRequestHandler(handler)
+}
+
 // Close will close the component
 func (c *client) Close() error {
-	defer c.safeCloser.Close()
+	// the reconnection is stopped first, otherwise the connection might be opened again once closed
+	c.safeCloser.Close()
 
 	var lastErr error
 
 	c.log.Info("closing client...")
+	if c.queueSender != nil {
+		err := c.queueSender.Close()
+		if err != nil {
+			c.log.Warn("client.Close() queue sender", "error", err)
+			lastErr = err
+		}
+	}
+
 	err := c.transceiver.Close()
 	if err != nil {
 		c.log.Warn("client.Close() transceiver", "error", err)
@@ -162,9 +632,15 @@ func (c *client) Close() error {
 	}
 
 	err = c.wsConn.Close()
+	if errors.Is(err, data.ErrConnectionNotOpen) {
+		// the client did not connect yet, so there is nothing to close
+		return lastErr
+	}
 	if err != nil {
 		c.log.Warn("client.Close() cannot close connection", "error", err)
 		lastErr = err
+	} else {
+		c.notifyStateChange(data.ConnectionClosed)
 	}
 
 	return lastErr
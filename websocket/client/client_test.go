@@ -1,6 +1,7 @@
 package client
 
 import (
+	"errors"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -8,6 +9,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 	"github.com/subrahamanyam341/andes-communication/testscommon"
+	"github.com/subrahamanyam341/andes-communication/testscommon/transceiver"
 	"github.com/subrahamanyam341/andes-communication/websocket"
 	"github.com/subrahamanyam341/andes-communication/websocket/data"
 	"github.com/subrahamanyam341/andes-core-16/data/outport"
@@ -60,6 +62,162 @@ func TestNewWebSocketServer(t *testing.T) {
 		require.Nil(t, ws)
 		require.Equal(t, data.ErrZeroValueRetryDuration, err)
 	})
+
+	t.Run("unknown payload compression, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.PayloadCompression = "gzip"
+		ws, err := NewWebSocketClient(args)
+		require.Nil(t, ws)
+		require.Equal(t, data.ErrUnknownCompressionAlgorithm, err)
+	})
+
+	t.Run("negative compression threshold, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.CompressionThreshold = -1
+		ws, err := NewWebSocketClient(args)
+		require.Nil(t, ws)
+		require.Equal(t, data.ErrNegativeCompressionThreshold, err)
+	})
+
+	t.Run("negative max reassembly size, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.MaxReassemblySizeInBytes = -1
+		ws, err := NewWebSocketClient(args)
+		require.Nil(t, ws)
+		require.Equal(t, data.ErrInvalidChunkingConfig, err)
+	})
+
+	t.Run("ping interval without pong timeout, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.PingIntervalInSeconds = 1
+		ws, err := NewWebSocketClient(args)
+		require.Nil(t, ws)
+		require.Equal(t, data.ErrInvalidKeepAliveConfig, err)
+	})
+
+	t.Run("pong timeout without ping interval, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.PongTimeoutInSeconds = 1
+		ws, err := NewWebSocketClient(args)
+		require.Nil(t, ws)
+		require.Equal(t, data.ErrInvalidKeepAliveConfig, err)
+	})
+
+	t.Run("only the endpoints list, should work", func(t *testing.T) {
+		args := createArgs()
+		args.URL = ""
+		args.URLs = []string{"url1", "url2"}
+		ws, err := NewWebSocketClient(args)
+		require.Nil(t, err)
+		require.Equal(t, "ws://url1/save", ws.endpoints.current())
+		_ = ws.Close()
+	})
+
+	t.Run("empty url in the endpoints list, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.URLs = []string{"url1", ""}
+		ws, err := NewWebSocketClient(args)
+		require.Nil(t, ws)
+		require.Equal(t, data.ErrEmptyUrl, err)
+	})
+
+	t.Run("unknown endpoint selection, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.EndpointSelection = "random"
+		ws, err := NewWebSocketClient(args)
+		require.Nil(t, ws)
+		require.Equal(t, data.ErrUnknownEndpointSelection, err)
+	})
+
+	t.Run("fan-out endpoint selection, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.EndpointSelection = data.FanOutEndpointSelection
+		ws, err := NewWebSocketClient(args)
+		require.Nil(t, ws)
+		require.Equal(t, data.ErrFanOutNeedsFanOutClient, err)
+	})
+
+	t.Run("min payload version greater than the payload version, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.MinPayloadVersion = 3
+		args.PayloadVersion = 2
+		ws, err := NewWebSocketClient(args)
+		require.Nil(t, ws)
+		require.True(t, errors.Is(err, data.ErrInvalidPayloadVersionRange))
+	})
+
+	t.Run("unknown codec, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.Codec = "xml"
+		ws, err := NewWebSocketClient(args)
+		require.Nil(t, ws)
+		require.Equal(t, data.ErrUnknownCodec, err)
+	})
+
+	t.Run("unknown transport, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.Transport = "quic"
+		ws, err := NewWebSocketClient(args)
+		require.Nil(t, ws)
+		require.Equal(t, data.ErrUnknownTransport, err)
+	})
+
+	t.Run("memory transport with negative latency, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.Transport = data.MemoryTransport
+		args.MemoryTransport.LatencyInMs = -1
+		ws, err := NewWebSocketClient(args)
+		require.Nil(t, ws)
+		require.Equal(t, data.ErrInvalidMemoryTransportConfig, err)
+	})
+
+	t.Run("route path without leading slash, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.RoutePath = "save"
+		ws, err := NewWebSocketClient(args)
+		require.Nil(t, ws)
+		require.Equal(t, data.ErrInvalidRoutePath, err)
+	})
+
+	t.Run("negative read buffer size, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.ReadBufferSize = -1
+		ws, err := NewWebSocketClient(args)
+		require.Nil(t, ws)
+		require.Equal(t, data.ErrInvalidBufferSize, err)
+	})
+
+	t.Run("negative write buffer size, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.WriteBufferSize = -1
+		ws, err := NewWebSocketClient(args)
+		require.Nil(t, ws)
+		require.Equal(t, data.ErrInvalidBufferSize, err)
+	})
+
+	t.Run("negative max message size, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.MaxMessageSize = -1
+		ws, err := NewWebSocketClient(args)
+		require.Nil(t, ws)
+		require.Equal(t, data.ErrInvalidMaxMessageSize, err)
+	})
+
+	t.Run("negative write timeout, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.WriteTimeoutInSeconds = -1
+		ws, err := NewWebSocketClient(args)
+		require.Nil(t, ws)
+		require.Equal(t, data.ErrInvalidTimeout, err)
+	})
+
+	t.Run("negative handshake timeout, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.HandshakeTimeoutInSeconds = -1
+		ws, err := NewWebSocketClient(args)
+		require.Nil(t, ws)
+		require.Equal(t, data.ErrInvalidTimeout, err)
+	})
 }
 
 func TestClient_SendAndClose(t *testing.T) {
@@ -84,6 +242,14 @@ func TestClient_SendAndClose(t *testing.T) {
 	require.Equal(t, uint64(1), atomic.LoadUint64(&count))
 }
 
+func TestClient_CloseWithoutConnectionShouldWork(t *testing.T) {
+	t.Parallel()
+
+	ws, err := NewWebSocketClient(createArgs())
+	require.Nil(t, err)
+	require.Nil(t, ws.Close())
+}
+
 func TestClient_Send(t *testing.T) {
 	args := createArgs()
 	args.DropMessagesIfNoConnection = false
@@ -121,3 +287,87 @@ func TestClient_DropMessageIfNoConnection(t *testing.T) {
 	err = ws.Send([]byte("test"), "test")
 	require.Nil(t, err)
 }
+
+func TestClient_SubscribeAndUnsubscribe(t *testing.T) {
+	t.Parallel()
+
+	isOpen := atomic.Bool{}
+	subscribed := make([][]string, 0)
+	unsubscribed := make([][]string, 0)
+	ws := &client{
+		log:           &testscommon.LoggerMock{},
+		subscriptions: make(map[string]struct{}),
+		wsConn: &testscommon.WebsocketConnectionStub{
+			IsOpenCalled: func() bool {
+				return isOpen.Load()
+			},
+		},
+		transceiver: &transceiver.WebSocketTransceiverStub{
+			SubscribeCalled: func(topics []string, _ websocket.WSConClient) error {
+				subscribed = append(subscribed, topics)
+				return nil
+			},
+			UnsubscribeCalled: func(topics []string, _ websocket.WSConClient) error {
+				unsubscribed = append(unsubscribed, topics)
+				return nil
+			},
+		},
+	}
+
+	// nothing is sent on reconnection if the client never subscribed
+	ws.sendSubscriptions()
+	require.Empty(t, subscribed)
+
+	// without a connection the subscriptions are only stored
+	require.Nil(t, ws.Subscribe("topic1", "topic2"))
+	require.Nil(t, ws.Unsubscribe("topic1"))
+	require.Empty(t, subscribed)
+	require.Empty(t, unsubscribed)
+
+	isOpen.Store(true)
+	require.Nil(t, ws.Subscribe("topic3"))
+	require.Nil(t, ws.Unsubscribe("topic3"))
+	require.Equal(t, [][]string{{"topic3"}}, subscribed)
+	require.Equal(t, [][]string{{"topic3"}}, unsubscribed)
+
+	// on reconnection all the stored subscriptions are sent again
+	ws.sendSubscriptions()
+	require.Equal(t, [][]string{{"topic3"}, {"topic2"}}, subscribed)
+}
+
+func TestClient_ReconnectPolicyGivesUp(t *testing.T) {
+	mutAttempts := sync.Mutex{}
+	attempts := make([]int, 0)
+	args := createArgs()
+	args.ReconnectPolicy = &testscommon.ReconnectPolicyStub{
+		NextDelayCalled: func(attempt int) (time.Duration, bool) {
+			mutAttempts.Lock()
+			attempts = append(attempts, attempt)
+			mutAttempts.Unlock()
+
+			return 10 * time.Millisecond, attempt < 3
+		},
+	}
+	ws, err := NewWebSocketClient(args)
+	require.Nil(t, err)
+	defer func() {
+		_ = ws.Close()
+	}()
+
+	chFailed := make(chan struct{})
+	ws.OnStateChange(func(state data.ConnectionState) {
+		if state == data.ConnectionFailed {
+			close(chFailed)
+		}
+	})
+
+	select {
+	case <-chFailed:
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "timeout waiting for the client to give up")
+	}
+
+	mutAttempts.Lock()
+	require.Equal(t, []int{1, 2, 3}, attempts)
+	mutAttempts.Unlock()
+}
@@ -0,0 +1,72 @@
+package client
+
+import (
+	"sync"
+
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+)
+
+// checkEndpointSelection checks the strategies of the endpoint selector. The fan-out strategy never goes through the
+// selector, as the fan-out client connects every endpoint with its own single endpoint client
+func checkEndpointSelection(strategy string) error {
+	switch strategy {
+	case "", data.FailoverEndpointSelection, data.RoundRobinEndpointSelection:
+		return nil
+	case data.FanOutEndpointSelection:
+		return data.ErrFanOutNeedsFanOutClient
+	default:
+		return data.ErrUnknownEndpointSelection
+	}
+}
+
+// endpointSelector chooses the endpoint of the next connection attempt. The failover strategy starts every reconnection
+// from the first endpoint and moves to the next one after each failed attempt, while the round-robin strategy also
+// moves to the next endpoint when an established connection is lost
+type endpointSelector struct {
+	mut        sync.RWMutex
+	urls       []string
+	roundRobin bool
+	index      int
+	started    bool
+}
+
+func newEndpointSelector(urls []string, strategy string) *endpointSelector {
+	return &endpointSelector{
+		urls:       urls,
+		roundRobin: strategy == data.RoundRobinEndpointSelection,
+	}
+}
+
+// current returns the endpoint of the next connection attempt
+func (es *endpointSelector) current() string {
+	es.mut.RLock()
+	defer es.mut.RUnlock()
+
+	return es.urls[es.index]
+}
+
+// reconnecting should be called once the connection is lost, before the first attempt to open it again
+func (es *endpointSelector) reconnecting() {
+	es.mut.Lock()
+	defer es.mut.Unlock()
+
+	if !es.started {
+		es.started = true
+		return
+	}
+
+	if es.roundRobin {
+		es.index = (es.index + 1) % len(es.urls)
+		return
+	}
+
+	es.index = 0
+}
+
+// attemptFailed should be called after each failed connection attempt
+func (es *endpointSelector) attemptFailed() {
+	es.mut.Lock()
+	defer es.mut.Unlock()
+
+	es.index = (es.index + 1) % len(es.urls)
+}
@@ -0,0 +1,56 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+)
+
+func TestCheckEndpointSelection(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, checkEndpointSelection(""))
+	require.Nil(t, checkEndpointSelection(data.FailoverEndpointSelection))
+	require.Nil(t, checkEndpointSelection(data.RoundRobinEndpointSelection))
+	require.Equal(t, data.ErrFanOutNeedsFanOutClient, checkEndpointSelection(data.FanOutEndpointSelection))
+	require.Equal(t, data.ErrUnknownEndpointSelection, checkEndpointSelection("random"))
+}
+
+func TestEndpointSelector_Failover(t *testing.T) {
+	t.Parallel()
+
+	selector := newEndpointSelector([]string{"primary", "backup1", "backup2"}, data.FailoverEndpointSelection)
+	selector.reconnecting()
+	require.Equal(t, "primary", selector.current())
+
+	selector.attemptFailed()
+	require.Equal(t, "backup1", selector.current())
+	selector.attemptFailed()
+	require.Equal(t, "backup2", selector.current())
+	selector.attemptFailed()
+	require.Equal(t, "primary", selector.current())
+	selector.attemptFailed()
+	require.Equal(t, "backup1", selector.current())
+
+	// connected to the first backup, then the connection is lost: the primary is tried first
+	selector.reconnecting()
+	require.Equal(t, "primary", selector.current())
+}
+
+func TestEndpointSelector_RoundRobin(t *testing.T) {
+	t.Parallel()
+
+	selector := newEndpointSelector([]string{"url1", "url2", "url3"}, data.RoundRobinEndpointSelection)
+	selector.reconnecting()
+	require.Equal(t, "url1", selector.current())
+
+	selector.reconnecting()
+	require.Equal(t, "url2", selector.current())
+
+	selector.attemptFailed()
+	require.Equal(t, "url3", selector.current())
+
+	selector.reconnecting()
+	require.Equal(t, "url1", selector.current())
+}
@@ -0,0 +1,222 @@
+package client
+
+import (
+	"context"
+	"sync"
+
+	"github.com/subrahamanyam341/andes-communication/websocket"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	"github.com/subrahamanyam341/andes-communication/websocket/queue"
+	"github.com/subrahamanyam341/andes-communication/websocket/transceiver"
+	"github.com/subrahamanyam341/andes-core-16/core"
+	"github.com/subrahamanyam341/andes-core-16/core/check"
+)
+
+// fanOutClient is connected to all the endpoints at the same time, each of them through its own client, so each
+// connection keeps its own message counters and pending acknowledgements
+type fanOutClient struct {
+	clients     []*client
+	log         core.Logger
+	queueSender QueueSender
+}
+
+// NewFanOutClient will create a client that sends every payload to all the provided endpoints
+func NewFanOutClient(args ArgsWebSocketClient) (*fanOutClient, error) {
+	argsEndpoint := args
+	argsEndpoint.EndpointSelection = ""
+	err := checkArgs(argsEndpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	// every endpoint client is connected to a single endpoint, the outbound queue being shared at this level
+	argsEndpoint.URLs = nil
+	argsEndpoint.OutboundQueue = nil
+
+	fc := &fanOutClient{
+		log: args.Log,
+	}
+	for _, endpoint := range getEndpointHosts(args) {
+		argsEndpoint.URL = endpoint
+		endpointClient, errCreate := NewWebSocketClient(argsEndpoint)
+		if errCreate != nil {
+			_ = fc.Close()
+			return nil, errCreate
+		}
+
+		fc.clients = append(fc.clients, endpointClient)
+	}
+
+	if !check.IfNil(args.OutboundQueue) {
+		fc.queueSender, err = queue.NewQueueSender(queue.ArgsQueueSender{
+			Queue:              args.OutboundQueue,
+			SendHandler:        fc.sendQueued,
+			Log:                args.Log,
+			RetryDurationInSec: args.RetryDurationInSeconds,
+		})
+		if err != nil {
+			_ = fc.Close()
+			return nil, err
+		}
+	}
+
+	return fc, nil
+}
+
+// Send will send the provided payload to all the endpoints. It succeeds if at least one of the endpoints received
+// (and acknowledged, if enabled) the payload. If an outbound queue is set, the payload is persisted and sent asynchronously
+func (fc *fanOutClient) Send(payload []byte, topic string) error {
+	if fc.queueSender != nil {
+		return fc.queueSender.Send(payload, topic)
+	}
+
+	return fc.sendToAllEndpoints(&data.OutgoingMessage{Payload: payload, Topic: topic}).Wait()
+}
+
+// sendQueued sends a message of the outbound queue, which is retried with the same ID until it is sent
+func (fc *fanOutClient) sendQueued(message *data.QueuedMessage) error {
+	return fc.sendToAllEndpoints(message.ToOutgoingMessage()).Wait()
+}
+
+// SendAsync will send the provided payload to all the endpoints without waiting for the acknowledgements. The returned
+// future succeeds if at least one of the endpoints acknowledged the payload
+func (fc *fanOutClient) SendAsync(payload []byte, topic string) (websocket.AckFuture, error) {
+	if fc.queueSender != nil {
+		return transceiver.NewCompletedAckFuture(nil), fc.queueSender.Send(payload, topic)
+	}
+
+	return fc.sendToAllEndpoints(&data.OutgoingMessage{Payload: payload, Topic: topic}), nil
+}
+
+func (fc *fanOutClient) sendToAllEndpoints(message *data.OutgoingMessage) websocket.AckFuture {
+	futures := make([]websocket.AckFuture, 0, len(fc.clients))
+	for _, endpointClient := range fc.clients {
+		future, err := endpointClient.sendMessageAsync(message)
+		if err != nil {
+			fc.log.Debug("fc.sendToAllEndpoints() cannot send message", "url", endpointClient.endpoints.current(), "error", err.Error())
+			future = transceiver.NewCompletedAckFuture(err)
+		}
+
+		futures = append(futures, future)
+	}
+
+	return transceiver.NewAckFutureGroup(futures)
+}
+
+// Subscribe will ask all the endpoints to send only the payloads of the subscribed topics
+func (fc *fanOutClient) Subscribe(topics ...string) error {
+	var lastErr error
+	for _, endpointClient := range fc.clients {
+		err := endpointClient.Subscribe(topics...)
+		if err != nil {
+			lastErr = err
+		}
+	}
+
+	return lastErr
+}
+
+// Unsubscribe will ask all the endpoints to stop sending the payloads of the provided topics
+func (fc *fanOutClient) Unsubscribe(topics ...string) error {
+	var lastErr error
+	for _, endpointClient := range fc.clients {
+		err := endpointClient.Unsubscribe(topics...)
+		if err != nil {
+			lastErr = err
+		}
+	}
+
+	return lastErr
+}
+
+// Request will send the provided payload to the first endpoint with an open connection and will wait for its response
+// until the context is done
+func (fc *fanOutClient) Request(ctx context.Context, topic string, payload []byte) ([]byte, error) {
+	for _, endpointClient := range fc.clients {
+		if endpointClient.wsConn.IsOpen() {
+			return endpointClient.Request(ctx, topic, payload)
+		}
+	}
+
+	return nil, data.ErrConnectionNotOpen
+}
+
+// SetPayloadHandler sets the payload handler on all the endpoints. The handler is closed only once
+func (fc *fanOutClient) SetPayloadHandler(handler websocket.PayloadHandler) error {
+	if check.IfNil(handler) {
+		return data.ErrNilPayloadProcessor
+	}
+
+	sharedHandler := &closeOncePayloadHandler{PayloadHandler: handler}
+	for _, endpointClient := range fc.clients {
+		err := endpointClient.SetPayloadHandler(sharedHandler)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SetRequestHandler sets the request handler on all the endpoints
+func (fc *fanOutClient) SetRequestHandler(handler websocket.RequestHandler) error {
+	for _, endpointClient := range fc.clients {
+		err := endpointClient.SetRequestHandler(handler)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// OnEndpointStateChange will set the handler called on every connection state transition of each endpoint
+func (fc *fanOutClient) OnEndpointStateChange(handler func(url string, state data.ConnectionState)) {
+	for _, endpointClient := range fc.clients {
+		url := endpointClient.endpoints.current()
+		endpointClient.OnStateChange(func(state data.ConnectionState) {
+			handler(url, state)
+		})
+	}
+}
+
+// Close will close the connections to all the endpoints
+func (fc *fanOutClient) Close() error {
+	var lastErr error
+	if fc.queueSender != nil {
+		err := fc.queueSender.Close()
+		if err != nil {
+			fc.log.Warn("fanOutClient.Close() queue sender", "error", err)
+			lastErr = err
+		}
+	}
+
+	for _, endpointClient := range fc.clients {
+		err := endpointClient.Close()
+		if err != nil {
+			lastErr = err
+		}
+	}
+
+	return lastErr
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (fc *fanOutClient) IsInterfaceNil() bool {
+	return fc == nil
+}
+
+type closeOncePayloadHandler struct {
+	websocket.PayloadHandler
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// Close will close the wrapped payload handler, only on the first call
+func (handler *closeOncePayloadHandler) Close() error {
+	handler.closeOnce.Do(func() {
+		handler.closeErr = handler.PayloadHandler.Close()
+	})
+
+	return handler.closeErr
+}
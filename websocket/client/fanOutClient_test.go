@@ -0,0 +1,74 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/testscommon"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+)
+
+func TestNewFanOutClient(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty url, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.URL = ""
+		fc, err := NewFanOutClient(args)
+		require.Nil(t, fc)
+		require.Equal(t, data.ErrEmptyUrl, err)
+	})
+
+	t.Run("should work", func(t *testing.T) {
+		args := createArgs()
+		args.URLs = []string{"url2"}
+		args.EndpointSelection = data.FanOutEndpointSelection
+		fc, err := NewFanOutClient(args)
+		require.Nil(t, err)
+		require.Len(t, fc.clients, 2)
+		require.Equal(t, "ws://url/save", fc.clients[0].endpoints.current())
+		require.Equal(t, "ws://url2/save", fc.clients[1].endpoints.current())
+
+		_ = fc.Close()
+	})
+}
+
+func TestFanOutClient_SetPayloadHandlerShouldCloseTheHandlerOnce(t *testing.T) {
+	t.Parallel()
+
+	args := createArgs()
+	args.URLs = []string{"url2", "url3"}
+	fc, err := NewFanOutClient(args)
+	require.Nil(t, err)
+
+	err = fc.SetPayloadHandler(nil)
+	require.Equal(t, data.ErrNilPayloadProcessor, err)
+
+	numCloseCalls := 0
+	err = fc.SetPayloadHandler(&testscommon.PayloadHandlerStub{
+		CloseCalled: func() error {
+			numCloseCalls++
+			return nil
+		},
+	})
+	require.Nil(t, err)
+
+	_ = fc.Close()
+	require.Equal(t, 1, numCloseCalls)
+}
+
+func TestFanOutClient_RequestWithoutOpenConnectionShouldError(t *testing.T) {
+	t.Parallel()
+
+	args := createArgs()
+	fc, err := NewFanOutClient(args)
+	require.Nil(t, err)
+	defer func() {
+		_ = fc.Close()
+	}()
+
+	response, err := fc.Request(context.Background(), "topic", []byte("payload"))
+	require.Nil(t, response)
+	require.Equal(t, data.ErrConnectionNotOpen, err)
+}
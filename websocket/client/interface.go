@@ -1,13 +1,46 @@
 package client
 
 import (
+	"context"
+	"net/http"
+
 	"github.com/subrahamanyam341/andes-communication/websocket"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
 )
 
 // Transceiver defines what a WebSocket transceiver should be able to do
 type Transceiver interface {
 	Send(payload []byte, topic string, connection websocket.WSConClient) error
+	SendAsync(payload []byte, topic string, connection websocket.WSConClient) (websocket.AckFuture, error)
+	SendMessage(message *data.OutgoingMessage, connection websocket.WSConClient) error
+	SendMessageAsync(message *data.OutgoingMessage, connection websocket.WSConClient) (websocket.AckFuture, error)
+	Subscribe(topics []string, connection websocket.WSConClient) error
+	Unsubscribe(topics []string, connection websocket.WSConClient) error
+	Request(ctx context.Context, payload []byte, topic string, connection websocket.WSConClient) ([]byte, error)
 	SetPayloadHandler(handler websocket.PayloadHandler) error
+	SetRequestHandler(handler websocket.RequestHandler) error
 	Listen(connection websocket.WSConClient) (closed bool)
+	SetPayloadVersion(version uint32)
+	Close() error
+}
+
+// CompressingPayloadConverter defines what a payload converter that compresses the payloads should be able to do
+type CompressingPayloadConverter interface {
+	websocket.PayloadConverter
+	SetCompressionEnabled(enabled bool)
+}
+
+type handshakeResponseHeaderProvider interface {
+	GetHandshakeResponseHeader() http.Header
+}
+
+type unresponsivenessProvider interface {
+	IsUnresponsive() bool
+}
+
+// QueueSender defines what a component that sends the messages through a persistent queue should be able to do
+type QueueSender interface {
+	Send(payload []byte, topic string) error
 	Close() error
+	IsInterfaceNil() bool
 }
@@ -0,0 +1,39 @@
+package codec
+
+import (
+	webSocket "github.com/subrahamanyam341/andes-communication/websocket"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	"github.com/subrahamanyam341/andes-core-16/core/check"
+	"github.com/subrahamanyam341/andes-core-16/marshal"
+)
+
+// CheckCodec returns an error if the provided codec is not known. An empty codec means protobuf
+func CheckCodec(codec string) error {
+	switch codec {
+	case "", data.ProtobufCodec, data.JSONCodec:
+		return nil
+	default:
+		return data.ErrUnknownCodec
+	}
+}
+
+// NewPayloadConverter returns the payload converter of the provided codec. The protobuf codec uses the provided
+// converter, while the JSON codec marshals the messages with the standard JSON encoding, the payloads being base64 strings
+func NewPayloadConverter(codec string, protobufConverter webSocket.PayloadConverter) (webSocket.PayloadConverter, error) {
+	switch codec {
+	case "", data.ProtobufCodec:
+		if check.IfNil(protobufConverter) {
+			return nil, data.ErrNilPayloadConverter
+		}
+		return protobufConverter, nil
+	case data.JSONCodec:
+		return webSocket.NewWebSocketPayloadConverter(&marshal.JsonMarshalizer{})
+	default:
+		return nil, data.ErrUnknownCodec
+	}
+}
+
+// UsesTextFrames returns true if the messages of the provided codec should be written as text frames
+func UsesTextFrames(codec string) bool {
+	return codec == data.JSONCodec
+}
@@ -0,0 +1,72 @@
+package codec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/testscommon"
+	webSocket "github.com/subrahamanyam341/andes-communication/websocket"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+)
+
+func TestCheckCodec(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, CheckCodec(""))
+	require.Nil(t, CheckCodec(data.ProtobufCodec))
+	require.Nil(t, CheckCodec(data.JSONCodec))
+	require.Equal(t, data.ErrUnknownCodec, CheckCodec("xml"))
+}
+
+func TestNewPayloadConverter(t *testing.T) {
+	t.Parallel()
+
+	protobufConverter, _ := webSocket.NewWebSocketPayloadConverter(&testscommon.MarshallerMock{})
+
+	t.Run("unknown codec, should return error", func(t *testing.T) {
+		converter, err := NewPayloadConverter("xml", protobufConverter)
+		require.Nil(t, converter)
+		require.Equal(t, data.ErrUnknownCodec, err)
+	})
+
+	t.Run("protobuf with nil converter, should return error", func(t *testing.T) {
+		converter, err := NewPayloadConverter(data.ProtobufCodec, nil)
+		require.Nil(t, converter)
+		require.Equal(t, data.ErrNilPayloadConverter, err)
+	})
+
+	t.Run("protobuf should return the provided converter", func(t *testing.T) {
+		converter, err := NewPayloadConverter("", protobufConverter)
+		require.Nil(t, err)
+		require.True(t, converter == protobufConverter)
+	})
+
+	t.Run("json should marshal readable text", func(t *testing.T) {
+		converter, err := NewPayloadConverter(data.JSONCodec, protobufConverter)
+		require.Nil(t, err)
+
+		wsMessage := &data.WsMessage{
+			WithAcknowledge: true,
+			Counter:         7,
+			Type:            data.PayloadMessage,
+			Payload:         []byte("payload"),
+			Topic:           "topic",
+			Version:         1,
+		}
+		payload, err := converter.ConstructPayload(wsMessage)
+		require.Nil(t, err)
+		require.Equal(t, `{"withAcknowledge":true,"counter":7,"type":2,"payload":"cGF5bG9hZA==","topic":"topic","version":1}`, string(payload))
+
+		extracted, err := converter.ExtractWsMessage(payload)
+		require.Nil(t, err)
+		require.Equal(t, wsMessage, extracted)
+	})
+}
+
+func TestUsesTextFrames(t *testing.T) {
+	t.Parallel()
+
+	require.False(t, UsesTextFrames(""))
+	require.False(t, UsesTextFrames(data.ProtobufCodec))
+	require.True(t, UsesTextFrames(data.JSONCodec))
+}
@@ -0,0 +1,38 @@
+package codec
+
+import (
+	"net/http"
+
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+)
+
+const subprotocolHeader = "Sec-Websocket-Protocol"
+
+// SupportedSubprotocols returns the subprotocols accepted by a server, in the order of preference
+func SupportedSubprotocols() []string {
+	return []string{data.ProtobufSubprotocol, data.JSONSubprotocol}
+}
+
+// CodecToSubprotocol returns the subprotocol a client requests for the provided codec
+func CodecToSubprotocol(codec string) string {
+	if codec == data.JSONCodec {
+		return data.JSONSubprotocol
+	}
+
+	return data.ProtobufSubprotocol
+}
+
+// SubprotocolToCodec returns the codec of the negotiated subprotocol. Peers that do not know about the codecs do not
+// negotiate any subprotocol, so they use protobuf
+func SubprotocolToCodec(subprotocol string) string {
+	if subprotocol == data.JSONSubprotocol {
+		return data.JSONCodec
+	}
+
+	return data.ProtobufCodec
+}
+
+// GetNegotiatedCodec returns the codec selected by the server in the handshake response header
+func GetNegotiatedCodec(header http.Header) string {
+	return SubprotocolToCodec(header.Get(subprotocolHeader))
+}
@@ -0,0 +1,35 @@
+package codec
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+)
+
+func TestCodecAndSubprotocol(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, data.ProtobufSubprotocol, CodecToSubprotocol(""))
+	require.Equal(t, data.ProtobufSubprotocol, CodecToSubprotocol(data.ProtobufCodec))
+	require.Equal(t, data.JSONSubprotocol, CodecToSubprotocol(data.JSONCodec))
+
+	require.Equal(t, data.ProtobufCodec, SubprotocolToCodec(""))
+	require.Equal(t, data.ProtobufCodec, SubprotocolToCodec(data.ProtobufSubprotocol))
+	require.Equal(t, data.JSONCodec, SubprotocolToCodec(data.JSONSubprotocol))
+}
+
+func TestGetNegotiatedCodec(t *testing.T) {
+	t.Parallel()
+
+	t.Run("peer without codecs support", func(t *testing.T) {
+		require.Equal(t, data.ProtobufCodec, GetNegotiatedCodec(http.Header{}))
+	})
+
+	t.Run("json negotiated", func(t *testing.T) {
+		header := http.Header{}
+		header.Set("Sec-WebSocket-Protocol", data.JSONSubprotocol)
+		require.Equal(t, data.JSONCodec, GetNegotiatedCodec(header))
+	})
+}
@@ -0,0 +1,89 @@
+package compression
+
+import (
+	"sync"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+)
+
+// maxDecompressedSizeInBytes protects the receiver against payloads that expand to an unreasonable size
+const maxDecompressedSizeInBytes = 512 * 1024 * 1024
+
+var (
+	onceZstd    sync.Once
+	zstdEncoder *zstd.Encoder
+	zstdDecoder *zstd.Decoder
+	errZstd     error
+)
+
+// the zstd encoder and decoder are safe for concurrent use and expensive to create, so they are shared
+func getZstdCodec() (*zstd.Encoder, *zstd.Decoder, error) {
+	onceZstd.Do(func() {
+		zstdEncoder, errZstd = zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedFastest))
+		if errZstd != nil {
+			return
+		}
+		zstdDecoder, errZstd = zstd.NewReader(nil, zstd.WithDecoderMaxMemory(maxDecompressedSizeInBytes))
+	})
+
+	return zstdEncoder, zstdDecoder, errZstd
+}
+
+func compress(compressionFlag int32, payload []byte) ([]byte, error) {
+	switch compressionFlag {
+	case data.SnappyCompressionFlag:
+		return snappy.Encode(nil, payload), nil
+	case data.ZstdCompressionFlag:
+		encoder, _, err := getZstdCodec()
+		if err != nil {
+			return nil, err
+		}
+		return encoder.EncodeAll(payload, nil), nil
+	default:
+		return nil, data.ErrUnknownCompressionAlgorithm
+	}
+}
+
+func decompress(compressionFlag int32, payload []byte) ([]byte, error) {
+	switch compressionFlag {
+	case data.SnappyCompressionFlag:
+		decodedLen, err := snappy.DecodedLen(payload)
+		if err != nil {
+			return nil, err
+		}
+		if decodedLen > maxDecompressedSizeInBytes {
+			return nil, snappy.ErrTooLarge
+		}
+		return snappy.Decode(nil, payload)
+	case data.ZstdCompressionFlag:
+		_, decoder, err := getZstdCodec()
+		if err != nil {
+			return nil, err
+		}
+		return decoder.DecodeAll(payload, nil)
+	default:
+		return nil, data.ErrUnknownCompressionAlgorithm
+	}
+}
+
+func algorithmToFlag(algorithm string) (int32, error) {
+	switch algorithm {
+	case "":
+		return data.NoCompressionFlag, nil
+	case data.SnappyCompression:
+		return data.SnappyCompressionFlag, nil
+	case data.ZstdCompression:
+		return data.ZstdCompressionFlag, nil
+	default:
+		return data.NoCompressionFlag, data.ErrUnknownCompressionAlgorithm
+	}
+}
+
+// CheckAlgorithm returns an error if the provided payload compression algorithm is unknown. An empty algorithm
+// disables the payload compression
+func CheckAlgorithm(algorithm string) error {
+	_, err := algorithmToFlag(algorithm)
+	return err
+}
@@ -0,0 +1,36 @@
+package compression
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+)
+
+var supportedAlgorithms = []string{data.ZstdCompression, data.SnappyCompression}
+
+// CreateHandshakeHeader returns the header advertising the payload compression algorithms this host can decompress
+func CreateHandshakeHeader() http.Header {
+	header := http.Header{}
+	header.Set(data.PayloadCompressionHeader, strings.Join(supportedAlgorithms, ", "))
+
+	return header
+}
+
+// IsAlgorithmSupportedByPeer returns true if the peer advertised the provided algorithm in its handshake header. Peers
+// that do not know about the payload compression do not send the header, so they never receive compressed payloads
+func IsAlgorithmSupportedByPeer(header http.Header, algorithm string) bool {
+	if len(algorithm) == 0 {
+		return false
+	}
+
+	for _, value := range header.Values(data.PayloadCompressionHeader) {
+		for _, peerAlgorithm := range strings.Split(value, ",") {
+			if strings.TrimSpace(peerAlgorithm) == algorithm {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,36 @@
+package compression
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+)
+
+func TestIsAlgorithmSupportedByPeer(t *testing.T) {
+	t.Parallel()
+
+	header := CreateHandshakeHeader()
+	require.True(t, IsAlgorithmSupportedByPeer(header, data.SnappyCompression))
+	require.True(t, IsAlgorithmSupportedByPeer(header, data.ZstdCompression))
+	require.False(t, IsAlgorithmSupportedByPeer(header, ""))
+	require.False(t, IsAlgorithmSupportedByPeer(header, "gzip"))
+
+	// peers that do not know about the payload compression
+	require.False(t, IsAlgorithmSupportedByPeer(http.Header{}, data.SnappyCompression))
+
+	header = http.Header{}
+	header.Set(data.PayloadCompressionHeader, "snappy")
+	require.True(t, IsAlgorithmSupportedByPeer(header, data.SnappyCompression))
+	require.False(t, IsAlgorithmSupportedByPeer(header, data.ZstdCompression))
+}
+
+func TestCheckAlgorithm(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, CheckAlgorithm(""))
+	require.Nil(t, CheckAlgorithm(data.SnappyCompression))
+	require.Nil(t, CheckAlgorithm(data.ZstdCompression))
+	require.Equal(t, data.ErrUnknownCompressionAlgorithm, CheckAlgorithm("gzip"))
+}
@@ -0,0 +1,97 @@
+package compression
+
+import (
+	"sync/atomic"
+
+	webSocket "github.com/subrahamanyam341/andes-communication/websocket"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	"github.com/subrahamanyam341/andes-core-16/core/check"
+)
+
+// ArgsCompressingPayloadConverter holds the arguments needed for creating a compressing payload converter
+type ArgsCompressingPayloadConverter struct {
+	PayloadConverter webSocket.PayloadConverter
+	Algorithm        string
+	ThresholdInBytes int
+}
+
+// compressingPayloadConverter compresses the payloads of the outgoing messages, once the peer confirmed it can
+// decompress them, and decompresses the payloads of the incoming messages
+type compressingPayloadConverter struct {
+	payloadConverter webSocket.PayloadConverter
+	compressionFlag  int32
+	thresholdInBytes int
+	enabled          atomic.Bool
+}
+
+// NewCompressingPayloadConverter will create a new payload converter that wraps the provided one. The compression
+// starts disabled, as it has to be negotiated with the peer
+func NewCompressingPayloadConverter(args ArgsCompressingPayloadConverter) (*compressingPayloadConverter, error) {
+	if check.IfNil(args.PayloadConverter) {
+		return nil, data.ErrNilPayloadConverter
+	}
+	if args.ThresholdInBytes < 0 {
+		return nil, data.ErrNegativeCompressionThreshold
+	}
+	compressionFlag, err := algorithmToFlag(args.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	return &compressingPayloadConverter{
+		payloadConverter: args.PayloadConverter,
+		compressionFlag:  compressionFlag,
+		thresholdInBytes: args.ThresholdInBytes,
+	}, nil
+}
+
+// SetCompressionEnabled will enable or disable the compression of the outgoing payloads
+func (cpc *compressingPayloadConverter) SetCompressionEnabled(enabled bool) {
+	cpc.enabled.Store(enabled && cpc.compressionFlag != data.NoCompressionFlag)
+}
+
+// ExtractWsMessage will extract the provided payload in a *data.WsMessage, decompressing its payload if needed
+func (cpc *compressingPayloadConverter) ExtractWsMessage(payload []byte) (*data.WsMessage, error) {
+	wsMessage, err := cpc.payloadConverter.ExtractWsMessage(payload)
+	if err != nil {
+		return nil, err
+	}
+	if wsMessage.Compression == data.NoCompressionFlag {
+		return wsMessage, nil
+	}
+
+	wsMessage.Payload, err = decompress(wsMessage.Compression, wsMessage.Payload)
+	if err != nil {
+		return nil, err
+	}
+	wsMessage.Compression = data.NoCompressionFlag
+
+	return wsMessage, nil
+}
+
+// ConstructPayload will marshal the provided *data.WsMessage, compressing its payload if it reached the threshold
+func (cpc *compressingPayloadConverter) ConstructPayload(wsMessage *data.WsMessage) ([]byte, error) {
+	if !cpc.enabled.Load() || len(wsMessage.Payload) == 0 || len(wsMessage.Payload) < cpc.thresholdInBytes {
+		return cpc.payloadConverter.ConstructPayload(wsMessage)
+	}
+
+	compressedPayload, err := compress(cpc.compressionFlag, wsMessage.Payload)
+	if err != nil {
+		return nil, err
+	}
+	if len(compressedPayload) >= len(wsMessage.Payload) {
+		// incompressible payload, the receiver would only lose time decompressing it
+		return cpc.payloadConverter.ConstructPayload(wsMessage)
+	}
+
+	compressedMessage := *wsMessage
+	compressedMessage.Payload = compressedPayload
+	compressedMessage.Compression = cpc.compressionFlag
+
+	return cpc.payloadConverter.ConstructPayload(&compressedMessage)
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (cpc *compressingPayloadConverter) IsInterfaceNil() bool {
+	return cpc == nil
+}
@@ -0,0 +1,196 @@
+package compression
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	webSocket "github.com/subrahamanyam341/andes-communication/websocket"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	"github.com/subrahamanyam341/andes-core-16/data/outport"
+	"github.com/subrahamanyam341/andes-core-16/marshal/factory"
+)
+
+func createPayloadConverter(tb testing.TB) webSocket.PayloadConverter {
+	marshaller, err := factory.NewMarshalizer("gogo protobuf")
+	require.Nil(tb, err)
+	payloadConverter, err := webSocket.NewWebSocketPayloadConverter(marshaller)
+	require.Nil(tb, err)
+
+	return payloadConverter
+}
+
+func createArgs(tb testing.TB, algorithm string) ArgsCompressingPayloadConverter {
+	return ArgsCompressingPayloadConverter{
+		PayloadConverter: createPayloadConverter(tb),
+		Algorithm:        algorithm,
+		ThresholdInBytes: 100,
+	}
+}
+
+func createMessage(payload []byte) *data.WsMessage {
+	return &data.WsMessage{
+		WithAcknowledge: true,
+		Counter:         10,
+		Type:            data.PayloadMessage,
+		Payload:         payload,
+		Topic:           outport.TopicSaveBlock,
+		Version:         1,
+	}
+}
+
+func TestNewCompressingPayloadConverter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil payload converter, should return error", func(t *testing.T) {
+		args := createArgs(t, data.SnappyCompression)
+		args.PayloadConverter = nil
+		converter, err := NewCompressingPayloadConverter(args)
+		require.Nil(t, converter)
+		require.Equal(t, data.ErrNilPayloadConverter, err)
+	})
+	t.Run("negative threshold, should return error", func(t *testing.T) {
+		args := createArgs(t, data.SnappyCompression)
+		args.ThresholdInBytes = -1
+		converter, err := NewCompressingPayloadConverter(args)
+		require.Nil(t, converter)
+		require.Equal(t, data.ErrNegativeCompressionThreshold, err)
+	})
+	t.Run("unknown algorithm, should return error", func(t *testing.T) {
+		converter, err := NewCompressingPayloadConverter(createArgs(t, "gzip"))
+		require.Nil(t, converter)
+		require.Equal(t, data.ErrUnknownCompressionAlgorithm, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		converter, err := NewCompressingPayloadConverter(createArgs(t, data.ZstdCompression))
+		require.Nil(t, err)
+		require.False(t, converter.IsInterfaceNil())
+	})
+}
+
+func TestCompressingPayloadConverter_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	compressiblePayload := bytes.Repeat([]byte("compressible payload "), 100)
+
+	testRoundTrip := func(t *testing.T, algorithm string, expectedFlag int32) {
+		converter, _ := NewCompressingPayloadConverter(createArgs(t, algorithm))
+		converter.SetCompressionEnabled(true)
+
+		message := createMessage(compressiblePayload)
+		payload, err := converter.ConstructPayload(message)
+		require.Nil(t, err)
+		require.Less(t, len(payload), len(compressiblePayload))
+		// the provided message should not be altered
+		require.Equal(t, compressiblePayload, message.Payload)
+
+		sentMessage, err := converter.payloadConverter.ExtractWsMessage(payload)
+		require.Nil(t, err)
+		require.Equal(t, expectedFlag, sentMessage.Compression)
+
+		receivedMessage, err := converter.ExtractWsMessage(payload)
+		require.Nil(t, err)
+		require.Equal(t, message, receivedMessage)
+	}
+
+	t.Run("snappy", func(t *testing.T) {
+		testRoundTrip(t, data.SnappyCompression, data.SnappyCompressionFlag)
+	})
+	t.Run("zstd", func(t *testing.T) {
+		testRoundTrip(t, data.ZstdCompression, data.ZstdCompressionFlag)
+	})
+}
+
+func TestCompressingPayloadConverter_ConstructPayloadShouldNotCompress(t *testing.T) {
+	t.Parallel()
+
+	checkNotCompressed := func(t *testing.T, converter *compressingPayloadConverter, payload []byte) {
+		message := createMessage(payload)
+		constructed, err := converter.ConstructPayload(message)
+		require.Nil(t, err)
+
+		sentMessage, err := converter.payloadConverter.ExtractWsMessage(constructed)
+		require.Nil(t, err)
+		require.Equal(t, int32(data.NoCompressionFlag), sentMessage.Compression)
+		require.Equal(t, payload, sentMessage.Payload)
+	}
+
+	t.Run("compression not negotiated", func(t *testing.T) {
+		converter, _ := NewCompressingPayloadConverter(createArgs(t, data.SnappyCompression))
+		checkNotCompressed(t, converter, bytes.Repeat([]byte("a"), 1000))
+	})
+	t.Run("compression disabled after being enabled", func(t *testing.T) {
+		converter, _ := NewCompressingPayloadConverter(createArgs(t, data.SnappyCompression))
+		converter.SetCompressionEnabled(true)
+		converter.SetCompressionEnabled(false)
+		checkNotCompressed(t, converter, bytes.Repeat([]byte("a"), 1000))
+	})
+	t.Run("no algorithm configured", func(t *testing.T) {
+		converter, _ := NewCompressingPayloadConverter(createArgs(t, ""))
+		converter.SetCompressionEnabled(true)
+		checkNotCompressed(t, converter, bytes.Repeat([]byte("a"), 1000))
+	})
+	t.Run("payload below threshold", func(t *testing.T) {
+		converter, _ := NewCompressingPayloadConverter(createArgs(t, data.SnappyCompression))
+		converter.SetCompressionEnabled(true)
+		checkNotCompressed(t, converter, bytes.Repeat([]byte("a"), 99))
+	})
+	t.Run("incompressible payload", func(t *testing.T) {
+		converter, _ := NewCompressingPayloadConverter(createArgs(t, data.ZstdCompression))
+		converter.SetCompressionEnabled(true)
+		payload := make([]byte, 1000)
+		_, _ = rand.Read(payload)
+		checkNotCompressed(t, converter, payload)
+	})
+}
+
+func TestCompressingPayloadConverter_ExtractWsMessageInvalidCompressedPayload(t *testing.T) {
+	t.Parallel()
+
+	converter, _ := NewCompressingPayloadConverter(createArgs(t, data.SnappyCompression))
+	message := createMessage([]byte("not a snappy payload"))
+	message.Compression = data.SnappyCompressionFlag
+	payload, _ := converter.payloadConverter.ConstructPayload(message)
+
+	receivedMessage, err := converter.ExtractWsMessage(payload)
+	require.Nil(t, receivedMessage)
+	require.NotNil(t, err)
+
+	message.Compression = 99
+	payload, _ = converter.payloadConverter.ConstructPayload(message)
+	receivedMessage, err = converter.ExtractWsMessage(payload)
+	require.Nil(t, receivedMessage)
+	require.Equal(t, data.ErrUnknownCompressionAlgorithm, err)
+}
+
+func benchmarkConstructPayload(b *testing.B, algorithm string) {
+	converter, _ := NewCompressingPayloadConverter(ArgsCompressingPayloadConverter{
+		PayloadConverter: createPayloadConverter(b),
+		Algorithm:        algorithm,
+	})
+	converter.SetCompressionEnabled(true)
+	message := createMessage(bytes.Repeat([]byte(`{"hash":"0a1b2c3d","nonce":1234,"round":5678},`), 20000))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		payload, err := converter.ConstructPayload(message)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.ReportMetric(float64(len(payload)), "bytes/message")
+	}
+}
+
+func BenchmarkConstructPayload_NoCompression(b *testing.B) {
+	benchmarkConstructPayload(b, "")
+}
+
+func BenchmarkConstructPayload_Snappy(b *testing.B) {
+	benchmarkConstructPayload(b, data.SnappyCompression)
+}
+
+func BenchmarkConstructPayload_Zstd(b *testing.B) {
+	benchmarkConstructPayload(b, data.ZstdCompression)
+}
@@ -1,39 +1,116 @@
 package connection
 
 import (
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"net"
+	"net/http"
+	"net/url"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/gorilla/websocket"
+	webSocket "github.com/subrahamanyam341/andes-communication/websocket"
 	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	"github.com/subrahamanyam341/andes-core-16/core/check"
 	logger "github.com/subrahamanyam341/andes-logger-123"
 )
 
 var log = logger.GetOrCreate("connection")
 
+// ArgsWSConnClient holds the arguments needed for creating a websocket connection that will be opened by the client
+type ArgsWSConnClient struct {
+	TLSConfig           *tls.Config
+	CredentialsProvider webSocket.CredentialsProvider
+	HandshakeHeader     http.Header
+	EnableCompression   bool
+	PingInterval        time.Duration
+	PongTimeout         time.Duration
+	ReadBufferSize      int
+	WriteBufferSize     int
+	HandshakeTimeout    time.Duration
+	MaxMessageSize      int64
+	WriteTimeout        time.Duration
+	Subprotocols        []string
+}
+
 type wsConnClient struct {
-	mut      sync.RWMutex
-	conn     *websocket.Conn
-	clientID string
+	mut                 sync.RWMutex
+	conn                *websocket.Conn
+	dialer              *websocket.Dialer
+	credentialsProvider webSocket.CredentialsProvider
+	handshakeHeader     http.Header
+	responseHeader      http.Header
+	clientID            string
+	pingInterval        time.Duration
+	pongTimeout         time.Duration
+	chStopPings         chan struct{}
+	unresponsive        atomic.Bool
+	maxMessageSize      int64
+	writeTimeout        time.Duration
 }
 
 // NewWSConnClient creates a new wrapper over a websocket connection
 func NewWSConnClient() *wsConnClient {
-	return &wsConnClient{}
+	return NewWSConnClientWithArgs(ArgsWSConnClient{})
+}
+
+// NewWSConnClientWithArgs creates a new wrapper over a websocket connection that will be dialed using the provided arguments
+func NewWSConnClientWithArgs(args ArgsWSConnClient) *wsConnClient {
+	return &wsConnClient{
+		dialer:              createDialer(args),
+		credentialsProvider: args.CredentialsProvider,
+		handshakeHeader:     args.HandshakeHeader,
+		pingInterval:        args.PingInterval,
+		pongTimeout:         args.PongTimeout,
+		maxMessageSize:      args.MaxMessageSize,
+		writeTimeout:        args.WriteTimeout,
+	}
 }
 
 // NewWSConnClientWithConn creates a new wrapper over a provided websocket connection
 func NewWSConnClientWithConn(conn *websocket.Conn) *wsConnClient {
+	return NewWSConnClientWithConnAndArgs(conn, ArgsWSConnClient{})
+}
+
+// NewWSConnClientWithConnAndArgs creates a new wrapper over a provided websocket connection, keeping it alive as
+// configured in the provided arguments
+func NewWSConnClientWithConnAndArgs(conn *websocket.Conn, args ArgsWSConnClient) *wsConnClient {
 	wsc := &wsConnClient{
-		conn: conn,
+		conn:           conn,
+		dialer:         createDialer(args),
+		pingInterval:   args.PingInterval,
+		pongTimeout:    args.PongTimeout,
+		maxMessageSize: args.MaxMessageSize,
+		writeTimeout:   args.WriteTimeout,
 	}
 	wsc.clientID = fmt.Sprintf("%p", wsc)
+	wsc.configureConn(conn)
 
 	return wsc
 }
 
+func createDialer(args ArgsWSConnClient) *websocket.Dialer {
+	handshakeTimeout := args.HandshakeTimeout
+	if handshakeTimeout == 0 {
+		handshakeTimeout = websocket.DefaultDialer.HandshakeTimeout
+	}
+
+	return &websocket.Dialer{
+		Proxy:             http.ProxyFromEnvironment,
+		HandshakeTimeout:  handshakeTimeout,
+		ReadBufferSize:    args.ReadBufferSize,
+		WriteBufferSize:   args.WriteBufferSize,
+		TLSClientConfig:   args.TLSConfig,
+		EnableCompression: args.EnableCompression,
+		Subprotocols:      args.Subprotocols,
+	}
+}
+
 // OpenConnection will open a new client with a background context
-func (wsc *wsConnClient) OpenConnection(url string) error {
+func (wsc *wsConnClient) OpenConnection(wsURL string) error {
 	wsc.mut.Lock()
 	defer wsc.mut.Unlock()
 
@@ -41,15 +118,97 @@ func (wsc *wsConnClient) OpenConnection(url string) error {
 		return data.ErrConnectionAlreadyOpen
 	}
 
-	var err error
-	wsc.conn, _, err = websocket.DefaultDialer.Dial(url, nil)
+	header := wsc.handshakeHeader.Clone()
+	if !check.IfNil(wsc.credentialsProvider) {
+		parsedURL, err := url.Parse(wsURL)
+		if err != nil {
+			return err
+		}
+		credentials, err := wsc.credentialsProvider.CreateCredentials(parsedURL.Host, parsedURL.Path)
+		if err != nil {
+			return err
+		}
+		if header == nil {
+			header = http.Header{}
+		}
+		for key, values := range credentials {
+			header[key] = values
+		}
+	}
+
+	conn, response, err := wsc.dialer.Dial(wsURL, header)
 	if err != nil {
+		if response != nil {
+			return fmt.Errorf("%w, status: %s", err, response.Status)
+		}
 		return err
 	}
+	wsc.conn = conn
+	wsc.responseHeader = response.Header
+	wsc.configureConn(conn)
 
 	return nil
 }
 
+func (wsc *wsConnClient) configureConn(conn *websocket.Conn) {
+	if wsc.maxMessageSize > 0 {
+		// larger messages close the connection with the CloseMessageTooBig code
+		conn.SetReadLimit(wsc.maxMessageSize)
+	}
+
+	wsc.startKeepAlive(conn)
+}
+
+// startKeepAlive will ping the peer periodically and will close the reading side of the connection if no pong or
+// message is received in time. It does nothing if the keep alive is disabled
+func (wsc *wsConnClient) startKeepAlive(conn *websocket.Conn) {
+	wsc.unresponsive.Store(false)
+	if wsc.pingInterval == 0 {
+		return
+	}
+
+	wsc.extendReadDeadline(conn)
+	conn.SetPongHandler(func(_ string) error {
+		wsc.extendReadDeadline(conn)
+		return nil
+	})
+
+	wsc.chStopPings = make(chan struct{})
+	go wsc.sendPings(conn, wsc.chStopPings)
+}
+
+func (wsc *wsConnClient) extendReadDeadline(conn *websocket.Conn) {
+	if wsc.pingInterval == 0 {
+		return
+	}
+
+	err := conn.SetReadDeadline(time.Now().Add(wsc.pingInterval + wsc.pongTimeout))
+	if err != nil {
+		log.Trace("cannot extend the read deadline", "error", err)
+	}
+}
+
+func (wsc *wsConnClient) sendPings(conn *websocket.Conn, chStop chan struct{}) {
+	ticker := time.NewTicker(wsc.pingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-chStop:
+			return
+		case <-ticker.C:
+		}
+
+		// control messages can be written concurrently with the other messages
+		err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsc.pongTimeout))
+		if err != nil {
+			// the read deadline will detect the broken connection
+			log.Trace("cannot send ping", "error", err)
+			return
+		}
+	}
+}
+
 // ReadMessage calls the underlying reading message ws connection func
 func (wsc *wsConnClient) ReadMessage() (messageType int, p []byte, err error) {
 	conn, err := wsc.getConn()
@@ -57,7 +216,30 @@ func (wsc *wsConnClient) ReadMessage() (messageType int, p []byte, err error) {
 		return 0, nil, err
 	}
 
-	return conn.ReadMessage()
+	messageType, p, err = conn.ReadMessage()
+	if err != nil {
+		if isTimeout(err) {
+			wsc.unresponsive.Store(true)
+			return 0, nil, fmt.Errorf("%w: %s", data.ErrConnectionUnresponsive, err.Error())
+		}
+		if errors.Is(err, websocket.ErrReadLimit) {
+			return 0, nil, fmt.Errorf("%w: %s, limit %d bytes", data.ErrMessageTooLarge, err.Error(), wsc.maxMessageSize)
+		}
+		return 0, nil, err
+	}
+	wsc.extendReadDeadline(conn)
+
+	return messageType, p, nil
+}
+
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// IsUnresponsive returns true if the last connection was dropped because the peer stopped answering the pings
+func (wsc *wsConnClient) IsUnresponsive() bool {
+	return wsc.unresponsive.Load()
 }
 
 // WriteMessage calls the underlying write message ws connection func
@@ -69,9 +251,23 @@ func (wsc *wsConnClient) WriteMessage(messageType int, payload []byte) error {
 		return data.ErrConnectionNotOpen
 	}
 
+	wsc.setWriteDeadline()
+
 	return wsc.conn.WriteMessage(messageType, payload)
 }
 
+// setWriteDeadline should be called under the mutex, before every write
+func (wsc *wsConnClient) setWriteDeadline() {
+	if wsc.writeTimeout == 0 {
+		return
+	}
+
+	err := wsc.conn.SetWriteDeadline(time.Now().Add(wsc.writeTimeout))
+	if err != nil {
+		log.Trace("cannot set the write deadline", "error", err)
+	}
+}
+
 // IsOpen will return true if the connection is open, false otherwise
 func (wsc *wsConnClient) IsOpen() bool {
 	wsc.mut.RLock()
@@ -93,6 +289,14 @@ func (wsc *wsConnClient) getConn() (*websocket.Conn, error) {
 	return conn, nil
 }
 
+// GetHandshakeResponseHeader will return the header of the response received when the connection was opened
+func (wsc *wsConnClient) GetHandshakeResponseHeader() http.Header {
+	wsc.mut.RLock()
+	defer wsc.mut.RUnlock()
+
+	return wsc.responseHeader
+}
+
 // GetID will return the unique id of the client
 func (wsc *wsConnClient) GetID() string {
 	return wsc.clientID
@@ -100,6 +304,11 @@ func (wsc *wsConnClient) GetID() string {
 
 // Close will try to cleanly close the connection, if possible
 func (wsc *wsConnClient) Close() error {
+	return wsc.CloseWithReason(websocket.CloseNormalClosure, "")
+}
+
+// CloseWithReason will try to cleanly close the connection, the peer reading the provided close code and reason
+func (wsc *wsConnClient) CloseWithReason(code int, reason string) error {
 	// critical section
 	wsc.mut.Lock()
 	defer wsc.mut.Unlock()
@@ -110,9 +319,15 @@ func (wsc *wsConnClient) Close() error {
 
 	log.Debug("closing ws connection...")
 
+	if wsc.chStopPings != nil {
+		close(wsc.chStopPings)
+		wsc.chStopPings = nil
+	}
+
 	//Cleanly close the connection by sending a close message and then
 	//waiting (with timeout) for the server to close the connection.
-	err := wsc.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	wsc.setWriteDeadline()
+	err := wsc.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason))
 	if err != nil {
 		log.Trace("cannot send close message", "error", err)
 	}
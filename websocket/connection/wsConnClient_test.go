@@ -1,10 +1,13 @@
 package connection
 
 import (
+	"errors"
+	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
@@ -176,3 +179,142 @@ func TestWsConnClient_IsOpen(t *testing.T) {
 
 	_ = conClient.Close()
 }
+
+func createSilentTestServer() *httptest.Server {
+	upgrader := websocket.Upgrader{}
+	chConnections := make(chan *websocket.Conn, 10)
+
+	// the accepted connections are never read, so the pings are never answered
+	return httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		conn, err := upgrader.Upgrade(writer, request, nil)
+		if err != nil {
+			return
+		}
+		chConnections <- conn
+	}))
+}
+
+func TestWsConnClient_KeepAliveShouldKeepAnsweringPeerConnected(t *testing.T) {
+	t.Parallel()
+
+	testServer := testscommon.NewHttpTestEchoHandler()
+	defer testServer.Close()
+
+	conClient := NewWSConnClientWithArgs(ArgsWSConnClient{
+		PingInterval: 50 * time.Millisecond,
+		PongTimeout:  50 * time.Millisecond,
+	})
+	connectionURL := createConnectionURLForTestServer(testServer)
+	err := conClient.OpenConnection(connectionURL)
+	require.Nil(t, err)
+	defer func() {
+		_ = conClient.Close()
+	}()
+
+	chReceived := make(chan []byte, 1)
+	chErr := make(chan error, 1)
+	go func() {
+		_, message, errRead := conClient.ReadMessage()
+		if errRead != nil {
+			chErr <- errRead
+			return
+		}
+		chReceived <- message
+	}()
+
+	// several times the read deadline, the pongs should keep extending it
+	time.Sleep(500 * time.Millisecond)
+	err = conClient.WriteMessage(websocket.TextMessage, []byte("TEST"))
+	require.Nil(t, err)
+
+	select {
+	case message := <-chReceived:
+		assert.Equal(t, "ECHO: TEST", string(message))
+	case err = <-chErr:
+		require.Fail(t, "unexpected read error", err.Error())
+	case <-time.After(time.Second):
+		require.Fail(t, "timeout waiting for the echo")
+	}
+	assert.False(t, conClient.IsUnresponsive())
+}
+
+func TestWsConnClient_KeepAliveShouldDetectUnresponsivePeer(t *testing.T) {
+	t.Parallel()
+
+	testServer := createSilentTestServer()
+	defer testServer.Close()
+
+	conClient := NewWSConnClientWithArgs(ArgsWSConnClient{
+		PingInterval: 50 * time.Millisecond,
+		PongTimeout:  50 * time.Millisecond,
+	})
+	connectionURL := createConnectionURLForTestServer(testServer)
+	err := conClient.OpenConnection(connectionURL)
+	require.Nil(t, err)
+
+	start := time.Now()
+	_, _, err = conClient.ReadMessage()
+	require.True(t, errors.Is(err, data.ErrConnectionUnresponsive))
+	require.Less(t, time.Since(start), time.Second)
+	require.True(t, conClient.IsUnresponsive())
+
+	err = conClient.Close()
+	require.Nil(t, err)
+
+	// a new connection starts as responsive
+	err = conClient.OpenConnection(connectionURL)
+	require.Nil(t, err)
+	require.False(t, conClient.IsUnresponsive())
+
+	_ = conClient.Close()
+}
+
+func TestWsConnClient_ReadingAMessageLargerThanTheLimitShouldError(t *testing.T) {
+	t.Parallel()
+
+	testServer := testscommon.NewHttpTestEchoHandler()
+	defer testServer.Close()
+
+	conClient := NewWSConnClientWithArgs(ArgsWSConnClient{
+		MaxMessageSize: 10,
+	})
+	connectionURL := createConnectionURLForTestServer(testServer)
+	err := conClient.OpenConnection(connectionURL)
+	require.Nil(t, err)
+	defer func() {
+		_ = conClient.Close()
+	}()
+
+	err = conClient.WriteMessage(websocket.TextMessage, []byte("TEST"))
+	require.Nil(t, err)
+	_, message, err := conClient.ReadMessage()
+	require.Nil(t, err)
+	require.Equal(t, "ECHO: TEST", string(message))
+
+	err = conClient.WriteMessage(websocket.TextMessage, []byte("LARGE MESSAGE"))
+	require.Nil(t, err)
+	_, _, err = conClient.ReadMessage()
+	require.True(t, errors.Is(err, data.ErrMessageTooLarge))
+}
+
+func TestCreateDialer(t *testing.T) {
+	t.Parallel()
+
+	t.Run("default values", func(t *testing.T) {
+		dialer := createDialer(ArgsWSConnClient{})
+		require.Equal(t, websocket.DefaultDialer.HandshakeTimeout, dialer.HandshakeTimeout)
+		require.Zero(t, dialer.ReadBufferSize)
+		require.Zero(t, dialer.WriteBufferSize)
+	})
+
+	t.Run("configured values", func(t *testing.T) {
+		dialer := createDialer(ArgsWSConnClient{
+			HandshakeTimeout: time.Second,
+			ReadBufferSize:   2048,
+			WriteBufferSize:  4096,
+		})
+		require.Equal(t, time.Second, dialer.HandshakeTimeout)
+		require.Equal(t, 2048, dialer.ReadBufferSize)
+		require.Equal(t, 4096, dialer.WriteBufferSize)
+	})
+}
@@ -0,0 +1,9 @@
+package data
+
+// CapturedMessage holds a received message, as stored in a capture file
+type CapturedMessage struct {
+	Timestamp int64
+	Topic     string
+	Version   uint32
+	Payload   []byte
+}
@@ -0,0 +1,17 @@
+package data
+
+import "time"
+
+// ClientInfo holds the details of a client connected to the websocket server
+type ClientInfo struct {
+	ID                  string
+	RemoteAddress       string
+	ConnectedAt         time.Time
+	BytesSent           uint64
+	BytesReceived       uint64
+	MessagesSent        uint64
+	MessagesReceived    uint64
+	QueuedMessages      int
+	DroppedMessages     uint64
+	RateLimitedMessages uint64
+}
@@ -0,0 +1,35 @@
+package data
+
+// ConnectionState defines the state of a websocket connection
+type ConnectionState int
+
+const (
+	// ConnectionConnecting signals that the client is trying to open the connection
+	ConnectionConnecting ConnectionState = iota + 1
+	// ConnectionOpen signals that the connection was established
+	ConnectionOpen
+	// ConnectionClosed signals that the connection was closed by one of the peers or by a network error
+	ConnectionClosed
+	// ConnectionUnresponsive signals that the connection was closed because the peer stopped answering the pings
+	ConnectionUnresponsive
+	// ConnectionFailed signals that the client gave up trying to open the connection
+	ConnectionFailed
+)
+
+// String returns the human-readable name of the connection state
+func (cs ConnectionState) String() string {
+	switch cs {
+	case ConnectionConnecting:
+		return "connecting"
+	case ConnectionOpen:
+		return "open"
+	case ConnectionClosed:
+		return "closed"
+	case ConnectionUnresponsive:
+		return "unresponsive"
+	case ConnectionFailed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
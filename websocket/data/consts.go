@@ -3,4 +3,58 @@ package data
 const (
 	// ClosedConnectionMessage is the message that is received when try to send a message over a closed WebSocket connection
 	ClosedConnectionMessage = "use of closed network connection"
+	// AuthorizationHeader is the header that holds the bearer token of a websocket upgrade request
+	AuthorizationHeader = "Authorization"
+	// BearerTokenPrefix is the prefix of the authorization header value when using a bearer token
+	BearerTokenPrefix = "Bearer "
+	// AuthTimestampHeader is the header that holds the unix timestamp at which the credentials were created
+	AuthTimestampHeader = "X-Ws-Auth-Timestamp"
+	// AuthNonceHeader is the header that holds the random nonce of the credentials
+	AuthNonceHeader = "X-Ws-Auth-Nonce"
+	// AuthSignatureHeader is the header that holds the hex encoded signature or HMAC over the timestamp and nonce
+	AuthSignatureHeader = "X-Ws-Auth-Signature"
+	// AuthPublicKeyHeader is the header that holds the hex encoded public key of the signer
+	AuthPublicKeyHeader = "X-Ws-Auth-Public-Key"
+	// PayloadCompressionHeader is the header that holds the payload compression algorithms supported by a host
+	PayloadCompressionHeader = "X-Ws-Payload-Compression"
+	// SnappyCompression is the name of the snappy payload compression algorithm
+	SnappyCompression = "snappy"
+	// ZstdCompression is the name of the zstd payload compression algorithm
+	ZstdCompression = "zstd"
+	// FixedReconnectPolicy is the name of the reconnect policy that waits the same duration between the attempts
+	FixedReconnectPolicy = "fixed"
+	// ExponentialReconnectPolicy is the name of the reconnect policy that increases the wait after every failed attempt
+	ExponentialReconnectPolicy = "exponential"
+	// DropOldestOverflowPolicy is the name of the policy that drops the oldest queued message of a client with a full queue
+	DropOldestOverflowPolicy = "drop-oldest"
+	// DropNewestOverflowPolicy is the name of the policy that drops the new message of a client with a full queue
+	DropNewestOverflowPolicy = "drop-newest"
+	// DisconnectOverflowPolicy is the name of the policy that disconnects a client with a full queue
+	DisconnectOverflowPolicy = "disconnect"
+	// DropRateLimitAction is the name of the action that drops, without acknowledging it, a message exceeding the rate limits
+	DropRateLimitAction = "drop"
+	// DelayRateLimitAction is the name of the action that waits until a message fits the rate limits before processing it
+	DelayRateLimitAction = "delay"
+	// DisconnectRateLimitAction is the name of the action that disconnects a client exceeding the rate limits
+	DisconnectRateLimitAction = "disconnect"
+	// FailoverEndpointSelection is the name of the strategy that connects to the first reachable endpoint, preferring them in order
+	FailoverEndpointSelection = "failover"
+	// RoundRobinEndpointSelection is the name of the strategy that connects to the next endpoint on every reconnection
+	RoundRobinEndpointSelection = "round-robin"
+	// FanOutEndpointSelection is the name of the strategy that connects to all the endpoints at the same time
+	FanOutEndpointSelection = "fan-out"
+	// ProtobufCodec is the name of the codec that marshals the messages as protobuf binary frames
+	ProtobufCodec = "protobuf"
+	// JSONCodec is the name of the codec that marshals the messages as JSON text frames
+	JSONCodec = "json"
+	// ProtobufSubprotocol is the websocket subprotocol negotiated by the hosts using the protobuf codec
+	ProtobufSubprotocol = "andes.protobuf"
+	// JSONSubprotocol is the websocket subprotocol negotiated by the hosts using the JSON codec
+	JSONSubprotocol = "andes.json"
+	// PayloadVersionsHeader is the header that holds the range of payload versions supported by a host, as "min-max"
+	PayloadVersionsHeader = "X-Ws-Payload-Versions"
+	// WebSocketTransport is the name of the transport that dials and serves real websocket connections over TCP
+	WebSocketTransport = "websocket"
+	// MemoryTransport is the name of the transport that connects the hosts of the same process through in-memory connections
+	MemoryTransport = "memory"
 )
@@ -0,0 +1,11 @@
+package data
+
+// ErrorPolicy defines what happens with the error returned by a payload handler
+type ErrorPolicy int
+
+const (
+	// PropagateErrorPolicy returns the error to the transceiver, so no acknowledgement is sent if BlockingAckOnError is set
+	PropagateErrorPolicy ErrorPolicy = iota
+	// IgnoreErrorPolicy logs the error and acknowledges the message as if it was processed
+	IgnoreErrorPolicy
+)
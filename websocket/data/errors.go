@@ -40,3 +40,289 @@ var ErrNoClientsConnected = errors.New("no client connected")
 
 // ErrAckTimeout signals that an acknowledgment timeout has been reached
 var ErrAckTimeout = errors.New("acknowledge waiting timeout occurred")
+
+// ErrEmptyTLSCertificateFile signals that an empty TLS certificate file path has been provided
+var ErrEmptyTLSCertificateFile = errors.New("empty TLS certificate file provided")
+
+// ErrEmptyTLSKeyFile signals that an empty TLS key file path has been provided
+var ErrEmptyTLSKeyFile = errors.New("empty TLS key file provided")
+
+// ErrEmptyTLSCACertificateFile signals that an empty TLS CA certificate file path has been provided
+var ErrEmptyTLSCACertificateFile = errors.New("empty TLS CA certificate file provided")
+
+// ErrInvalidTLSCACertificate signals that no valid certificate could be loaded from the provided CA file
+var ErrInvalidTLSCACertificate = errors.New("invalid TLS CA certificate")
+
+// ErrEmptyBearerToken signals that an empty bearer token has been provided
+var ErrEmptyBearerToken = errors.New("empty bearer token provided")
+
+// ErrEmptyHMACSecret signals that an empty HMAC secret has been provided
+var ErrEmptyHMACSecret = errors.New("empty HMAC secret provided")
+
+// ErrZeroValueMaxTimestampDrift signals that a zero value for the maximum timestamp drift has been provided
+var ErrZeroValueMaxTimestampDrift = errors.New("zero value provided for maximum timestamp drift")
+
+// ErrNilSingleSigner signals that a nil single signer has been provided
+var ErrNilSingleSigner = errors.New("nil single signer")
+
+// ErrNilKeyGenerator signals that a nil key generator has been provided
+var ErrNilKeyGenerator = errors.New("nil key generator")
+
+// ErrNilPrivateKey signals that a nil private key has been provided
+var ErrNilPrivateKey = errors.New("nil private key")
+
+// ErrEmptyTrustedPublicKeys signals that no trusted public key has been provided
+var ErrEmptyTrustedPublicKeys = errors.New("empty trusted public keys provided")
+
+// ErrMissingCredentials signals that the handshake request does not contain the expected credentials
+var ErrMissingCredentials = errors.New("missing credentials")
+
+// ErrInvalidCredentials signals that the handshake request contains invalid credentials
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+// ErrUntrustedPublicKey signals that the handshake request was signed by a public key that is not trusted
+var ErrUntrustedPublicKey = errors.New("untrusted public key")
+
+// ErrCredentialsExpired signals that the timestamp of the provided credentials is outside the accepted window
+var ErrCredentialsExpired = errors.New("credentials expired")
+
+// ErrNonceAlreadyUsed signals that the nonce of the provided credentials was already used
+var ErrNonceAlreadyUsed = errors.New("nonce already used")
+
+// ErrNilOutboundQueue signals that a nil outbound queue has been provided
+var ErrNilOutboundQueue = errors.New("nil outbound queue")
+
+// ErrNilSendHandler signals that a nil send handler has been provided
+var ErrNilSendHandler = errors.New("nil send handler")
+
+// ErrEmptyOutboundQueuePath signals that an empty outbound queue path has been provided
+var ErrEmptyOutboundQueuePath = errors.New("empty outbound queue path provided")
+
+// ErrOutboundQueueFull signals that the outbound queue has reached one of its limits
+var ErrOutboundQueueFull = errors.New("outbound queue is full")
+
+// ErrOutboundQueueClosed signals that the outbound queue was closed
+var ErrOutboundQueueClosed = errors.New("outbound queue is closed")
+
+// ErrOutboundQueueLocked signals that the path of the outbound queue is already used by another queue
+var ErrOutboundQueueLocked = errors.New("outbound queue path is locked")
+
+// ErrInvalidQueuedMessage signals that a stored queued message could not be decoded
+var ErrInvalidQueuedMessage = errors.New("invalid queued message")
+
+// ErrConnectionClosedBeforeAck signals that the connection was closed before the acknowledgment message was received
+var ErrConnectionClosedBeforeAck = errors.New("connection closed before the acknowledgment was received")
+
+// ErrInvalidAckWindowSize signals that an invalid acknowledgement window size has been provided
+var ErrInvalidAckWindowSize = errors.New("invalid acknowledgement window size")
+
+// ErrClientNotFound signals that no connected client has the provided ID
+var ErrClientNotFound = errors.New("client not found")
+
+// ErrNilRequestHandler signals that a nil request handler has been provided
+var ErrNilRequestHandler = errors.New("nil request handler provided")
+
+// ErrConnectionClosedBeforeResponse signals that the connection was closed before the response was received
+var ErrConnectionClosedBeforeResponse = errors.New("connection closed before the response was received")
+
+// ErrClosedBeforeResponse signals that the component was closed before the response was received
+var ErrClosedBeforeResponse = errors.New("closed before the response was received")
+
+// ErrUnknownCompressionAlgorithm signals that an unknown payload compression algorithm has been provided
+var ErrUnknownCompressionAlgorithm = errors.New("unknown payload compression algorithm")
+
+// ErrNegativeCompressionThreshold signals that a negative payload compression threshold has been provided
+var ErrNegativeCompressionThreshold = errors.New("negative payload compression threshold")
+
+// ErrInvalidKeepAliveConfig signals that the ping interval and the pong timeout were not provided together
+var ErrInvalidKeepAliveConfig = errors.New("invalid keep alive config, ping interval and pong timeout should be both set or both disabled")
+
+// ErrConnectionUnresponsive signals that the peer did not answer the pings in time
+var ErrConnectionUnresponsive = errors.New("connection unresponsive")
+
+// ErrInvalidReconnectDelay signals that an invalid reconnect delay has been provided
+var ErrInvalidReconnectDelay = errors.New("invalid reconnect delay")
+
+// ErrInvalidReconnectMultiplier signals that an invalid reconnect delay multiplier has been provided
+var ErrInvalidReconnectMultiplier = errors.New("invalid reconnect delay multiplier, should be at least 1")
+
+// ErrInvalidReconnectJitter signals that an invalid reconnect jitter has been provided
+var ErrInvalidReconnectJitter = errors.New("invalid reconnect jitter, should be between 0 and 1")
+
+// ErrInvalidMaxReconnectAttempts signals that an invalid maximum number of reconnect attempts has been provided
+var ErrInvalidMaxReconnectAttempts = errors.New("invalid maximum number of reconnect attempts")
+
+// ErrUnknownReconnectPolicy signals that an unknown reconnect policy has been provided
+var ErrUnknownReconnectPolicy = errors.New("unknown reconnect policy")
+
+// ErrEmptyTopicPattern signals that an empty topic pattern has been provided
+var ErrEmptyTopicPattern = errors.New("empty topic pattern")
+
+// ErrTopicPatternAlreadyRegistered signals that a handler was already registered for the provided topic pattern
+var ErrTopicPatternAlreadyRegistered = errors.New("a handler was already registered for the topic pattern")
+
+// ErrNoHandlerForTopic signals that no handler was registered for the topic of a payload
+var ErrNoHandlerForTopic = errors.New("no handler registered for topic")
+
+// ErrUnknownErrorPolicy signals that an unknown error policy has been provided
+var ErrUnknownErrorPolicy = errors.New("unknown error policy")
+
+// ErrInvalidClientQueueSize signals that an invalid client queue size has been provided
+var ErrInvalidClientQueueSize = errors.New("invalid client queue size")
+
+// ErrUnknownOverflowPolicy signals that an unknown client queue overflow policy has been provided
+var ErrUnknownOverflowPolicy = errors.New("unknown client queue overflow policy")
+
+// ErrClientQueueFull signals that a message was dropped because the queue of the client was full
+var ErrClientQueueFull = errors.New("client queue is full")
+
+// ErrClientQueueClosed signals that a message was dropped because the queue of the client was closed
+var ErrClientQueueClosed = errors.New("client queue is closed")
+
+// ErrMessageTooLarge signals that a received message exceeded the maximum message size
+var ErrMessageTooLarge = errors.New("message too large")
+
+// ErrInvalidRoutePath signals that an invalid websocket route path has been provided
+var ErrInvalidRoutePath = errors.New("invalid route path, it should start with /")
+
+// ErrInvalidBufferSize signals that a negative buffer size has been provided
+var ErrInvalidBufferSize = errors.New("invalid buffer size")
+
+// ErrInvalidMaxMessageSize signals that a negative maximum message size has been provided
+var ErrInvalidMaxMessageSize = errors.New("invalid maximum message size")
+
+// ErrInvalidTimeout signals that a negative timeout has been provided
+var ErrInvalidTimeout = errors.New("invalid timeout")
+
+// ErrUnknownEndpointSelection signals that an unknown endpoint selection strategy has been provided
+var ErrUnknownEndpointSelection = errors.New("unknown endpoint selection strategy")
+
+// ErrFanOutNeedsFanOutClient signals that the fan-out endpoint selection has been provided to a client connecting to a
+// single endpoint at a time, instead of a fan-out client
+var ErrFanOutNeedsFanOutClient = errors.New("the fan-out endpoint selection needs a fan-out client")
+
+// ErrUnknownCodec signals that an unknown wire codec has been provided
+var ErrUnknownCodec = errors.New("unknown codec")
+
+// ErrCodecNotAcceptedByPeer signals that the peer did not accept the wire codec during the handshake
+var ErrCodecNotAcceptedByPeer = errors.New("codec not accepted by peer")
+
+// ErrInvalidPayloadVersionRange signals that the minimum payload version is greater than the payload version
+var ErrInvalidPayloadVersionRange = errors.New("invalid payload version range")
+
+// ErrNoCommonPayloadVersion signals that the peers do not have any payload version in common
+var ErrNoCommonPayloadVersion = errors.New("no common payload version")
+
+// ErrUnsupportedPayloadVersion signals that a payload was received with a version outside the supported range
+var ErrUnsupportedPayloadVersion = errors.New("unsupported payload version")
+
+// ErrPayloadVersionAlreadyRegistered signals that a handler is already registered for the provided payload version
+var ErrPayloadVersionAlreadyRegistered = errors.New("payload version already registered")
+
+// ErrNoHandlerForPayloadVersion signals that no handler is registered for the version of a received payload
+var ErrNoHandlerForPayloadVersion = errors.New("no handler for payload version")
+
+// ErrNilMetricsSnapshotProvider signals that a nil metrics snapshot provider has been provided
+var ErrNilMetricsSnapshotProvider = errors.New("nil metrics snapshot provider")
+
+// ErrRouteConflict signals that two of the routes served by the websocket server have the same path
+var ErrRouteConflict = errors.New("route path conflicts with another route of the server")
+
+// ErrMetricsSnapshotNotSupported signals that the metrics must be served, but the metrics handler cannot provide snapshots
+var ErrMetricsSnapshotNotSupported = errors.New("metrics handler does not provide snapshots")
+
+// ErrEmptyCapturePath signals that an empty capture file path has been provided
+var ErrEmptyCapturePath = errors.New("empty capture file path provided")
+
+// ErrInvalidCaptureFile signals that the file is not a capture file or it is corrupted
+var ErrInvalidCaptureFile = errors.New("invalid capture file")
+
+// ErrNilCaptureReader signals that a nil capture reader has been provided
+var ErrNilCaptureReader = errors.New("nil capture reader")
+
+// ErrNilPayloadSender signals that a nil payload sender has been provided
+var ErrNilPayloadSender = errors.New("nil payload sender")
+
+// ErrInvalidReplaySpeed signals that a negative replay speed has been provided
+var ErrInvalidReplaySpeed = errors.New("invalid replay speed")
+
+// ErrUnknownTransport signals that the provided transport is not known
+var ErrUnknownTransport = errors.New("unknown transport")
+
+// ErrInvalidMemoryTransportConfig signals that a negative latency or a drop percentage outside 0-100 has been provided
+var ErrInvalidMemoryTransportConfig = errors.New("invalid memory transport config")
+
+// ErrNotSupportedByMemoryTransport signals that an option that needs a real network has been set on a host using the memory transport
+var ErrNotSupportedByMemoryTransport = errors.New("option not supported by the memory transport")
+
+// ErrMemoryAddressInUse signals that another in-memory listener already serves the same address
+var ErrMemoryAddressInUse = errors.New("memory address already in use")
+
+// ErrNoMemoryListener signals that no in-memory listener serves the dialed address
+var ErrNoMemoryListener = errors.New("no memory listener for the dialed address")
+
+// ErrMemoryHandshakeRejected signals that the in-memory listener rejected the handshake
+var ErrMemoryHandshakeRejected = errors.New("memory handshake rejected")
+
+// ErrInvalidChunkingConfig signals that a negative chunk size, reassembly size or reassembly timeout has been provided
+var ErrInvalidChunkingConfig = errors.New("invalid chunking config")
+
+// ErrInvalidChunk signals that a received chunk does not match the other chunks of its message
+var ErrInvalidChunk = errors.New("invalid chunk")
+
+// ErrReassemblyBufferFull signals that the chunks waiting for the rest of their messages would exceed the maximum reassembly size
+var ErrReassemblyBufferFull = errors.New("reassembly buffer full")
+
+// ErrTooManyPartialMessages signals that too many messages are already waiting for the rest of their chunks
+var ErrTooManyPartialMessages = errors.New("too many partial messages")
+
+// ErrMissingSignature signals that a received message is not signed, although signed messages are expected
+var ErrMissingSignature = errors.New("missing message signature")
+
+// ErrUntrustedSigner signals that a received message was signed by a public key that is not trusted
+var ErrUntrustedSigner = errors.New("untrusted message signer")
+
+// ErrInvalidSignature signals that the signature of a received message does not match its content
+var ErrInvalidSignature = errors.New("invalid message signature")
+
+// ErrAckQuorumNotReached signals that fewer receivers than required acknowledged a message
+var ErrAckQuorumNotReached = errors.New("acknowledgement quorum not reached")
+
+// ErrInvalidAckQuorum signals that a negative acknowledgement quorum has been provided
+var ErrInvalidAckQuorum = errors.New("invalid acknowledgement quorum")
+
+// ErrEmptyRelayListenURL signals that a relay has no address to serve its downstream clients on
+var ErrEmptyRelayListenURL = errors.New("empty relay listen url")
+
+// ErrNilUpstreamHost signals that a nil upstream host has been provided
+var ErrNilUpstreamHost = errors.New("nil upstream host")
+
+// ErrNilDownstreamHost signals that a nil downstream host has been provided
+var ErrNilDownstreamHost = errors.New("nil downstream host")
+
+// ErrQuorumNotSupportedByDownstreamHost signals that the provided downstream host can not wait for the acknowledgements
+// of a quorum of clients
+var ErrQuorumNotSupportedByDownstreamHost = errors.New("the downstream host does not support the acknowledgement quorum")
+
+// ErrRelayWithoutBlockingAckOnError signals that a relay was configured to acknowledge the payloads whose processing
+// failed
+var ErrRelayWithoutBlockingAckOnError = errors.New("the relay mode needs the blocking acknowledgement on error")
+
+// ErrInvalidRateLimitConfig signals that an invalid rate limit has been provided
+var ErrInvalidRateLimitConfig = errors.New("invalid rate limit config")
+
+// ErrUnknownRateLimitAction signals that an unknown action for the messages exceeding the rate limits has been provided
+var ErrUnknownRateLimitAction = errors.New("unknown rate limit action")
+
+// ErrRateLimitExceeded signals that a client sent more messages than allowed by the rate limits
+var ErrRateLimitExceeded = errors.New("rate limit exceeded")
+
+// ErrPayloadRefused signals that a payload handler refused a payload, which is not acknowledged whatever the
+// acknowledgement policy is
+var ErrPayloadRefused = errors.New("payload refused")
+
+// ErrInvalidMaxConcurrentRequests signals that an invalid maximum number of requests handled at the same time has been provided
+var ErrInvalidMaxConcurrentRequests = errors.New("invalid maximum number of concurrent requests")
+
+// ErrTooManyRequests signals that a request was refused because the maximum number of requests are already being handled
+var ErrTooManyRequests = errors.New("too many requests handled at the same time")
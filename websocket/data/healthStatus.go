@@ -0,0 +1,11 @@
+package data
+
+import "time"
+
+// HealthStatus holds the state of a websocket server, as reported by its health and readiness endpoints
+type HealthStatus struct {
+	Listening          bool                 `json:"listening"`
+	ConnectedClients   int                  `json:"connectedClients"`
+	PendingAcks        int                  `json:"pendingAcks"`
+	LastSuccessfulSend map[string]time.Time `json:"lastSuccessfulSend"`
+}
@@ -0,0 +1,34 @@
+package data
+
+// TopicMetrics holds the counters of the messages of a single topic
+type TopicMetrics struct {
+	MessagesSent      uint64
+	BytesSent         uint64
+	MessagesReceived  uint64
+	BytesReceived     uint64
+	HandlerErrors     uint64
+	InvalidSignatures uint64
+}
+
+// HistogramBucket holds the number of observations lower than or equal to the upper bound of the bucket
+type HistogramBucket struct {
+	UpperBoundInSeconds float64
+	Count               uint64
+}
+
+// Histogram holds the cumulative buckets of a latency histogram, together with the number and the sum of all the
+// observations
+type Histogram struct {
+	Buckets      []HistogramBucket
+	Count        uint64
+	SumInSeconds float64
+}
+
+// MetricsSnapshot holds the metrics collected by a websocket host at a point in time
+type MetricsSnapshot struct {
+	Topics           map[string]TopicMetrics
+	AckLatency       Histogram
+	AckTimeouts      uint64
+	Reconnects       uint64
+	ConnectedClients int64
+}
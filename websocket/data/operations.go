@@ -3,20 +3,108 @@ package data
 const (
 	// WSRoute is the route which data will be sent over websocket
 	WSRoute = "/save"
+	// HealthRoute is the route on which the server reports whether it is alive
+	HealthRoute = "/health"
+	// ReadyRoute is the route on which the server reports whether it is ready to serve the traffic
+	ReadyRoute = "/ready"
+	// DefaultServerBufferSize is the size in bytes of the read and write buffers used by the server when none is configured
+	DefaultServerBufferSize = 1024
 	// ModeServer is a constant value that is used to indicate that the WebSocket host should start in server mode, meaning it will listen for incoming connections from clients and respond to them.
 	ModeServer = "server"
 	// ModeClient is a constant value that is used to indicate that the WebSocket host should start in client mode, meaning it will initiate connections to a remote server.
 	ModeClient = "client"
+	// ModeRelay is a constant value that is used to indicate that the WebSocket host should start in relay mode, meaning it will connect to a remote server as a client and re-serve the received stream to its own clients.
+	ModeRelay = "relay"
 )
 
 // WebSocketConfig holds the configuration needed for instantiating a new web socket server
 type WebSocketConfig struct {
-	URL                        string // The WebSocket URL to connect to.
-	Mode                       string // The host operation mode: 'client' or 'server'.
-	RetryDurationInSec         int    // The duration in seconds to wait before retrying the connection in case of failure.
-	WithAcknowledge            bool   // Set to `true` to enable message acknowledgment mechanism.
-	AcknowledgeTimeoutInSec    int    // The duration in seconds to wait for an acknowledgement message
-	BlockingAckOnError         bool   // Set to `true` to send the acknowledgment message only if the processing part of a message succeeds. If an error occurs during processing, the acknowledgment will not be sent.
-	DropMessagesIfNoConnection bool   // Set to `true` to drop messages if there is no active WebSocket connection to send to.
-	Version                    uint32 // Defines the payload version.
+	URL                        string   // The WebSocket URL to connect to.
+	Mode                       string   // The host operation mode: 'client', 'server' or 'relay'. A relay connects to URL as a client and serves the received payloads on Relay.ListenURL.
+	Transport                  string   // How the hosts are connected: 'websocket' (default) over TCP or 'memory', connecting the hosts of the same process without sockets. Meant for tests.
+	URLs                       []string // Client mode only: more endpoints to connect to, after the one in URL. URL can be empty if this is set.
+	EndpointSelection          string   // Client mode only: how the endpoints are used: 'failover' (default) prefers them in order, 'round-robin' moves to the next one on every reconnection, 'fan-out' sends to all of them.
+	Codec                      string   // Client mode only: how the messages are marshalled: 'protobuf' (default) or 'json' text frames. The servers accept both at the same time.
+	RetryDurationInSec         int      // The duration in seconds to wait before retrying the connection in case of failure.
+	WithAcknowledge            bool     // Set to `true` to enable message acknowledgment mechanism.
+	AcknowledgeTimeoutInSec    int      // The duration in seconds to wait for an acknowledgement message
+	AckWindowSize              int      // The maximum number of messages waiting for their acknowledgement at the same time. 0 keeps the one-by-one acknowledgement.
+	BlockingAckOnError         bool     // Set to `true` to send the acknowledgment message only if the processing part of a message succeeds. If an error occurs during processing, the acknowledgment will not be sent. Must be `true` in relay mode.
+	DropMessagesIfNoConnection bool     // Set to `true` to drop messages if there is no active WebSocket connection to send to.
+	Version                    uint32   // Defines the payload version.
+	MinVersion                 uint32   // The oldest payload version still supported. When set, the peers negotiate the highest version they both support, up to Version, and close the connection if there is none. 0 disables the negotiation.
+	UseTLS                     bool     // Set to `true` to serve (server mode) or dial (client mode) the connection over TLS, using the wss:// scheme.
+	TLSCertificateFile         string   // Path to the PEM encoded certificate. Required in server mode, used in client mode only for mutual TLS.
+	TLSKeyFile                 string   // Path to the PEM encoded private key matching the certificate file.
+	TLSCACertificateFile       string   // Path to a PEM encoded CA bundle. The client verifies the server against it, the server verifies clients against it in mutual TLS mode.
+	TLSMutualAuthentication    bool     // Set to `true` to require and verify a client certificate on every connection.
+	SubscribedTopics           []string // Client mode only: the topics the server should send to this client. Empty means all the topics.
+	PerMessageDeflate          bool     // Set to `true` to negotiate the permessage-deflate websocket extension, compressing every frame at transport level.
+	PayloadCompression         string   // The algorithm used to compress the payloads: 'snappy', 'zstd' or empty to disable it. Used only if the peer supports it.
+	CompressionThreshold       int      // Payloads smaller than this size in bytes are sent uncompressed.
+	PingIntervalInSec          int      // The interval in seconds between the pings sent to the peer. 0 disables the keep alive.
+	PongTimeoutInSec           int      // The duration in seconds to wait for the pong, after the ping interval, before dropping the connection as unresponsive.
+	ClientQueueSize            int      // Server mode only: the maximum number of messages waiting to be sent to a client, each client being served by its own go routine. 0 sends to the clients one by one.
+	ClientQueueOverflowPolicy  string   // Server mode only: what happens when the queue of a client is full: 'drop-oldest' (default), 'drop-newest' or 'disconnect'.
+	RoutePath                  string   // The path on which the websocket is served (server mode) or dialed (client mode). Empty means '/save'.
+	ReadBufferSizeInBytes      int      // The size of the connection read buffer. 0 keeps the default size.
+	WriteBufferSizeInBytes     int      // The size of the connection write buffer. 0 keeps the default size.
+	MaxMessageSizeInBytes      int64    // The maximum size of an inbound message. Larger messages close the connection with the 'message too big' close code. 0 means no limit.
+	WriteTimeoutInSec          int      // The duration in seconds a single write may take before the connection is considered broken. 0 means no timeout.
+	HandshakeTimeoutInSec      int      // The duration in seconds the websocket opening handshake may take. 0 keeps the default timeout.
+	MetricsRoutePath           string   // Server mode only: the path on which the metrics are served in the Prometheus text format, next to the websocket route. Empty disables the endpoint.
+	HealthEndpoints            bool     // Server mode only: set to `true` to serve the '/health' and '/ready' endpoints next to the websocket route. '/ready' fails while no client is connected.
+	ChunkSizeInBytes           int      // Messages larger than this size are written in chunks of this size, reassembled by the peer before being processed and acknowledged once. 0 disables the chunking. The peer must support the chunks.
+	MaxReassemblySizeInBytes   int      // The maximum accumulated size of the received chunks waiting for the rest of their messages, each chunk counting 64 more bytes. 0 keeps the default of 256 MB.
+	ReassemblyTimeoutInSec     int      // A message whose next chunk does not arrive within this duration is dropped. 0 keeps the default of 30 seconds.
+	MaxConcurrentRequests      int      // The maximum number of requests of a peer handled at the same time. The next requests are answered with an error until one is handled. 0 keeps the default of 64.
+	OutboundQueue              OutboundQueueConfig
+	Reconnect                  ReconnectConfig
+	MemoryTransport            MemoryTransportConfig
+	Relay                      RelayConfig
+	RateLimit                  RateLimitConfig
+}
+
+// OutboundQueueConfig holds the configuration of the disk backed queue that buffers the outgoing messages
+type OutboundQueueConfig struct {
+	Enabled        bool   // Set to `true` to persist every outgoing message and replay it, in order, until it is sent (and acknowledged, if enabled). Takes precedence over DropMessagesIfNoConnection.
+	Path           string // The directory where the queued messages are stored. It is locked, so a single host can use it at a time.
+	MaxMessages    int    // The maximum number of queued messages. 0 means no limit.
+	MaxSizeInBytes int64  // The maximum accumulated size of the queued messages. 0 means no limit.
+	MaxAgeInSec    int    // Queued messages older than this value are discarded instead of being sent. 0 means no limit.
+}
+
+// ReconnectConfig holds the configuration of the policy used by a client to open the connection again
+type ReconnectConfig struct {
+	Policy           string // 'fixed' (default) waits RetryDurationInSec between the attempts, 'exponential' doubles the wait after every failed attempt.
+	MaxDelayInSec    int    // Exponential policy only: the maximum wait between two attempts. 0 means no limit.
+	JitterPercentage int    // Exponential policy only: the wait is randomized by up to this percentage, so clients do not reconnect all at once.
+	MaxAttempts      int    // The number of consecutive failed attempts after which the client gives up. 0 means retrying forever.
+}
+
+// RelayConfig holds the configuration of a host running in relay mode
+type RelayConfig struct {
+	ListenURL string // The address on which the downstream clients connect. The server options of the config apply to it.
+	AckQuorum int    // The number of downstream clients that must acknowledge a payload before it is acknowledged upstream. 0 means all the clients subscribed to its topic.
+}
+
+// RateLimitConfig holds the token bucket limits of the payload messages a server accepts from each of its clients
+type RateLimitConfig struct {
+	MessagesPerSecond float64                // Server mode only: the rate at which a client may send payload messages, on all the topics. 0 disables the limit.
+	Burst             int                    // The number of messages a client may send at once, above the rate. 0 means one second worth of messages.
+	Topics            []TopicRateLimitConfig // The limits of the messages sent by a client on a single topic, applied on top of the limit of the client.
+	Action            string                 // What happens with a message exceeding the limits: 'drop' (default) discards it without acknowledging it, so the sender gets an acknowledgement timeout, 'delay' waits until it fits the limits, slowing down the reading from the client, 'disconnect' closes the connection with the policy violation close code.
+}
+
+// TopicRateLimitConfig holds the token bucket limit of the payload messages a client sends on a topic
+type TopicRateLimitConfig struct {
+	Topic             string  // The topic of the limited messages.
+	MessagesPerSecond float64 // The rate at which a client may send payload messages on the topic. Must be positive.
+	Burst             int     // The number of messages a client may send at once on the topic, above the rate. 0 means one second worth of messages.
+}
+
+// MemoryTransportConfig holds the faults injected in the messages written by a host using the memory transport
+type MemoryTransportConfig struct {
+	LatencyInMs    int // The delay of every written message. The messages are still delivered in order.
+	DropPercentage int // The percentage of the written messages that are silently lost, between 0 and 100.
 }
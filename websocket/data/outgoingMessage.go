@@ -0,0 +1,15 @@
+package data
+
+// OutgoingMessage holds a payload to be sent by a transceiver
+type OutgoingMessage struct {
+	Payload []byte
+	Topic   string
+	// ID identifies the message across its resends. A message sent again with the ID of a previous attempt that might
+	// have reached the peer reuses the sequence of that attempt, so the peer can drop it if it was already processed.
+	// The messages sent only once have no ID
+	ID string
+	// Signature and SignerPublicKey hold the signature created by the origin of a forwarded message. A message carrying
+	// the signature of its origin is sent as it is, without being signed again
+	Signature       []byte
+	SignerPublicKey []byte
+}
@@ -0,0 +1,21 @@
+package data
+
+import "strconv"
+
+// QueuedMessage holds an outgoing message stored in the outbound queue
+type QueuedMessage struct {
+	ID        uint64
+	Topic     string
+	Payload   []byte
+	Timestamp int64
+}
+
+// ToOutgoingMessage returns the message to be sent for the queued one. It is identified by its ID in the queue, so every
+// retry of the queued message is recognized as a resend
+func (message *QueuedMessage) ToOutgoingMessage() *OutgoingMessage {
+	return &OutgoingMessage{
+		Payload: message.Payload,
+		Topic:   message.Topic,
+		ID:      strconv.FormatUint(message.ID, 10),
+	}
+}
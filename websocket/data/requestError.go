@@ -0,0 +1,26 @@
+package data
+
+import "fmt"
+
+const (
+	// RequestErrorCodeInternal is the code used when the request handler returned an unstructured error
+	RequestErrorCodeInternal = 1
+	// RequestErrorCodeNoHandler is the code used when the peer has no request handler set
+	RequestErrorCodeNoHandler = 2
+	// RequestErrorCodeRejected is the code used when the request failed the checks of a received message, like its
+	// version, its signature or the rate limits
+	RequestErrorCodeRejected = 3
+	// RequestErrorCodeBusy is the code used when all the request workers of the peer are busy
+	RequestErrorCodeBusy = 4
+)
+
+// RequestError holds the structured error a request handler can return, which is sent back to the requester
+type RequestError struct {
+	Code    int32
+	Message string
+}
+
+// Error returns the string representation of the request error
+func (re *RequestError) Error() string {
+	return fmt.Sprintf("request failed, code: %d, message: %s", re.Code, re.Message)
+}
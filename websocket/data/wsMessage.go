@@ -7,4 +7,26 @@ const (
 	AckMessage = 1
 	// PayloadMessage holds the identifier for a payload message
 	PayloadMessage = 2
+	// CumulativeAckMessage holds the identifier for an ack message that acknowledges all the messages up to its counter
+	CumulativeAckMessage = 3
+	// SubscribeMessage holds the identifier for a message that subscribes the sender to the listed topics
+	SubscribeMessage = 4
+	// UnsubscribeMessage holds the identifier for a message that unsubscribes the sender from the listed topics
+	UnsubscribeMessage = 5
+	// RequestMessage holds the identifier for a message that expects a response with the same correlation ID
+	RequestMessage = 6
+	// ResponseMessage holds the identifier for a message that answers a request
+	ResponseMessage = 7
+	// ChunkMessage holds the identifier for a message carrying a part of a larger message, the counter identifying the
+	// message and the chunk index and total identifying the part
+	ChunkMessage = 8
+)
+
+const (
+	// NoCompressionFlag signals that the payload of a message is not compressed
+	NoCompressionFlag = 0
+	// SnappyCompressionFlag signals that the payload of a message is compressed with snappy
+	SnappyCompressionFlag = 1
+	// ZstdCompressionFlag signals that the payload of a message is compressed with zstd
+	ZstdCompressionFlag = 2
 )
@@ -28,12 +28,24 @@ const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
 
 // WsMessage contains all the information needed for a WebSocket message
 type WsMessage struct {
-	WithAcknowledge bool   `protobuf:"varint,1,opt,name=WithAcknowledge,proto3" json:"withAcknowledge,omitempty"`
-	Counter         uint64 `protobuf:"varint,2,opt,name=Counter,proto3" json:"counter,omitempty"`
-	Type            int32  `protobuf:"varint,3,opt,name=Type,proto3" json:"type,omitempty"`
-	Payload         []byte `protobuf:"bytes,4,opt,name=Payload,proto3" json:"payload,omitempty"`
-	Topic           string `protobuf:"bytes,5,opt,name=Topic,proto3" json:"topic,omitempty"`
-	Version         uint32 `protobuf:"varint,6,opt,name=Version,proto3" json:"version,omitempty"`
+	WithAcknowledge   bool     `protobuf:"varint,1,opt,name=WithAcknowledge,proto3" json:"withAcknowledge,omitempty"`
+	Counter           uint64   `protobuf:"varint,2,opt,name=Counter,proto3" json:"counter,omitempty"`
+	Type              int32    `protobuf:"varint,3,opt,name=Type,proto3" json:"type,omitempty"`
+	Payload           []byte   `protobuf:"bytes,4,opt,name=Payload,proto3" json:"payload,omitempty"`
+	Topic             string   `protobuf:"bytes,5,opt,name=Topic,proto3" json:"topic,omitempty"`
+	Version           uint32   `protobuf:"varint,6,opt,name=Version,proto3" json:"version,omitempty"`
+	WithCumulativeAck bool     `protobuf:"varint,7,opt,name=WithCumulativeAck,proto3" json:"withCumulativeAck,omitempty"`
+	Topics            []string `protobuf:"bytes,8,rep,name=Topics,proto3" json:"topics,omitempty"`
+	CorrelationID     uint64   `protobuf:"varint,9,opt,name=CorrelationID,proto3" json:"correlationID,omitempty"`
+	ErrorCode         int32    `protobuf:"varint,10,opt,name=ErrorCode,proto3" json:"errorCode,omitempty"`
+	ErrorMessage      string   `protobuf:"bytes,11,opt,name=ErrorMessage,proto3" json:"errorMessage,omitempty"`
+	Compression       int32    `protobuf:"varint,12,opt,name=Compression,proto3" json:"compression,omitempty"`
+	SessionID         string   `protobuf:"bytes,13,opt,name=SessionID,proto3" json:"sessionID,omitempty"`
+	Sequence          uint64   `protobuf:"varint,14,opt,name=Sequence,proto3" json:"sequence,omitempty"`
+	ChunkIndex        uint32   `protobuf:"varint,15,opt,name=ChunkIndex,proto3" json:"chunkIndex,omitempty"`
+	ChunkTotal        uint32   `protobuf:"varint,16,opt,name=ChunkTotal,proto3" json:"chunkTotal,omitempty"`
+	Signature         []byte   `protobuf:"bytes,17,opt,name=Signature,proto3" json:"signature,omitempty"`
+	SignerPublicKey   []byte   `protobuf:"bytes,18,opt,name=SignerPublicKey,proto3" json:"signerPublicKey,omitempty"`
 }
 
 func (m *WsMessage) Reset()      { *m = WsMessage{} }
@@ -106,6 +118,90 @@ func (m *WsMessage) GetVersion() uint32 {
 	return 0
 }
 
+func (m *WsMessage) GetWithCumulativeAck() bool {
+	if m != nil {
+		return m.WithCumulativeAck
+	}
+	return false
+}
+
+func (m *WsMessage) GetTopics() []string {
+	if m != nil {
+		return m.Topics
+	}
+	return nil
+}
+
+func (m *WsMessage) GetCorrelationID() uint64 {
+	if m != nil {
+		return m.CorrelationID
+	}
+	return 0
+}
+
+func (m *WsMessage) GetErrorCode() int32 {
+	if m != nil {
+		return m.ErrorCode
+	}
+	return 0
+}
+
+func (m *WsMessage) GetErrorMessage() string {
+	if m != nil {
+		return m.ErrorMessage
+	}
+	return ""
+}
+
+func (m *WsMessage) GetCompression() int32 {
+	if m != nil {
+		return m.Compression
+	}
+	return 0
+}
+
+func (m *WsMessage) GetSessionID() string {
+	if m != nil {
+		return m.SessionID
+	}
+	return ""
+}
+
+func (m *WsMessage) GetSequence() uint64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
+func (m *WsMessage) GetChunkIndex() uint32 {
+	if m != nil {
+		return m.ChunkIndex
+	}
+	return 0
+}
+
+func (m *WsMessage) GetChunkTotal() uint32 {
+	if m != nil {
+		return m.ChunkTotal
+	}
+	return 0
+}
+
+func (m *WsMessage) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *WsMessage) GetSignerPublicKey() []byte {
+	if m != nil {
+		return m.SignerPublicKey
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*WsMessage)(nil), "proto.WsMessage")
 }
@@ -113,28 +209,46 @@ func init() {
 func init() { proto.RegisterFile("wsMessage.proto", fileDescriptor_5e88e8c2dafbb96c) }
 
 var fileDescriptor_5e88e8c2dafbb96c = []byte{
-	// 334 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x5c, 0x91, 0xb1, 0x4a, 0xc3, 0x40,
-	0x18, 0xc7, 0xf3, 0xd5, 0xa4, 0xda, 0xa0, 0x16, 0x23, 0x42, 0x14, 0xfc, 0x12, 0x1c, 0x24, 0x82,
-	0xb6, 0x83, 0xbb, 0x60, 0x1c, 0x9c, 0x04, 0x91, 0x62, 0xc1, 0x2d, 0x4d, 0xcf, 0x34, 0xd8, 0xf6,
-	0x8e, 0xe6, 0x6a, 0xc9, 0xe6, 0x23, 0xf8, 0x18, 0x3e, 0x83, 0x4f, 0xe0, 0xd8, 0xb1, 0x53, 0xb0,
-	0xd7, 0x45, 0x32, 0xf5, 0x11, 0x24, 0x77, 0x2d, 0x44, 0xa7, 0xbb, 0xef, 0xff, 0xfd, 0xfe, 0xbf,
-	0x1b, 0xce, 0xac, 0x4f, 0x92, 0x3b, 0x92, 0x24, 0x41, 0x44, 0x1a, 0x6c, 0x44, 0x39, 0xb5, 0x0c,
-	0x79, 0x1c, 0x5d, 0x44, 0x31, 0xef, 0x8d, 0x3b, 0x8d, 0x90, 0x0e, 0x9a, 0x11, 0x8d, 0x68, 0x53,
-	0xc6, 0x9d, 0xf1, 0xb3, 0x9c, 0xe4, 0x20, 0x6f, 0xaa, 0x75, 0xf2, 0x59, 0x31, 0x6b, 0xed, 0xb5,
-	0xc9, 0xba, 0x35, 0xeb, 0xed, 0x98, 0xf7, 0xae, 0xc3, 0x97, 0x21, 0x9d, 0xf4, 0x49, 0x37, 0x22,
-	0x36, 0xb8, 0xe0, 0x6d, 0xf9, 0xc7, 0x79, 0xe6, 0x1c, 0x4e, 0xfe, 0xae, 0xce, 0xe9, 0x20, 0xe6,
-	0x64, 0xc0, 0x78, 0xfa, 0xf0, 0xbf, 0x65, 0x35, 0xcd, 0xcd, 0x1b, 0x3a, 0x1e, 0x72, 0x32, 0xb2,
-	0x2b, 0x2e, 0x78, 0xba, 0x7f, 0x90, 0x67, 0xce, 0x5e, 0xa8, 0xa2, 0x52, 0x71, 0x4d, 0x59, 0xa7,
-	0xa6, 0xde, 0x4a, 0x19, 0xb1, 0x37, 0x5c, 0xf0, 0x0c, 0xdf, 0xca, 0x33, 0x67, 0x97, 0xa7, 0xac,
-	0xfc, 0x86, 0xdc, 0x17, 0xe2, 0xfb, 0x20, 0xed, 0xd3, 0xa0, 0x6b, 0xeb, 0x2e, 0x78, 0xdb, 0x4a,
-	0xcc, 0x54, 0x54, 0x16, 0xaf, 0x28, 0xeb, 0xcc, 0x34, 0x5a, 0x94, 0xc5, 0xa1, 0x6d, 0xb8, 0xe0,
-	0xd5, 0xfc, 0xfd, 0x3c, 0x73, 0xea, 0xbc, 0x08, 0x4a, 0xb0, 0x22, 0x0a, 0xf7, 0x23, 0x19, 0x25,
-	0x31, 0x1d, 0xda, 0x55, 0x17, 0xbc, 0x1d, 0xe5, 0x7e, 0x55, 0x51, 0xd9, 0xbd, 0xa2, 0xfc, 0xab,
-	0xe9, 0x1c, 0xb5, 0xd9, 0x1c, 0xb5, 0xe5, 0x1c, 0xe1, 0x4d, 0x20, 0x7c, 0x08, 0x84, 0x2f, 0x81,
-	0x30, 0x15, 0x08, 0x33, 0x81, 0xf0, 0x2d, 0x10, 0x7e, 0x04, 0x6a, 0x4b, 0x81, 0xf0, 0xbe, 0x40,
-	0x6d, 0xba, 0x40, 0x6d, 0xb6, 0x40, 0xed, 0x49, 0xef, 0x06, 0x3c, 0xe8, 0x54, 0xe5, 0x1f, 0x5c,
-	0xfe, 0x06, 0x00, 0x00, 0xff, 0xff, 0x37, 0xf9, 0x37, 0xe2, 0xcc, 0x01, 0x00, 0x00,
+	// 614 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0x94, 0x4d, 0x4b, 0x1b, 0x41,
+	0x18, 0xc7, 0x33, 0x35, 0xd1, 0x64, 0x7c, 0x89, 0x8e, 0x6f, 0xa3, 0xd2, 0x99, 0xa5, 0x87, 0xb2,
+	0x05, 0xab, 0xd0, 0x52, 0x28, 0x14, 0x04, 0x13, 0x4b, 0x91, 0x22, 0x48, 0x95, 0x0a, 0xbd, 0x6d,
+	0x36, 0xd3, 0xb8, 0x98, 0xec, 0x6c, 0xf7, 0xc5, 0x34, 0xb7, 0x7e, 0x81, 0x42, 0x3f, 0x46, 0x3f,
+	0x4a, 0x8f, 0x1e, 0x3d, 0x0d, 0x75, 0x73, 0x29, 0x73, 0xf2, 0x23, 0x94, 0x7d, 0x36, 0x1b, 0x27,
+	0xf1, 0x94, 0x9d, 0xff, 0xf3, 0xff, 0xfd, 0xe7, 0x99, 0x87, 0x99, 0xe0, 0x7a, 0x3f, 0x3a, 0x11,
+	0x51, 0xe4, 0x74, 0xc4, 0x5e, 0x10, 0xca, 0x58, 0x92, 0x0a, 0xfc, 0x6c, 0xbf, 0xec, 0x78, 0xf1,
+	0x65, 0xd2, 0xda, 0x73, 0x65, 0x6f, 0xbf, 0x23, 0x3b, 0x72, 0x1f, 0xe4, 0x56, 0xf2, 0x15, 0x56,
+	0xb0, 0x80, 0xaf, 0x9c, 0x7a, 0xf6, 0xb3, 0x8a, 0x6b, 0x17, 0x45, 0x12, 0xf9, 0x80, 0xeb, 0x17,
+	0x5e, 0x7c, 0x79, 0xe8, 0x5e, 0xf9, 0xb2, 0xdf, 0x15, 0xed, 0x8e, 0xa0, 0xc8, 0x42, 0x76, 0xb5,
+	0xf1, 0x54, 0x2b, 0xbe, 0xd5, 0x9f, 0x2c, 0xed, 0xca, 0x9e, 0x17, 0x8b, 0x5e, 0x10, 0x0f, 0x3e,
+	0x4d, 0x53, 0x64, 0x1f, 0xcf, 0x35, 0x65, 0xe2, 0xc7, 0x22, 0xa4, 0x4f, 0x2c, 0x64, 0x97, 0x1b,
+	0xeb, 0x5a, 0xf1, 0x15, 0x37, 0x97, 0x0c, 0xb0, 0x70, 0x91, 0xe7, 0xb8, 0x7c, 0x3e, 0x08, 0x04,
+	0x9d, 0xb1, 0x90, 0x5d, 0x69, 0x10, 0xad, 0xf8, 0x52, 0x3c, 0x08, 0xcc, 0x3d, 0xa0, 0x9e, 0x05,
+	0x9f, 0x3a, 0x83, 0xae, 0x74, 0xda, 0xb4, 0x6c, 0x21, 0x7b, 0x21, 0x0f, 0x0e, 0x72, 0xc9, 0x0c,
+	0x1e, 0xb9, 0xc8, 0x0b, 0x5c, 0x39, 0x97, 0x81, 0xe7, 0xd2, 0x8a, 0x85, 0xec, 0x5a, 0x63, 0x55,
+	0x2b, 0x5e, 0x8f, 0x33, 0xc1, 0x30, 0xe7, 0x8e, 0x2c, 0xfb, 0xb3, 0x08, 0x23, 0x4f, 0xfa, 0x74,
+	0xd6, 0x42, 0xf6, 0x62, 0x9e, 0x7d, 0x9d, 0x4b, 0x66, 0xf6, 0xc8, 0x45, 0x4e, 0xf0, 0x4a, 0x76,
+	0xf0, 0x66, 0xd2, 0x4b, 0xba, 0x4e, 0xec, 0x5d, 0x8b, 0x43, 0xf7, 0x8a, 0xce, 0xc1, 0xc0, 0xb8,
+	0x56, 0x7c, 0xa7, 0x3f, 0x5d, 0x34, 0x42, 0x1e, 0x93, 0x64, 0x17, 0xcf, 0x42, 0x23, 0x11, 0xad,
+	0x5a, 0x33, 0x76, 0xad, 0xb1, 0xa6, 0x15, 0x5f, 0x86, 0x5e, 0x23, 0x03, 0x1c, 0x79, 0xc8, 0x21,
+	0x5e, 0x6c, 0xca, 0x30, 0x14, 0x19, 0x2f, 0xfd, 0xe3, 0x23, 0x5a, 0x83, 0x41, 0xef, 0x68, 0xc5,
+	0x37, 0x5d, 0xb3, 0x60, 0xb0, 0x93, 0x04, 0x79, 0x83, 0x6b, 0xef, 0xc3, 0x50, 0x86, 0x4d, 0xd9,
+	0x16, 0x14, 0xc3, 0xe4, 0x37, 0xb5, 0xe2, 0xab, 0xa2, 0x10, 0x0d, 0xf4, 0xc1, 0x49, 0x0e, 0xf0,
+	0x02, 0x2c, 0x46, 0xb7, 0x86, 0xce, 0xc3, 0x64, 0xb7, 0xb5, 0xe2, 0x1b, 0xc2, 0xd0, 0x0d, 0x78,
+	0xc2, 0x4f, 0xde, 0xe1, 0xf9, 0xa6, 0xec, 0x05, 0xa1, 0x88, 0x60, 0xd6, 0x0b, 0xb0, 0xf1, 0x96,
+	0x56, 0x7c, 0xdd, 0x7d, 0x90, 0x0d, 0xda, 0x74, 0x67, 0x3d, 0x9f, 0xe5, 0x9f, 0xc7, 0x47, 0x74,
+	0x11, 0x76, 0x86, 0x9e, 0xa3, 0x42, 0x34, 0x7b, 0x1e, 0x3b, 0xc9, 0x2b, 0x5c, 0x3d, 0x13, 0xdf,
+	0x12, 0xe1, 0xbb, 0x82, 0x2e, 0xc1, 0xa0, 0x36, 0xb4, 0xe2, 0x24, 0x1a, 0x69, 0x06, 0x34, 0xf6,
+	0x91, 0xb7, 0x18, 0x37, 0x2f, 0x13, 0xff, 0xea, 0xd8, 0x6f, 0x8b, 0xef, 0xb4, 0x0e, 0x57, 0x82,
+	0x6a, 0xc5, 0xd7, 0xdc, 0xb1, 0x6a, 0x70, 0x86, 0x77, 0x4c, 0x9e, 0xcb, 0xd8, 0xe9, 0xd2, 0xe5,
+	0x29, 0x12, 0xd4, 0x47, 0x24, 0xa8, 0x70, 0x3c, 0xaf, 0xe3, 0x3b, 0x71, 0x12, 0x0a, 0xba, 0x02,
+	0x37, 0x3c, 0x3f, 0x5e, 0x21, 0x4e, 0x1c, 0xaf, 0x10, 0xb3, 0x87, 0x9b, 0x2d, 0x44, 0x78, 0x9a,
+	0xb4, 0xba, 0x9e, 0xfb, 0x51, 0x0c, 0x28, 0x01, 0x18, 0x1e, 0x6e, 0x34, 0x59, 0x32, 0x1f, 0xee,
+	0x14, 0xd5, 0x38, 0xb8, 0xb9, 0x63, 0xa5, 0xdb, 0x3b, 0x56, 0xba, 0xbf, 0x63, 0xe8, 0x47, 0xca,
+	0xd0, 0xef, 0x94, 0xa1, 0x3f, 0x29, 0x43, 0x37, 0x29, 0x43, 0xb7, 0x29, 0x43, 0x7f, 0x53, 0x86,
+	0xfe, 0xa5, 0xac, 0x74, 0x9f, 0x32, 0xf4, 0x6b, 0xc8, 0x4a, 0x37, 0x43, 0x56, 0xba, 0x1d, 0xb2,
+	0xd2, 0x97, 0x72, 0xdb, 0x89, 0x9d, 0xd6, 0x2c, 0xfc, 0xad, 0xbc, 0xfe, 0x3f, 0x00, 0x08, 0x44,
+	0x47, 0x22, 0x9f, 0x04, 0x00, 0x00,
 }
 
 func (this *WsMessage) Equal(that interface{}) bool {
@@ -174,13 +288,54 @@ func (this *WsMessage) Equal(that interface{}) bool {
 	if this.Version != that1.Version {
 		return false
 	}
+	if this.WithCumulativeAck != that1.WithCumulativeAck {
+		return false
+	}
+	if len(this.Topics) != len(that1.Topics) {
+		return false
+	}
+	for i := range this.Topics {
+		if this.Topics[i] != that1.Topics[i] {
+			return false
+		}
+	}
+	if this.CorrelationID != that1.CorrelationID {
+		return false
+	}
+	if this.ErrorCode != that1.ErrorCode {
+		return false
+	}
+	if this.ErrorMessage != that1.ErrorMessage {
+		return false
+	}
+	if this.Compression != that1.Compression {
+		return false
+	}
+	if this.SessionID != that1.SessionID {
+		return false
+	}
+	if this.Sequence != that1.Sequence {
+		return false
+	}
+	if this.ChunkIndex != that1.ChunkIndex {
+		return false
+	}
+	if this.ChunkTotal != that1.ChunkTotal {
+		return false
+	}
+	if !bytes.Equal(this.Signature, that1.Signature) {
+		return false
+	}
+	if !bytes.Equal(this.SignerPublicKey, that1.SignerPublicKey) {
+		return false
+	}
 	return true
 }
 func (this *WsMessage) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 10)
+	s := make([]string, 0, 22)
 	s = append(s, "&data.WsMessage{")
 	s = append(s, "WithAcknowledge: "+fmt.Sprintf("%#v", this.WithAcknowledge)+",\n")
 	s = append(s, "Counter: "+fmt.Sprintf("%#v", this.Counter)+",\n")
@@ -188,6 +343,18 @@ func (this *WsMessage) GoString() string {
 	s = append(s, "Payload: "+fmt.Sprintf("%#v", this.Payload)+",\n")
 	s = append(s, "Topic: "+fmt.Sprintf("%#v", this.Topic)+",\n")
 	s = append(s, "Version: "+fmt.Sprintf("%#v", this.Version)+",\n")
+	s = append(s, "WithCumulativeAck: "+fmt.Sprintf("%#v", this.WithCumulativeAck)+",\n")
+	s = append(s, "Topics: "+fmt.Sprintf("%#v", this.Topics)+",\n")
+	s = append(s, "CorrelationID: "+fmt.Sprintf("%#v", this.CorrelationID)+",\n")
+	s = append(s, "ErrorCode: "+fmt.Sprintf("%#v", this.ErrorCode)+",\n")
+	s = append(s, "ErrorMessage: "+fmt.Sprintf("%#v", this.ErrorMessage)+",\n")
+	s = append(s, "Compression: "+fmt.Sprintf("%#v", this.Compression)+",\n")
+	s = append(s, "SessionID: "+fmt.Sprintf("%#v", this.SessionID)+",\n")
+	s = append(s, "Sequence: "+fmt.Sprintf("%#v", this.Sequence)+",\n")
+	s = append(s, "ChunkIndex: "+fmt.Sprintf("%#v", this.ChunkIndex)+",\n")
+	s = append(s, "ChunkTotal: "+fmt.Sprintf("%#v", this.ChunkTotal)+",\n")
+	s = append(s, "Signature: "+fmt.Sprintf("%#v", this.Signature)+",\n")
+	s = append(s, "SignerPublicKey: "+fmt.Sprintf("%#v", this.SignerPublicKey)+",\n")
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
@@ -219,6 +386,89 @@ func (m *WsMessage) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.SignerPublicKey) > 0 {
+		i -= len(m.SignerPublicKey)
+		copy(dAtA[i:], m.SignerPublicKey)
+		i = encodeVarintWsMessage(dAtA, i, uint64(len(m.SignerPublicKey)))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x92
+	}
+	if len(m.Signature) > 0 {
+		i -= len(m.Signature)
+		copy(dAtA[i:], m.Signature)
+		i = encodeVarintWsMessage(dAtA, i, uint64(len(m.Signature)))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x8a
+	}
+	if m.ChunkTotal != 0 {
+		i = encodeVarintWsMessage(dAtA, i, uint64(m.ChunkTotal))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x80
+	}
+	if m.ChunkIndex != 0 {
+		i = encodeVarintWsMessage(dAtA, i, uint64(m.ChunkIndex))
+		i--
+		dAtA[i] = 0x78
+	}
+	if m.Sequence != 0 {
+		i = encodeVarintWsMessage(dAtA, i, uint64(m.Sequence))
+		i--
+		dAtA[i] = 0x70
+	}
+	if len(m.SessionID) > 0 {
+		i -= len(m.SessionID)
+		copy(dAtA[i:], m.SessionID)
+		i = encodeVarintWsMessage(dAtA, i, uint64(len(m.SessionID)))
+		i--
+		dAtA[i] = 0x6a
+	}
+	if m.Compression != 0 {
+		i = encodeVarintWsMessage(dAtA, i, uint64(m.Compression))
+		i--
+		dAtA[i] = 0x60
+	}
+	if len(m.ErrorMessage) > 0 {
+		i -= len(m.ErrorMessage)
+		copy(dAtA[i:], m.ErrorMessage)
+		i = encodeVarintWsMessage(dAtA, i, uint64(len(m.ErrorMessage)))
+		i--
+		dAtA[i] = 0x5a
+	}
+	if m.ErrorCode != 0 {
+		i = encodeVarintWsMessage(dAtA, i, uint64(m.ErrorCode))
+		i--
+		dAtA[i] = 0x50
+	}
+	if m.CorrelationID != 0 {
+		i = encodeVarintWsMessage(dAtA, i, uint64(m.CorrelationID))
+		i--
+		dAtA[i] = 0x48
+	}
+	if len(m.Topics) > 0 {
+		for iNdEx := len(m.Topics) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Topics[iNdEx])
+			copy(dAtA[i:], m.Topics[iNdEx])
+			i = encodeVarintWsMessage(dAtA, i, uint64(len(m.Topics[iNdEx])))
+			i--
+			dAtA[i] = 0x42
+		}
+	}
+	if m.WithCumulativeAck {
+		i--
+		if m.WithCumulativeAck {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x38
+	}
 	if m.Version != 0 {
 		i = encodeVarintWsMessage(dAtA, i, uint64(m.Version))
 		i--
@@ -298,6 +548,49 @@ func (m *WsMessage) Size() (n int) {
 	if m.Version != 0 {
 		n += 1 + sovWsMessage(uint64(m.Version))
 	}
+	if m.WithCumulativeAck {
+		n += 2
+	}
+	if len(m.Topics) > 0 {
+		for _, s := range m.Topics {
+			l = len(s)
+			n += 1 + l + sovWsMessage(uint64(l))
+		}
+	}
+	if m.CorrelationID != 0 {
+		n += 1 + sovWsMessage(uint64(m.CorrelationID))
+	}
+	if m.ErrorCode != 0 {
+		n += 1 + sovWsMessage(uint64(m.ErrorCode))
+	}
+	l = len(m.ErrorMessage)
+	if l > 0 {
+		n += 1 + l + sovWsMessage(uint64(l))
+	}
+	if m.Compression != 0 {
+		n += 1 + sovWsMessage(uint64(m.Compression))
+	}
+	l = len(m.SessionID)
+	if l > 0 {
+		n += 1 + l + sovWsMessage(uint64(l))
+	}
+	if m.Sequence != 0 {
+		n += 1 + sovWsMessage(uint64(m.Sequence))
+	}
+	if m.ChunkIndex != 0 {
+		n += 1 + sovWsMessage(uint64(m.ChunkIndex))
+	}
+	if m.ChunkTotal != 0 {
+		n += 2 + sovWsMessage(uint64(m.ChunkTotal))
+	}
+	l = len(m.Signature)
+	if l > 0 {
+		n += 2 + l + sovWsMessage(uint64(l))
+	}
+	l = len(m.SignerPublicKey)
+	if l > 0 {
+		n += 2 + l + sovWsMessage(uint64(l))
+	}
 	return n
 }
 
@@ -318,6 +611,18 @@ func (this *WsMessage) String() string {
 		`Payload:` + fmt.Sprintf("%v", this.Payload) + `,`,
 		`Topic:` + fmt.Sprintf("%v", this.Topic) + `,`,
 		`Version:` + fmt.Sprintf("%v", this.Version) + `,`,
+		`WithCumulativeAck:` + fmt.Sprintf("%v", this.WithCumulativeAck) + `,`,
+		`Topics:` + fmt.Sprintf("%v", this.Topics) + `,`,
+		`CorrelationID:` + fmt.Sprintf("%v", this.CorrelationID) + `,`,
+		`ErrorCode:` + fmt.Sprintf("%v", this.ErrorCode) + `,`,
+		`ErrorMessage:` + fmt.Sprintf("%v", this.ErrorMessage) + `,`,
+		`Compression:` + fmt.Sprintf("%v", this.Compression) + `,`,
+		`SessionID:` + fmt.Sprintf("%v", this.SessionID) + `,`,
+		`Sequence:` + fmt.Sprintf("%v", this.Sequence) + `,`,
+		`ChunkIndex:` + fmt.Sprintf("%v", this.ChunkIndex) + `,`,
+		`ChunkTotal:` + fmt.Sprintf("%v", this.ChunkTotal) + `,`,
+		`Signature:` + fmt.Sprintf("%v", this.Signature) + `,`,
+		`SignerPublicKey:` + fmt.Sprintf("%v", this.SignerPublicKey) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -502,6 +807,304 @@ func (m *WsMessage) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field WithCumulativeAck", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowWsMessage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.WithCumulativeAck = bool(v != 0)
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Topics", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowWsMessage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthWsMessage
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthWsMessage
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Topics = append(m.Topics, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 9:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CorrelationID", wireType)
+			}
+			m.CorrelationID = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowWsMessage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.CorrelationID |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 10:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ErrorCode", wireType)
+			}
+			m.ErrorCode = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowWsMessage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ErrorCode |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ErrorMessage", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowWsMessage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthWsMessage
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthWsMessage
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ErrorMessage = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 12:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Compression", wireType)
+			}
+			m.Compression = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowWsMessage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Compression |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 13:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SessionID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowWsMessage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthWsMessage
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthWsMessage
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.SessionID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 14:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sequence", wireType)
+			}
+			m.Sequence = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowWsMessage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Sequence |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 15:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ChunkIndex", wireType)
+			}
+			m.ChunkIndex = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowWsMessage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ChunkIndex |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 16:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ChunkTotal", wireType)
+			}
+			m.ChunkTotal = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowWsMessage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ChunkTotal |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 17:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Signature", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowWsMessage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthWsMessage
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthWsMessage
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Signature = append(m.Signature[:0], dAtA[iNdEx:postIndex]...)
+			if m.Signature == nil {
+				m.Signature = []byte{}
+			}
+			iNdEx = postIndex
+		case 18:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SignerPublicKey", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowWsMessage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthWsMessage
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthWsMessage
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.SignerPublicKey = append(m.SignerPublicKey[:0], dAtA[iNdEx:postIndex]...)
+			if m.SignerPublicKey == nil {
+				m.SignerPublicKey = []byte{}
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipWsMessage(dAtA[iNdEx:])
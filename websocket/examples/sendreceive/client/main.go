@@ -1,12 +1,15 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
 
 	"github.com/subrahamanyam341/andes-communication/testscommon"
+	"github.com/subrahamanyam341/andes-communication/websocket"
+	"github.com/subrahamanyam341/andes-communication/websocket/capture"
 	"github.com/subrahamanyam341/andes-communication/websocket/data"
 	factoryHost "github.com/subrahamanyam341/andes-communication/websocket/factory"
 	"github.com/subrahamanyam341/andes-core-16/marshal/factory"
@@ -17,9 +20,11 @@ var (
 	marshaller, _ = factory.NewMarshalizer("json")
 	log           = logger.GetOrCreate("client")
 	url           = ":12345"
+	recordPath    = flag.String("record", "", "file where the received payloads are captured, so they can be replayed later")
 )
 
 func main() {
+	flag.Parse()
 	_ = logger.SetLogLevel("*:DEBUG")
 	args := factoryHost.ArgsWebSocketHost{
 		WebSocketConfig: data.WebSocketConfig{
@@ -45,12 +50,25 @@ func main() {
 		_ = wsClient.Close()
 	}()
 
-	err = wsClient.SetPayloadHandler(&testscommon.PayloadHandlerStub{
+	var handler websocket.PayloadHandler = &testscommon.PayloadHandlerStub{
 		ProcessPayloadCalled: func(payload []byte, topic string, version uint32) error {
 			log.Info("received", "topic", topic, "payload", string(payload), "version", fmt.Sprint(version))
 			return nil
 		},
-	})
+	}
+	if *recordPath != "" {
+		handler, err = capture.NewRecorder(capture.ArgsRecorder{
+			Path:    *recordPath,
+			Handler: handler,
+			Log:     log,
+		})
+		if err != nil {
+			log.Error("cannot create the capture recorder", "error", err)
+			return
+		}
+	}
+
+	err = wsClient.SetPayloadHandler(handler)
 	log.LogIfError(err)
 
 	interrupt := make(chan os.Signal, 1)
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/subrahamanyam341/andes-communication/websocket/capture"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	factoryHost "github.com/subrahamanyam341/andes-communication/websocket/factory"
+	"github.com/subrahamanyam341/andes-core-16/marshal/factory"
+	logger "github.com/subrahamanyam341/andes-logger-123"
+)
+
+var (
+	marshaller, _ = factory.NewMarshalizer("json")
+	log           = logger.GetOrCreate("replayer")
+	url           = "localhost:12345"
+	capturePath   = flag.String("file", "", "capture file recorded by the client")
+	speed         = flag.Float64("speed", 1, "replay speed: 1 keeps the original pace, 2 is twice as fast, 0 is as fast as possible")
+)
+
+func main() {
+	flag.Parse()
+
+	args := factoryHost.ArgsWebSocketHost{
+		WebSocketConfig: data.WebSocketConfig{
+			URL:                        url,
+			Mode:                       data.ModeServer,
+			RetryDurationInSec:         1,
+			WithAcknowledge:            true,
+			BlockingAckOnError:         false,
+			DropMessagesIfNoConnection: false,
+			AcknowledgeTimeoutInSec:    10,
+		},
+		Marshaller: marshaller,
+		Log:        log,
+	}
+
+	captureReader, err := capture.NewReader(*capturePath)
+	if err != nil {
+		log.Error("cannot open the capture file", "error", err)
+		return
+	}
+	defer func() {
+		_ = captureReader.Close()
+	}()
+
+	wsServer, err := factoryHost.CreateWebSocketHost(args)
+	if err != nil {
+		log.Error("cannot create WebSocket server", "error", err)
+		return
+	}
+	defer func() {
+		err = wsServer.Close()
+		log.LogIfError(err)
+	}()
+
+	replayer, err := capture.NewReplayer(capture.ArgsReplayer{
+		Reader: captureReader,
+		Sender: wsServer,
+		Speed:  *speed,
+		Log:    log,
+	})
+	if err != nil {
+		log.Error("cannot create the replayer", "error", err)
+		return
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	numSent, err := replayer.Replay(ctx)
+	log.Info("replay finished", "messages sent", numSent)
+	log.LogIfError(err)
+}
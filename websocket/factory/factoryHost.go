@@ -1,9 +1,15 @@
 package factory
 
 import (
+	"crypto/tls"
+	"time"
+
 	"github.com/subrahamanyam341/andes-communication/websocket"
 	"github.com/subrahamanyam341/andes-communication/websocket/client"
 	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	"github.com/subrahamanyam341/andes-communication/websocket/queue"
+	"github.com/subrahamanyam341/andes-communication/websocket/reconnect"
+	"github.com/subrahamanyam341/andes-communication/websocket/relay"
 	"github.com/subrahamanyam341/andes-communication/websocket/server"
 	"github.com/subrahamanyam341/andes-core-16/core"
 	"github.com/subrahamanyam341/andes-core-16/marshal"
@@ -11,9 +17,15 @@ import (
 
 // ArgsWebSocketHost holds all the arguments needed in order to create a FullDuplexHost
 type ArgsWebSocketHost struct {
-	WebSocketConfig data.WebSocketConfig
-	Marshaller      marshal.Marshalizer
-	Log             core.Logger
+	WebSocketConfig        data.WebSocketConfig
+	Marshaller             marshal.Marshalizer
+	Log                    core.Logger
+	HandshakeAuthenticator websocket.HandshakeAuthenticator
+	CredentialsProvider    websocket.CredentialsProvider
+	Metrics                websocket.MetricsHandler
+	UpstreamMetrics        websocket.MetricsHandler
+	MessageSigner          websocket.MessageSigner
+	MessageVerifier        websocket.MessageVerifier
 }
 
 // CreateWebSocketHost will create and start a new instance of factory.FullDuplexHost
@@ -23,6 +35,8 @@ func CreateWebSocketHost(args ArgsWebSocketHost) (FullDuplexHost, error) {
 		return createWebSocketServer(args)
 	case data.ModeClient:
 		return createWebSocketClient(args)
+	case data.ModeRelay:
+		return createWebSocketRelay(args)
 	default:
 		return nil, data.ErrInvalidWebSocketHostMode
 	}
@@ -34,17 +48,67 @@ func createWebSocketClient(args ArgsWebSocketHost) (FullDuplexHost, error) {
 		return nil, err
 	}
 
-	return client.NewWebSocketClient(client.ArgsWebSocketClient{
+	tlsConfig, err := createClientTLSConfig(args.WebSocketConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	outboundQueue, err := createOutboundQueue(args)
+	if err != nil {
+		return nil, err
+	}
+
+	reconnectPolicy, err := createReconnectPolicy(args.WebSocketConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	argsClient := client.ArgsWebSocketClient{
 		RetryDurationInSeconds:     args.WebSocketConfig.RetryDurationInSec,
 		WithAcknowledge:            args.WebSocketConfig.WithAcknowledge,
 		URL:                        args.WebSocketConfig.URL,
+		URLs:                       args.WebSocketConfig.URLs,
+		EndpointSelection:          args.WebSocketConfig.EndpointSelection,
+		Codec:                      args.WebSocketConfig.Codec,
 		PayloadConverter:           payloadConverter,
 		Log:                        args.Log,
 		BlockingAckOnError:         args.WebSocketConfig.BlockingAckOnError,
 		DropMessagesIfNoConnection: args.WebSocketConfig.DropMessagesIfNoConnection,
 		AckTimeoutInSeconds:        args.WebSocketConfig.AcknowledgeTimeoutInSec,
+		AckWindowSize:              args.WebSocketConfig.AckWindowSize,
 		PayloadVersion:             args.WebSocketConfig.Version,
-	})
+		MinPayloadVersion:          args.WebSocketConfig.MinVersion,
+		TLSConfig:                  tlsConfig,
+		CredentialsProvider:        args.CredentialsProvider,
+		MessageSigner:              args.MessageSigner,
+		MessageVerifier:            args.MessageVerifier,
+		OutboundQueue:              outboundQueue,
+		SubscribedTopics:           args.WebSocketConfig.SubscribedTopics,
+		EnablePerMessageDeflate:    args.WebSocketConfig.PerMessageDeflate,
+		PayloadCompression:         args.WebSocketConfig.PayloadCompression,
+		CompressionThreshold:       args.WebSocketConfig.CompressionThreshold,
+		PingIntervalInSeconds:      args.WebSocketConfig.PingIntervalInSec,
+		PongTimeoutInSeconds:       args.WebSocketConfig.PongTimeoutInSec,
+		RoutePath:                  args.WebSocketConfig.RoutePath,
+		ReadBufferSize:             args.WebSocketConfig.ReadBufferSizeInBytes,
+		WriteBufferSize:            args.WebSocketConfig.WriteBufferSizeInBytes,
+		MaxMessageSize:             args.WebSocketConfig.MaxMessageSizeInBytes,
+		WriteTimeoutInSeconds:      args.WebSocketConfig.WriteTimeoutInSec,
+		HandshakeTimeoutInSeconds:  args.WebSocketConfig.HandshakeTimeoutInSec,
+		ReconnectPolicy:            reconnectPolicy,
+		Metrics:                    args.Metrics,
+		Transport:                  args.WebSocketConfig.Transport,
+		MemoryTransport:            args.WebSocketConfig.MemoryTransport,
+		ChunkSizeInBytes:           args.WebSocketConfig.ChunkSizeInBytes,
+		MaxReassemblySizeInBytes:   args.WebSocketConfig.MaxReassemblySizeInBytes,
+		ReassemblyTimeoutInSeconds: args.WebSocketConfig.ReassemblyTimeoutInSec,
+		MaxConcurrentRequests:      args.WebSocketConfig.MaxConcurrentRequests,
+	}
+	if args.WebSocketConfig.EndpointSelection == data.FanOutEndpointSelection {
+		return client.NewFanOutClient(argsClient)
+	}
+
+	return client.NewWebSocketClient(argsClient)
 }
 
 func createWebSocketServer(args ArgsWebSocketHost) (FullDuplexHost, error) {
@@ -53,6 +117,16 @@ func createWebSocketServer(args ArgsWebSocketHost) (FullDuplexHost, error) {
 		return nil, err
 	}
 
+	tlsConfig, err := createServerTLSConfig(args.WebSocketConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	outboundQueue, err := createOutboundQueue(args)
+	if err != nil {
+		return nil, err
+	}
+
 	host, err := server.NewWebSocketServer(server.ArgsWebSocketServer{
 		RetryDurationInSeconds:     args.WebSocketConfig.RetryDurationInSec,
 		WithAcknowledge:            args.WebSocketConfig.WithAcknowledge,
@@ -62,7 +136,37 @@ func createWebSocketServer(args ArgsWebSocketHost) (FullDuplexHost, error) {
 		BlockingAckOnError:         args.WebSocketConfig.BlockingAckOnError,
 		DropMessagesIfNoConnection: args.WebSocketConfig.DropMessagesIfNoConnection,
 		AckTimeoutInSeconds:        args.WebSocketConfig.AcknowledgeTimeoutInSec,
+		AckWindowSize:              args.WebSocketConfig.AckWindowSize,
 		PayloadVersion:             args.WebSocketConfig.Version,
+		MinPayloadVersion:          args.WebSocketConfig.MinVersion,
+		TLSConfig:                  tlsConfig,
+		HandshakeAuthenticator:     args.HandshakeAuthenticator,
+		MessageSigner:              args.MessageSigner,
+		MessageVerifier:            args.MessageVerifier,
+		OutboundQueue:              outboundQueue,
+		EnablePerMessageDeflate:    args.WebSocketConfig.PerMessageDeflate,
+		PayloadCompression:         args.WebSocketConfig.PayloadCompression,
+		CompressionThreshold:       args.WebSocketConfig.CompressionThreshold,
+		PingIntervalInSeconds:      args.WebSocketConfig.PingIntervalInSec,
+		PongTimeoutInSeconds:       args.WebSocketConfig.PongTimeoutInSec,
+		RoutePath:                  args.WebSocketConfig.RoutePath,
+		ReadBufferSize:             args.WebSocketConfig.ReadBufferSizeInBytes,
+		WriteBufferSize:            args.WebSocketConfig.WriteBufferSizeInBytes,
+		MaxMessageSize:             args.WebSocketConfig.MaxMessageSizeInBytes,
+		WriteTimeoutInSeconds:      args.WebSocketConfig.WriteTimeoutInSec,
+		HandshakeTimeoutInSeconds:  args.WebSocketConfig.HandshakeTimeoutInSec,
+		ClientQueueSize:            args.WebSocketConfig.ClientQueueSize,
+		ClientQueueOverflowPolicy:  args.WebSocketConfig.ClientQueueOverflowPolicy,
+		Metrics:                    args.Metrics,
+		MetricsRoutePath:           args.WebSocketConfig.MetricsRoutePath,
+		HealthEndpoints:            args.WebSocketConfig.HealthEndpoints,
+		Transport:                  args.WebSocketConfig.Transport,
+		MemoryTransport:            args.WebSocketConfig.MemoryTransport,
+		ChunkSizeInBytes:           args.WebSocketConfig.ChunkSizeInBytes,
+		MaxReassemblySizeInBytes:   args.WebSocketConfig.MaxReassemblySizeInBytes,
+		ReassemblyTimeoutInSeconds: args.WebSocketConfig.ReassemblyTimeoutInSec,
+		MaxConcurrentRequests:      args.WebSocketConfig.MaxConcurrentRequests,
+		RateLimit:                  args.WebSocketConfig.RateLimit,
 	})
 	if err != nil {
 		return nil, err
@@ -70,3 +174,116 @@ func createWebSocketServer(args ArgsWebSocketHost) (FullDuplexHost, error) {
 
 	return host, nil
 }
+
+// createWebSocketRelay creates a server for the downstream clients and a client connected to the upstream server. The
+// outbound queue, the handshake authenticator, the message signer and the metrics apply to the downstream side, while
+// the credentials provider, the message verifier and the upstream metrics apply to the upstream side
+func createWebSocketRelay(args ArgsWebSocketHost) (FullDuplexHost, error) {
+	if args.WebSocketConfig.Relay.ListenURL == "" {
+		return nil, data.ErrEmptyRelayListenURL
+	}
+	if args.WebSocketConfig.Relay.AckQuorum < 0 {
+		return nil, data.ErrInvalidAckQuorum
+	}
+	// a payload not acknowledged by the downstream quorum must not be acknowledged upstream either
+	if !args.WebSocketConfig.BlockingAckOnError {
+		return nil, data.ErrRelayWithoutBlockingAckOnError
+	}
+
+	downstreamArgs := args
+	downstreamArgs.WebSocketConfig.URL = args.WebSocketConfig.Relay.ListenURL
+	downstreamArgs.CredentialsProvider = nil
+	downstreamArgs.MessageVerifier = nil
+	// the forwarded payloads keep the signature of their origin, the signer of the relay signing only the unsigned ones
+	downstream, err := createWebSocketServer(downstreamArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	upstreamArgs := args
+	upstreamArgs.Metrics = args.UpstreamMetrics
+	upstreamArgs.WebSocketConfig.OutboundQueue = data.OutboundQueueConfig{}
+	upstreamArgs.HandshakeAuthenticator = nil
+	upstreamArgs.MessageSigner = nil
+	upstream, err := createWebSocketClient(upstreamArgs)
+	if err != nil {
+		_ = downstream.Close()
+		return nil, err
+	}
+
+	host, err := relay.NewRelay(relay.ArgsRelay{
+		Upstream:   upstream,
+		Downstream: downstream,
+		AckQuorum:  args.WebSocketConfig.Relay.AckQuorum,
+		Log:        args.Log,
+	})
+	if err != nil {
+		_ = upstream.Close()
+		_ = downstream.Close()
+		return nil, err
+	}
+
+	return host, nil
+}
+
+func createClientTLSConfig(config data.WebSocketConfig) (*tls.Config, error) {
+	if !config.UseTLS {
+		return nil, nil
+	}
+
+	return websocket.NewClientTLSConfig(createArgsTLSConfig(config))
+}
+
+func createServerTLSConfig(config data.WebSocketConfig) (*tls.Config, error) {
+	if !config.UseTLS {
+		return nil, nil
+	}
+
+	return websocket.NewServerTLSConfig(createArgsTLSConfig(config))
+}
+
+func createArgsTLSConfig(config data.WebSocketConfig) websocket.ArgsTLSConfig {
+	return websocket.ArgsTLSConfig{
+		CertificateFile:      config.TLSCertificateFile,
+		KeyFile:              config.TLSKeyFile,
+		CACertificateFile:    config.TLSCACertificateFile,
+		MutualAuthentication: config.TLSMutualAuthentication,
+	}
+}
+
+func createOutboundQueue(args ArgsWebSocketHost) (websocket.OutboundQueue, error) {
+	config := args.WebSocketConfig.OutboundQueue
+	if !config.Enabled {
+		return nil, nil
+	}
+
+	return queue.NewDiskQueue(queue.ArgsDiskQueue{
+		Path:           config.Path,
+		MaxMessages:    config.MaxMessages,
+		MaxSizeInBytes: config.MaxSizeInBytes,
+		MaxAgeInSec:    config.MaxAgeInSec,
+		Log:            args.Log,
+	})
+}
+
+func createReconnectPolicy(config data.WebSocketConfig) (websocket.ReconnectPolicy, error) {
+	if config.RetryDurationInSec == 0 {
+		return nil, data.ErrZeroValueRetryDuration
+	}
+	retryDuration := time.Duration(config.RetryDurationInSec) * time.Second
+
+	switch config.Reconnect.Policy {
+	case "", data.FixedReconnectPolicy:
+		return reconnect.NewFixedPolicy(retryDuration, config.Reconnect.MaxAttempts)
+	case data.ExponentialReconnectPolicy:
+		return reconnect.NewExponentialPolicy(reconnect.ArgsExponentialPolicy{
+			InitialDelay: retryDuration,
+			MaxDelay:     time.Duration(config.Reconnect.MaxDelayInSec) * time.Second,
+			Multiplier:   2,
+			Jitter:       float64(config.Reconnect.JitterPercentage) / 100,
+			MaxAttempts:  config.Reconnect.MaxAttempts,
+		})
+	default:
+		return nil, data.ErrUnknownReconnectPolicy
+	}
+}
@@ -41,3 +41,188 @@ func TestCreateServer(t *testing.T) {
 	require.Nil(t, err)
 	require.Equal(t, "*server.server", fmt.Sprintf("%T", webSocketsClient))
 }
+
+func TestCreateHostWithTLS(t *testing.T) {
+	t.Parallel()
+
+	t.Run("server without certificate, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.WebSocketConfig.Mode = data.ModeServer
+		args.WebSocketConfig.UseTLS = true
+		webSocketsServer, err := CreateWebSocketHost(args)
+		require.Nil(t, webSocketsServer)
+		require.Equal(t, data.ErrEmptyTLSCertificateFile, err)
+	})
+
+	t.Run("client with mutual authentication and without certificate, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.WebSocketConfig.UseTLS = true
+		args.WebSocketConfig.TLSMutualAuthentication = true
+		webSocketsClient, err := CreateWebSocketHost(args)
+		require.Nil(t, webSocketsClient)
+		require.Equal(t, data.ErrEmptyTLSCertificateFile, err)
+	})
+
+	t.Run("client should work", func(t *testing.T) {
+		args := createArgs()
+		args.WebSocketConfig.UseTLS = true
+		webSocketsClient, err := CreateWebSocketHost(args)
+		require.Nil(t, err)
+		require.Equal(t, "*client.client", fmt.Sprintf("%T", webSocketsClient))
+		_ = webSocketsClient.Close()
+	})
+}
+
+func TestCreateClientWithReconnectPolicy(t *testing.T) {
+	t.Parallel()
+
+	t.Run("unknown policy, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.WebSocketConfig.Reconnect.Policy = "linear"
+		webSocketsClient, err := CreateWebSocketHost(args)
+		require.Nil(t, webSocketsClient)
+		require.Equal(t, data.ErrUnknownReconnectPolicy, err)
+	})
+
+	t.Run("invalid jitter, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.WebSocketConfig.Reconnect.Policy = data.ExponentialReconnectPolicy
+		args.WebSocketConfig.Reconnect.JitterPercentage = 150
+		webSocketsClient, err := CreateWebSocketHost(args)
+		require.Nil(t, webSocketsClient)
+		require.Equal(t, data.ErrInvalidReconnectJitter, err)
+	})
+
+	t.Run("exponential policy should work", func(t *testing.T) {
+		args := createArgs()
+		args.WebSocketConfig.Reconnect = data.ReconnectConfig{
+			Policy:           data.ExponentialReconnectPolicy,
+			MaxDelayInSec:    30,
+			JitterPercentage: 20,
+			MaxAttempts:      10,
+		}
+		webSocketsClient, err := CreateWebSocketHost(args)
+		require.Nil(t, err)
+		require.Equal(t, "*client.client", fmt.Sprintf("%T", webSocketsClient))
+		_ = webSocketsClient.Close()
+	})
+}
+
+func TestCreateClientWithEndpointSelection(t *testing.T) {
+	t.Parallel()
+
+	t.Run("unknown strategy, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.WebSocketConfig.EndpointSelection = "random"
+		webSocketsClient, err := CreateWebSocketHost(args)
+		require.Nil(t, webSocketsClient)
+		require.Equal(t, data.ErrUnknownEndpointSelection, err)
+	})
+
+	t.Run("round robin should work", func(t *testing.T) {
+		args := createArgs()
+		args.WebSocketConfig.URLs = []string{"localhost:1235"}
+		args.WebSocketConfig.EndpointSelection = data.RoundRobinEndpointSelection
+		webSocketsClient, err := CreateWebSocketHost(args)
+		require.Nil(t, err)
+		require.Equal(t, "*client.client", fmt.Sprintf("%T", webSocketsClient))
+		_ = webSocketsClient.Close()
+	})
+
+	t.Run("fan out should work", func(t *testing.T) {
+		args := createArgs()
+		args.WebSocketConfig.URLs = []string{"localhost:1235"}
+		args.WebSocketConfig.EndpointSelection = data.FanOutEndpointSelection
+		webSocketsClient, err := CreateWebSocketHost(args)
+		require.Nil(t, err)
+		require.Equal(t, "*client.fanOutClient", fmt.Sprintf("%T", webSocketsClient))
+		_ = webSocketsClient.Close()
+	})
+}
+
+func TestCreateHostWithMemoryTransport(t *testing.T) {
+	t.Parallel()
+
+	args := createArgs()
+	args.WebSocketConfig.URL = "memory-factory-test"
+	args.WebSocketConfig.Transport = data.MemoryTransport
+	args.WebSocketConfig.Mode = data.ModeServer
+	webSocketsServer, err := CreateWebSocketHost(args)
+	require.Nil(t, err)
+	defer func() {
+		_ = webSocketsServer.Close()
+	}()
+
+	args.WebSocketConfig.Mode = data.ModeClient
+	webSocketsClient, err := CreateWebSocketHost(args)
+	require.Nil(t, err)
+	require.Nil(t, webSocketsClient.Close())
+
+	args.WebSocketConfig.Transport = "quic"
+	webSocketsClient, err = CreateWebSocketHost(args)
+	require.Nil(t, webSocketsClient)
+	require.Equal(t, data.ErrUnknownTransport, err)
+}
+
+func TestCreateRelay(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty listen url, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.WebSocketConfig.Mode = data.ModeRelay
+		webSocketsRelay, err := CreateWebSocketHost(args)
+		require.Nil(t, webSocketsRelay)
+		require.Equal(t, data.ErrEmptyRelayListenURL, err)
+	})
+
+	t.Run("negative acknowledgement quorum, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.WebSocketConfig.Mode = data.ModeRelay
+		args.WebSocketConfig.Relay.ListenURL = "memory-relay-factory-test"
+		args.WebSocketConfig.Relay.AckQuorum = -1
+		webSocketsRelay, err := CreateWebSocketHost(args)
+		require.Nil(t, webSocketsRelay)
+		require.Equal(t, data.ErrInvalidAckQuorum, err)
+	})
+
+	t.Run("acknowledgement of the failed payloads, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.WebSocketConfig.Mode = data.ModeRelay
+		args.WebSocketConfig.Relay.ListenURL = "memory-relay-factory-test"
+		webSocketsRelay, err := CreateWebSocketHost(args)
+		require.Nil(t, webSocketsRelay)
+		require.Equal(t, data.ErrRelayWithoutBlockingAckOnError, err)
+	})
+
+	t.Run("invalid upstream config, should close the downstream server and return error", func(t *testing.T) {
+		args := createArgs()
+		args.WebSocketConfig.Mode = data.ModeRelay
+		args.WebSocketConfig.Transport = data.MemoryTransport
+		args.WebSocketConfig.Relay.ListenURL = "memory-relay-factory-test"
+		args.WebSocketConfig.BlockingAckOnError = true
+		args.WebSocketConfig.EndpointSelection = "random"
+		webSocketsRelay, err := CreateWebSocketHost(args)
+		require.Nil(t, webSocketsRelay)
+		require.NotNil(t, err)
+
+		// the listen address was released
+		args.WebSocketConfig.Mode = data.ModeServer
+		args.WebSocketConfig.URL = args.WebSocketConfig.Relay.ListenURL
+		webSocketsServer, err := CreateWebSocketHost(args)
+		require.Nil(t, err)
+		_ = webSocketsServer.Close()
+	})
+
+	t.Run("should work", func(t *testing.T) {
+		args := createArgs()
+		args.WebSocketConfig.Mode = data.ModeRelay
+		args.WebSocketConfig.Transport = data.MemoryTransport
+		args.WebSocketConfig.URL = "memory-relay-upstream"
+		args.WebSocketConfig.Relay.ListenURL = "memory-relay-downstream"
+		args.WebSocketConfig.BlockingAckOnError = true
+		webSocketsRelay, err := CreateWebSocketHost(args)
+		require.Nil(t, err)
+		require.Equal(t, "*relay.relay", fmt.Sprintf("%T", webSocketsRelay))
+		_ = webSocketsRelay.Close()
+	})
+}
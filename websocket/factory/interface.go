@@ -1,11 +1,18 @@
 package factory
 
-import "github.com/subrahamanyam341/andes-communication/websocket"
+import (
+	"context"
+
+	"github.com/subrahamanyam341/andes-communication/websocket"
+)
 
 // FullDuplexHost defines what a full duplex host should be able to do
 type FullDuplexHost interface {
 	Send(payload []byte, topic string) error
+	SendAsync(payload []byte, topic string) (websocket.AckFuture, error)
+	Request(ctx context.Context, topic string, payload []byte) ([]byte, error)
 	SetPayloadHandler(handler websocket.PayloadHandler) error
+	SetRequestHandler(handler websocket.RequestHandler) error
 	Close() error
 	IsInterfaceNil() bool
 }
@@ -0,0 +1,32 @@
+package websocket
+
+import (
+	"reflect"
+
+	"github.com/subrahamanyam341/andes-core-16/core"
+)
+
+// closeHandlersOnce closes the provided handlers in order, a handler registered more than once being closed only once.
+// It returns the last error
+func closeHandlersOnce(handlers []PayloadHandler, log core.Logger) error {
+	var lastErr error
+	closedHandlers := make(map[PayloadHandler]struct{})
+	for _, handler := range handlers {
+		// handlers of non comparable types can not be used as map keys, they are closed every time they are found
+		if reflect.TypeOf(handler).Comparable() {
+			_, alreadyClosed := closedHandlers[handler]
+			if alreadyClosed {
+				continue
+			}
+			closedHandlers[handler] = struct{}{}
+		}
+
+		err := handler.Close()
+		if err != nil {
+			log.Warn("closeHandlersOnce: cannot close handler", "error", err)
+			lastErr = err
+		}
+	}
+
+	return lastErr
+}
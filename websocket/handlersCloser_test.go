@@ -0,0 +1,54 @@
+package websocket
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/testscommon"
+)
+
+// nonComparableHandler can not be used as a map key, because of its slice field
+type nonComparableHandler struct {
+	numCloses *int
+	names     []string
+}
+
+func (handler nonComparableHandler) ProcessPayload(_ []byte, _ string, _ uint32) error {
+	return nil
+}
+
+func (handler nonComparableHandler) Close() error {
+	*handler.numCloses++
+	return nil
+}
+
+func (handler nonComparableHandler) IsInterfaceNil() bool {
+	return false
+}
+
+func TestCloseHandlersOnce(t *testing.T) {
+	t.Parallel()
+
+	numSharedCloses := 0
+	sharedHandler := &testscommon.PayloadHandlerStub{
+		CloseCalled: func() error {
+			numSharedCloses++
+			return nil
+		},
+	}
+	expectedErr := errors.New("expected error")
+	failingHandler := &testscommon.PayloadHandlerStub{
+		CloseCalled: func() error {
+			return expectedErr
+		},
+	}
+	numNonComparableCloses := 0
+	otherHandler := nonComparableHandler{numCloses: &numNonComparableCloses, names: []string{"other"}}
+
+	handlers := []PayloadHandler{sharedHandler, failingHandler, otherHandler, sharedHandler, otherHandler}
+	err := closeHandlersOnce(handlers, &testscommon.LoggerMock{})
+	require.Equal(t, expectedErr, err)
+	require.Equal(t, 1, numSharedCloses)
+	require.Equal(t, 2, numNonComparableCloses)
+}
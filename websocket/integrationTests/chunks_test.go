@@ -0,0 +1,67 @@
+package integrationTests
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/testscommon"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	"github.com/subrahamanyam341/andes-core-16/data/outport"
+)
+
+func TestClientAndServerShouldExchangePayloadsLargerThanTheMaxMessageSizeInChunks(t *testing.T) {
+	chunkSize := 16 * 1024
+	serverURL := "localhost:" + getFreePort()
+	config := baseConfig(serverURL, data.ModeServer)
+	config.ChunkSizeInBytes = chunkSize
+	config.MaxMessageSizeInBytes = int64(2 * chunkSize)
+	wsServer := createHost(t, config)
+	defer func() {
+		_ = wsServer.Close()
+	}()
+
+	chServerReceived := make(chan []byte, 10)
+	_ = wsServer.SetPayloadHandler(&testscommon.PayloadHandlerStub{
+		ProcessPayloadCalled: func(payload []byte, _ string, _ uint32) error {
+			chServerReceived <- payload
+			return nil
+		},
+	})
+
+	config.Mode = data.ModeClient
+	wsClient := createHost(t, config)
+	defer func() {
+		_ = wsClient.Close()
+	}()
+
+	chClientReceived := make(chan []byte, 10)
+	_ = wsClient.SetPayloadHandler(&testscommon.PayloadHandlerStub{
+		ProcessPayloadCalled: func(payload []byte, _ string, _ uint32) error {
+			chClientReceived <- payload
+			return nil
+		},
+	})
+
+	// a whole payload would exceed the max message size, so the peer would close the connection
+	payload := bytes.Repeat([]byte("block"), 200*1024)
+	sendUntilSucceeds(t, wsClient, payload)
+
+	select {
+	case received := <-chServerReceived:
+		require.Equal(t, payload, received)
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "timeout waiting for the payload on the server")
+	}
+
+	err := wsServer.Send(payload, outport.TopicSaveBlock)
+	require.Nil(t, err)
+
+	select {
+	case received := <-chClientReceived:
+		require.Equal(t, payload, received)
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "timeout waiting for the payload on the client")
+	}
+}
@@ -0,0 +1,108 @@
+package integrationTests
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/testscommon"
+	"github.com/subrahamanyam341/andes-communication/websocket/authentication"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	"github.com/subrahamanyam341/andes-core-16/data/outport"
+)
+
+func TestStartAuthenticatedServerAndSendDataWithValidCredentials(t *testing.T) {
+	url := "localhost:" + getFreePort()
+	secret := []byte("shared secret")
+	authenticator, _ := authentication.NewHMACAuthenticator(authentication.ArgsHMACAuthenticator{
+		Secret:                 secret,
+		MaxTimestampDriftInSec: 10,
+	})
+	serverArgs := createHostArgs(baseConfig(url, data.ModeServer))
+	serverArgs.HandshakeAuthenticator = authenticator
+	wsServer := createHostWithArgs(t, serverArgs)
+
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	_ = wsServer.SetPayloadHandler(&testscommon.PayloadHandlerStub{
+		ProcessPayloadCalled: func(payload []byte, topic string, version uint32) error {
+			require.Equal(t, []byte("test"), payload)
+			wg.Done()
+			return nil
+		},
+	})
+
+	credentialsProvider, _ := authentication.NewHMACCredentialsProvider(secret)
+	clientArgs := createHostArgs(baseConfig(url, data.ModeClient))
+	clientArgs.CredentialsProvider = credentialsProvider
+	wsClient := createHostWithArgs(t, clientArgs)
+
+	for {
+		err := wsClient.Send([]byte("test"), outport.TopicSaveAccounts)
+		if err == nil {
+			break
+		}
+		time.Sleep(300 * time.Millisecond)
+	}
+
+	wg.Wait()
+	_ = wsClient.Close()
+	_ = wsServer.Close()
+}
+
+func TestStartAuthenticatedServerShouldRejectInvalidCredentials(t *testing.T) {
+	url := "localhost:" + getFreePort()
+	authenticator, _ := authentication.NewBearerTokenAuthenticator([]string{"valid token"})
+
+	rejectedHandshake := make(chan struct{}, 1)
+	serverLog := &testscommon.LoggerStub{
+		WarnCalled: func(message string, args ...interface{}) {
+			if message == "rejected websocket handshake" {
+				select {
+				case rejectedHandshake <- struct{}{}:
+				default:
+				}
+			}
+		},
+	}
+	serverArgs := createHostArgs(baseConfig(url, data.ModeServer))
+	serverArgs.HandshakeAuthenticator = authenticator
+	serverArgs.Log = serverLog
+	wsServer := createHostWithArgs(t, serverArgs)
+
+	_ = wsServer.SetPayloadHandler(&testscommon.PayloadHandlerStub{
+		ProcessPayloadCalled: func(payload []byte, topic string, version uint32) error {
+			require.Fail(t, "should have not received any payload")
+			return nil
+		},
+	})
+
+	clientDialErrors := make(chan string, 1)
+	clientLog := &testscommon.LoggerStub{
+		WarnCalled: func(message string, args ...interface{}) {
+			if strings.Contains(fmt.Sprint(args...), "401") {
+				select {
+				case clientDialErrors <- message:
+				default:
+				}
+			}
+		},
+	}
+	credentialsProvider, _ := authentication.NewBearerTokenCredentialsProvider("invalid token")
+	clientArgs := createHostArgs(baseConfig(url, data.ModeClient))
+	clientArgs.CredentialsProvider = credentialsProvider
+	clientArgs.Log = clientLog
+	wsClient := createHostWithArgs(t, clientArgs)
+
+	<-rejectedHandshake
+	<-clientDialErrors
+
+	err := wsClient.Send([]byte("test"), outport.TopicSaveAccounts)
+	require.Equal(t, data.ErrConnectionNotOpen, err)
+
+	_ = wsClient.Close()
+	_ = wsServer.Close()
+}
@@ -0,0 +1,116 @@
+package integrationTests
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/testscommon"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	"github.com/subrahamanyam341/andes-core-16/data/outport"
+)
+
+func createTLSConfig(url string, mode string, files *testscommon.TLSCertificateFiles, mutualAuthentication bool) data.WebSocketConfig {
+	config := baseConfig(url, mode)
+	config.UseTLS = true
+	config.TLSCACertificateFile = files.CACertificateFile
+	config.TLSMutualAuthentication = mutualAuthentication
+	if mode == data.ModeServer {
+		config.TLSCertificateFile = files.ServerCertificateFile
+		config.TLSKeyFile = files.ServerKeyFile
+	} else {
+		config.TLSCertificateFile = files.ClientCertificateFile
+		config.TLSKeyFile = files.ClientKeyFile
+	}
+
+	return config
+}
+
+func TestStartTLSServerAddClientAndSendData(t *testing.T) {
+	files, err := testscommon.GenerateTLSCertificateFiles(t.TempDir())
+	require.Nil(t, err)
+
+	url := "localhost:" + getFreePort()
+	wsServer := createHost(t, createTLSConfig(url, data.ModeServer, files, false))
+
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	_ = wsServer.SetPayloadHandler(&testscommon.PayloadHandlerStub{
+		ProcessPayloadCalled: func(payload []byte, topic string, version uint32) error {
+			require.Equal(t, []byte("test"), payload)
+			wg.Done()
+			return nil
+		},
+	})
+
+	wsClient := createHost(t, createTLSConfig(url, data.ModeClient, files, false))
+
+	for {
+		err = wsClient.Send([]byte("test"), outport.TopicSaveAccounts)
+		if err == nil {
+			break
+		}
+		time.Sleep(300 * time.Millisecond)
+	}
+
+	wg.Wait()
+	_ = wsClient.Close()
+	_ = wsServer.Close()
+}
+
+func TestStartMutualTLSServerAddClientAndSendData(t *testing.T) {
+	files, err := testscommon.GenerateTLSCertificateFiles(t.TempDir())
+	require.Nil(t, err)
+
+	url := "localhost:" + getFreePort()
+	wsServer := createHost(t, createTLSConfig(url, data.ModeServer, files, true))
+
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	_ = wsServer.SetPayloadHandler(&testscommon.PayloadHandlerStub{
+		ProcessPayloadCalled: func(payload []byte, topic string, version uint32) error {
+			require.Equal(t, []byte("test"), payload)
+			wg.Done()
+			return nil
+		},
+	})
+
+	wsClient := createHost(t, createTLSConfig(url, data.ModeClient, files, true))
+
+	for {
+		err = wsClient.Send([]byte("test"), outport.TopicSaveAccounts)
+		if err == nil {
+			break
+		}
+		time.Sleep(300 * time.Millisecond)
+	}
+
+	wg.Wait()
+	_ = wsClient.Close()
+	_ = wsServer.Close()
+}
+
+func TestStartMutualTLSServerClientWithoutCertificateShouldNotConnect(t *testing.T) {
+	files, err := testscommon.GenerateTLSCertificateFiles(t.TempDir())
+	require.Nil(t, err)
+
+	url := "localhost:" + getFreePort()
+	wsServer := createHost(t, createTLSConfig(url, data.ModeServer, files, true))
+
+	_ = wsServer.SetPayloadHandler(&testscommon.PayloadHandlerStub{
+		ProcessPayloadCalled: func(payload []byte, topic string, version uint32) error {
+			require.Fail(t, "should have not received any payload")
+			return nil
+		},
+	})
+
+	wsClient := createHost(t, createTLSConfig(url, data.ModeClient, files, false))
+
+	time.Sleep(2 * time.Second)
+	err = wsClient.Send([]byte("test"), outport.TopicSaveAccounts)
+	require.Equal(t, data.ErrConnectionNotOpen, err)
+
+	_ = wsClient.Close()
+	_ = wsServer.Close()
+}
@@ -0,0 +1,85 @@
+package integrationTests
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/testscommon"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	"github.com/subrahamanyam341/andes-core-16/data/outport"
+)
+
+func TestServerWithClientQueuesShouldNotBeStalledBySlowClient(t *testing.T) {
+	url := "localhost:" + getFreePort()
+	queueSize := 10
+	serverConfig := baseConfig(url, data.ModeServer)
+	serverConfig.ClientQueueSize = queueSize
+	serverConfig.ClientQueueOverflowPolicy = data.DropNewestOverflowPolicy
+	wsServer := createHost(t, serverConfig)
+	defer func() {
+		_ = wsServer.Close()
+	}()
+	manager, ok := wsServer.(serverWithClientsManagement)
+	require.True(t, ok)
+
+	// the slow client never finishes processing the first payload, so it never acknowledges it
+	chReleaseSlowClient := make(chan struct{})
+	slowClient, err := createClient(url, &testscommon.LoggerMock{})
+	require.Nil(t, err)
+	defer func() {
+		_ = slowClient.Close()
+	}()
+	_ = slowClient.SetPayloadHandler(&testscommon.PayloadHandlerStub{
+		ProcessPayloadCalled: func(_ []byte, _ string, _ uint32) error {
+			<-chReleaseSlowClient
+			return nil
+		},
+	})
+	defer close(chReleaseSlowClient)
+	for len(manager.ConnectedClients()) != 1 {
+		time.Sleep(50 * time.Millisecond)
+	}
+	slowClientID := manager.ConnectedClients()[0].ID
+
+	numMessages := 20
+	fastReceiver := newOrderedReceiver(numMessages)
+	fastClient, err := createClient(url, &testscommon.LoggerMock{})
+	require.Nil(t, err)
+	defer func() {
+		_ = fastClient.Close()
+	}()
+	_ = fastClient.SetPayloadHandler(&testscommon.PayloadHandlerStub{
+		ProcessPayloadCalled: fastReceiver.processPayload,
+	})
+	for len(manager.ConnectedClients()) != 2 {
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	start := time.Now()
+	for i := 0; i < numMessages; i++ {
+		err = wsServer.Send([]byte(fmt.Sprintf("%d", i)), outport.TopicSaveBlock)
+		require.Nil(t, err)
+
+		// keep the queue of the fast client empty, only the slow client should overflow
+		for fastReceiver.numReceived() != i+1 {
+			time.Sleep(time.Millisecond)
+		}
+	}
+	require.Equal(t, createExpectedMessages(numMessages), fastReceiver.waitAndGetReceived(t))
+	// a single unacknowledged message would have taken the whole acknowledgement timeout
+	require.Less(t, time.Since(start), time.Second)
+
+	for _, info := range manager.ConnectedClients() {
+		if info.ID != slowClientID {
+			require.Equal(t, 0, info.QueuedMessages)
+			require.Equal(t, uint64(0), info.DroppedMessages)
+			continue
+		}
+
+		// one message is in flight, the queue is full and the rest were dropped
+		require.Equal(t, queueSize, info.QueuedMessages)
+		require.Equal(t, uint64(numMessages-queueSize-1), info.DroppedMessages)
+	}
+}
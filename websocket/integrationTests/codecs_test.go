@@ -0,0 +1,86 @@
+package integrationTests
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	"github.com/subrahamanyam341/andes-core-16/data/outport"
+)
+
+func TestServerShouldServeProtobufAndJSONClientsAtOnce(t *testing.T) {
+	serverURL := "localhost:" + getFreePort()
+	wsServer, chServerReceived := createServerWithReceiver(t, serverURL)
+	defer func() {
+		_ = wsServer.Close()
+	}()
+
+	protobufClientConfig := baseConfig(serverURL, data.ModeClient)
+	protobufClientConfig.Codec = data.ProtobufCodec
+	protobufClient := createHost(t, protobufClientConfig)
+	defer func() {
+		_ = protobufClient.Close()
+	}()
+	jsonClientConfig := baseConfig(serverURL, data.ModeClient)
+	jsonClientConfig.Codec = data.JSONCodec
+	jsonClient := createHost(t, jsonClientConfig)
+	defer func() {
+		_ = jsonClient.Close()
+	}()
+
+	sendUntilSucceeds(t, protobufClient, []byte("from protobuf"))
+	requireReceived(t, chServerReceived, []byte("from protobuf"))
+	sendUntilSucceeds(t, jsonClient, []byte("from json"))
+	requireReceived(t, chServerReceived, []byte("from json"))
+}
+
+func TestServerShouldTalkToAGenericJSONConsumer(t *testing.T) {
+	serverURL := "localhost:" + getFreePort()
+	wsServer, chServerReceived := createServerWithReceiver(t, serverURL)
+	defer func() {
+		_ = wsServer.Close()
+	}()
+
+	dialer := websocket.Dialer{Subprotocols: []string{data.JSONSubprotocol}}
+	wsURL := url.URL{Scheme: "ws", Host: serverURL, Path: data.WSRoute}
+	var conn *websocket.Conn
+	require.Eventually(t, func() bool {
+		var errDial error
+		conn, _, errDial = dialer.Dial(wsURL.String(), nil)
+		return errDial == nil
+	}, 10*time.Second, 100*time.Millisecond)
+	defer func() {
+		_ = conn.Close()
+	}()
+	require.Equal(t, data.JSONSubprotocol, conn.Subprotocol())
+
+	// the server expects acknowledgements, the consumer sends them as plain JSON
+	chSendErr := make(chan error, 1)
+	go func() {
+		chSendErr <- wsServer.Send([]byte("to the dashboard"), outport.TopicSaveBlock)
+	}()
+
+	messageType, message, err := conn.ReadMessage()
+	require.Nil(t, err)
+	require.Equal(t, websocket.TextMessage, messageType)
+	received := &data.WsMessage{}
+	err = json.Unmarshal(message, received)
+	require.Nil(t, err)
+	require.Equal(t, []byte("to the dashboard"), received.Payload)
+	require.Equal(t, outport.TopicSaveBlock, received.Topic)
+	require.True(t, received.WithAcknowledge)
+
+	ack, _ := json.Marshal(&data.WsMessage{Type: data.AckMessage, Counter: received.Counter})
+	err = conn.WriteMessage(websocket.TextMessage, ack)
+	require.Nil(t, err)
+	require.Nil(t, <-chSendErr)
+
+	payload, _ := json.Marshal(&data.WsMessage{Type: data.PayloadMessage, Payload: []byte("from the dashboard"), Topic: outport.TopicSaveBlock, Version: 1})
+	err = conn.WriteMessage(websocket.TextMessage, payload)
+	require.Nil(t, err)
+	requireReceived(t, chServerReceived, []byte("from the dashboard"))
+}
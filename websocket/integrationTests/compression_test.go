@@ -0,0 +1,112 @@
+package integrationTests
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/testscommon"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	hostFactory "github.com/subrahamanyam341/andes-communication/websocket/factory"
+	"github.com/subrahamanyam341/andes-core-16/data/outport"
+)
+
+func TestClientAndServerWithCompression(t *testing.T) {
+	t.Run("both hosts compress the payloads", func(t *testing.T) {
+		testClientAndServerWithCompression(t,
+			func(url string) (hostFactory.FullDuplexHost, error) {
+				return createCompressingHost(url, data.ModeServer, data.ZstdCompression)
+			},
+			func(url string) (hostFactory.FullDuplexHost, error) {
+				return createCompressingHost(url, data.ModeClient, data.SnappyCompression)
+			})
+	})
+	t.Run("server compresses, client without compression support", func(t *testing.T) {
+		testClientAndServerWithCompression(t,
+			func(url string) (hostFactory.FullDuplexHost, error) {
+				return createCompressingHost(url, data.ModeServer, data.SnappyCompression)
+			},
+			func(url string) (hostFactory.FullDuplexHost, error) {
+				return createClient(url, &testscommon.LoggerMock{})
+			})
+	})
+	t.Run("client compresses, server without compression support", func(t *testing.T) {
+		testClientAndServerWithCompression(t,
+			func(url string) (hostFactory.FullDuplexHost, error) {
+				return createServer(url, &testscommon.LoggerMock{})
+			},
+			func(url string) (hostFactory.FullDuplexHost, error) {
+				return createCompressingHost(url, data.ModeClient, data.ZstdCompression)
+			})
+	})
+}
+
+func testClientAndServerWithCompression(
+	t *testing.T,
+	createServerHost func(url string) (hostFactory.FullDuplexHost, error),
+	createClientHost func(url string) (hostFactory.FullDuplexHost, error),
+) {
+	url := "localhost:" + getFreePort()
+	wsServer, err := createServerHost(url)
+	require.Nil(t, err)
+	defer func() {
+		_ = wsServer.Close()
+	}()
+
+	wsClient, err := createClientHost(url)
+	require.Nil(t, err)
+	defer func() {
+		_ = wsClient.Close()
+	}()
+
+	payload := bytes.Repeat([]byte("compressible payload "), 10000)
+	chServerReceived := make(chan []byte, 10)
+	_ = wsServer.SetPayloadHandler(&testscommon.PayloadHandlerStub{
+		ProcessPayloadCalled: func(payload []byte, _ string, _ uint32) error {
+			chServerReceived <- payload
+			return nil
+		},
+	})
+	chClientReceived := make(chan []byte, 10)
+	_ = wsClient.SetPayloadHandler(&testscommon.PayloadHandlerStub{
+		ProcessPayloadCalled: func(payload []byte, _ string, _ uint32) error {
+			chClientReceived <- payload
+			return nil
+		},
+	})
+
+	// wait for the connection to be established
+	for {
+		err = wsClient.Send(payload, outport.TopicSaveBlock)
+		if err == nil {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	select {
+	case received := <-chServerReceived:
+		require.Equal(t, payload, received)
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "timeout waiting for the server to receive the payload")
+	}
+
+	err = wsServer.Send(payload, outport.TopicSaveBlock)
+	require.Nil(t, err)
+
+	select {
+	case received := <-chClientReceived:
+		require.Equal(t, payload, received)
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "timeout waiting for the client to receive the payload")
+	}
+}
+
+func createCompressingHost(url string, mode string, payloadCompression string) (hostFactory.FullDuplexHost, error) {
+	config := baseConfig(url, mode)
+	config.PerMessageDeflate = true
+	config.PayloadCompression = payloadCompression
+
+	return hostFactory.CreateWebSocketHost(createHostArgs(config))
+}
@@ -0,0 +1,77 @@
+package integrationTests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/testscommon"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	"github.com/subrahamanyam341/andes-core-16/data/outport"
+)
+
+type serverWithClientsManagement interface {
+	SendTo(clientID string, payload []byte, topic string) error
+	ConnectedClients() []data.ClientInfo
+	Disconnect(clientID string) error
+}
+
+func TestServerSendToAndDisconnectClient(t *testing.T) {
+	url := "localhost:" + getFreePort()
+	wsServer, err := createServer(url, &testscommon.LoggerMock{})
+	require.Nil(t, err)
+	defer func() {
+		_ = wsServer.Close()
+	}()
+	manager, ok := wsServer.(serverWithClientsManagement)
+	require.True(t, ok)
+
+	firstRecorder := &topicsRecorder{}
+	firstClient, err := createClient(url, &testscommon.LoggerMock{})
+	require.Nil(t, err)
+	defer func() {
+		_ = firstClient.Close()
+	}()
+	_ = firstClient.SetPayloadHandler(firstRecorder.payloadHandler())
+
+	// wait for the first client, so the connection times are ordered
+	for len(manager.ConnectedClients()) != 1 {
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	secondRecorder := &topicsRecorder{}
+	secondClient, err := createClient(url, &testscommon.LoggerMock{})
+	require.Nil(t, err)
+	defer func() {
+		_ = secondClient.Close()
+	}()
+	_ = secondClient.SetPayloadHandler(secondRecorder.payloadHandler())
+
+	for len(manager.ConnectedClients()) != 2 {
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	clients := manager.ConnectedClients()
+	secondID := clients[1].ID
+	require.NotEmpty(t, clients[1].RemoteAddress)
+	require.False(t, clients[1].ConnectedAt.Before(clients[0].ConnectedAt))
+
+	err = manager.SendTo(secondID, []byte("payload"), outport.TopicSaveBlock)
+	require.Nil(t, err)
+	require.Equal(t, []string{outport.TopicSaveBlock}, secondRecorder.receivedTopics())
+	require.Empty(t, firstRecorder.receivedTopics())
+
+	clients = manager.ConnectedClients()
+	require.Equal(t, uint64(1), clients[1].MessagesSent)
+	require.NotZero(t, clients[1].BytesSent)
+	// the acknowledgement
+	require.Equal(t, uint64(1), clients[1].MessagesReceived)
+	require.NotZero(t, clients[1].BytesReceived)
+
+	err = manager.Disconnect(secondID)
+	require.Nil(t, err)
+	clients = manager.ConnectedClients()
+	require.Len(t, clients, 1)
+	require.NotEqual(t, secondID, clients[0].ID)
+	require.Equal(t, data.ErrClientNotFound, manager.SendTo(secondID, []byte("payload"), outport.TopicSaveBlock))
+}
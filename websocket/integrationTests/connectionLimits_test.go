@@ -0,0 +1,97 @@
+package integrationTests
+
+import (
+	"bytes"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/testscommon"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	"github.com/subrahamanyam341/andes-core-16/data/outport"
+)
+
+const customRoutePath = "/custom"
+
+func TestClientAndServerOnCustomRoute(t *testing.T) {
+	serverURL := "localhost:" + getFreePort()
+	serverConfig := baseConfig(serverURL, data.ModeServer)
+	serverConfig.RoutePath = customRoutePath
+	wsServer := createHost(t, serverConfig)
+	defer func() {
+		_ = wsServer.Close()
+	}()
+
+	chReceived := make(chan []byte, 1)
+	_ = wsServer.SetPayloadHandler(&testscommon.PayloadHandlerStub{
+		ProcessPayloadCalled: func(payload []byte, _ string, _ uint32) error {
+			chReceived <- payload
+			return nil
+		},
+	})
+
+	clientConfig := baseConfig(serverURL, data.ModeClient)
+	clientConfig.RoutePath = customRoutePath
+	wsClient := createHost(t, clientConfig)
+	defer func() {
+		_ = wsClient.Close()
+	}()
+
+	payload := []byte("payload on the custom route")
+	for {
+		err := wsClient.Send(payload, outport.TopicSaveBlock)
+		if err == nil {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	select {
+	case received := <-chReceived:
+		require.Equal(t, payload, received)
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "timeout waiting for the payload")
+	}
+
+	// the default route is not served anymore
+	defaultURL := url.URL{Scheme: "ws", Host: serverURL, Path: data.WSRoute}
+	_, _, err := websocket.DefaultDialer.Dial(defaultURL.String(), nil)
+	require.Equal(t, websocket.ErrBadHandshake, err)
+}
+
+func TestServerShouldCloseTheConnectionOnOversizedMessage(t *testing.T) {
+	serverURL := "localhost:" + getFreePort()
+	serverConfig := baseConfig(serverURL, data.ModeServer)
+	serverConfig.RoutePath = customRoutePath
+	serverConfig.MaxMessageSizeInBytes = 1024
+	// the buffers hold the whole oversized message
+	serverConfig.ReadBufferSizeInBytes = 4096
+	serverConfig.WriteBufferSizeInBytes = 4096
+	wsServer := createHost(t, serverConfig)
+	defer func() {
+		_ = wsServer.Close()
+	}()
+
+	wsURL := url.URL{Scheme: "ws", Host: serverURL, Path: customRoutePath}
+	var conn *websocket.Conn
+	var err error
+	for {
+		conn, _, err = websocket.DefaultDialer.Dial(wsURL.String(), nil)
+		if err == nil {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	err = conn.WriteMessage(websocket.BinaryMessage, bytes.Repeat([]byte("a"), 2048))
+	require.Nil(t, err)
+
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, _, err = conn.ReadMessage()
+	require.True(t, websocket.IsCloseError(err, websocket.CloseMessageTooBig), "unexpected error %v", err)
+}
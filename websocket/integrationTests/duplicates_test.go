@@ -0,0 +1,75 @@
+package integrationTests
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	"github.com/subrahamanyam341/andes-core-16/data/outport"
+)
+
+func dialRawConnection(t *testing.T, serverURL string) *websocket.Conn {
+	wsURL := url.URL{Scheme: "ws", Host: serverURL, Path: data.WSRoute}
+	var conn *websocket.Conn
+	require.Eventually(t, func() bool {
+		var errDial error
+		conn, _, errDial = websocket.DefaultDialer.Dial(wsURL.String(), nil)
+		return errDial == nil
+	}, 10*time.Second, 100*time.Millisecond)
+
+	return conn
+}
+
+func sendAndWaitForAck(t *testing.T, conn *websocket.Conn, wsMessage *data.WsMessage) {
+	payload, err := payloadConverter.ConstructPayload(wsMessage)
+	require.Nil(t, err)
+	err = conn.WriteMessage(websocket.BinaryMessage, payload)
+	require.Nil(t, err)
+
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, message, err := conn.ReadMessage()
+	require.Nil(t, err)
+	ack, err := payloadConverter.ExtractWsMessage(message)
+	require.Nil(t, err)
+	require.Equal(t, int32(data.AckMessage), ack.Type)
+	require.Equal(t, wsMessage.Counter, ack.Counter)
+}
+
+func TestServerShouldDropTheMessagesResentAfterReconnect(t *testing.T) {
+	serverURL := "localhost:" + getFreePort()
+	wsServer, chServerReceived := createServerWithReceiver(t, serverURL)
+	defer func() {
+		_ = wsServer.Close()
+	}()
+
+	createMessage := func(payload string, counter uint64, sequence uint64) *data.WsMessage {
+		return &data.WsMessage{
+			WithAcknowledge: true,
+			Counter:         counter,
+			Type:            data.PayloadMessage,
+			Payload:         []byte(payload),
+			Topic:           outport.TopicSaveBlock,
+			Version:         1,
+			SessionID:       "client session",
+			Sequence:        sequence,
+		}
+	}
+
+	firstConn := dialRawConnection(t, serverURL)
+	sendAndWaitForAck(t, firstConn, createMessage("first", 1, 1))
+	requireReceived(t, chServerReceived, []byte("first"))
+	// the client did not see the acknowledgement before losing the connection
+	_ = firstConn.Close()
+
+	secondConn := dialRawConnection(t, serverURL)
+	defer func() {
+		_ = secondConn.Close()
+	}()
+	sendAndWaitForAck(t, secondConn, createMessage("first", 1, 1))
+	sendAndWaitForAck(t, secondConn, createMessage("second", 2, 2))
+	requireReceived(t, chServerReceived, []byte("second"))
+	require.Empty(t, chServerReceived)
+}
@@ -0,0 +1,151 @@
+package integrationTests
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/testscommon"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	hostFactory "github.com/subrahamanyam341/andes-communication/websocket/factory"
+	"github.com/subrahamanyam341/andes-core-16/data/outport"
+)
+
+func createServerWithReceiver(t *testing.T, url string) (hostFactory.FullDuplexHost, chan []byte) {
+	wsServer, err := createServer(url, &testscommon.LoggerMock{})
+	require.Nil(t, err)
+
+	chReceived := make(chan []byte, 10)
+	_ = wsServer.SetPayloadHandler(&testscommon.PayloadHandlerStub{
+		ProcessPayloadCalled: func(payload []byte, _ string, _ uint32) error {
+			chReceived <- payload
+			return nil
+		},
+	})
+
+	return wsServer, chReceived
+}
+
+// waitUntilListening waits until the server accepts connections, as the servers start listening in the background
+func waitUntilListening(t *testing.T, url string) {
+	require.Eventually(t, func() bool {
+		conn, err := net.Dial("tcp", url)
+		if err != nil {
+			return false
+		}
+
+		_ = conn.Close()
+		return true
+	}, 10*time.Second, 10*time.Millisecond)
+}
+
+func sendUntilSucceeds(t *testing.T, host hostFactory.FullDuplexHost, payload []byte) {
+	timeout := time.After(20 * time.Second)
+	for {
+		err := host.Send(payload, outport.TopicSaveBlock)
+		if err == nil {
+			return
+		}
+
+		select {
+		case <-timeout:
+			require.Fail(t, "timeout sending the payload", err.Error())
+			return
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
+
+func requireReceived(t *testing.T, chReceived chan []byte, payload []byte) {
+	select {
+	case received := <-chReceived:
+		require.Equal(t, payload, received)
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "timeout waiting for the payload")
+	}
+}
+
+func TestClientShouldFailoverToTheBackupEndpoint(t *testing.T) {
+	primaryURL := "localhost:" + getFreePort()
+	backupURL := "localhost:" + getFreePort()
+
+	primaryServer, chPrimaryReceived := createServerWithReceiver(t, primaryURL)
+	backupServer, chBackupReceived := createServerWithReceiver(t, backupURL)
+	defer func() {
+		_ = backupServer.Close()
+	}()
+	// the first connection must be opened to the primary
+	waitUntilListening(t, primaryURL)
+
+	clientConfig := baseConfig("", data.ModeClient)
+	clientConfig.URLs = []string{primaryURL, backupURL}
+	clientConfig.EndpointSelection = data.FailoverEndpointSelection
+	wsClient := createHost(t, clientConfig)
+	defer func() {
+		_ = wsClient.Close()
+	}()
+
+	sendUntilSucceeds(t, wsClient, []byte("to primary"))
+	requireReceived(t, chPrimaryReceived, []byte("to primary"))
+
+	client, ok := wsClient.(clientWithStateChanges)
+	require.True(t, ok)
+	states := &statesRecorder{}
+	client.OnStateChange(states.record)
+
+	// the primary is restarted, the client keeps streaming to the backup once it opened the connection to it
+	_ = primaryServer.Close()
+	states.waitForLastState(t, data.ConnectionOpen)
+	sendUntilSucceeds(t, wsClient, []byte("to backup"))
+	requireReceived(t, chBackupReceived, []byte("to backup"))
+	require.Empty(t, chPrimaryReceived)
+}
+
+func TestFanOutClientShouldSendToAllTheEndpoints(t *testing.T) {
+	firstURL := "localhost:" + getFreePort()
+	secondURL := "localhost:" + getFreePort()
+
+	firstServer, chFirstReceived := createServerWithReceiver(t, firstURL)
+	defer func() {
+		_ = firstServer.Close()
+	}()
+	secondServer, chSecondReceived := createServerWithReceiver(t, secondURL)
+
+	clientConfig := baseConfig("", data.ModeClient)
+	clientConfig.URLs = []string{firstURL, secondURL}
+	clientConfig.EndpointSelection = data.FanOutEndpointSelection
+	wsClient := createHost(t, clientConfig)
+	defer func() {
+		_ = wsClient.Close()
+	}()
+
+	// wait for both connections to be established
+	require.Eventually(t, func() bool {
+		_ = wsClient.Send([]byte("warm up"), outport.TopicSaveBlock)
+		return len(chFirstReceived) > 0 && len(chSecondReceived) > 0
+	}, 10*time.Second, 100*time.Millisecond)
+	drain(chFirstReceived)
+	drain(chSecondReceived)
+
+	payload := []byte("to all")
+	sendUntilSucceeds(t, wsClient, payload)
+	requireReceived(t, chFirstReceived, payload)
+	requireReceived(t, chSecondReceived, payload)
+
+	// one of the endpoints is restarted, the other one keeps receiving
+	_ = secondServer.Close()
+	payload = []byte("to the remaining endpoint")
+	sendUntilSucceeds(t, wsClient, payload)
+	requireReceived(t, chFirstReceived, payload)
+}
+
+func drain(ch chan []byte) {
+	for {
+		select {
+		case <-ch:
+		default:
+			return
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package integrationTests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/testscommon"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	"github.com/subrahamanyam341/andes-core-16/data/outport"
+)
+
+func getHealthStatus(url string) (int, data.HealthStatus, error) {
+	status := data.HealthStatus{}
+	response, err := http.Get(url)
+	if err != nil {
+		return 0, status, err
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+
+	err = json.NewDecoder(response.Body).Decode(&status)
+
+	return response.StatusCode, status, err
+}
+
+func TestServerShouldReportItsHealthAndReadiness(t *testing.T) {
+	serverURL := "localhost:" + getFreePort()
+	serverConfig := baseConfig(serverURL, data.ModeServer)
+	serverConfig.HealthEndpoints = true
+	wsServer := createHost(t, serverConfig)
+	defer func() {
+		_ = wsServer.Close()
+	}()
+	_ = wsServer.SetPayloadHandler(&testscommon.PayloadHandlerStub{})
+
+	healthURL := fmt.Sprintf("http://%s%s", serverURL, data.HealthRoute)
+	readyURL := fmt.Sprintf("http://%s%s", serverURL, data.ReadyRoute)
+
+	require.Eventually(t, func() bool {
+		code, status, errGet := getHealthStatus(healthURL)
+		return errGet == nil && code == http.StatusOK && status.Listening
+	}, 10*time.Second, 100*time.Millisecond)
+
+	// no consumer is connected yet
+	code, status, err := getHealthStatus(readyURL)
+	require.Nil(t, err)
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.Zero(t, status.ConnectedClients)
+
+	wsClient, err := createClient(serverURL, &testscommon.LoggerMock{})
+	require.Nil(t, err)
+	_ = wsClient.SetPayloadHandler(&testscommon.PayloadHandlerStub{})
+
+	require.Eventually(t, func() bool {
+		return wsServer.Send([]byte("payload"), outport.TopicSaveBlock) == nil
+	}, 10*time.Second, 100*time.Millisecond)
+
+	code, status, err = getHealthStatus(readyURL)
+	require.Nil(t, err)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, 1, status.ConnectedClients)
+	require.Contains(t, status.LastSuccessfulSend, outport.TopicSaveBlock)
+
+	_ = wsClient.Close()
+	require.Eventually(t, func() bool {
+		code, _, _ = getHealthStatus(readyURL)
+		return code == http.StatusServiceUnavailable
+	}, 10*time.Second, 100*time.Millisecond)
+}
@@ -0,0 +1,187 @@
+package integrationTests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	"github.com/subrahamanyam341/andes-core-16/data/outport"
+)
+
+type serverWithStateChanges interface {
+	OnClientStateChange(handler func(clientID string, state data.ConnectionState))
+	ConnectedClients() []data.ClientInfo
+}
+
+type clientWithStateChanges interface {
+	OnStateChange(handler func(state data.ConnectionState))
+}
+
+type statesRecorder struct {
+	mut    sync.Mutex
+	states []data.ConnectionState
+}
+
+func (sr *statesRecorder) record(state data.ConnectionState) {
+	sr.mut.Lock()
+	sr.states = append(sr.states, state)
+	sr.mut.Unlock()
+}
+
+func (sr *statesRecorder) recordedStates() []data.ConnectionState {
+	sr.mut.Lock()
+	defer sr.mut.Unlock()
+
+	return append([]data.ConnectionState{}, sr.states...)
+}
+
+func (sr *statesRecorder) waitForStates(t *testing.T, numStates int) []data.ConnectionState {
+	return sr.waitUntil(t, func(states []data.ConnectionState) bool {
+		return len(states) >= numStates
+	})
+}
+
+func (sr *statesRecorder) waitForLastState(t *testing.T, state data.ConnectionState) []data.ConnectionState {
+	return sr.waitUntil(t, func(states []data.ConnectionState) bool {
+		return len(states) > 0 && states[len(states)-1] == state
+	})
+}
+
+func (sr *statesRecorder) waitUntil(t *testing.T, condition func(states []data.ConnectionState) bool) []data.ConnectionState {
+	timeout := time.After(10 * time.Second)
+	for {
+		states := sr.recordedStates()
+		if condition(states) {
+			return states
+		}
+
+		select {
+		case <-timeout:
+			require.Fail(t, "timeout waiting for the connection states", "received %v", states)
+			return nil
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+}
+
+func TestServerWithKeepAliveShouldDropUnresponsiveClient(t *testing.T) {
+	url := "localhost:" + getFreePort()
+	serverConfig := baseConfig(url, data.ModeServer)
+	serverConfig.PingIntervalInSec = 1
+	serverConfig.PongTimeoutInSec = 1
+	wsServer := createHost(t, serverConfig)
+	defer func() {
+		_ = wsServer.Close()
+	}()
+	server, ok := wsServer.(serverWithStateChanges)
+	require.True(t, ok)
+
+	mutStates := sync.Mutex{}
+	clientsStates := make(map[string]*statesRecorder)
+	getRecorder := func(clientID string) *statesRecorder {
+		mutStates.Lock()
+		defer mutStates.Unlock()
+
+		recorder, found := clientsStates[clientID]
+		if !found {
+			recorder = &statesRecorder{}
+			clientsStates[clientID] = recorder
+		}
+		return recorder
+	}
+	server.OnClientStateChange(func(clientID string, state data.ConnectionState) {
+		getRecorder(clientID).record(state)
+	})
+
+	clientConfig := baseConfig(url, data.ModeClient)
+	clientConfig.PingIntervalInSec = 1
+	clientConfig.PongTimeoutInSec = 1
+	wsClient := createHost(t, clientConfig)
+	defer func() {
+		_ = wsClient.Close()
+	}()
+	for len(server.ConnectedClients()) != 1 {
+		time.Sleep(50 * time.Millisecond)
+	}
+	responsiveClientID := server.ConnectedClients()[0].ID
+
+	// a raw connection that never reads, so it never answers the pings
+	unresponsiveConn, _, err := websocket.DefaultDialer.Dial("ws://"+url+data.WSRoute, nil)
+	require.Nil(t, err)
+	defer func() {
+		_ = unresponsiveConn.Close()
+	}()
+	for len(server.ConnectedClients()) != 2 {
+		time.Sleep(50 * time.Millisecond)
+	}
+	var unresponsiveClientID string
+	for _, info := range server.ConnectedClients() {
+		if info.ID != responsiveClientID {
+			unresponsiveClientID = info.ID
+		}
+	}
+
+	states := getRecorder(unresponsiveClientID).waitForStates(t, 2)
+	require.Equal(t, []data.ConnectionState{data.ConnectionOpen, data.ConnectionUnresponsive}, states)
+
+	clients := server.ConnectedClients()
+	require.Len(t, clients, 1)
+	require.Equal(t, responsiveClientID, clients[0].ID)
+	require.Equal(t, []data.ConnectionState{data.ConnectionOpen}, getRecorder(responsiveClientID).recordedStates())
+
+	err = wsServer.Send([]byte("still connected"), outport.TopicSaveBlock)
+	require.Nil(t, err)
+}
+
+func TestClientWithKeepAliveShouldReconnectToUnresponsiveServer(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	mutConnections := sync.Mutex{}
+	connections := make([]*websocket.Conn, 0)
+	// the accepted connections are never read, so the pings are never answered
+	silentServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		conn, errUpgrade := upgrader.Upgrade(writer, request, nil)
+		if errUpgrade != nil {
+			return
+		}
+		mutConnections.Lock()
+		connections = append(connections, conn)
+		mutConnections.Unlock()
+	}))
+	defer func() {
+		mutConnections.Lock()
+		for _, conn := range connections {
+			_ = conn.Close()
+		}
+		mutConnections.Unlock()
+		silentServer.Close()
+	}()
+
+	serverURL, err := url.Parse(silentServer.URL)
+	require.Nil(t, err)
+
+	clientConfig := baseConfig(serverURL.Host, data.ModeClient)
+	clientConfig.PingIntervalInSec = 1
+	clientConfig.PongTimeoutInSec = 1
+	wsClient := createHost(t, clientConfig)
+	defer func() {
+		_ = wsClient.Close()
+	}()
+	client, ok := wsClient.(clientWithStateChanges)
+	require.True(t, ok)
+
+	recorder := &statesRecorder{}
+	client.OnStateChange(func(state data.ConnectionState) {
+		if state != data.ConnectionConnecting {
+			recorder.record(state)
+		}
+	})
+
+	states := recorder.waitForStates(t, 3)
+	require.Equal(t, []data.ConnectionState{data.ConnectionOpen, data.ConnectionUnresponsive, data.ConnectionOpen}, states[:3])
+}
@@ -0,0 +1,172 @@
+package integrationTests
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/testscommon"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	hostFactory "github.com/subrahamanyam341/andes-communication/websocket/factory"
+	"github.com/subrahamanyam341/andes-communication/websocket/memory"
+	"github.com/subrahamanyam341/andes-core-16/data/outport"
+)
+
+type payloadsRecorder struct {
+	mut      sync.Mutex
+	payloads []string
+}
+
+func (pr *payloadsRecorder) handler() *testscommon.PayloadHandlerStub {
+	return &testscommon.PayloadHandlerStub{
+		ProcessPayloadCalled: func(payload []byte, topic string, version uint32) error {
+			pr.mut.Lock()
+			pr.payloads = append(pr.payloads, string(payload))
+			pr.mut.Unlock()
+			return nil
+		},
+	}
+}
+
+func (pr *payloadsRecorder) recordedPayloads() []string {
+	pr.mut.Lock()
+	defer pr.mut.Unlock()
+
+	return append(make([]string, 0, len(pr.payloads)), pr.payloads...)
+}
+
+func createMemoryServer(t *testing.T, url string, config data.MemoryTransportConfig) hostFactory.FullDuplexHost {
+	wsServer, err := createMemoryHost(url, data.ModeServer, config)
+	require.Nil(t, err)
+	t.Cleanup(func() {
+		_ = wsServer.Close()
+	})
+
+	return wsServer
+}
+
+// createMemoryClient creates a client connected right away, so the handlers of the server must be already set
+func createMemoryClient(t *testing.T, url string, config data.MemoryTransportConfig) hostFactory.FullDuplexHost {
+	wsClient, err := createMemoryHost(url, data.ModeClient, config)
+	require.Nil(t, err)
+	t.Cleanup(func() {
+		_ = wsClient.Close()
+	})
+
+	return wsClient
+}
+
+func createMemoryServerAndClient(t *testing.T, url string, serverConfig data.MemoryTransportConfig, clientConfig data.MemoryTransportConfig) (hostFactory.FullDuplexHost, hostFactory.FullDuplexHost) {
+	wsServer := createMemoryServer(t, url, serverConfig)
+	wsClient := createMemoryClient(t, url, clientConfig)
+
+	return wsServer, wsClient
+}
+
+func TestMemoryTransportShouldSendAndRequestBothWays(t *testing.T) {
+	wsServer := createMemoryServer(t, "memory-send", data.MemoryTransportConfig{})
+	serverRecorder := &payloadsRecorder{}
+	_ = wsServer.SetPayloadHandler(serverRecorder.handler())
+
+	wsClient := createMemoryClient(t, "memory-send", data.MemoryTransportConfig{})
+	clientRecorder := &payloadsRecorder{}
+	_ = wsClient.SetPayloadHandler(clientRecorder.handler())
+	_ = wsClient.SetRequestHandler(&testscommon.RequestHandlerStub{
+		ProcessRequestCalled: func(payload []byte, topic string, version uint32) ([]byte, error) {
+			return append([]byte("pong: "), payload...), nil
+		},
+	})
+
+	// the client dials right away, without waiting a retry duration
+	sendUntilSucceeds(t, wsServer, []byte("to client 0"))
+	for i := 1; i < 5; i++ {
+		require.Nil(t, wsServer.Send([]byte(fmt.Sprintf("to client %d", i)), outport.TopicSaveBlock))
+		require.Nil(t, wsClient.Send([]byte(fmt.Sprintf("to server %d", i)), outport.TopicSaveBlock))
+	}
+
+	require.Equal(t, []string{"to client 0", "to client 1", "to client 2", "to client 3", "to client 4"}, clientRecorder.recordedPayloads())
+	require.Equal(t, []string{"to server 1", "to server 2", "to server 3", "to server 4"}, serverRecorder.recordedPayloads())
+
+	response, err := wsServer.Request(context.Background(), outport.TopicSaveBlock, []byte("ping"))
+	require.Nil(t, err)
+	require.Equal(t, "pong: ping", string(response))
+}
+
+func TestMemoryTransportShouldDelayTheMessages(t *testing.T) {
+	latency := 200 * time.Millisecond
+	config := data.MemoryTransportConfig{LatencyInMs: int(latency.Milliseconds())}
+	_, wsClient := createMemoryServerAndClient(t, "memory-latency", config, config)
+
+	sendUntilSucceeds(t, wsClient, []byte("warm up"))
+
+	start := time.Now()
+	require.Nil(t, wsClient.Send([]byte("delayed"), outport.TopicSaveBlock))
+	// the payload and its acknowledgement are both delayed
+	require.GreaterOrEqual(t, time.Since(start), 2*latency)
+}
+
+func TestMemoryTransportShouldDropTheMessages(t *testing.T) {
+	wsServer := createMemoryServer(t, "memory-drops", data.MemoryTransportConfig{})
+	serverRecorder := &payloadsRecorder{}
+	_ = wsServer.SetPayloadHandler(serverRecorder.handler())
+
+	wsClient := createMemoryClient(t, "memory-drops", data.MemoryTransportConfig{DropPercentage: 100})
+	clientRecorder := &payloadsRecorder{}
+	_ = wsClient.SetPayloadHandler(clientRecorder.handler())
+
+	// only the messages written by the client are lost
+	sendUntilSucceeds(t, wsServer, []byte("to client"))
+	require.Equal(t, []string{"to client"}, clientRecorder.recordedPayloads())
+
+	require.Equal(t, data.ErrAckTimeout, wsClient.Send([]byte("to server"), outport.TopicSaveBlock))
+	require.Empty(t, serverRecorder.recordedPayloads())
+}
+
+func TestMemoryTransportClientShouldReconnectAfterTheConnectionIsDropped(t *testing.T) {
+	url := "memory-reconnect"
+	wsServer, wsClient := createMemoryServerAndClient(t, url, data.MemoryTransportConfig{}, data.MemoryTransportConfig{})
+	client, ok := wsClient.(clientWithStateChanges)
+	require.True(t, ok)
+
+	recorder := &statesRecorder{}
+	client.OnStateChange(recorder.record)
+
+	clientRecorder := &payloadsRecorder{}
+	_ = wsClient.SetPayloadHandler(clientRecorder.handler())
+	sendUntilSucceeds(t, wsServer, []byte("before"))
+
+	require.Equal(t, 1, memory.DropConnections(url))
+	recorder.waitUntil(t, func(states []data.ConnectionState) bool {
+		return len(states) > 2 && states[len(states)-1] == data.ConnectionOpen
+	})
+	require.Contains(t, recorder.recordedStates(), data.ConnectionClosed)
+
+	sendUntilSucceeds(t, wsServer, []byte("after"))
+	require.Equal(t, []string{"before", "after"}, clientRecorder.recordedPayloads())
+}
+
+func TestMemoryTransportShouldRejectTheSecondServerOnTheSameURL(t *testing.T) {
+	wsServer, err := createMemoryHost("memory-address", data.ModeServer, data.MemoryTransportConfig{})
+	require.Nil(t, err)
+
+	secondServer, err := createMemoryHost("memory-address", data.ModeServer, data.MemoryTransportConfig{})
+	require.Nil(t, secondServer)
+	require.ErrorIs(t, err, data.ErrMemoryAddressInUse)
+
+	// the address is released once the server is closed
+	require.Nil(t, wsServer.Close())
+	secondServer, err = createMemoryHost("memory-address", data.ModeServer, data.MemoryTransportConfig{})
+	require.Nil(t, err)
+	require.Nil(t, secondServer.Close())
+}
+
+func createMemoryHost(url string, mode string, config data.MemoryTransportConfig) (hostFactory.FullDuplexHost, error) {
+	hostConfig := baseConfig(url, mode)
+	hostConfig.Transport = data.MemoryTransport
+	hostConfig.MemoryTransport = config
+
+	return hostFactory.CreateWebSocketHost(createHostArgs(hostConfig))
+}
@@ -0,0 +1,62 @@
+package integrationTests
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/testscommon"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	"github.com/subrahamanyam341/andes-communication/websocket/metrics"
+	"github.com/subrahamanyam341/andes-core-16/data/outport"
+)
+
+func getMetricsText(t *testing.T, metricsURL string) string {
+	response, err := http.Get(metricsURL)
+	require.Nil(t, err)
+	defer func() {
+		_ = response.Body.Close()
+	}()
+	require.Equal(t, http.StatusOK, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	require.Nil(t, err)
+
+	return string(body)
+}
+
+func TestServerShouldServeThePrometheusMetrics(t *testing.T) {
+	serverURL := "localhost:" + getFreePort()
+	serverConfig := baseConfig(serverURL, data.ModeServer)
+	serverConfig.MetricsRoutePath = "/metrics"
+	wsServer := createHost(t, serverConfig)
+	defer func() {
+		_ = wsServer.Close()
+	}()
+	_ = wsServer.SetPayloadHandler(&testscommon.PayloadHandlerStub{})
+
+	clientMetrics := metrics.NewInMemoryMetrics()
+	clientArgs := createHostArgs(baseConfig(serverURL, data.ModeClient))
+	clientArgs.Metrics = clientMetrics
+	wsClient := createHostWithArgs(t, clientArgs)
+	defer func() {
+		_ = wsClient.Close()
+	}()
+
+	sendUntilSucceeds(t, wsClient, []byte("payload"))
+
+	clientSnapshot := clientMetrics.GetSnapshot()
+	require.Equal(t, uint64(1), clientSnapshot.Topics[outport.TopicSaveBlock].MessagesSent)
+	require.Equal(t, uint64(1), clientSnapshot.AckLatency.Count)
+
+	metricsURL := fmt.Sprintf("http://%s/metrics", serverURL)
+	require.Eventually(t, func() bool {
+		text := getMetricsText(t, metricsURL)
+		return strings.Contains(text, fmt.Sprintf("websocket_messages_received_total{topic=%q} 1\n", outport.TopicSaveBlock)) &&
+			strings.Contains(text, "websocket_connected_clients 1\n")
+	}, 5*time.Second, 100*time.Millisecond)
+}
@@ -0,0 +1,173 @@
+package integrationTests
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/testscommon"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	"github.com/subrahamanyam341/andes-core-16/data/outport"
+)
+
+type orderedReceiver struct {
+	mut         sync.Mutex
+	received    []string
+	numExpected int
+	chanDone    chan struct{}
+}
+
+func newOrderedReceiver(numExpected int) *orderedReceiver {
+	return &orderedReceiver{
+		numExpected: numExpected,
+		chanDone:    make(chan struct{}),
+	}
+}
+
+func (or *orderedReceiver) processPayload(payload []byte, _ string, _ uint32) error {
+	or.mut.Lock()
+	defer or.mut.Unlock()
+
+	// a message that was processed but whose ack was lost because of a restart is sent again
+	numReceived := len(or.received)
+	if numReceived > 0 && or.received[numReceived-1] == string(payload) {
+		return nil
+	}
+
+	or.received = append(or.received, string(payload))
+	if len(or.received) == or.numExpected {
+		close(or.chanDone)
+	}
+
+	return nil
+}
+
+func (or *orderedReceiver) numReceived() int {
+	or.mut.Lock()
+	defer or.mut.Unlock()
+
+	return len(or.received)
+}
+
+func (or *orderedReceiver) waitAndGetReceived(t *testing.T) []string {
+	select {
+	case <-or.chanDone:
+	case <-time.After(30 * time.Second):
+		require.Fail(t, "timeout waiting for the queued messages")
+	}
+
+	or.mut.Lock()
+	defer or.mut.Unlock()
+
+	return append([]string{}, or.received...)
+}
+
+func createExpectedMessages(numMessages int) []string {
+	messages := make([]string, 0, numMessages)
+	for idx := 0; idx < numMessages; idx++ {
+		messages = append(messages, fmt.Sprintf("%d", idx))
+	}
+
+	return messages
+}
+
+func TestClientWithOutboundQueueShouldReplayMessagesQueuedWhileServerWasDown(t *testing.T) {
+	url := "localhost:" + getFreePort()
+	queuePath := t.TempDir()
+	numMessages := 20
+
+	clientConfig := baseConfig(url, data.ModeClient)
+	clientConfig.OutboundQueue = data.OutboundQueueConfig{Enabled: true, Path: queuePath}
+	wsClient := createHost(t, clientConfig)
+
+	for _, message := range createExpectedMessages(numMessages) {
+		err := wsClient.Send([]byte(message), outport.TopicSaveBlock)
+		require.Nil(t, err)
+	}
+
+	receiver := newOrderedReceiver(numMessages)
+	wsServer, err := createServer(url, &testscommon.LoggerMock{})
+	require.Nil(t, err)
+	_ = wsServer.SetPayloadHandler(&testscommon.PayloadHandlerStub{
+		ProcessPayloadCalled: receiver.processPayload,
+	})
+
+	require.Equal(t, createExpectedMessages(numMessages), receiver.waitAndGetReceived(t))
+
+	_ = wsClient.Close()
+	_ = wsServer.Close()
+}
+
+func TestClientWithOutboundQueueRestartedMidStreamShouldNotLoseMessages(t *testing.T) {
+	url := "localhost:" + getFreePort()
+	queuePath := t.TempDir()
+	numMessages := 100
+
+	receiver := newOrderedReceiver(numMessages)
+	wsServer, err := createServer(url, &testscommon.LoggerMock{})
+	require.Nil(t, err)
+	_ = wsServer.SetPayloadHandler(&testscommon.PayloadHandlerStub{
+		ProcessPayloadCalled: receiver.processPayload,
+	})
+
+	expectedMessages := createExpectedMessages(numMessages)
+	clientConfig := baseConfig(url, data.ModeClient)
+	clientConfig.OutboundQueue = data.OutboundQueueConfig{Enabled: true, Path: queuePath}
+	wsClient := createHost(t, clientConfig)
+	for _, message := range expectedMessages[:numMessages/2] {
+		err = wsClient.Send([]byte(message), outport.TopicSaveBlock)
+		require.Nil(t, err)
+	}
+
+	// restart the client while the first half is still being delivered
+	for receiver.numReceived() < 5 {
+		time.Sleep(time.Millisecond)
+	}
+	_ = wsClient.Close()
+
+	wsClient = createHost(t, clientConfig)
+	for _, message := range expectedMessages[numMessages/2:] {
+		err = wsClient.Send([]byte(message), outport.TopicSaveBlock)
+		require.Nil(t, err)
+	}
+
+	require.Equal(t, expectedMessages, receiver.waitAndGetReceived(t))
+
+	_ = wsClient.Close()
+	_ = wsServer.Close()
+}
+
+func TestServerWithOutboundQueueRestartedShouldReplayMessagesToClient(t *testing.T) {
+	url := "localhost:" + getFreePort()
+	queuePath := t.TempDir()
+	numMessages := 10
+
+	serverConfig := baseConfig(url, data.ModeServer)
+	serverConfig.OutboundQueue = data.OutboundQueueConfig{Enabled: true, Path: queuePath}
+	wsServer := createHost(t, serverConfig)
+	expectedMessages := createExpectedMessages(numMessages)
+	for _, message := range expectedMessages {
+		err := wsServer.Send([]byte(message), outport.TopicSaveBlock)
+		require.Nil(t, err)
+	}
+	_ = wsServer.Close()
+
+	// the client is ready to receive before the restarted server replays the messages
+	receiver := newOrderedReceiver(numMessages)
+	wsClient, err := createClient(url, &testscommon.LoggerMock{})
+	require.Nil(t, err)
+	_ = wsClient.SetPayloadHandler(&testscommon.PayloadHandlerStub{
+		ProcessPayloadCalled: receiver.processPayload,
+	})
+
+	// give the http server time to release the port
+	time.Sleep(time.Second)
+	wsServer = createHost(t, serverConfig)
+
+	require.Equal(t, expectedMessages, receiver.waitAndGetReceived(t))
+
+	_ = wsClient.Close()
+	_ = wsServer.Close()
+}
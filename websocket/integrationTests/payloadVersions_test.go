@@ -0,0 +1,110 @@
+package integrationTests
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/testscommon"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	"github.com/subrahamanyam341/andes-core-16/data/outport"
+)
+
+func TestPeersShouldNegotiateTheHighestCommonPayloadVersion(t *testing.T) {
+	serverURL := "localhost:" + getFreePort()
+	serverConfig := baseConfig(serverURL, data.ModeServer)
+	serverConfig.MinVersion = 1
+	serverConfig.Version = 2
+	wsServer := createHost(t, serverConfig)
+	defer func() {
+		_ = wsServer.Close()
+	}()
+	chServerVersions := make(chan uint32, 10)
+	_ = wsServer.SetPayloadHandler(&testscommon.PayloadHandlerStub{
+		ProcessPayloadCalled: func(_ []byte, _ string, version uint32) error {
+			chServerVersions <- version
+			return nil
+		},
+	})
+
+	clientConfig := baseConfig(serverURL, data.ModeClient)
+	clientConfig.MinVersion = 2
+	clientConfig.Version = 3
+	wsClient := createHost(t, clientConfig)
+	defer func() {
+		_ = wsClient.Close()
+	}()
+	chClientVersions := make(chan uint32, 10)
+	_ = wsClient.SetPayloadHandler(&testscommon.PayloadHandlerStub{
+		ProcessPayloadCalled: func(_ []byte, _ string, version uint32) error {
+			chClientVersions <- version
+			return nil
+		},
+	})
+
+	sendUntilSucceeds(t, wsClient, []byte("to server"))
+	require.Equal(t, uint32(2), <-chServerVersions)
+
+	err := wsServer.Send([]byte("to client"), outport.TopicSaveBlock)
+	require.Nil(t, err)
+	require.Equal(t, uint32(2), <-chClientVersions)
+}
+
+func TestServerShouldCloseTheConnectionWithoutACommonPayloadVersion(t *testing.T) {
+	serverURL := "localhost:" + getFreePort()
+	serverConfig := baseConfig(serverURL, data.ModeServer)
+	serverConfig.MinVersion = 1
+	serverConfig.Version = 2
+	wsServer := createHost(t, serverConfig)
+	defer func() {
+		_ = wsServer.Close()
+	}()
+
+	header := http.Header{}
+	header.Set(data.PayloadVersionsHeader, "3-4")
+	wsURL := url.URL{Scheme: "ws", Host: serverURL, Path: data.WSRoute}
+	var conn *websocket.Conn
+	require.Eventually(t, func() bool {
+		var errDial error
+		conn, _, errDial = websocket.DefaultDialer.Dial(wsURL.String(), header)
+		return errDial == nil
+	}, 10*time.Second, 100*time.Millisecond)
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, _, err := conn.ReadMessage()
+	var closeError *websocket.CloseError
+	require.True(t, errors.As(err, &closeError))
+	require.Equal(t, websocket.ClosePolicyViolation, closeError.Code)
+	require.Contains(t, closeError.Text, data.ErrNoCommonPayloadVersion.Error())
+	require.Contains(t, closeError.Text, "supported 1-2, peer supports 3-4")
+}
+
+func TestClientWithoutACommonPayloadVersionShouldNotConnect(t *testing.T) {
+	serverURL := "localhost:" + getFreePort()
+	serverConfig := baseConfig(serverURL, data.ModeServer)
+	serverConfig.MinVersion = 1
+	serverConfig.Version = 2
+	wsServer := createHost(t, serverConfig)
+	defer func() {
+		_ = wsServer.Close()
+	}()
+
+	clientConfig := baseConfig(serverURL, data.ModeClient)
+	clientConfig.MinVersion = 3
+	clientConfig.Version = 4
+	wsClient := createHost(t, clientConfig)
+	defer func() {
+		_ = wsClient.Close()
+	}()
+
+	time.Sleep(2 * time.Second)
+	err := wsClient.Send([]byte("payload"), outport.TopicSaveBlock)
+	require.NotNil(t, err)
+}
@@ -0,0 +1,80 @@
+package integrationTests
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/testscommon"
+	"github.com/subrahamanyam341/andes-communication/websocket"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	"github.com/subrahamanyam341/andes-core-16/data/outport"
+)
+
+func TestClientWithAckWindowSendsToServer(t *testing.T) {
+	t.Run("server with acknowledgement window", func(t *testing.T) {
+		testClientWithAckWindowSendsToServer(t, 10)
+	})
+	t.Run("server with one by one acknowledgement", func(t *testing.T) {
+		testClientWithAckWindowSendsToServer(t, 0)
+	})
+}
+
+func testClientWithAckWindowSendsToServer(t *testing.T, serverAckWindowSize int) {
+	url := "localhost:" + getFreePort()
+	serverConfig := baseConfig(url, data.ModeServer)
+	serverConfig.AckWindowSize = serverAckWindowSize
+	wsServer := createHost(t, serverConfig)
+	defer func() {
+		_ = wsServer.Close()
+	}()
+
+	numMessages := 50
+	mutReceived := sync.Mutex{}
+	received := make([]string, 0, numMessages)
+	_ = wsServer.SetPayloadHandler(&testscommon.PayloadHandlerStub{
+		ProcessPayloadCalled: func(payload []byte, _ string, _ uint32) error {
+			mutReceived.Lock()
+			received = append(received, string(payload))
+			mutReceived.Unlock()
+			return nil
+		},
+	})
+
+	clientConfig := baseConfig(url, data.ModeClient)
+	clientConfig.AckWindowSize = 10
+	wsClient := createHost(t, clientConfig)
+	defer func() {
+		_ = wsClient.Close()
+	}()
+
+	// wait for the connection to be established
+	for {
+		err := wsClient.Send([]byte("message 0"), outport.TopicSaveAccounts)
+		if err == nil {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	futures := make([]websocket.AckFuture, 0, numMessages)
+	for i := 1; i < numMessages; i++ {
+		future, errSend := wsClient.SendAsync([]byte(fmt.Sprintf("message %d", i)), outport.TopicSaveAccounts)
+		require.Nil(t, errSend)
+		futures = append(futures, future)
+	}
+	for _, future := range futures {
+		require.Nil(t, future.Wait())
+	}
+
+	mutReceived.Lock()
+	defer mutReceived.Unlock()
+
+	require.GreaterOrEqual(t, len(received), numMessages)
+	last := received[len(received)-numMessages+1:]
+	for i, message := range last {
+		require.Equal(t, fmt.Sprintf("message %d", i+1), message)
+	}
+}
@@ -0,0 +1,166 @@
+package integrationTests
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/testscommon"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	"github.com/subrahamanyam341/andes-core-16/data/outport"
+)
+
+func TestServerShouldDropThePayloadsExceedingTheRateLimits(t *testing.T) {
+	serverURL := "localhost:" + getFreePort()
+	serverConfig := baseConfig(serverURL, data.ModeServer)
+	serverConfig.RateLimit = data.RateLimitConfig{
+		Topics: []data.TopicRateLimitConfig{
+			{Topic: outport.TopicSaveBlock, MessagesPerSecond: 0.01, Burst: 2},
+		},
+	}
+	wsServer := createHost(t, serverConfig)
+	defer func() {
+		_ = wsServer.Close()
+	}()
+	manager, ok := wsServer.(serverWithClientsManagement)
+	require.True(t, ok)
+
+	numProcessed := atomic.Int32{}
+	_ = wsServer.SetPayloadHandler(&testscommon.PayloadHandlerStub{
+		ProcessPayloadCalled: func(_ []byte, _ string, _ uint32) error {
+			numProcessed.Add(1)
+			return nil
+		},
+	})
+
+	wsClient, err := createClient(serverURL, &testscommon.LoggerMock{})
+	require.Nil(t, err)
+	defer func() {
+		_ = wsClient.Close()
+	}()
+
+	sendUntilSucceeds(t, wsClient, []byte("payload"))
+	err = wsClient.Send([]byte("payload"), outport.TopicSaveBlock)
+	require.Nil(t, err)
+	// the dropped payload is not acknowledged, so the client knows it was not processed
+	err = wsClient.Send([]byte("payload"), outport.TopicSaveBlock)
+	require.Equal(t, data.ErrAckTimeout, err)
+	// the other topics are not limited
+	for i := 0; i < 2; i++ {
+		err = wsClient.Send([]byte("payload"), outport.TopicSaveAccounts)
+		require.Nil(t, err)
+	}
+
+	require.Equal(t, int32(4), numProcessed.Load())
+	clients := manager.ConnectedClients()
+	require.Len(t, clients, 1)
+	require.Equal(t, uint64(1), clients[0].RateLimitedMessages)
+}
+
+func TestServerShouldDisconnectTheClientExceedingTheRateLimits(t *testing.T) {
+	serverURL := "localhost:" + getFreePort()
+	serverConfig := baseConfig(serverURL, data.ModeServer)
+	serverConfig.RateLimit = data.RateLimitConfig{
+		MessagesPerSecond: 0.01,
+		Action:            data.DisconnectRateLimitAction,
+	}
+	wsServer := createHost(t, serverConfig)
+	defer func() {
+		_ = wsServer.Close()
+	}()
+	server, ok := wsServer.(serverWithStateChanges)
+	require.True(t, ok)
+	chClosed := make(chan data.ConnectionState, 1)
+	server.OnClientStateChange(func(_ string, state data.ConnectionState) {
+		if state != data.ConnectionOpen {
+			chClosed <- state
+		}
+	})
+
+	conn := dialRawConnection(t, serverURL)
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	createMessage := func(counter uint64) *data.WsMessage {
+		return &data.WsMessage{
+			WithAcknowledge: true,
+			Counter:         counter,
+			Type:            data.PayloadMessage,
+			Topic:           outport.TopicSaveBlock,
+			Payload:         []byte("payload"),
+			Version:         1,
+		}
+	}
+	sendAndWaitForAck(t, conn, createMessage(1))
+
+	message, err := payloadConverter.ConstructPayload(createMessage(2))
+	require.Nil(t, err)
+	err = conn.WriteMessage(websocket.BinaryMessage, message)
+	require.Nil(t, err)
+
+	// the refused message is not acknowledged, the connection is closed instead
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, _, err = conn.ReadMessage()
+	var closeError *websocket.CloseError
+	require.True(t, errors.As(err, &closeError), "unexpected error %v", err)
+	require.Equal(t, websocket.ClosePolicyViolation, closeError.Code)
+	require.Equal(t, data.ErrRateLimitExceeded.Error(), closeError.Text)
+
+	// the server stopped listening to the client and cleaned up after it
+	select {
+	case state := <-chClosed:
+		require.Equal(t, data.ConnectionClosed, state)
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "timeout waiting for the server to release the connection")
+	}
+	require.Empty(t, server.ConnectedClients())
+}
+
+func TestServerShouldRejectTheRequestsExceedingTheRateLimits(t *testing.T) {
+	serverURL := "localhost:" + getFreePort()
+	serverConfig := baseConfig(serverURL, data.ModeServer)
+	serverConfig.RateLimit = data.RateLimitConfig{
+		Topics: []data.TopicRateLimitConfig{
+			{Topic: outport.TopicSaveBlock, MessagesPerSecond: 0.01, Burst: 1},
+		},
+	}
+	wsServer := createHost(t, serverConfig)
+	defer func() {
+		_ = wsServer.Close()
+	}()
+	err := wsServer.SetRequestHandler(createEchoRequestHandler("server: "))
+	require.Nil(t, err)
+
+	wsClient, err := createClient(serverURL, &testscommon.LoggerMock{})
+	require.Nil(t, err)
+	defer func() {
+		_ = wsClient.Close()
+	}()
+	err = wsClient.SetRequestHandler(createEchoRequestHandler("client: "))
+	require.Nil(t, err)
+
+	// wait for the connection to be established, the requests of the server not being limited
+	for {
+		_, err = wsServer.Request(context.Background(), outport.TopicSaveBlock, []byte("ping"))
+		if err == nil {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	response, err := wsClient.Request(ctx, outport.TopicSaveBlock, []byte("hello"))
+	require.Nil(t, err)
+	require.Equal(t, []byte("server: hello"), response)
+
+	response, err = wsClient.Request(ctx, outport.TopicSaveBlock, []byte("hello"))
+	require.Nil(t, response)
+	require.Equal(t, &data.RequestError{Code: data.RequestErrorCodeRejected, Message: data.ErrRateLimitExceeded.Error()}, err)
+}
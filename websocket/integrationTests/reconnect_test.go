@@ -0,0 +1,70 @@
+package integrationTests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/testscommon"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+)
+
+func TestClientWithExponentialReconnectShouldReportStateChanges(t *testing.T) {
+	url := "localhost:" + getFreePort()
+	clientConfig := baseConfig(url, data.ModeClient)
+	clientConfig.Reconnect = data.ReconnectConfig{
+		Policy:           data.ExponentialReconnectPolicy,
+		MaxDelayInSec:    2,
+		JitterPercentage: 10,
+	}
+	wsClient := createHost(t, clientConfig)
+	defer func() {
+		_ = wsClient.Close()
+	}()
+	client, ok := wsClient.(clientWithStateChanges)
+	require.True(t, ok)
+
+	recorder := &statesRecorder{}
+	client.OnStateChange(recorder.record)
+
+	// let the client fail a few times before the server is started
+	time.Sleep(1500 * time.Millisecond)
+	wsServer, err := createServer(url, &testscommon.LoggerMock{})
+	require.Nil(t, err)
+
+	states := recorder.waitForLastState(t, data.ConnectionOpen)
+	require.NotContains(t, states, data.ConnectionFailed)
+
+	_ = wsServer.Close()
+	states = recorder.waitForLastState(t, data.ConnectionConnecting)
+	require.Equal(t, data.ConnectionClosed, states[len(states)-2])
+}
+
+func TestClientWithCappedReconnectAttemptsShouldGiveUp(t *testing.T) {
+	url := "localhost:" + getFreePort()
+	clientConfig := baseConfig(url, data.ModeClient)
+	clientConfig.Reconnect = data.ReconnectConfig{
+		Policy:      data.FixedReconnectPolicy,
+		MaxAttempts: 2,
+	}
+	wsClient := createHost(t, clientConfig)
+	defer func() {
+		_ = wsClient.Close()
+	}()
+	client, ok := wsClient.(clientWithStateChanges)
+	require.True(t, ok)
+
+	recorder := &statesRecorder{}
+	client.OnStateChange(recorder.record)
+
+	states := recorder.waitForLastState(t, data.ConnectionFailed)
+
+	// the server started after the client gave up should never receive its connection
+	wsServer, err := createServer(url, &testscommon.LoggerMock{})
+	require.Nil(t, err)
+	defer func() {
+		_ = wsServer.Close()
+	}()
+	time.Sleep(2 * time.Second)
+	require.Equal(t, states, recorder.recordedStates())
+}
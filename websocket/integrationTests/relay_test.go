@@ -0,0 +1,310 @@
+package integrationTests
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/testscommon"
+	"github.com/subrahamanyam341/andes-communication/websocket"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	hostFactory "github.com/subrahamanyam341/andes-communication/websocket/factory"
+	"github.com/subrahamanyam341/andes-communication/websocket/metrics"
+	"github.com/subrahamanyam341/andes-communication/websocket/relay"
+	"github.com/subrahamanyam341/andes-communication/websocket/signature"
+	"github.com/subrahamanyam341/andes-core-16/data/outport"
+	crypto "github.com/subrahamanyam341/andes-crypto-123"
+	"github.com/subrahamanyam341/andes-crypto-123/signing"
+	"github.com/subrahamanyam341/andes-crypto-123/signing/secp256k1"
+	"github.com/subrahamanyam341/andes-crypto-123/signing/secp256k1/singlesig"
+)
+
+type relayConsumer struct {
+	host       hostFactory.FullDuplexHost
+	chReceived chan []byte
+	shouldFail atomic.Bool
+}
+
+func createRelayConsumer(t *testing.T, url string) *relayConsumer {
+	hostConfig := baseConfig(url, data.ModeClient)
+	hostConfig.BlockingAckOnError = true
+	host := createHost(t, hostConfig)
+
+	return newRelayConsumer(host)
+}
+
+func newRelayConsumer(host hostFactory.FullDuplexHost) *relayConsumer {
+	consumer := &relayConsumer{
+		host:       host,
+		chReceived: make(chan []byte, 100),
+	}
+	_ = host.SetPayloadHandler(&testscommon.PayloadHandlerStub{
+		ProcessPayloadCalled: func(payload []byte, _ string, _ uint32) error {
+			if consumer.shouldFail.Load() {
+				return errors.New("consumer failure")
+			}
+
+			consumer.chReceived <- payload
+			return nil
+		},
+	})
+
+	return consumer
+}
+
+// waitUntilConsumersAreConnected sends probes through the relay until every consumer received one
+func waitUntilConsumersAreConnected(t *testing.T, node hostFactory.FullDuplexHost, consumers ...*relayConsumer) {
+	timeout := time.After(20 * time.Second)
+	connected := make([]bool, len(consumers))
+	numConnected := 0
+	for numConnected < len(consumers) {
+		future, err := node.SendAsync([]byte("probe"), outport.TopicSaveBlock)
+		if err == nil {
+			_ = future.Wait()
+		}
+
+		for i, consumer := range consumers {
+			for len(consumer.chReceived) > 0 {
+				<-consumer.chReceived
+				if !connected[i] {
+					connected[i] = true
+					numConnected++
+				}
+			}
+		}
+
+		select {
+		case <-timeout:
+			require.Fail(t, "timeout waiting for the relay consumers")
+			return
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
+
+func sendThroughRelay(t *testing.T, node hostFactory.FullDuplexHost, payload []byte) error {
+	future, err := node.SendAsync(payload, outport.TopicSaveBlock)
+	require.Nil(t, err)
+
+	return future.Wait()
+}
+
+func TestRelayShouldAcknowledgeUpstreamOnlyAfterAllConsumersAcknowledged(t *testing.T) {
+	nodeURL := "localhost:" + getFreePort()
+	relayURL := "localhost:" + getFreePort()
+
+	node, err := createServer(nodeURL, &testscommon.LoggerMock{})
+	require.Nil(t, err)
+	defer func() {
+		_ = node.Close()
+	}()
+
+	relayConfig := baseConfig(nodeURL, data.ModeRelay)
+	relayConfig.BlockingAckOnError = true
+	relayConfig.Relay = data.RelayConfig{ListenURL: relayURL}
+	relayHost := createHost(t, relayConfig)
+	defer func() {
+		_ = relayHost.Close()
+	}()
+
+	firstConsumer := createRelayConsumer(t, relayURL)
+	defer func() {
+		_ = firstConsumer.host.Close()
+	}()
+	secondConsumer := createRelayConsumer(t, relayURL)
+	defer func() {
+		_ = secondConsumer.host.Close()
+	}()
+
+	waitUntilConsumersAreConnected(t, node, firstConsumer, secondConsumer)
+
+	err = sendThroughRelay(t, node, []byte("payload 1"))
+	require.Nil(t, err)
+	requireReceived(t, firstConsumer.chReceived, []byte("payload 1"))
+	requireReceived(t, secondConsumer.chReceived, []byte("payload 1"))
+
+	secondConsumer.shouldFail.Store(true)
+	err = sendThroughRelay(t, node, []byte("payload 2"))
+	require.NotNil(t, err)
+	requireReceived(t, firstConsumer.chReceived, []byte("payload 2"))
+}
+
+func TestRelayShouldAcknowledgeUpstreamOnceTheQuorumAcknowledged(t *testing.T) {
+	nodeURL := "localhost:" + getFreePort()
+	relayURL := "localhost:" + getFreePort()
+
+	node, err := createServer(nodeURL, &testscommon.LoggerMock{})
+	require.Nil(t, err)
+	defer func() {
+		_ = node.Close()
+	}()
+
+	relayConfig := baseConfig(nodeURL, data.ModeRelay)
+	relayConfig.BlockingAckOnError = true
+	relayConfig.Relay = data.RelayConfig{ListenURL: relayURL, AckQuorum: 1}
+	relayHost := createHost(t, relayConfig)
+	defer func() {
+		_ = relayHost.Close()
+	}()
+
+	firstConsumer := createRelayConsumer(t, relayURL)
+	defer func() {
+		_ = firstConsumer.host.Close()
+	}()
+	secondConsumer := createRelayConsumer(t, relayURL)
+	defer func() {
+		_ = secondConsumer.host.Close()
+	}()
+
+	waitUntilConsumersAreConnected(t, node, firstConsumer, secondConsumer)
+
+	secondConsumer.shouldFail.Store(true)
+	err = sendThroughRelay(t, node, []byte("payload"))
+	require.Nil(t, err)
+	requireReceived(t, firstConsumer.chReceived, []byte("payload"))
+}
+
+func TestRelayShouldForwardTheSignatureOfTheOrigin(t *testing.T) {
+	keyGen := signing.NewKeyGenerator(secp256k1.NewSecp256k1())
+	originKey, originPublicKey := keyGen.GeneratePair()
+	originPublicKeyBytes, _ := originPublicKey.ToByteArray()
+	relayKey, _ := keyGen.GeneratePair()
+
+	createVerifier := func() websocket.MessageVerifier {
+		verifier, err := signature.NewMessageVerifier(signature.ArgsMessageVerifier{
+			Signer:            &singlesig.Secp256k1Signer{},
+			KeyGenerator:      keyGen,
+			TrustedPublicKeys: [][]byte{originPublicKeyBytes},
+		})
+		require.Nil(t, err)
+		return verifier
+	}
+	createSigner := func(privateKey crypto.PrivateKey) websocket.MessageSigner {
+		signer, err := signature.NewMessageSigner(signature.ArgsMessageSigner{
+			Signer:     &singlesig.Secp256k1Signer{},
+			PrivateKey: privateKey,
+		})
+		require.Nil(t, err)
+		return signer
+	}
+
+	nodeURL := "localhost:" + getFreePort()
+	relayURL := "localhost:" + getFreePort()
+
+	nodeArgs := createHostArgs(baseConfig(nodeURL, data.ModeServer))
+	nodeArgs.MessageSigner = createSigner(originKey)
+	node := createHostWithArgs(t, nodeArgs)
+	defer func() {
+		_ = node.Close()
+	}()
+
+	// the relay signs with its own key, which the consumer does not trust
+	relayConfig := baseConfig(nodeURL, data.ModeRelay)
+	relayConfig.BlockingAckOnError = true
+	relayConfig.Relay = data.RelayConfig{ListenURL: relayURL}
+	relayArgs := createHostArgs(relayConfig)
+	relayArgs.MessageSigner = createSigner(relayKey)
+	relayArgs.MessageVerifier = createVerifier()
+	downstreamMetrics := metrics.NewInMemoryMetrics()
+	relayArgs.Metrics = downstreamMetrics
+	upstreamMetrics := metrics.NewInMemoryMetrics()
+	relayArgs.UpstreamMetrics = upstreamMetrics
+	relayHost := createHostWithArgs(t, relayArgs)
+	defer func() {
+		_ = relayHost.Close()
+	}()
+
+	consumerMetrics := metrics.NewInMemoryMetrics()
+	consumerHostArgs := createHostArgs(baseConfig(relayURL, data.ModeClient))
+	consumerHostArgs.MessageVerifier = createVerifier()
+	consumerHostArgs.Metrics = consumerMetrics
+	consumerHost := createHostWithArgs(t, consumerHostArgs)
+	consumer := newRelayConsumer(consumerHost)
+	defer func() {
+		_ = consumer.host.Close()
+	}()
+
+	waitUntilConsumersAreConnected(t, node, consumer)
+
+	err := sendThroughRelay(t, node, []byte("signed payload"))
+	require.Nil(t, err)
+	requireReceived(t, consumer.chReceived, []byte("signed payload"))
+	require.Zero(t, consumerMetrics.GetSnapshot().Topics[outport.TopicSaveBlock].InvalidSignatures)
+
+	// every side of the relay has its own metrics
+	upstreamTopicMetrics := upstreamMetrics.GetSnapshot().Topics[outport.TopicSaveBlock]
+	require.NotZero(t, upstreamTopicMetrics.MessagesReceived)
+	require.Zero(t, upstreamTopicMetrics.MessagesSent)
+	downstreamTopicMetrics := downstreamMetrics.GetSnapshot().Topics[outport.TopicSaveBlock]
+	require.NotZero(t, downstreamTopicMetrics.MessagesSent)
+	require.Zero(t, downstreamTopicMetrics.MessagesReceived)
+}
+
+func TestRelayWithRateLimitedUpstreamShouldForwardTheSignatureOfTheOrigin(t *testing.T) {
+	keyGen := signing.NewKeyGenerator(secp256k1.NewSecp256k1())
+	originKey, originPublicKey := keyGen.GeneratePair()
+	originPublicKeyBytes, _ := originPublicKey.ToByteArray()
+
+	upstreamURL := "localhost:" + getFreePort()
+	relayURL := "localhost:" + getFreePort()
+
+	// the relay receives the payloads of the origin on a rate limited server
+	upstreamConfig := baseConfig(upstreamURL, data.ModeServer)
+	upstreamConfig.BlockingAckOnError = true
+	upstreamConfig.RateLimit = data.RateLimitConfig{
+		Topics: []data.TopicRateLimitConfig{
+			{Topic: outport.TopicSaveAccounts, MessagesPerSecond: 0.01, Burst: 1},
+		},
+	}
+	upstream := createHost(t, upstreamConfig)
+	downstreamHost, err := createServer(relayURL, &testscommon.LoggerMock{})
+	require.Nil(t, err)
+	relayHost, err := relay.NewRelay(relay.ArgsRelay{
+		Upstream:   upstream,
+		Downstream: downstreamHost,
+		Log:        &testscommon.LoggerMock{},
+	})
+	require.Nil(t, err)
+	defer func() {
+		_ = relayHost.Close()
+	}()
+
+	verifier, err := signature.NewMessageVerifier(signature.ArgsMessageVerifier{
+		Signer:            &singlesig.Secp256k1Signer{},
+		KeyGenerator:      keyGen,
+		TrustedPublicKeys: [][]byte{originPublicKeyBytes},
+	})
+	require.Nil(t, err)
+	consumerArgs := createHostArgs(baseConfig(relayURL, data.ModeClient))
+	consumerArgs.MessageVerifier = verifier
+	consumer := newRelayConsumer(createHostWithArgs(t, consumerArgs))
+	defer func() {
+		_ = consumer.host.Close()
+	}()
+
+	signer, err := signature.NewMessageSigner(signature.ArgsMessageSigner{
+		Signer:     &singlesig.Secp256k1Signer{},
+		PrivateKey: originKey,
+	})
+	require.Nil(t, err)
+	originArgs := createHostArgs(baseConfig(upstreamURL, data.ModeClient))
+	originArgs.MessageSigner = signer
+	origin := createHostWithArgs(t, originArgs)
+	defer func() {
+		_ = origin.Close()
+	}()
+
+	// the probes are sent on a topic without rate limits
+	waitUntilConsumersAreConnected(t, origin, consumer)
+
+	err = origin.Send([]byte("signed payload"), outport.TopicSaveAccounts)
+	require.Nil(t, err)
+	requireReceived(t, consumer.chReceived, []byte("signed payload"))
+
+	// the payload over the rate limits is neither forwarded nor acknowledged
+	err = origin.Send([]byte("limited payload"), outport.TopicSaveAccounts)
+	require.Equal(t, data.ErrAckTimeout, err)
+	require.Empty(t, consumer.chReceived)
+}
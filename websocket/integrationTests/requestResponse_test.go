@@ -0,0 +1,66 @@
+package integrationTests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/testscommon"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	"github.com/subrahamanyam341/andes-core-16/data/outport"
+)
+
+func createEchoRequestHandler(prefix string) *testscommon.RequestHandlerStub {
+	return &testscommon.RequestHandlerStub{
+		ProcessRequestCalled: func(payload []byte, topic string, _ uint32) ([]byte, error) {
+			if topic != outport.TopicSaveBlock {
+				return nil, &data.RequestError{Code: 404, Message: "unknown topic " + topic}
+			}
+			return append([]byte(prefix), payload...), nil
+		},
+	}
+}
+
+func TestClientAndServerRequestResponse(t *testing.T) {
+	url := "localhost:" + getFreePort()
+	wsServer, err := createServer(url, &testscommon.LoggerMock{})
+	require.Nil(t, err)
+	defer func() {
+		_ = wsServer.Close()
+	}()
+	err = wsServer.SetRequestHandler(createEchoRequestHandler("server: "))
+	require.Nil(t, err)
+
+	wsClient, err := createClient(url, &testscommon.LoggerMock{})
+	require.Nil(t, err)
+	defer func() {
+		_ = wsClient.Close()
+	}()
+	err = wsClient.SetRequestHandler(createEchoRequestHandler("client: "))
+	require.Nil(t, err)
+
+	// wait for the connection to be established
+	for {
+		_, err = wsServer.Request(context.Background(), outport.TopicSaveBlock, []byte("ping"))
+		if err == nil {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	response, err := wsClient.Request(ctx, outport.TopicSaveBlock, []byte("hello"))
+	require.Nil(t, err)
+	require.Equal(t, []byte("server: hello"), response)
+
+	response, err = wsServer.Request(ctx, outport.TopicSaveBlock, []byte("hello"))
+	require.Nil(t, err)
+	require.Equal(t, []byte("client: hello"), response)
+
+	response, err = wsClient.Request(ctx, outport.TopicSaveAccounts, []byte("hello"))
+	require.Nil(t, response)
+	require.Equal(t, &data.RequestError{Code: 404, Message: "unknown topic " + outport.TopicSaveAccounts}, err)
+}
@@ -0,0 +1,86 @@
+package integrationTests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/testscommon"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	"github.com/subrahamanyam341/andes-communication/websocket/metrics"
+	"github.com/subrahamanyam341/andes-communication/websocket/signature"
+	"github.com/subrahamanyam341/andes-core-16/data/outport"
+	"github.com/subrahamanyam341/andes-crypto-123/signing"
+	"github.com/subrahamanyam341/andes-crypto-123/signing/secp256k1"
+	"github.com/subrahamanyam341/andes-crypto-123/signing/secp256k1/singlesig"
+)
+
+func TestServerShouldProcessOnlyThePayloadsSignedByTrustedNodes(t *testing.T) {
+	keyGen := signing.NewKeyGenerator(secp256k1.NewSecp256k1())
+	trustedKey, trustedPublicKey := keyGen.GeneratePair()
+	trustedPublicKeyBytes, _ := trustedPublicKey.ToByteArray()
+	untrustedKey, _ := keyGen.GeneratePair()
+
+	messageVerifier, err := signature.NewMessageVerifier(signature.ArgsMessageVerifier{
+		Signer:            &singlesig.Secp256k1Signer{},
+		KeyGenerator:      keyGen,
+		TrustedPublicKeys: [][]byte{trustedPublicKeyBytes},
+	})
+	require.Nil(t, err)
+
+	serverMetrics := metrics.NewInMemoryMetrics()
+	serverURL := "localhost:" + getFreePort()
+	serverArgs := createHostArgs(baseConfig(serverURL, data.ModeServer))
+	serverArgs.MessageVerifier = messageVerifier
+	serverArgs.Metrics = serverMetrics
+	wsServer := createHostWithArgs(t, serverArgs)
+	defer func() {
+		_ = wsServer.Close()
+	}()
+
+	chReceived := make(chan []byte, 10)
+	_ = wsServer.SetPayloadHandler(&testscommon.PayloadHandlerStub{
+		ProcessPayloadCalled: func(payload []byte, _ string, _ uint32) error {
+			chReceived <- payload
+			return nil
+		},
+	})
+
+	untrustedSigner, err := signature.NewMessageSigner(signature.ArgsMessageSigner{
+		Signer:     &singlesig.Secp256k1Signer{},
+		PrivateKey: untrustedKey,
+	})
+	require.Nil(t, err)
+	untrustedClientArgs := createHostArgs(baseConfig(serverURL, data.ModeClient))
+	untrustedClientArgs.MessageSigner = untrustedSigner
+	untrustedClient := createHostWithArgs(t, untrustedClientArgs)
+	defer func() {
+		_ = untrustedClient.Close()
+	}()
+
+	// the invalid payload is dropped and acknowledged, so the client does not resend it
+	sendUntilSucceeds(t, untrustedClient, []byte("untrusted payload"))
+	require.Equal(t, uint64(1), serverMetrics.GetSnapshot().Topics[outport.TopicSaveBlock].InvalidSignatures)
+
+	trustedSigner, err := signature.NewMessageSigner(signature.ArgsMessageSigner{
+		Signer:     &singlesig.Secp256k1Signer{},
+		PrivateKey: trustedKey,
+	})
+	require.Nil(t, err)
+	trustedClientArgs := createHostArgs(baseConfig(serverURL, data.ModeClient))
+	trustedClientArgs.MessageSigner = trustedSigner
+	trustedClient := createHostWithArgs(t, trustedClientArgs)
+	defer func() {
+		_ = trustedClient.Close()
+	}()
+
+	sendUntilSucceeds(t, trustedClient, []byte("trusted payload"))
+
+	select {
+	case received := <-chReceived:
+		require.Equal(t, []byte("trusted payload"), received)
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "timeout waiting for the payload")
+	}
+	require.Empty(t, chReceived)
+}
@@ -0,0 +1,102 @@
+package integrationTests
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/testscommon"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	"github.com/subrahamanyam341/andes-core-16/data/outport"
+)
+
+type topicsRecorder struct {
+	mut    sync.Mutex
+	topics []string
+}
+
+func (tr *topicsRecorder) payloadHandler() *testscommon.PayloadHandlerStub {
+	return &testscommon.PayloadHandlerStub{
+		ProcessPayloadCalled: func(_ []byte, topic string, _ uint32) error {
+			tr.mut.Lock()
+			tr.topics = append(tr.topics, topic)
+			tr.mut.Unlock()
+			return nil
+		},
+	}
+}
+
+func (tr *topicsRecorder) receivedTopics() []string {
+	tr.mut.Lock()
+	defer tr.mut.Unlock()
+
+	return append([]string{}, tr.topics...)
+}
+
+func TestServerSendsOnlySubscribedTopics(t *testing.T) {
+	url := "localhost:" + getFreePort()
+
+	// the clients are ready to receive before the server starts
+	allTopicsRecorder := &topicsRecorder{}
+	allTopicsClient, err := createClient(url, &testscommon.LoggerMock{})
+	require.Nil(t, err)
+	defer func() {
+		_ = allTopicsClient.Close()
+	}()
+	_ = allTopicsClient.SetPayloadHandler(allTopicsRecorder.payloadHandler())
+
+	subscribedRecorder := &topicsRecorder{}
+	subscribedClientConfig := baseConfig(url, data.ModeClient)
+	subscribedClientConfig.SubscribedTopics = []string{outport.TopicSaveBlock}
+	subscribedClient := createHost(t, subscribedClientConfig)
+	defer func() {
+		_ = subscribedClient.Close()
+	}()
+	_ = subscribedClient.SetPayloadHandler(subscribedRecorder.payloadHandler())
+	client, ok := subscribedClient.(clientWithStateChanges)
+	require.True(t, ok)
+	states := &statesRecorder{}
+	client.OnStateChange(states.record)
+
+	wsServer, err := createServer(url, &testscommon.LoggerMock{})
+	require.Nil(t, err)
+	defer func() {
+		_ = wsServer.Close()
+	}()
+
+	// the subscriptions are sent before the client reports the open connection, and the server waits for the
+	// acknowledgements, so a block received after that proves the server processed the subscriptions
+	states.waitForLastState(t, data.ConnectionOpen)
+	require.Eventually(t, func() bool {
+		_ = wsServer.Send([]byte("block"), outport.TopicSaveBlock)
+		return len(subscribedRecorder.receivedTopics()) > 0 && len(allTopicsRecorder.receivedTopics()) > 0
+	}, 10*time.Second, 100*time.Millisecond)
+
+	err = wsServer.Send([]byte("accounts"), outport.TopicSaveAccounts)
+	require.Nil(t, err)
+	err = wsServer.Send([]byte("block"), outport.TopicSaveBlock)
+	require.Nil(t, err)
+
+	for _, topic := range subscribedRecorder.receivedTopics() {
+		require.Equal(t, outport.TopicSaveBlock, topic)
+	}
+	require.Contains(t, allTopicsRecorder.receivedTopics(), outport.TopicSaveAccounts)
+
+	unsubscriber, ok := subscribedClient.(interface{ Unsubscribe(topics ...string) error })
+	require.True(t, ok)
+	err = unsubscriber.Unsubscribe(outport.TopicSaveBlock)
+	require.Nil(t, err)
+
+	// a block not reaching the client any more proves the server processed the unsubscription
+	require.Eventually(t, func() bool {
+		numReceived := len(subscribedRecorder.receivedTopics())
+		_ = wsServer.Send([]byte("block"), outport.TopicSaveBlock)
+		return numReceived == len(subscribedRecorder.receivedTopics())
+	}, 10*time.Second, 100*time.Millisecond)
+
+	numReceived := len(subscribedRecorder.receivedTopics())
+	err = wsServer.Send([]byte("block"), outport.TopicSaveBlock)
+	require.Nil(t, err)
+	require.Equal(t, numReceived, len(subscribedRecorder.receivedTopics()))
+}
@@ -3,9 +3,13 @@ package integrationTests
 import (
 	"fmt"
 	"net"
+	"testing"
 
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/testscommon"
 	"github.com/subrahamanyam341/andes-communication/websocket"
 	"github.com/subrahamanyam341/andes-communication/websocket/client"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
 	hostFactory "github.com/subrahamanyam341/andes-communication/websocket/factory"
 	"github.com/subrahamanyam341/andes-communication/websocket/server"
 	"github.com/subrahamanyam341/andes-core-16/core"
@@ -45,6 +49,40 @@ func createServer(url string, log core.Logger) (hostFactory.FullDuplexHost, erro
 	})
 }
 
+// baseConfig returns the configuration of a host acknowledging every message, to be changed by each test only where it
+// needs to
+func baseConfig(url string, mode string) data.WebSocketConfig {
+	return data.WebSocketConfig{
+		URL:                     url,
+		Mode:                    mode,
+		RetryDurationInSec:      retryDurationInSeconds,
+		WithAcknowledge:         true,
+		AcknowledgeTimeoutInSec: retryDurationInSeconds,
+		Version:                 1,
+	}
+}
+
+// createHostArgs returns the arguments of a host with the provided configuration, to be completed by the tests
+// needing more components
+func createHostArgs(config data.WebSocketConfig) hostFactory.ArgsWebSocketHost {
+	return hostFactory.ArgsWebSocketHost{
+		WebSocketConfig: config,
+		Marshaller:      marshaller,
+		Log:             &testscommon.LoggerMock{},
+	}
+}
+
+func createHost(t *testing.T, config data.WebSocketConfig) hostFactory.FullDuplexHost {
+	return createHostWithArgs(t, createHostArgs(config))
+}
+
+func createHostWithArgs(t *testing.T, args hostFactory.ArgsWebSocketHost) hostFactory.FullDuplexHost {
+	host, err := hostFactory.CreateWebSocketHost(args)
+	require.Nil(t, err)
+
+	return host
+}
+
 func getFreePort() string {
 	// Listen on port 0 to get a free port
 	l, err := net.Listen("tcp", "localhost:0")
@@ -0,0 +1,70 @@
+package integrationTests
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/testscommon"
+	"github.com/subrahamanyam341/andes-communication/websocket"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	"github.com/subrahamanyam341/andes-core-16/data/outport"
+)
+
+func TestClientAndServerWithTopicRouters(t *testing.T) {
+	url := "localhost:" + getFreePort()
+	serverConfig := baseConfig(url, data.ModeServer)
+	serverConfig.BlockingAckOnError = true
+	wsServer := createHost(t, serverConfig)
+	defer func() {
+		_ = wsServer.Close()
+	}()
+
+	blocksRecorder := &topicsRecorder{}
+	savesRecorder := &topicsRecorder{}
+	serverRouter, err := websocket.NewTopicRouter(&testscommon.LoggerMock{})
+	require.Nil(t, err)
+	_ = serverRouter.RegisterHandler(outport.TopicSaveBlock, blocksRecorder.payloadHandler(), data.PropagateErrorPolicy)
+	_ = serverRouter.RegisterHandler("Save*", savesRecorder.payloadHandler(), data.PropagateErrorPolicy)
+	// the failures of this handler should not block the acknowledgements
+	_ = serverRouter.SetFallbackHandler(&testscommon.PayloadHandlerStub{
+		ProcessPayloadCalled: func(_ []byte, _ string, _ uint32) error {
+			return errors.New("cannot process the payload")
+		},
+	}, data.IgnoreErrorPolicy)
+	_ = wsServer.SetPayloadHandler(serverRouter)
+
+	wsClient, err := createClient(url, &testscommon.LoggerMock{})
+	require.Nil(t, err)
+	defer func() {
+		_ = wsClient.Close()
+	}()
+
+	finalizedRecorder := &topicsRecorder{}
+	clientRouter, err := websocket.NewTopicRouter(&testscommon.LoggerMock{})
+	require.Nil(t, err)
+	_ = clientRouter.RegisterHandler("*Block", finalizedRecorder.payloadHandler(), data.PropagateErrorPolicy)
+	_ = wsClient.SetPayloadHandler(clientRouter)
+
+	// wait for the connection to be established
+	for {
+		err = wsClient.Send([]byte("block"), outport.TopicSaveBlock)
+		if err == nil {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	err = wsClient.Send([]byte("accounts"), outport.TopicSaveAccounts)
+	require.Nil(t, err)
+	err = wsClient.Send([]byte("settings"), outport.TopicSettings)
+	require.Nil(t, err)
+
+	require.Equal(t, []string{outport.TopicSaveBlock}, blocksRecorder.receivedTopics())
+	require.Equal(t, []string{outport.TopicSaveAccounts}, savesRecorder.receivedTopics())
+
+	err = wsServer.Send([]byte("finalized"), outport.TopicFinalizedBlock)
+	require.Nil(t, err)
+	require.Equal(t, []string{outport.TopicFinalizedBlock}, finalizedRecorder.receivedTopics())
+}
@@ -3,17 +3,33 @@ package websocket
 import (
 	"context"
 	"io"
+	"net/http"
+	"time"
 
 	"github.com/subrahamanyam341/andes-communication/websocket/data"
 )
 
-// PayloadHandler defines what a payload handler should be able to do
+// PayloadHandler defines what a payload handler should be able to do. A payload refused with an error wrapping
+// data.ErrPayloadRefused is never acknowledged
 type PayloadHandler interface {
 	ProcessPayload(payload []byte, topic string, version uint32) error
 	Close() error
 	IsInterfaceNil() bool
 }
 
+// PayloadMessageHandler defines what a payload handler that needs the whole received payload message, including the
+// signature of its origin, should be able to do. The transceiver passes the message to a payload handler implementing it
+type PayloadMessageHandler interface {
+	ProcessPayloadMessage(message *data.WsMessage) error
+}
+
+// RequestHandler defines what a component that answers the requests should be able to do. A *data.RequestError
+// returned by ProcessRequest is sent back to the requester as it is
+type RequestHandler interface {
+	ProcessRequest(payload []byte, topic string, version uint32) ([]byte, error)
+	IsInterfaceNil() bool
+}
+
 // PayloadConverter defines what a websocket payload converter should do
 type PayloadConverter interface {
 	ExtractWsMessage(payload []byte) (*data.WsMessage, error)
@@ -21,6 +37,35 @@ type PayloadConverter interface {
 	IsInterfaceNil() bool
 }
 
+// AckFuture defines the outcome of a message sent without waiting for its acknowledgement
+type AckFuture interface {
+	Done() <-chan struct{}
+	Err() error
+	Wait() error
+}
+
+// SubscriptionsHandler defines what a component that tracks the topics the peers are interested in should be able to do
+type SubscriptionsHandler interface {
+	Subscribe(connectionID string, topics []string)
+	Unsubscribe(connectionID string, topics []string)
+	IsInterfaceNil() bool
+}
+
+// SessionsTracker defines what a component that tracks the sequences processed from each sender session should be
+// able to do, so the duplicated messages are dropped
+type SessionsTracker interface {
+	IsDuplicate(sessionID string, sequence uint64) bool
+	MarkProcessed(sessionID string, sequence uint64) (numMissing uint64)
+	IsInterfaceNil() bool
+}
+
+// ReconnectPolicy defines how long a client waits before trying again to open the connection and when it gives up.
+// The provided attempt is the number of consecutive failed attempts, starting from 1
+type ReconnectPolicy interface {
+	NextDelay(attempt int) (delay time.Duration, shouldRetry bool)
+	IsInterfaceNil() bool
+}
+
 // WSConClient defines what a web-sockets connection client should be able to do
 type WSConClient interface {
 	io.Closer
@@ -35,5 +80,60 @@ type WSConClient interface {
 // HttpServerHandler defines the minimum behaviour of a http server
 type HttpServerHandler interface {
 	ListenAndServe() error
+	ListenAndServeTLS(certFile string, keyFile string) error
 	Shutdown(ctx context.Context) error
 }
+
+// HandshakeAuthenticator defines what a component that authenticates the websocket upgrade requests should be able to do
+type HandshakeAuthenticator interface {
+	Authenticate(request *http.Request) error
+	IsInterfaceNil() bool
+}
+
+// CredentialsProvider defines what a component that provides the credentials for the websocket upgrade requests should be able to do
+type CredentialsProvider interface {
+	CreateCredentials(host string, route string) (http.Header, error)
+	IsInterfaceNil() bool
+}
+
+// MessageSigner defines what a component that signs the outgoing payload messages should be able to do
+type MessageSigner interface {
+	Sign(message *data.WsMessage) error
+	IsInterfaceNil() bool
+}
+
+// MessageVerifier defines what a component that verifies the signature of the received payload messages should be able to do
+type MessageVerifier interface {
+	Verify(message *data.WsMessage) error
+	IsInterfaceNil() bool
+}
+
+// OutboundQueue defines what a persistent queue of outgoing messages should be able to do
+type OutboundQueue interface {
+	Add(payload []byte, topic string) error
+	Peek() (*data.QueuedMessage, error)
+	Remove(id uint64) error
+	Len() int
+	Close() error
+	IsInterfaceNil() bool
+}
+
+// MetricsHandler defines what a component that collects the metrics of a websocket host should be able to do
+type MetricsHandler interface {
+	MessageSent(topic string, numBytes int)
+	MessageReceived(topic string, numBytes int)
+	AckReceived(latency time.Duration)
+	AckTimedOut()
+	Reconnected()
+	ClientConnected()
+	ClientDisconnected()
+	HandlerFailed(topic string)
+	InvalidSignature(topic string)
+	IsInterfaceNil() bool
+}
+
+// MetricsSnapshotProvider defines what a component that can report the collected metrics should be able to do
+type MetricsSnapshotProvider interface {
+	GetSnapshot() data.MetricsSnapshot
+	IsInterfaceNil() bool
+}
@@ -0,0 +1,203 @@
+package memory
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/websocket"
+	webSocket "github.com/subrahamanyam341/andes-communication/websocket"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	"github.com/subrahamanyam341/andes-core-16/core/check"
+)
+
+// ArgsConn holds the arguments needed for creating an in-memory connection that will be dialed by the client
+type ArgsConn struct {
+	CredentialsProvider webSocket.CredentialsProvider
+	HandshakeHeader     http.Header
+	Subprotocols        []string
+	Config              data.MemoryTransportConfig
+}
+
+// conn is an in-memory replacement of the websocket connection, dialing the listeners of the same process
+type conn struct {
+	mut                 sync.RWMutex
+	end                 *end
+	id                  string
+	credentialsProvider webSocket.CredentialsProvider
+	handshakeHeader     http.Header
+	subprotocols        []string
+	responseHeader      http.Header
+	subprotocol         string
+	faults              faults
+}
+
+// NewConn creates an in-memory connection that will be dialed using the provided arguments
+func NewConn(args ArgsConn) *conn {
+	return &conn{
+		credentialsProvider: args.CredentialsProvider,
+		handshakeHeader:     args.HandshakeHeader,
+		subprotocols:        args.Subprotocols,
+		faults:              newFaults(args.Config),
+	}
+}
+
+func newServerConn(serverEnd *end, subprotocol string) *conn {
+	c := &conn{
+		end:         serverEnd,
+		subprotocol: subprotocol,
+	}
+	c.id = fmt.Sprintf("%p", c)
+
+	return c
+}
+
+// OpenConnection will dial the in-memory listener serving the provided websocket url
+func (c *conn) OpenConnection(wsURL string) error {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	if c.end != nil {
+		return data.ErrConnectionAlreadyOpen
+	}
+
+	parsedURL, err := url.Parse(wsURL)
+	if err != nil {
+		return err
+	}
+	l, err := defaultNetwork.getListener(parsedURL)
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, wsURL)
+	}
+
+	request, err := c.createHandshakeRequest(parsedURL)
+	if err != nil {
+		return err
+	}
+
+	clientEnd, responseHeader, err := l.dial(request, c.faults)
+	if err != nil {
+		return err
+	}
+
+	c.end = clientEnd
+	c.responseHeader = responseHeader
+	c.subprotocol = responseHeader.Get(subprotocolHeader)
+
+	return nil
+}
+
+func (c *conn) createHandshakeRequest(wsURL *url.URL) (*http.Request, error) {
+	header := c.handshakeHeader.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	if !check.IfNil(c.credentialsProvider) {
+		credentials, err := c.credentialsProvider.CreateCredentials(wsURL.Host, wsURL.Path)
+		if err != nil {
+			return nil, err
+		}
+		for key, values := range credentials {
+			header[key] = values
+		}
+	}
+	if len(c.subprotocols) > 0 {
+		header.Set(subprotocolHeader, strings.Join(c.subprotocols, ", "))
+	}
+
+	return &http.Request{
+		Method:     http.MethodGet,
+		URL:        wsURL,
+		Host:       wsURL.Host,
+		Header:     header,
+		RemoteAddr: fmt.Sprintf("memory:%p", c),
+	}, nil
+}
+
+// ReadMessage blocks until the peer writes a message or the connection is closed
+func (c *conn) ReadMessage() (messageType int, p []byte, err error) {
+	connectionEnd, err := c.getEnd()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return connectionEnd.read()
+}
+
+// WriteMessage hands the message to the peer after the configured latency, unless it is dropped
+func (c *conn) WriteMessage(messageType int, payload []byte) error {
+	connectionEnd, err := c.getEnd()
+	if err != nil {
+		return err
+	}
+
+	return connectionEnd.write(messageType, payload)
+}
+
+func (c *conn) getEnd() (*end, error) {
+	c.mut.RLock()
+	defer c.mut.RUnlock()
+
+	if c.end == nil {
+		return nil, data.ErrConnectionNotOpen
+	}
+
+	return c.end, nil
+}
+
+// IsOpen will return true if the connection is open, false otherwise
+func (c *conn) IsOpen() bool {
+	c.mut.RLock()
+	defer c.mut.RUnlock()
+
+	return c.end != nil
+}
+
+// GetHandshakeResponseHeader will return the header of the response received when the connection was opened
+func (c *conn) GetHandshakeResponseHeader() http.Header {
+	c.mut.RLock()
+	defer c.mut.RUnlock()
+
+	return c.responseHeader
+}
+
+// Subprotocol returns the subprotocol negotiated during the handshake
+func (c *conn) Subprotocol() string {
+	c.mut.RLock()
+	defer c.mut.RUnlock()
+
+	return c.subprotocol
+}
+
+// GetID will return the unique id of the connection
+func (c *conn) GetID() string {
+	return c.id
+}
+
+// Close will close the connection, the peer reading a normal closure
+func (c *conn) Close() error {
+	return c.CloseWithReason(websocket.CloseNormalClosure, "")
+}
+
+// CloseWithReason will close the connection, the peer reading the provided close code and reason
+func (c *conn) CloseWithReason(code int, reason string) error {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	if c.end == nil {
+		return data.ErrConnectionNotOpen
+	}
+
+	log.Debug("closing memory connection...")
+	c.end.close(c.end, code, reason)
+	c.end = nil
+
+	return nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (c *conn) IsInterfaceNil() bool {
+	return c == nil
+}
@@ -0,0 +1,306 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/websocket/authentication"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+)
+
+const testPath = "/save"
+
+func createListener(t *testing.T, url string, config data.MemoryTransportConfig) (*listener, chan *conn) {
+	chAccepted := make(chan *conn, 10)
+	l, err := NewListener(ArgsListener{
+		URL:          url,
+		Path:         testPath,
+		Subprotocols: []string{"first", "second"},
+		Handler: func(request *http.Request, upgrade UpgradeFunc) error {
+			chAccepted <- upgrade(http.Header{"X-Test": []string{request.Header.Get("X-Test")}})
+			return nil
+		},
+		Config: config,
+	})
+	require.Nil(t, err)
+	t.Cleanup(func() {
+		_ = l.Shutdown(context.Background())
+	})
+
+	return l, chAccepted
+}
+
+func dial(t *testing.T, url string, config data.MemoryTransportConfig) *conn {
+	c := NewConn(ArgsConn{
+		HandshakeHeader: http.Header{"X-Test": []string{"handshake"}},
+		Subprotocols:    []string{"second"},
+		Config:          config,
+	})
+	require.Nil(t, c.OpenConnection("ws://"+url+testPath))
+	t.Cleanup(func() {
+		_ = c.Close()
+	})
+
+	return c
+}
+
+func readPayload(t *testing.T, c *conn) string {
+	messageType, payload, err := c.ReadMessage()
+	require.Nil(t, err)
+	require.Equal(t, websocket.BinaryMessage, messageType)
+
+	return string(payload)
+}
+
+func TestNewListener(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty url, should return error", func(t *testing.T) {
+		l, err := NewListener(ArgsListener{})
+		require.Nil(t, l)
+		require.Equal(t, data.ErrEmptyUrl, err)
+	})
+	t.Run("invalid faults, should return error", func(t *testing.T) {
+		l, err := NewListener(ArgsListener{URL: "listener-faults", Config: data.MemoryTransportConfig{DropPercentage: 200}})
+		require.Nil(t, l)
+		require.Equal(t, data.ErrInvalidMemoryTransportConfig, err)
+	})
+	t.Run("address in use, should return error", func(t *testing.T) {
+		createListener(t, "listener-in-use", data.MemoryTransportConfig{})
+		l, err := NewListener(ArgsListener{URL: "listener-in-use", Path: testPath})
+		require.Nil(t, l)
+		require.True(t, errors.Is(err, data.ErrMemoryAddressInUse))
+	})
+	t.Run("TLS is not supported", func(t *testing.T) {
+		l, _ := createListener(t, "listener-tls", data.MemoryTransportConfig{})
+		require.True(t, errors.Is(l.ListenAndServeTLS("", ""), data.ErrNotSupportedByMemoryTransport))
+	})
+}
+
+func TestListener_ShutdownShouldReleaseTheAddress(t *testing.T) {
+	t.Parallel()
+
+	l, _ := createListener(t, "listener-shutdown", data.MemoryTransportConfig{})
+	chServeDone := make(chan error)
+	go func() {
+		chServeDone <- l.ListenAndServe()
+	}()
+
+	require.Nil(t, l.Shutdown(context.Background()))
+	require.Equal(t, http.ErrServerClosed, <-chServeDone)
+
+	c := NewConn(ArgsConn{})
+	err := c.OpenConnection("ws://listener-shutdown" + testPath)
+	require.True(t, errors.Is(err, data.ErrNoMemoryListener))
+	require.False(t, c.IsOpen())
+
+	createListener(t, "listener-shutdown", data.MemoryTransportConfig{})
+}
+
+func TestConn_OpenConnection(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no listener, should return error", func(t *testing.T) {
+		c := NewConn(ArgsConn{})
+		err := c.OpenConnection("ws://missing" + testPath)
+		require.True(t, errors.Is(err, data.ErrNoMemoryListener))
+	})
+	t.Run("should send the credentials in the handshake", func(t *testing.T) {
+		authenticator, _ := authentication.NewBearerTokenAuthenticator([]string{"token"})
+		credentialsProvider, _ := authentication.NewBearerTokenCredentialsProvider("token")
+		l, err := NewListener(ArgsListener{
+			URL: "conn-credentials",
+			Handler: func(request *http.Request, upgrade UpgradeFunc) error {
+				errAuthenticate := authenticator.Authenticate(request)
+				if errAuthenticate != nil {
+					return errAuthenticate
+				}
+				upgrade(nil)
+				return nil
+			},
+		})
+		require.Nil(t, err)
+		defer func() {
+			_ = l.Shutdown(context.Background())
+		}()
+
+		c := NewConn(ArgsConn{})
+		err = c.OpenConnection("ws://conn-credentials")
+		require.True(t, errors.Is(err, data.ErrMemoryHandshakeRejected))
+
+		c = NewConn(ArgsConn{CredentialsProvider: credentialsProvider})
+		require.Nil(t, c.OpenConnection("ws://conn-credentials"))
+		require.Nil(t, c.Close())
+	})
+	t.Run("rejected handshake, should return error", func(t *testing.T) {
+		l, err := NewListener(ArgsListener{
+			URL: "conn-rejected",
+			Handler: func(request *http.Request, upgrade UpgradeFunc) error {
+				return errors.New("unauthorized")
+			},
+		})
+		require.Nil(t, err)
+		defer func() {
+			_ = l.Shutdown(context.Background())
+		}()
+
+		c := NewConn(ArgsConn{})
+		err = c.OpenConnection("ws://conn-rejected")
+		require.True(t, errors.Is(err, data.ErrMemoryHandshakeRejected))
+		require.True(t, strings.Contains(err.Error(), "unauthorized"))
+	})
+	t.Run("already open, should return error", func(t *testing.T) {
+		createListener(t, "conn-already-open", data.MemoryTransportConfig{})
+		c := dial(t, "conn-already-open", data.MemoryTransportConfig{})
+		require.Equal(t, data.ErrConnectionAlreadyOpen, c.OpenConnection("ws://conn-already-open"+testPath))
+	})
+	t.Run("should negotiate the handshake", func(t *testing.T) {
+		_, chAccepted := createListener(t, "conn-handshake", data.MemoryTransportConfig{})
+		c := dial(t, "conn-handshake", data.MemoryTransportConfig{})
+		serverConn := <-chAccepted
+
+		require.True(t, c.IsOpen())
+		require.Equal(t, "second", c.Subprotocol())
+		require.Equal(t, "second", serverConn.Subprotocol())
+		require.Equal(t, "handshake", c.GetHandshakeResponseHeader().Get("X-Test"))
+		require.NotEmpty(t, serverConn.GetID())
+		require.False(t, c.IsInterfaceNil())
+	})
+}
+
+func TestConn_ReadAndWrite(t *testing.T) {
+	t.Parallel()
+
+	_, chAccepted := createListener(t, "conn-read-write", data.MemoryTransportConfig{})
+	c := dial(t, "conn-read-write", data.MemoryTransportConfig{})
+	serverConn := <-chAccepted
+
+	payload := []byte("to server")
+	require.Nil(t, c.WriteMessage(websocket.BinaryMessage, payload))
+	payload[0] = 'X'
+	require.Nil(t, serverConn.WriteMessage(websocket.BinaryMessage, []byte("to client")))
+
+	require.Equal(t, "to server", readPayload(t, serverConn))
+	require.Equal(t, "to client", readPayload(t, c))
+}
+
+func TestConn_Close(t *testing.T) {
+	t.Parallel()
+
+	t.Run("not open, should return error", func(t *testing.T) {
+		c := NewConn(ArgsConn{})
+		require.Equal(t, data.ErrConnectionNotOpen, c.Close())
+		_, _, err := c.ReadMessage()
+		require.Equal(t, data.ErrConnectionNotOpen, err)
+		require.Equal(t, data.ErrConnectionNotOpen, c.WriteMessage(websocket.BinaryMessage, nil))
+	})
+	t.Run("peer should read the messages written before the close and then the close reason", func(t *testing.T) {
+		_, chAccepted := createListener(t, "conn-close", data.MemoryTransportConfig{})
+		c := dial(t, "conn-close", data.MemoryTransportConfig{})
+		serverConn := <-chAccepted
+
+		chRead := make(chan error)
+		go func() {
+			_, _, err := c.ReadMessage()
+			chRead <- err
+		}()
+
+		require.Nil(t, serverConn.WriteMessage(websocket.BinaryMessage, []byte("last")))
+		require.Nil(t, <-chRead)
+		require.Nil(t, serverConn.WriteMessage(websocket.BinaryMessage, []byte("before close")))
+		time.Sleep(50 * time.Millisecond)
+		require.Nil(t, serverConn.CloseWithReason(websocket.ClosePolicyViolation, "no common version"))
+		require.False(t, serverConn.IsOpen())
+
+		require.Equal(t, "before close", readPayload(t, c))
+		_, _, err := c.ReadMessage()
+		closeErr := &websocket.CloseError{}
+		require.True(t, errors.As(err, &closeErr))
+		require.Equal(t, websocket.ClosePolicyViolation, closeErr.Code)
+		require.Equal(t, "no common version", closeErr.Text)
+		require.True(t, errors.As(c.WriteMessage(websocket.BinaryMessage, nil), &closeErr))
+
+		// the peer can still release its end
+		require.True(t, c.IsOpen())
+		require.Nil(t, c.Close())
+		require.False(t, c.IsOpen())
+	})
+	t.Run("closing end should read a closed connection", func(t *testing.T) {
+		_, chAccepted := createListener(t, "conn-close-own", data.MemoryTransportConfig{})
+		c := dial(t, "conn-close-own", data.MemoryTransportConfig{})
+		<-chAccepted
+
+		chRead := make(chan error)
+		go func() {
+			_, _, err := c.ReadMessage()
+			chRead <- err
+		}()
+
+		time.Sleep(50 * time.Millisecond)
+		require.Nil(t, c.Close())
+		err := <-chRead
+		require.True(t, strings.Contains(err.Error(), data.ClosedConnectionMessage))
+	})
+}
+
+func TestConn_Faults(t *testing.T) {
+	t.Parallel()
+
+	t.Run("latency should delay the messages and keep their order", func(t *testing.T) {
+		latency := 100 * time.Millisecond
+		_, chAccepted := createListener(t, "conn-latency", data.MemoryTransportConfig{})
+		c := dial(t, "conn-latency", data.MemoryTransportConfig{LatencyInMs: int(latency.Milliseconds())})
+		serverConn := <-chAccepted
+
+		start := time.Now()
+		for _, payload := range []string{"first", "second", "third"} {
+			require.Nil(t, c.WriteMessage(websocket.BinaryMessage, []byte(payload)))
+		}
+
+		require.Equal(t, "first", readPayload(t, serverConn))
+		require.GreaterOrEqual(t, time.Since(start), latency)
+		require.Equal(t, "second", readPayload(t, serverConn))
+		require.Equal(t, "third", readPayload(t, serverConn))
+	})
+	t.Run("dropped messages should be lost", func(t *testing.T) {
+		_, chAccepted := createListener(t, "conn-drops", data.MemoryTransportConfig{DropPercentage: 100})
+		c := dial(t, "conn-drops", data.MemoryTransportConfig{})
+		serverConn := <-chAccepted
+
+		require.Nil(t, serverConn.WriteMessage(websocket.BinaryMessage, []byte("lost")))
+		require.Nil(t, c.WriteMessage(websocket.BinaryMessage, []byte("delivered")))
+		require.Equal(t, "delivered", readPayload(t, serverConn))
+
+		require.Nil(t, serverConn.Close())
+		_, _, err := c.ReadMessage()
+		closeErr := &websocket.CloseError{}
+		require.True(t, errors.As(err, &closeErr))
+		require.Equal(t, websocket.CloseNormalClosure, closeErr.Code)
+	})
+	t.Run("dropped connections should be abnormally closed on both ends", func(t *testing.T) {
+		_, chAccepted := createListener(t, "conn-dropped", data.MemoryTransportConfig{})
+		c := dial(t, "conn-dropped", data.MemoryTransportConfig{})
+		serverConn := <-chAccepted
+
+		require.Equal(t, 1, DropConnections("conn-dropped"))
+		require.Equal(t, 0, DropConnections("conn-dropped-other"))
+
+		for _, connectionEnd := range []*conn{c, serverConn} {
+			_, _, err := connectionEnd.ReadMessage()
+			closeErr := &websocket.CloseError{}
+			require.True(t, errors.As(err, &closeErr))
+			require.Equal(t, websocket.CloseAbnormalClosure, closeErr.Code)
+			require.Nil(t, connectionEnd.Close())
+		}
+
+		require.Eventually(t, func() bool {
+			return DropConnections("conn-dropped") == 0
+		}, time.Second, 10*time.Millisecond)
+	})
+}
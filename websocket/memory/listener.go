@@ -0,0 +1,170 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/websocket"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+)
+
+// subprotocolHeader is the header holding the requested and the selected websocket subprotocols
+const subprotocolHeader = "Sec-Websocket-Protocol"
+
+// HandshakeHandler is called for every dialed connection. It rejects the connection by returning an error, otherwise it
+// calls the upgrade function with the handshake response header and receives the server end of the connection
+type HandshakeHandler func(request *http.Request, upgrade UpgradeFunc) error
+
+// UpgradeFunc accepts a dialed connection and returns its server end
+type UpgradeFunc func(responseHeader http.Header) *conn
+
+// ArgsListener holds the arguments needed for creating an in-memory listener
+type ArgsListener struct {
+	URL          string
+	Path         string
+	Subprotocols []string
+	Handler      HandshakeHandler
+	Config       data.MemoryTransportConfig
+}
+
+// listener accepts the in-memory connections dialed on its address. It replaces the http server of a websocket server
+type listener struct {
+	url          string
+	address      string
+	subprotocols []string
+	handler      HandshakeHandler
+	faults       faults
+	mutPipes     sync.Mutex
+	pipes        map[*pipe]*end
+	shutdownOnce sync.Once
+	chShutdown   chan struct{}
+}
+
+// NewListener creates an in-memory listener, registered right away on the address made of the URL and the path
+func NewListener(args ArgsListener) (*listener, error) {
+	if args.URL == "" {
+		return nil, data.ErrEmptyUrl
+	}
+	err := CheckTransport(data.MemoryTransport, args.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &listener{
+		url:          args.URL,
+		address:      createAddress(args.URL, args.Path),
+		subprotocols: args.Subprotocols,
+		handler:      args.Handler,
+		faults:       newFaults(args.Config),
+		pipes:        make(map[*pipe]*end),
+		chShutdown:   make(chan struct{}),
+	}
+
+	err = defaultNetwork.register(l.address, l)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, l.address)
+	}
+
+	return l, nil
+}
+
+// dial runs the handshake of a new connection and returns its client end together with the handshake response header
+func (l *listener) dial(request *http.Request, clientFaults faults) (*end, http.Header, error) {
+	var clientEnd *end
+	var responseHeader http.Header
+	upgrade := func(header http.Header) *conn {
+		responseHeader = header.Clone()
+		if responseHeader == nil {
+			responseHeader = http.Header{}
+		}
+		subprotocol := l.selectSubprotocol(request)
+		if subprotocol != "" {
+			responseHeader.Set(subprotocolHeader, subprotocol)
+		}
+
+		var serverEnd *end
+		clientEnd, serverEnd = newPipe(clientFaults, l.faults)
+		l.track(serverEnd)
+
+		return newServerConn(serverEnd, subprotocol)
+	}
+
+	err := l.handler(request, upgrade)
+	if err != nil {
+		if clientEnd != nil {
+			clientEnd.close(nil, websocket.CloseAbnormalClosure, "")
+		}
+		return nil, nil, fmt.Errorf("%w: %s", data.ErrMemoryHandshakeRejected, err.Error())
+	}
+	if clientEnd == nil {
+		return nil, nil, data.ErrMemoryHandshakeRejected
+	}
+
+	return clientEnd, responseHeader, nil
+}
+
+// selectSubprotocol returns the first subprotocol of the listener requested by the client, as the websocket upgrader does
+func (l *listener) selectSubprotocol(request *http.Request) string {
+	requested := websocket.Subprotocols(request)
+	for _, subprotocol := range l.subprotocols {
+		for _, requestedSubprotocol := range requested {
+			if subprotocol == requestedSubprotocol {
+				return subprotocol
+			}
+		}
+	}
+
+	return ""
+}
+
+func (l *listener) track(serverEnd *end) {
+	l.mutPipes.Lock()
+	l.pipes[serverEnd.pipe] = serverEnd
+	l.mutPipes.Unlock()
+
+	go func() {
+		<-serverEnd.pipe.chClosed
+
+		l.mutPipes.Lock()
+		delete(l.pipes, serverEnd.pipe)
+		l.mutPipes.Unlock()
+	}()
+}
+
+func (l *listener) dropConnections() int {
+	l.mutPipes.Lock()
+	serverEnds := make([]*end, 0, len(l.pipes))
+	for _, serverEnd := range l.pipes {
+		serverEnds = append(serverEnds, serverEnd)
+	}
+	l.mutPipes.Unlock()
+
+	for _, serverEnd := range serverEnds {
+		serverEnd.close(nil, websocket.CloseAbnormalClosure, "")
+	}
+
+	return len(serverEnds)
+}
+
+// ListenAndServe blocks until the listener is shut down, the connections being accepted since its creation
+func (l *listener) ListenAndServe() error {
+	<-l.chShutdown
+	return http.ErrServerClosed
+}
+
+// ListenAndServeTLS returns an error, the in-memory connections are not encrypted
+func (l *listener) ListenAndServeTLS(_ string, _ string) error {
+	return fmt.Errorf("%w: TLS", data.ErrNotSupportedByMemoryTransport)
+}
+
+// Shutdown stops accepting new connections. As for the http server, the accepted connections are left open
+func (l *listener) Shutdown(_ context.Context) error {
+	l.shutdownOnce.Do(func() {
+		defaultNetwork.unregister(l.address, l)
+		close(l.chShutdown)
+	})
+
+	return nil
+}
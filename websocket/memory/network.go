@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"net/url"
+	"sync"
+
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+)
+
+// network holds the in-memory listeners of the process, by the address they serve
+type network struct {
+	mut       sync.RWMutex
+	listeners map[string]*listener
+}
+
+var defaultNetwork = &network{
+	listeners: make(map[string]*listener),
+}
+
+func createAddress(host string, path string) string {
+	return host + path
+}
+
+func (n *network) register(address string, l *listener) error {
+	n.mut.Lock()
+	defer n.mut.Unlock()
+
+	_, found := n.listeners[address]
+	if found {
+		return data.ErrMemoryAddressInUse
+	}
+
+	n.listeners[address] = l
+	return nil
+}
+
+func (n *network) unregister(address string, l *listener) {
+	n.mut.Lock()
+	defer n.mut.Unlock()
+
+	if n.listeners[address] == l {
+		delete(n.listeners, address)
+	}
+}
+
+func (n *network) getListener(wsURL *url.URL) (*listener, error) {
+	n.mut.RLock()
+	defer n.mut.RUnlock()
+
+	l, found := n.listeners[createAddress(wsURL.Host, wsURL.Path)]
+	if !found {
+		return nil, data.ErrNoMemoryListener
+	}
+
+	return l, nil
+}
+
+func (n *network) getListenersOfHost(host string) []*listener {
+	n.mut.RLock()
+	defer n.mut.RUnlock()
+
+	listeners := make([]*listener, 0, 1)
+	for _, l := range n.listeners {
+		if l.url == host {
+			listeners = append(listeners, l)
+		}
+	}
+
+	return listeners
+}
+
+// DropConnections drops the in-memory connections accepted by the listeners of the provided server URL, as a broken
+// network would: both peers read an abnormal closure. It returns the number of dropped connections
+func DropConnections(serverURL string) int {
+	numDropped := 0
+	for _, l := range defaultNetwork.getListenersOfHost(serverURL) {
+		numDropped += l.dropConnections()
+	}
+
+	return numDropped
+}
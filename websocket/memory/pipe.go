@@ -0,0 +1,173 @@
+package memory
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+)
+
+// frameBufferSize is the number of messages an end of an in-memory connection buffers before the writes block
+const frameBufferSize = 1024
+
+// errUseOfClosedConnection is returned to the end that closed the connection, as a closed network connection would
+var errUseOfClosedConnection = errors.New(data.ClosedConnectionMessage)
+
+// frame is a message written on one end of an in-memory connection
+type frame struct {
+	messageType int
+	payload     []byte
+	deliverAt   time.Time
+}
+
+// pipe links the two ends of an in-memory connection. Closing it from any end closes both of them
+type pipe struct {
+	closeOnce  sync.Once
+	chClosed   chan struct{}
+	closedBy   *end
+	closeError *websocket.CloseError
+}
+
+// end is one side of an in-memory connection. The written messages wait in its outbox for the injected latency and
+// are then moved, in order, to the inbox of the peer
+type end struct {
+	pipe   *pipe
+	peer   *end
+	inbox  chan frame
+	outbox chan frame
+	faults faults
+}
+
+func newPipe(clientFaults faults, serverFaults faults) (*end, *end) {
+	p := &pipe{
+		chClosed: make(chan struct{}),
+	}
+
+	clientEnd := newEnd(p, clientFaults)
+	serverEnd := newEnd(p, serverFaults)
+	clientEnd.peer = serverEnd
+	serverEnd.peer = clientEnd
+
+	go clientEnd.deliver()
+	go serverEnd.deliver()
+
+	return clientEnd, serverEnd
+}
+
+func newEnd(p *pipe, f faults) *end {
+	return &end{
+		pipe:   p,
+		inbox:  make(chan frame, frameBufferSize),
+		outbox: make(chan frame, frameBufferSize),
+		faults: f,
+	}
+}
+
+func (e *end) write(messageType int, payload []byte) error {
+	select {
+	case <-e.pipe.chClosed:
+		return e.closedError()
+	default:
+	}
+
+	if e.faults.shouldDrop() {
+		return nil
+	}
+
+	f := frame{
+		messageType: messageType,
+		payload:     append(make([]byte, 0, len(payload)), payload...),
+		deliverAt:   time.Now().Add(e.faults.latency),
+	}
+	select {
+	case e.outbox <- f:
+		return nil
+	case <-e.pipe.chClosed:
+		return e.closedError()
+	}
+}
+
+func (e *end) deliver() {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		var f frame
+		select {
+		case f = <-e.outbox:
+		case <-e.pipe.chClosed:
+			return
+		}
+
+		delay := time.Until(f.deliverAt)
+		if delay > 0 {
+			timer.Reset(delay)
+			select {
+			case <-timer.C:
+			case <-e.pipe.chClosed:
+				return
+			}
+		}
+
+		select {
+		case e.peer.inbox <- f:
+		case <-e.pipe.chClosed:
+			return
+		}
+	}
+}
+
+// read returns the next message delivered to this end. The messages delivered before the peer closed the connection
+// can still be read
+func (e *end) read() (int, []byte, error) {
+	select {
+	case f := <-e.inbox:
+		if e.isClosed() && e.pipe.closedBy == e {
+			return 0, nil, errUseOfClosedConnection
+		}
+		return f.messageType, f.payload, nil
+	case <-e.pipe.chClosed:
+		return e.readAfterClose()
+	}
+}
+
+func (e *end) readAfterClose() (int, []byte, error) {
+	if e.pipe.closedBy != e {
+		select {
+		case f := <-e.inbox:
+			return f.messageType, f.payload, nil
+		default:
+		}
+	}
+
+	return 0, nil, e.closedError()
+}
+
+// close closes both ends of the connection. A nil closer means the connection was dropped, as a broken network would
+func (e *end) close(closer *end, code int, reason string) {
+	e.pipe.closeOnce.Do(func() {
+		e.pipe.closedBy = closer
+		e.pipe.closeError = &websocket.CloseError{Code: code, Text: reason}
+		close(e.pipe.chClosed)
+	})
+}
+
+// closedError should be called only after the pipe was closed
+func (e *end) closedError() error {
+	if e.pipe.closedBy == e {
+		return errUseOfClosedConnection
+	}
+
+	return e.pipe.closeError
+}
+
+func (e *end) isClosed() bool {
+	select {
+	case <-e.pipe.chClosed:
+		return true
+	default:
+		return false
+	}
+}
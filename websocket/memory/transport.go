@@ -0,0 +1,48 @@
+package memory
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	logger "github.com/subrahamanyam341/andes-logger-123"
+)
+
+var log = logger.GetOrCreate("memory")
+
+// CheckTransport returns an error if the transport is not known or if the faults of the memory transport are invalid
+func CheckTransport(transport string, config data.MemoryTransportConfig) error {
+	switch transport {
+	case "", data.WebSocketTransport:
+		return nil
+	case data.MemoryTransport:
+		if config.LatencyInMs < 0 || config.DropPercentage < 0 || config.DropPercentage > 100 {
+			return data.ErrInvalidMemoryTransportConfig
+		}
+		return nil
+	default:
+		return data.ErrUnknownTransport
+	}
+}
+
+// IsMemoryTransport returns true if the provided transport connects the hosts through in-memory connections
+func IsMemoryTransport(transport string) bool {
+	return transport == data.MemoryTransport
+}
+
+// faults holds what goes wrong with the messages written on one end of an in-memory connection
+type faults struct {
+	latency         time.Duration
+	dropProbability float64
+}
+
+func newFaults(config data.MemoryTransportConfig) faults {
+	return faults{
+		latency:         time.Duration(config.LatencyInMs) * time.Millisecond,
+		dropProbability: float64(config.DropPercentage) / 100,
+	}
+}
+
+func (f faults) shouldDrop() bool {
+	return f.dropProbability > 0 && rand.Float64() < f.dropProbability
+}
@@ -0,0 +1,28 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+)
+
+func TestCheckTransport(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, CheckTransport("", data.MemoryTransportConfig{}))
+	require.Nil(t, CheckTransport(data.WebSocketTransport, data.MemoryTransportConfig{LatencyInMs: -1}))
+	require.Nil(t, CheckTransport(data.MemoryTransport, data.MemoryTransportConfig{LatencyInMs: 10, DropPercentage: 100}))
+	require.Equal(t, data.ErrUnknownTransport, CheckTransport("quic", data.MemoryTransportConfig{}))
+	require.Equal(t, data.ErrInvalidMemoryTransportConfig, CheckTransport(data.MemoryTransport, data.MemoryTransportConfig{LatencyInMs: -1}))
+	require.Equal(t, data.ErrInvalidMemoryTransportConfig, CheckTransport(data.MemoryTransport, data.MemoryTransportConfig{DropPercentage: -1}))
+	require.Equal(t, data.ErrInvalidMemoryTransportConfig, CheckTransport(data.MemoryTransport, data.MemoryTransportConfig{DropPercentage: 101}))
+}
+
+func TestIsMemoryTransport(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, IsMemoryTransport(data.MemoryTransport))
+	require.False(t, IsMemoryTransport(data.WebSocketTransport))
+	require.False(t, IsMemoryTransport(""))
+}
@@ -0,0 +1,160 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+)
+
+// ackLatencyBuckets are the upper bounds, in seconds, of the acknowledgement latency histogram buckets
+var ackLatencyBuckets = []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// inMemoryMetrics keeps the metrics of a websocket host in memory, so they can be read through a snapshot
+type inMemoryMetrics struct {
+	mut              sync.RWMutex
+	topics           map[string]*data.TopicMetrics
+	ackLatencyCounts []uint64
+	ackLatencyCount  uint64
+	ackLatencySum    float64
+	ackTimeouts      uint64
+	reconnects       uint64
+	connectedClients int64
+}
+
+// NewInMemoryMetrics will create a new metrics handler that keeps the metrics in memory
+func NewInMemoryMetrics() *inMemoryMetrics {
+	return &inMemoryMetrics{
+		topics:           make(map[string]*data.TopicMetrics),
+		ackLatencyCounts: make([]uint64, len(ackLatencyBuckets)),
+	}
+}
+
+// MessageSent will count a message sent on the provided topic
+func (im *inMemoryMetrics) MessageSent(topic string, numBytes int) {
+	im.mut.Lock()
+	defer im.mut.Unlock()
+
+	topicMetrics := im.getTopicMetrics(topic)
+	topicMetrics.MessagesSent++
+	topicMetrics.BytesSent += uint64(numBytes)
+}
+
+// MessageReceived will count a message received on the provided topic
+func (im *inMemoryMetrics) MessageReceived(topic string, numBytes int) {
+	im.mut.Lock()
+	defer im.mut.Unlock()
+
+	topicMetrics := im.getTopicMetrics(topic)
+	topicMetrics.MessagesReceived++
+	topicMetrics.BytesReceived += uint64(numBytes)
+}
+
+// HandlerFailed will count a failed processing of a message received on the provided topic
+func (im *inMemoryMetrics) HandlerFailed(topic string) {
+	im.mut.Lock()
+	defer im.mut.Unlock()
+
+	im.getTopicMetrics(topic).HandlerErrors++
+}
+
+// InvalidSignature will count a message received on the provided topic that was rejected for its signature
+func (im *inMemoryMetrics) InvalidSignature(topic string) {
+	im.mut.Lock()
+	defer im.mut.Unlock()
+
+	im.getTopicMetrics(topic).InvalidSignatures++
+}
+
+func (im *inMemoryMetrics) getTopicMetrics(topic string) *data.TopicMetrics {
+	topicMetrics, found := im.topics[topic]
+	if !found {
+		topicMetrics = &data.TopicMetrics{}
+		im.topics[topic] = topicMetrics
+	}
+
+	return topicMetrics
+}
+
+// AckReceived will add the latency of an acknowledgement in the histogram
+func (im *inMemoryMetrics) AckReceived(latency time.Duration) {
+	seconds := latency.Seconds()
+
+	im.mut.Lock()
+	defer im.mut.Unlock()
+
+	for i, upperBound := range ackLatencyBuckets {
+		if seconds <= upperBound {
+			im.ackLatencyCounts[i]++
+			break
+		}
+	}
+	im.ackLatencyCount++
+	im.ackLatencySum += seconds
+}
+
+// AckTimedOut will count an acknowledgement that did not arrive in time
+func (im *inMemoryMetrics) AckTimedOut() {
+	im.mut.Lock()
+	im.ackTimeouts++
+	im.mut.Unlock()
+}
+
+// Reconnected will count a connection opened again after it was lost
+func (im *inMemoryMetrics) Reconnected() {
+	im.mut.Lock()
+	im.reconnects++
+	im.mut.Unlock()
+}
+
+// ClientConnected will count a newly connected client
+func (im *inMemoryMetrics) ClientConnected() {
+	im.mut.Lock()
+	im.connectedClients++
+	im.mut.Unlock()
+}
+
+// ClientDisconnected will count a disconnected client
+func (im *inMemoryMetrics) ClientDisconnected() {
+	im.mut.Lock()
+	im.connectedClients--
+	im.mut.Unlock()
+}
+
+// GetSnapshot returns a copy of the metrics collected so far
+func (im *inMemoryMetrics) GetSnapshot() data.MetricsSnapshot {
+	im.mut.RLock()
+	defer im.mut.RUnlock()
+
+	topics := make(map[string]data.TopicMetrics, len(im.topics))
+	for topic, topicMetrics := range im.topics {
+		topics[topic] = *topicMetrics
+	}
+
+	buckets := make([]data.HistogramBucket, 0, len(ackLatencyBuckets))
+	cumulativeCount := uint64(0)
+	for i, upperBound := range ackLatencyBuckets {
+		cumulativeCount += im.ackLatencyCounts[i]
+		buckets = append(buckets, data.HistogramBucket{
+			UpperBoundInSeconds: upperBound,
+			Count:               cumulativeCount,
+		})
+	}
+
+	return data.MetricsSnapshot{
+		Topics: topics,
+		AckLatency: data.Histogram{
+			Buckets:      buckets,
+			Count:        im.ackLatencyCount,
+			SumInSeconds: im.ackLatencySum,
+		},
+		AckTimeouts:      im.ackTimeouts,
+		Reconnects:       im.reconnects,
+		ConnectedClients: im.connectedClients,
+	}
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (im *inMemoryMetrics) IsInterfaceNil() bool {
+	return im == nil
+}
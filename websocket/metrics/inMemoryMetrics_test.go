@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+)
+
+func TestInMemoryMetrics_TopicCounters(t *testing.T) {
+	t.Parallel()
+
+	metrics := NewInMemoryMetrics()
+	require.False(t, metrics.IsInterfaceNil())
+
+	metrics.MessageSent("blocks", 10)
+	metrics.MessageSent("blocks", 5)
+	metrics.MessageReceived("blocks", 7)
+	metrics.MessageReceived("accounts", 3)
+	metrics.HandlerFailed("accounts")
+	metrics.InvalidSignature("accounts")
+
+	snapshot := metrics.GetSnapshot()
+	require.Equal(t, map[string]data.TopicMetrics{
+		"blocks": {
+			MessagesSent:     2,
+			BytesSent:        15,
+			MessagesReceived: 1,
+			BytesReceived:    7,
+		},
+		"accounts": {
+			MessagesReceived:  1,
+			BytesReceived:     3,
+			HandlerErrors:     1,
+			InvalidSignatures: 1,
+		},
+	}, snapshot.Topics)
+}
+
+func TestInMemoryMetrics_ConnectionCounters(t *testing.T) {
+	t.Parallel()
+
+	metrics := NewInMemoryMetrics()
+	metrics.ClientConnected()
+	metrics.ClientConnected()
+	metrics.ClientDisconnected()
+	metrics.Reconnected()
+	metrics.AckTimedOut()
+	metrics.AckTimedOut()
+
+	snapshot := metrics.GetSnapshot()
+	require.Equal(t, int64(1), snapshot.ConnectedClients)
+	require.Equal(t, uint64(1), snapshot.Reconnects)
+	require.Equal(t, uint64(2), snapshot.AckTimeouts)
+}
+
+func TestInMemoryMetrics_AckLatencyHistogram(t *testing.T) {
+	t.Parallel()
+
+	metrics := NewInMemoryMetrics()
+	metrics.AckReceived(500 * time.Microsecond)
+	metrics.AckReceived(20 * time.Millisecond)
+	metrics.AckReceived(time.Minute)
+
+	histogram := metrics.GetSnapshot().AckLatency
+	require.Equal(t, uint64(3), histogram.Count)
+	require.InDelta(t, 60.0205, histogram.SumInSeconds, 0.0001)
+	require.Len(t, histogram.Buckets, len(ackLatencyBuckets))
+
+	// the buckets are cumulative, the observation above the last bound is counted only in the total
+	require.Equal(t, data.HistogramBucket{UpperBoundInSeconds: 0.001, Count: 1}, histogram.Buckets[0])
+	require.Equal(t, data.HistogramBucket{UpperBoundInSeconds: 0.01, Count: 1}, histogram.Buckets[2])
+	require.Equal(t, data.HistogramBucket{UpperBoundInSeconds: 0.025, Count: 2}, histogram.Buckets[3])
+	require.Equal(t, data.HistogramBucket{UpperBoundInSeconds: 10, Count: 2}, histogram.Buckets[len(histogram.Buckets)-1])
+}
+
+func TestInMemoryMetrics_SnapshotShouldBeACopy(t *testing.T) {
+	t.Parallel()
+
+	metrics := NewInMemoryMetrics()
+	metrics.MessageSent("blocks", 1)
+	snapshot := metrics.GetSnapshot()
+
+	metrics.MessageSent("blocks", 1)
+	require.Equal(t, uint64(1), snapshot.Topics["blocks"].MessagesSent)
+	require.Equal(t, uint64(2), metrics.GetSnapshot().Topics["blocks"].MessagesSent)
+}
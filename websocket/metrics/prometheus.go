@@ -0,0 +1,118 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/subrahamanyam341/andes-communication/websocket"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	"github.com/subrahamanyam341/andes-core-16/core/check"
+)
+
+const (
+	metricsPrefix      = "websocket_"
+	prometheusTextType = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+type prometheusHandler struct {
+	provider websocket.MetricsSnapshotProvider
+}
+
+// NewPrometheusHandler will create a http handler that serves the metrics of the provided provider in the Prometheus
+// text exposition format
+func NewPrometheusHandler(provider websocket.MetricsSnapshotProvider) (*prometheusHandler, error) {
+	if check.IfNil(provider) {
+		return nil, data.ErrNilMetricsSnapshotProvider
+	}
+
+	return &prometheusHandler{
+		provider: provider,
+	}, nil
+}
+
+// ServeHTTP will write the current metrics snapshot in the Prometheus text exposition format
+func (ph *prometheusHandler) ServeHTTP(writer http.ResponseWriter, _ *http.Request) {
+	writer.Header().Set("Content-Type", prometheusTextType)
+	_ = WritePrometheusText(writer, ph.provider.GetSnapshot())
+}
+
+// WritePrometheusText will write the provided metrics snapshot in the Prometheus text exposition format
+func WritePrometheusText(writer io.Writer, snapshot data.MetricsSnapshot) error {
+	buffered := bufio.NewWriter(writer)
+
+	topics := make([]string, 0, len(snapshot.Topics))
+	for topic := range snapshot.Topics {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+
+	writeTopicCounter(buffered, "messages_sent_total", "Messages sent, per topic.", topics, snapshot.Topics,
+		func(topicMetrics data.TopicMetrics) uint64 { return topicMetrics.MessagesSent })
+	writeTopicCounter(buffered, "bytes_sent_total", "Bytes sent, per topic.", topics, snapshot.Topics,
+		func(topicMetrics data.TopicMetrics) uint64 { return topicMetrics.BytesSent })
+	writeTopicCounter(buffered, "messages_received_total", "Messages received, per topic.", topics, snapshot.Topics,
+		func(topicMetrics data.TopicMetrics) uint64 { return topicMetrics.MessagesReceived })
+	writeTopicCounter(buffered, "bytes_received_total", "Bytes received, per topic.", topics, snapshot.Topics,
+		func(topicMetrics data.TopicMetrics) uint64 { return topicMetrics.BytesReceived })
+	writeTopicCounter(buffered, "handler_errors_total", "Received messages the handlers failed to process, per topic.", topics, snapshot.Topics,
+		func(topicMetrics data.TopicMetrics) uint64 { return topicMetrics.HandlerErrors })
+	writeTopicCounter(buffered, "invalid_signatures_total", "Received messages rejected for their signature, per topic.", topics, snapshot.Topics,
+		func(topicMetrics data.TopicMetrics) uint64 { return topicMetrics.InvalidSignatures })
+
+	writeHistogram(buffered, "ack_latency_seconds", "Time between sending a message and receiving its acknowledgement.", snapshot.AckLatency)
+
+	writeMetric(buffered, "ack_timeouts_total", "Acknowledgements not received in time.", "counter", strconv.FormatUint(snapshot.AckTimeouts, 10))
+	writeMetric(buffered, "reconnects_total", "Connections opened again after being lost.", "counter", strconv.FormatUint(snapshot.Reconnects, 10))
+	writeMetric(buffered, "connected_clients", "Clients currently connected.", "gauge", strconv.FormatInt(snapshot.ConnectedClients, 10))
+
+	return buffered.Flush()
+}
+
+func writeHeader(writer io.Writer, name string, help string, metricType string) {
+	_, _ = fmt.Fprintf(writer, "# HELP %s%s %s\n", metricsPrefix, name, help)
+	_, _ = fmt.Fprintf(writer, "# TYPE %s%s %s\n", metricsPrefix, name, metricType)
+}
+
+func writeMetric(writer io.Writer, name string, help string, metricType string, value string) {
+	writeHeader(writer, name, help, metricType)
+	_, _ = fmt.Fprintf(writer, "%s%s %s\n", metricsPrefix, name, value)
+}
+
+func writeTopicCounter(
+	writer io.Writer,
+	name string,
+	help string,
+	topics []string,
+	topicsMetrics map[string]data.TopicMetrics,
+	getValue func(topicMetrics data.TopicMetrics) uint64,
+) {
+	writeHeader(writer, name, help, "counter")
+	for _, topic := range topics {
+		_, _ = fmt.Fprintf(writer, "%s%s{topic=\"%s\"} %d\n", metricsPrefix, name, escapeLabelValue(topic), getValue(topicsMetrics[topic]))
+	}
+}
+
+func writeHistogram(writer io.Writer, name string, help string, histogram data.Histogram) {
+	writeHeader(writer, name, help, "histogram")
+	for _, bucket := range histogram.Buckets {
+		upperBound := strconv.FormatFloat(bucket.UpperBoundInSeconds, 'g', -1, 64)
+		_, _ = fmt.Fprintf(writer, "%s%s_bucket{le=\"%s\"} %d\n", metricsPrefix, name, upperBound, bucket.Count)
+	}
+	_, _ = fmt.Fprintf(writer, "%s%s_bucket{le=\"+Inf\"} %d\n", metricsPrefix, name, histogram.Count)
+	_, _ = fmt.Fprintf(writer, "%s%s_sum %s\n", metricsPrefix, name, strconv.FormatFloat(histogram.SumInSeconds, 'g', -1, 64))
+	_, _ = fmt.Fprintf(writer, "%s%s_count %d\n", metricsPrefix, name, histogram.Count)
+}
+
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (ph *prometheusHandler) IsInterfaceNil() bool {
+	return ph == nil
+}
@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+)
+
+func TestNewPrometheusHandler(t *testing.T) {
+	t.Parallel()
+
+	handler, err := NewPrometheusHandler(nil)
+	require.Nil(t, handler)
+	require.Equal(t, data.ErrNilMetricsSnapshotProvider, err)
+
+	handler, err = NewPrometheusHandler(NewInMemoryMetrics())
+	require.Nil(t, err)
+	require.False(t, handler.IsInterfaceNil())
+}
+
+func TestWritePrometheusText(t *testing.T) {
+	t.Parallel()
+
+	metrics := NewInMemoryMetrics()
+	metrics.MessageSent("blocks", 10)
+	metrics.MessageReceived(`quoted "topic"`, 4)
+	metrics.HandlerFailed(`quoted "topic"`)
+	metrics.InvalidSignature("blocks")
+	metrics.AckReceived(2 * time.Millisecond)
+	metrics.AckTimedOut()
+	metrics.Reconnected()
+	metrics.ClientConnected()
+
+	buff := &bytes.Buffer{}
+	err := WritePrometheusText(buff, metrics.GetSnapshot())
+	require.Nil(t, err)
+
+	text := buff.String()
+	require.Contains(t, text, "# TYPE websocket_messages_sent_total counter\n")
+	require.Contains(t, text, "websocket_messages_sent_total{topic=\"blocks\"} 1\n")
+	require.Contains(t, text, "websocket_bytes_sent_total{topic=\"blocks\"} 10\n")
+	require.Contains(t, text, "websocket_messages_received_total{topic=\"quoted \\\"topic\\\"\"} 1\n")
+	require.Contains(t, text, "websocket_handler_errors_total{topic=\"quoted \\\"topic\\\"\"} 1\n")
+	require.Contains(t, text, "websocket_invalid_signatures_total{topic=\"blocks\"} 1\n")
+	require.Contains(t, text, "# TYPE websocket_ack_latency_seconds histogram\n")
+	require.Contains(t, text, "websocket_ack_latency_seconds_bucket{le=\"0.001\"} 0\n")
+	require.Contains(t, text, "websocket_ack_latency_seconds_bucket{le=\"0.005\"} 1\n")
+	require.Contains(t, text, "websocket_ack_latency_seconds_bucket{le=\"+Inf\"} 1\n")
+	require.Contains(t, text, "websocket_ack_latency_seconds_count 1\n")
+	require.Contains(t, text, "websocket_ack_timeouts_total 1\n")
+	require.Contains(t, text, "websocket_reconnects_total 1\n")
+	require.Contains(t, text, "# TYPE websocket_connected_clients gauge\n")
+	require.Contains(t, text, "websocket_connected_clients 1\n")
+}
+
+func TestPrometheusHandler_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	metrics := NewInMemoryMetrics()
+	metrics.MessageSent("blocks", 10)
+	handler, _ := NewPrometheusHandler(metrics)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, prometheusTextType, recorder.Header().Get("Content-Type"))
+	require.Contains(t, recorder.Body.String(), "websocket_messages_sent_total{topic=\"blocks\"} 1\n")
+}
@@ -0,0 +1,51 @@
+package websocket
+
+import "time"
+
+type nilMetricsHandler struct{}
+
+// NewNilMetricsHandler will create a new instance of nilMetricsHandler
+func NewNilMetricsHandler() MetricsHandler {
+	return new(nilMetricsHandler)
+}
+
+// MessageSent will do nothing
+func (n nilMetricsHandler) MessageSent(_ string, _ int) {
+}
+
+// MessageReceived will do nothing
+func (n nilMetricsHandler) MessageReceived(_ string, _ int) {
+}
+
+// AckReceived will do nothing
+func (n nilMetricsHandler) AckReceived(_ time.Duration) {
+}
+
+// AckTimedOut will do nothing
+func (n nilMetricsHandler) AckTimedOut() {
+}
+
+// Reconnected will do nothing
+func (n nilMetricsHandler) Reconnected() {
+}
+
+// ClientConnected will do nothing
+func (n nilMetricsHandler) ClientConnected() {
+}
+
+// ClientDisconnected will do nothing
+func (n nilMetricsHandler) ClientDisconnected() {
+}
+
+// HandlerFailed will do nothing
+func (n nilMetricsHandler) HandlerFailed(_ string) {
+}
+
+// InvalidSignature will do nothing
+func (n nilMetricsHandler) InvalidSignature(_ string) {
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (n nilMetricsHandler) IsInterfaceNil() bool {
+	return false
+}
@@ -0,0 +1,23 @@
+package websocket
+
+import "github.com/subrahamanyam341/andes-communication/websocket/data"
+
+type nilRequestHandler struct{}
+
+// NewNilRequestHandler will create a new instance of nilRequestHandler
+func NewNilRequestHandler() RequestHandler {
+	return new(nilRequestHandler)
+}
+
+// ProcessRequest will answer every request with an error
+func (n nilRequestHandler) ProcessRequest(_ []byte, _ string, _ uint32) ([]byte, error) {
+	return nil, &data.RequestError{
+		Code:    data.RequestErrorCodeNoHandler,
+		Message: "no request handler set",
+	}
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (n nilRequestHandler) IsInterfaceNil() bool {
+	return false
+}
@@ -0,0 +1,366 @@
+package queue
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	"github.com/subrahamanyam341/andes-core-16/core"
+	"github.com/subrahamanyam341/andes-core-16/core/check"
+)
+
+const (
+	messageFileExtension = ".msg"
+	tempFileExtension    = ".tmp"
+	lockFileName         = "queue.lock"
+	// timestamp + topic length
+	recordHeaderSize = 8 + 4
+)
+
+// ArgsDiskQueue holds the arguments needed for creating a disk queue
+type ArgsDiskQueue struct {
+	Path           string
+	MaxMessages    int
+	MaxSizeInBytes int64
+	MaxAgeInSec    int
+	Log            core.Logger
+}
+
+type entryInfo struct {
+	id        uint64
+	size      int64
+	timestamp int64
+}
+
+// diskQueue stores every message in its own file named after the message ID, so the order is preserved across
+// restarts and removing an acknowledged message is a single file deletion
+type diskQueue struct {
+	mut            sync.Mutex
+	path           string
+	maxMessages    int
+	maxSizeInBytes int64
+	maxAge         time.Duration
+	log            core.Logger
+	entries        []entryInfo
+	totalSize      int64
+	lastID         uint64
+	closed         bool
+	lock           *os.File
+}
+
+// NewDiskQueue will create a new disk queue, loading the messages already stored at the provided path. The path is
+// locked until the queue is closed, so another queue can not send and remove the same messages
+func NewDiskQueue(args ArgsDiskQueue) (*diskQueue, error) {
+	if args.Path == "" {
+		return nil, data.ErrEmptyOutboundQueuePath
+	}
+	if check.IfNil(args.Log) {
+		return nil, core.ErrNilLogger
+	}
+
+	err := os.MkdirAll(args.Path, os.ModePerm)
+	if err != nil {
+		return nil, err
+	}
+
+	lock, err := acquireLock(args.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	dq := &diskQueue{
+		path:           args.Path,
+		maxMessages:    args.MaxMessages,
+		maxSizeInBytes: args.MaxSizeInBytes,
+		maxAge:         time.Duration(args.MaxAgeInSec) * time.Second,
+		log:            args.Log,
+		lock:           lock,
+	}
+
+	err = dq.load()
+	if err != nil {
+		_ = lock.Close()
+		return nil, err
+	}
+
+	return dq, nil
+}
+
+// acquireLock opens the lock file of the path and locks it. The lock is released by the operating system once the
+// file is closed, even if the process crashed
+func acquireLock(path string) (*os.File, error) {
+	lock, err := os.OpenFile(filepath.Join(path, lockFileName), os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	err = lockFile(lock)
+	if err != nil {
+		_ = lock.Close()
+		return nil, fmt.Errorf("%w: %s, %v", data.ErrOutboundQueueLocked, path, err)
+	}
+
+	return lock, nil
+}
+
+func (dq *diskQueue) load() error {
+	dirEntries, err := os.ReadDir(dq.path)
+	if err != nil {
+		return err
+	}
+
+	for _, dirEntry := range dirEntries {
+		name := dirEntry.Name()
+		if strings.HasSuffix(name, tempFileExtension) {
+			// leftover of a write interrupted by a crash, the message was never acknowledged as stored
+			_ = os.Remove(filepath.Join(dq.path, name))
+			continue
+		}
+		if dirEntry.IsDir() || !strings.HasSuffix(name, messageFileExtension) {
+			continue
+		}
+
+		id, errParse := strconv.ParseUint(strings.TrimSuffix(name, messageFileExtension), 10, 64)
+		if errParse != nil {
+			dq.log.Warn("diskQueue.load: skipping unknown file", "file", name)
+			continue
+		}
+
+		message, errRead := dq.readMessage(id)
+		if errRead != nil {
+			dq.log.Warn("diskQueue.load: removing unreadable message", "file", name, "error", errRead)
+			_ = os.Remove(dq.filePath(id))
+			continue
+		}
+
+		dq.entries = append(dq.entries, entryInfo{
+			id:        id,
+			size:      computeSize(message.Topic, message.Payload),
+			timestamp: message.Timestamp,
+		})
+	}
+
+	sort.Slice(dq.entries, func(i, j int) bool {
+		return dq.entries[i].id < dq.entries[j].id
+	})
+	for _, entry := range dq.entries {
+		dq.totalSize += entry.size
+	}
+	if len(dq.entries) > 0 {
+		dq.lastID = dq.entries[len(dq.entries)-1].id
+		dq.log.Info("diskQueue: loaded stored messages", "num messages", len(dq.entries), "size", dq.totalSize)
+	}
+
+	return nil
+}
+
+// Add will persist the provided message at the end of the queue
+func (dq *diskQueue) Add(payload []byte, topic string) error {
+	dq.mut.Lock()
+	defer dq.mut.Unlock()
+
+	if dq.closed {
+		return data.ErrOutboundQueueClosed
+	}
+
+	size := computeSize(topic, payload)
+	if dq.maxMessages > 0 && len(dq.entries) >= dq.maxMessages {
+		return data.ErrOutboundQueueFull
+	}
+	if dq.maxSizeInBytes > 0 && dq.totalSize+size > dq.maxSizeInBytes {
+		return data.ErrOutboundQueueFull
+	}
+
+	message := &data.QueuedMessage{
+		ID:        dq.lastID + 1,
+		Topic:     topic,
+		Payload:   payload,
+		Timestamp: time.Now().UnixNano(),
+	}
+	err := dq.writeMessage(message)
+	if err != nil {
+		return err
+	}
+
+	dq.lastID = message.ID
+	dq.totalSize += size
+	dq.entries = append(dq.entries, entryInfo{
+		id:        message.ID,
+		size:      size,
+		timestamp: message.Timestamp,
+	})
+
+	return nil
+}
+
+// Peek will return the oldest message that did not expire, or nil if the queue is empty. Expired messages are removed
+func (dq *diskQueue) Peek() (*data.QueuedMessage, error) {
+	dq.mut.Lock()
+	defer dq.mut.Unlock()
+
+	if dq.closed {
+		return nil, data.ErrOutboundQueueClosed
+	}
+
+	for len(dq.entries) > 0 {
+		head := dq.entries[0]
+		if !dq.isExpired(head) {
+			message, err := dq.readMessage(head.id)
+			if !os.IsNotExist(err) {
+				return message, err
+			}
+
+			dq.log.Warn("diskQueue.Peek: discarding missing message", "id", head.id)
+		} else {
+			dq.log.Warn("diskQueue.Peek: discarding expired message", "id", head.id)
+		}
+
+		err := dq.removeEntry(0)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, nil
+}
+
+// Remove will delete the message with the provided ID
+func (dq *diskQueue) Remove(id uint64) error {
+	dq.mut.Lock()
+	defer dq.mut.Unlock()
+
+	// the stored messages might already be loaded by another instance
+	if dq.closed {
+		return data.ErrOutboundQueueClosed
+	}
+
+	for idx, entry := range dq.entries {
+		if entry.id == id {
+			return dq.removeEntry(idx)
+		}
+	}
+
+	return nil
+}
+
+// Len returns the number of queued messages
+func (dq *diskQueue) Len() int {
+	dq.mut.Lock()
+	defer dq.mut.Unlock()
+
+	return len(dq.entries)
+}
+
+// Close will close the queue and release the lock of its path. The stored messages are kept on disk and will be
+// loaded by the next instance
+func (dq *diskQueue) Close() error {
+	dq.mut.Lock()
+	defer dq.mut.Unlock()
+
+	if dq.closed {
+		return nil
+	}
+	dq.closed = true
+
+	return dq.lock.Close()
+}
+
+func (dq *diskQueue) isExpired(entry entryInfo) bool {
+	if dq.maxAge == 0 {
+		return false
+	}
+
+	return time.Since(time.Unix(0, entry.timestamp)) > dq.maxAge
+}
+
+func (dq *diskQueue) removeEntry(idx int) error {
+	entry := dq.entries[idx]
+	err := os.Remove(dq.filePath(entry.id))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	dq.totalSize -= entry.size
+	dq.entries = append(dq.entries[:idx], dq.entries[idx+1:]...)
+
+	return nil
+}
+
+func (dq *diskQueue) filePath(id uint64) string {
+	return filepath.Join(dq.path, fmt.Sprintf("%020d%s", id, messageFileExtension))
+}
+
+func (dq *diskQueue) writeMessage(message *data.QueuedMessage) error {
+	record := make([]byte, recordHeaderSize, recordHeaderSize+len(message.Topic)+len(message.Payload))
+	binary.BigEndian.PutUint64(record[:8], uint64(message.Timestamp))
+	binary.BigEndian.PutUint32(record[8:recordHeaderSize], uint32(len(message.Topic)))
+	record = append(record, message.Topic...)
+	record = append(record, message.Payload...)
+
+	// write in a temporary file and rename it, so a crash will never leave a partially written message behind
+	finalPath := dq.filePath(message.ID)
+	tempPath := finalPath + tempFileExtension
+	file, err := os.OpenFile(tempPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+
+	_, err = file.Write(record)
+	if err == nil {
+		err = file.Sync()
+	}
+	errClose := file.Close()
+	if err == nil {
+		err = errClose
+	}
+	if err != nil {
+		_ = os.Remove(tempPath)
+		return err
+	}
+
+	err = os.Rename(tempPath, finalPath)
+	if err != nil {
+		return err
+	}
+
+	// the renamed file is durable only once the directory entry is written as well
+	return syncDir(dq.path)
+}
+
+func (dq *diskQueue) readMessage(id uint64) (*data.QueuedMessage, error) {
+	record, err := os.ReadFile(dq.filePath(id))
+	if err != nil {
+		return nil, err
+	}
+	if len(record) < recordHeaderSize {
+		return nil, data.ErrInvalidQueuedMessage
+	}
+
+	topicLength := int(binary.BigEndian.Uint32(record[8:recordHeaderSize]))
+	if len(record) < recordHeaderSize+topicLength {
+		return nil, data.ErrInvalidQueuedMessage
+	}
+
+	return &data.QueuedMessage{
+		ID:        id,
+		Timestamp: int64(binary.BigEndian.Uint64(record[:8])),
+		Topic:     string(record[recordHeaderSize : recordHeaderSize+topicLength]),
+		Payload:   record[recordHeaderSize+topicLength:],
+	}, nil
+}
+
+func computeSize(topic string, payload []byte) int64 {
+	return int64(len(topic) + len(payload))
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (dq *diskQueue) IsInterfaceNil() bool {
+	return dq == nil
+}
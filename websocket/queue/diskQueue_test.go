@@ -0,0 +1,231 @@
+package queue
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/testscommon"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	"github.com/subrahamanyam341/andes-core-16/core"
+)
+
+func createDiskQueueArgs(t *testing.T) ArgsDiskQueue {
+	return ArgsDiskQueue{
+		Path: t.TempDir(),
+		Log:  &testscommon.LoggerMock{},
+	}
+}
+
+func TestNewDiskQueue(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty path, should return error", func(t *testing.T) {
+		args := createDiskQueueArgs(t)
+		args.Path = ""
+		dq, err := NewDiskQueue(args)
+		require.Nil(t, dq)
+		require.Equal(t, data.ErrEmptyOutboundQueuePath, err)
+	})
+
+	t.Run("nil logger, should return error", func(t *testing.T) {
+		args := createDiskQueueArgs(t)
+		args.Log = nil
+		dq, err := NewDiskQueue(args)
+		require.Nil(t, dq)
+		require.Equal(t, core.ErrNilLogger, err)
+	})
+
+	t.Run("path used by another queue, should return error", func(t *testing.T) {
+		args := createDiskQueueArgs(t)
+		firstQueue, err := NewDiskQueue(args)
+		require.Nil(t, err)
+
+		dq, err := NewDiskQueue(args)
+		require.Nil(t, dq)
+		require.ErrorIs(t, err, data.ErrOutboundQueueLocked)
+
+		// the path is released once the first queue is closed
+		require.Nil(t, firstQueue.Close())
+		dq, err = NewDiskQueue(args)
+		require.Nil(t, err)
+		require.Nil(t, dq.Close())
+	})
+
+	t.Run("should work", func(t *testing.T) {
+		dq, err := NewDiskQueue(createDiskQueueArgs(t))
+		require.Nil(t, err)
+		require.False(t, dq.IsInterfaceNil())
+		require.Equal(t, 0, dq.Len())
+	})
+}
+
+func TestDiskQueue_AddPeekRemoveShouldKeepOrder(t *testing.T) {
+	t.Parallel()
+
+	dq, _ := NewDiskQueue(createDiskQueueArgs(t))
+
+	message, err := dq.Peek()
+	require.Nil(t, err)
+	require.Nil(t, message)
+
+	for i := 0; i < 5; i++ {
+		err = dq.Add([]byte(fmt.Sprintf("payload %d", i)), "topic")
+		require.Nil(t, err)
+	}
+	require.Equal(t, 5, dq.Len())
+
+	for i := 0; i < 5; i++ {
+		message, err = dq.Peek()
+		require.Nil(t, err)
+		require.Equal(t, []byte(fmt.Sprintf("payload %d", i)), message.Payload)
+		require.Equal(t, "topic", message.Topic)
+
+		err = dq.Remove(message.ID)
+		require.Nil(t, err)
+	}
+
+	require.Equal(t, 0, dq.Len())
+}
+
+func TestDiskQueue_ShouldReloadStoredMessages(t *testing.T) {
+	t.Parallel()
+
+	args := createDiskQueueArgs(t)
+	dq, _ := NewDiskQueue(args)
+	for i := 0; i < 3; i++ {
+		_ = dq.Add([]byte(fmt.Sprintf("payload %d", i)), fmt.Sprintf("topic %d", i))
+	}
+	message, _ := dq.Peek()
+	_ = dq.Remove(message.ID)
+	_ = dq.Close()
+
+	// simulate a crash in the middle of a write
+	_ = os.WriteFile(filepath.Join(args.Path, "00000000000000000009.msg.tmp"), []byte("partial"), 0600)
+
+	reloadedQueue, err := NewDiskQueue(args)
+	require.Nil(t, err)
+	require.Equal(t, 2, reloadedQueue.Len())
+
+	message, _ = reloadedQueue.Peek()
+	require.Equal(t, []byte("payload 1"), message.Payload)
+	require.Equal(t, "topic 1", message.Topic)
+
+	_ = reloadedQueue.Add([]byte("payload 3"), "topic 3")
+	_ = reloadedQueue.Remove(message.ID)
+	message, _ = reloadedQueue.Peek()
+	require.Equal(t, []byte("payload 2"), message.Payload)
+	_ = reloadedQueue.Remove(message.ID)
+	message, _ = reloadedQueue.Peek()
+	require.Equal(t, []byte("payload 3"), message.Payload)
+
+	_, err = os.Stat(filepath.Join(args.Path, "00000000000000000009.msg.tmp"))
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestDiskQueue_ShouldDiscardCorruptedMessagesOnLoad(t *testing.T) {
+	t.Parallel()
+
+	args := createDiskQueueArgs(t)
+	_ = os.WriteFile(filepath.Join(args.Path, "00000000000000000001.msg"), []byte("short"), 0600)
+
+	dq, err := NewDiskQueue(args)
+	require.Nil(t, err)
+	require.Equal(t, 0, dq.Len())
+}
+
+func TestDiskQueue_Limits(t *testing.T) {
+	t.Parallel()
+
+	t.Run("max messages reached, should return error", func(t *testing.T) {
+		args := createDiskQueueArgs(t)
+		args.MaxMessages = 2
+		dq, _ := NewDiskQueue(args)
+
+		require.Nil(t, dq.Add([]byte("1"), "topic"))
+		require.Nil(t, dq.Add([]byte("2"), "topic"))
+		require.Equal(t, data.ErrOutboundQueueFull, dq.Add([]byte("3"), "topic"))
+
+		message, _ := dq.Peek()
+		_ = dq.Remove(message.ID)
+		require.Nil(t, dq.Add([]byte("3"), "topic"))
+	})
+
+	t.Run("max size reached, should return error", func(t *testing.T) {
+		args := createDiskQueueArgs(t)
+		args.MaxSizeInBytes = 20
+		dq, _ := NewDiskQueue(args)
+
+		require.Nil(t, dq.Add([]byte("0123456789"), "topic"))
+		require.Equal(t, data.ErrOutboundQueueFull, dq.Add([]byte("0123456789"), "topic"))
+		require.Nil(t, dq.Add([]byte("0"), "t"))
+	})
+
+	t.Run("expired messages should be discarded", func(t *testing.T) {
+		args := createDiskQueueArgs(t)
+		args.MaxAgeInSec = 1
+		dq, _ := NewDiskQueue(args)
+
+		_ = dq.Add([]byte("old"), "topic")
+		time.Sleep(1100 * time.Millisecond)
+		_ = dq.Add([]byte("new"), "topic")
+
+		message, err := dq.Peek()
+		require.Nil(t, err)
+		require.Equal(t, []byte("new"), message.Payload)
+		require.Equal(t, 1, dq.Len())
+	})
+}
+
+func TestDiskQueue_Close(t *testing.T) {
+	t.Parallel()
+
+	dq, _ := NewDiskQueue(createDiskQueueArgs(t))
+	_ = dq.Add([]byte("payload"), "topic")
+
+	err := dq.Close()
+	require.Nil(t, err)
+	require.Nil(t, dq.Close())
+
+	require.Equal(t, data.ErrOutboundQueueClosed, dq.Add([]byte("payload"), "topic"))
+	message, err := dq.Peek()
+	require.Nil(t, message)
+	require.Equal(t, data.ErrOutboundQueueClosed, err)
+	require.Equal(t, data.ErrOutboundQueueClosed, dq.Remove(1))
+}
+
+func TestDiskQueue_RemoveAfterCloseShouldNotAffectTheNextInstance(t *testing.T) {
+	t.Parallel()
+
+	args := createDiskQueueArgs(t)
+	dq, _ := NewDiskQueue(args)
+	_ = dq.Add([]byte("payload 0"), "topic")
+	_ = dq.Add([]byte("payload 1"), "topic")
+	_ = dq.Close()
+
+	reloadedQueue, _ := NewDiskQueue(args)
+	// a send that was in progress while the first instance was closed
+	_ = dq.Remove(1)
+
+	message, err := reloadedQueue.Peek()
+	require.Nil(t, err)
+	require.Equal(t, []byte("payload 0"), message.Payload)
+}
+
+func TestDiskQueue_PeekShouldDiscardMissingMessages(t *testing.T) {
+	t.Parallel()
+
+	args := createDiskQueueArgs(t)
+	dq, _ := NewDiskQueue(args)
+	_ = dq.Add([]byte("payload 0"), "topic")
+	_ = dq.Add([]byte("payload 1"), "topic")
+	_ = os.Remove(dq.filePath(1))
+
+	message, err := dq.Peek()
+	require.Nil(t, err)
+	require.Equal(t, []byte("payload 1"), message.Payload)
+	require.Equal(t, 1, dq.Len())
+}
@@ -0,0 +1,29 @@
+//go:build unix
+
+package queue
+
+import (
+	"os"
+	"syscall"
+)
+
+// lockFile takes an exclusive lock on the opened file, failing right away if another process or queue holds it
+func lockFile(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+}
+
+// syncDir flushes the entries of the directory, like the files renamed in it
+func syncDir(path string) error {
+	dir, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	err = dir.Sync()
+	errClose := dir.Close()
+	if err != nil {
+		return err
+	}
+
+	return errClose
+}
@@ -0,0 +1,19 @@
+//go:build windows
+
+package queue
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// lockFile takes an exclusive lock on the opened file, failing right away if another process or queue holds it
+func lockFile(file *os.File) error {
+	return windows.LockFileEx(windows.Handle(file.Fd()), windows.LOCKFILE_EXCLUSIVE_LOCK|windows.LOCKFILE_FAIL_IMMEDIATELY, 0, 1, 0, &windows.Overlapped{})
+}
+
+// syncDir does nothing, as the directories can not be flushed on windows, where the file system journals the renames
+func syncDir(_ string) error {
+	return nil
+}
@@ -0,0 +1,133 @@
+package queue
+
+import (
+	"time"
+
+	"github.com/subrahamanyam341/andes-communication/websocket"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	"github.com/subrahamanyam341/andes-core-16/core"
+	"github.com/subrahamanyam341/andes-core-16/core/check"
+	"github.com/subrahamanyam341/andes-core-16/core/closing"
+)
+
+// ArgsQueueSender holds the arguments needed for creating a queue sender
+type ArgsQueueSender struct {
+	Queue              websocket.OutboundQueue
+	SendHandler        func(message *data.QueuedMessage) error
+	Log                core.Logger
+	RetryDurationInSec int
+}
+
+// queueSender persists the outgoing messages in the outbound queue and sends them, one by one and in order, from a
+// background go routine. A message is removed from the queue only after the send handler succeeded
+type queueSender struct {
+	queue         websocket.OutboundQueue
+	sendHandler   func(message *data.QueuedMessage) error
+	log           core.Logger
+	retryDuration time.Duration
+	chanNewItem   chan struct{}
+	safeCloser    core.SafeCloser
+}
+
+// NewQueueSender will create a new instance of queue sender and will start sending the already queued messages
+func NewQueueSender(args ArgsQueueSender) (*queueSender, error) {
+	if check.IfNil(args.Queue) {
+		return nil, data.ErrNilOutboundQueue
+	}
+	if args.SendHandler == nil {
+		return nil, data.ErrNilSendHandler
+	}
+	if check.IfNil(args.Log) {
+		return nil, core.ErrNilLogger
+	}
+	if args.RetryDurationInSec == 0 {
+		return nil, data.ErrZeroValueRetryDuration
+	}
+
+	qs := &queueSender{
+		queue:         args.Queue,
+		sendHandler:   args.SendHandler,
+		log:           args.Log,
+		retryDuration: time.Duration(args.RetryDurationInSec) * time.Second,
+		chanNewItem:   make(chan struct{}, 1),
+		safeCloser:    closing.NewSafeChanCloser(),
+	}
+
+	go qs.processLoop()
+
+	return qs, nil
+}
+
+// Send will persist the provided payload in the outbound queue. The payload will be sent asynchronously
+func (qs *queueSender) Send(payload []byte, topic string) error {
+	err := qs.queue.Add(payload, topic)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case qs.chanNewItem <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+func (qs *queueSender) processLoop() {
+	timer := time.NewTimer(qs.retryDuration)
+	defer timer.Stop()
+
+	for {
+		sent := qs.sendHead()
+		if sent {
+			continue
+		}
+
+		timer.Reset(qs.retryDuration)
+
+		select {
+		case <-qs.chanNewItem:
+		case <-timer.C:
+		case <-qs.safeCloser.ChanClose():
+			return
+		}
+	}
+}
+
+// sendHead returns true if the oldest queued message was sent and there might be other messages waiting
+func (qs *queueSender) sendHead() bool {
+	message, err := qs.queue.Peek()
+	if err != nil {
+		qs.log.Debug("queueSender.sendHead: cannot read the queued message", "error", err)
+		return false
+	}
+	if message == nil {
+		return false
+	}
+
+	err = qs.sendHandler(message)
+	if err != nil {
+		qs.log.Debug("queueSender.sendHead: cannot send the queued message, will retry", "id", message.ID, "error", err)
+		return false
+	}
+
+	err = qs.queue.Remove(message.ID)
+	if err != nil {
+		qs.log.Warn("queueSender.sendHead: cannot remove the sent message", "id", message.ID, "error", err)
+		return false
+	}
+
+	return true
+}
+
+// Close will stop sending the queued messages and will close the queue
+func (qs *queueSender) Close() error {
+	qs.safeCloser.Close()
+
+	return qs.queue.Close()
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (qs *queueSender) IsInterfaceNil() bool {
+	return qs == nil
+}
@@ -0,0 +1,142 @@
+package queue
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/testscommon"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	"github.com/subrahamanyam341/andes-core-16/core"
+)
+
+func createQueueSenderArgs(t *testing.T) ArgsQueueSender {
+	dq, _ := NewDiskQueue(createDiskQueueArgs(t))
+
+	return ArgsQueueSender{
+		Queue: dq,
+		SendHandler: func(message *data.QueuedMessage) error {
+			return nil
+		},
+		Log:                &testscommon.LoggerMock{},
+		RetryDurationInSec: 1,
+	}
+}
+
+func TestNewQueueSender(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil queue, should return error", func(t *testing.T) {
+		args := createQueueSenderArgs(t)
+		args.Queue = nil
+		qs, err := NewQueueSender(args)
+		require.Nil(t, qs)
+		require.Equal(t, data.ErrNilOutboundQueue, err)
+	})
+
+	t.Run("nil send handler, should return error", func(t *testing.T) {
+		args := createQueueSenderArgs(t)
+		args.SendHandler = nil
+		qs, err := NewQueueSender(args)
+		require.Nil(t, qs)
+		require.Equal(t, data.ErrNilSendHandler, err)
+	})
+
+	t.Run("nil logger, should return error", func(t *testing.T) {
+		args := createQueueSenderArgs(t)
+		args.Log = nil
+		qs, err := NewQueueSender(args)
+		require.Nil(t, qs)
+		require.Equal(t, core.ErrNilLogger, err)
+	})
+
+	t.Run("zero retry duration, should return error", func(t *testing.T) {
+		args := createQueueSenderArgs(t)
+		args.RetryDurationInSec = 0
+		qs, err := NewQueueSender(args)
+		require.Nil(t, qs)
+		require.Equal(t, data.ErrZeroValueRetryDuration, err)
+	})
+
+	t.Run("should work", func(t *testing.T) {
+		qs, err := NewQueueSender(createQueueSenderArgs(t))
+		require.Nil(t, err)
+		require.False(t, qs.IsInterfaceNil())
+		_ = qs.Close()
+	})
+}
+
+func TestQueueSender_ShouldSendInOrderAndRemoveOnlyAfterSuccess(t *testing.T) {
+	t.Parallel()
+
+	args := createQueueSenderArgs(t)
+
+	mut := sync.Mutex{}
+	var sent []string
+	numFailures := 0
+	failedID := uint64(0)
+	retriedID := uint64(0)
+	done := make(chan struct{})
+	args.SendHandler = func(message *data.QueuedMessage) error {
+		mut.Lock()
+		defer mut.Unlock()
+
+		// the second message fails once, it should be retried before the third one is sent
+		if string(message.Payload) == "1" {
+			if numFailures == 0 {
+				numFailures++
+				failedID = message.ID
+				return errors.New("connection not open")
+			}
+			retriedID = message.ID
+		}
+
+		sent = append(sent, string(message.Payload))
+		if len(sent) == 3 {
+			close(done)
+		}
+		return nil
+	}
+
+	qs, _ := NewQueueSender(args)
+	defer func() {
+		_ = qs.Close()
+	}()
+
+	for i := 0; i < 3; i++ {
+		err := qs.Send([]byte(fmt.Sprintf("%d", i)), "topic")
+		require.Nil(t, err)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "timeout waiting for the queued messages")
+	}
+
+	mut.Lock()
+	require.Equal(t, []string{"0", "1", "2"}, sent)
+	// the retry is sent with the ID of the failed attempt, so the receiver can recognize it
+	require.Equal(t, failedID, retriedID)
+	mut.Unlock()
+	require.Equal(t, 0, args.Queue.Len())
+}
+
+func TestQueueSender_FailedMessagesShouldRemainQueued(t *testing.T) {
+	t.Parallel()
+
+	args := createQueueSenderArgs(t)
+	args.SendHandler = func(message *data.QueuedMessage) error {
+		return data.ErrNoClientsConnected
+	}
+
+	qs, _ := NewQueueSender(args)
+	_ = qs.Send([]byte("payload"), "topic")
+	time.Sleep(100 * time.Millisecond)
+	_ = qs.Close()
+
+	require.Equal(t, 1, args.Queue.Len())
+}
@@ -0,0 +1,106 @@
+package reconnect
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+)
+
+// maxUncappedDelay keeps the delay, including the jitter, representable as a time.Duration when no MaxDelay is set
+const maxUncappedDelay = float64(math.MaxInt64 / 2)
+
+// ArgsExponentialPolicy holds the arguments needed for creating an exponential reconnect policy
+type ArgsExponentialPolicy struct {
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+	Multiplier   float64
+	Jitter       float64
+	MaxAttempts  int
+}
+
+// exponentialPolicy multiplies the delay after every failed attempt and randomizes it, so many clients that lost the
+// connection at the same time do not reconnect all at once
+type exponentialPolicy struct {
+	initialDelay time.Duration
+	maxDelay     time.Duration
+	multiplier   float64
+	jitter       float64
+	maxAttempts  int
+	mutRandom    sync.Mutex
+	random       *rand.Rand
+}
+
+// NewExponentialPolicy will create an exponential reconnect policy. A zero MaxDelay means no upper limit for the
+// delay and a zero MaxAttempts means retrying forever
+func NewExponentialPolicy(args ArgsExponentialPolicy) (*exponentialPolicy, error) {
+	err := checkArgs(args)
+	if err != nil {
+		return nil, err
+	}
+
+	return &exponentialPolicy{
+		initialDelay: args.InitialDelay,
+		maxDelay:     args.MaxDelay,
+		multiplier:   args.Multiplier,
+		jitter:       args.Jitter,
+		maxAttempts:  args.MaxAttempts,
+		random:       rand.New(rand.NewSource(time.Now().UnixNano())),
+	}, nil
+}
+
+func checkArgs(args ArgsExponentialPolicy) error {
+	if args.InitialDelay <= 0 {
+		return data.ErrInvalidReconnectDelay
+	}
+	if args.MaxDelay != 0 && args.MaxDelay < args.InitialDelay {
+		return data.ErrInvalidReconnectDelay
+	}
+	if args.Multiplier < 1 {
+		return data.ErrInvalidReconnectMultiplier
+	}
+	if args.Jitter < 0 || args.Jitter > 1 {
+		return data.ErrInvalidReconnectJitter
+	}
+	if args.MaxAttempts < 0 {
+		return data.ErrInvalidMaxReconnectAttempts
+	}
+
+	return nil
+}
+
+// NextDelay returns the delay for the provided attempt, as long as the maximum number of attempts was not reached
+func (ep *exponentialPolicy) NextDelay(attempt int) (time.Duration, bool) {
+	if isAttemptsLimitReached(attempt, ep.maxAttempts) {
+		return 0, false
+	}
+	if attempt < 1 {
+		attempt = 1
+	}
+
+	delay := float64(ep.initialDelay) * math.Pow(ep.multiplier, float64(attempt-1))
+	if ep.maxDelay > 0 && delay > float64(ep.maxDelay) {
+		delay = float64(ep.maxDelay)
+	}
+	if delay > maxUncappedDelay {
+		delay = maxUncappedDelay
+	}
+
+	// spread the delay uniformly in [delay * (1 - jitter), delay * (1 + jitter)]
+	ep.mutRandom.Lock()
+	delay *= 1 + ep.jitter*(2*ep.random.Float64()-1)
+	ep.mutRandom.Unlock()
+
+	if delay < 1 {
+		delay = 1
+	}
+
+	return time.Duration(delay), true
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (ep *exponentialPolicy) IsInterfaceNil() bool {
+	return ep == nil
+}
@@ -0,0 +1,119 @@
+package reconnect
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+)
+
+func createArgs() ArgsExponentialPolicy {
+	return ArgsExponentialPolicy{
+		InitialDelay: time.Second,
+		MaxDelay:     time.Minute,
+		Multiplier:   2,
+		Jitter:       0.2,
+		MaxAttempts:  0,
+	}
+}
+
+func TestNewExponentialPolicy(t *testing.T) {
+	t.Parallel()
+
+	t.Run("zero initial delay, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.InitialDelay = 0
+		policy, err := NewExponentialPolicy(args)
+		require.Nil(t, policy)
+		require.Equal(t, data.ErrInvalidReconnectDelay, err)
+	})
+	t.Run("max delay lower than the initial delay, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.MaxDelay = time.Millisecond
+		policy, err := NewExponentialPolicy(args)
+		require.Nil(t, policy)
+		require.Equal(t, data.ErrInvalidReconnectDelay, err)
+	})
+	t.Run("multiplier lower than 1, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.Multiplier = 0.5
+		policy, err := NewExponentialPolicy(args)
+		require.Nil(t, policy)
+		require.Equal(t, data.ErrInvalidReconnectMultiplier, err)
+	})
+	t.Run("negative jitter, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.Jitter = -0.1
+		policy, err := NewExponentialPolicy(args)
+		require.Nil(t, policy)
+		require.Equal(t, data.ErrInvalidReconnectJitter, err)
+	})
+	t.Run("jitter greater than 1, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.Jitter = 1.1
+		policy, err := NewExponentialPolicy(args)
+		require.Nil(t, policy)
+		require.Equal(t, data.ErrInvalidReconnectJitter, err)
+	})
+	t.Run("negative max attempts, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.MaxAttempts = -1
+		policy, err := NewExponentialPolicy(args)
+		require.Nil(t, policy)
+		require.Equal(t, data.ErrInvalidMaxReconnectAttempts, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		policy, err := NewExponentialPolicy(createArgs())
+		require.Nil(t, err)
+		require.False(t, policy.IsInterfaceNil())
+	})
+}
+
+func TestExponentialPolicy_NextDelay(t *testing.T) {
+	t.Parallel()
+
+	t.Run("without jitter should double the delay until the max delay", func(t *testing.T) {
+		args := createArgs()
+		args.Jitter = 0
+		policy, _ := NewExponentialPolicy(args)
+
+		expectedDelays := []time.Duration{
+			time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second, 32 * time.Second,
+			time.Minute, time.Minute,
+		}
+		for idx, expectedDelay := range expectedDelays {
+			delay, shouldRetry := policy.NextDelay(idx + 1)
+			require.True(t, shouldRetry)
+			require.Equal(t, expectedDelay, delay)
+		}
+	})
+	t.Run("with jitter should keep the delay in range", func(t *testing.T) {
+		policy, _ := NewExponentialPolicy(createArgs())
+
+		for i := 0; i < 1000; i++ {
+			delay, _ := policy.NextDelay(3)
+			require.GreaterOrEqual(t, delay, 3200*time.Millisecond)
+			require.LessOrEqual(t, delay, 4800*time.Millisecond)
+		}
+	})
+	t.Run("without max delay should not overflow", func(t *testing.T) {
+		args := createArgs()
+		args.MaxDelay = 0
+		policy, _ := NewExponentialPolicy(args)
+
+		delay, shouldRetry := policy.NextDelay(10000)
+		require.True(t, shouldRetry)
+		require.Greater(t, delay, time.Duration(0))
+	})
+	t.Run("capped attempts", func(t *testing.T) {
+		args := createArgs()
+		args.MaxAttempts = 2
+		policy, _ := NewExponentialPolicy(args)
+
+		_, shouldRetry := policy.NextDelay(1)
+		require.True(t, shouldRetry)
+		_, shouldRetry = policy.NextDelay(2)
+		require.False(t, shouldRetry)
+	})
+}
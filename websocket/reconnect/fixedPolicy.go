@@ -0,0 +1,46 @@
+package reconnect
+
+import (
+	"time"
+
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+)
+
+type fixedPolicy struct {
+	delay       time.Duration
+	maxAttempts int
+}
+
+// NewFixedPolicy will create a reconnect policy that waits the same delay between the attempts. A zero maxAttempts
+// means retrying forever
+func NewFixedPolicy(delay time.Duration, maxAttempts int) (*fixedPolicy, error) {
+	if delay <= 0 {
+		return nil, data.ErrInvalidReconnectDelay
+	}
+	if maxAttempts < 0 {
+		return nil, data.ErrInvalidMaxReconnectAttempts
+	}
+
+	return &fixedPolicy{
+		delay:       delay,
+		maxAttempts: maxAttempts,
+	}, nil
+}
+
+// NextDelay returns the configured delay, as long as the maximum number of attempts was not reached
+func (fp *fixedPolicy) NextDelay(attempt int) (time.Duration, bool) {
+	if isAttemptsLimitReached(attempt, fp.maxAttempts) {
+		return 0, false
+	}
+
+	return fp.delay, true
+}
+
+func isAttemptsLimitReached(attempt int, maxAttempts int) bool {
+	return maxAttempts > 0 && attempt >= maxAttempts
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (fp *fixedPolicy) IsInterfaceNil() bool {
+	return fp == nil
+}
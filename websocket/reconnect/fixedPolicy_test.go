@@ -0,0 +1,53 @@
+package reconnect
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+)
+
+func TestNewFixedPolicy(t *testing.T) {
+	t.Parallel()
+
+	t.Run("zero delay, should return error", func(t *testing.T) {
+		policy, err := NewFixedPolicy(0, 0)
+		require.Nil(t, policy)
+		require.Equal(t, data.ErrInvalidReconnectDelay, err)
+	})
+	t.Run("negative max attempts, should return error", func(t *testing.T) {
+		policy, err := NewFixedPolicy(time.Second, -1)
+		require.Nil(t, policy)
+		require.Equal(t, data.ErrInvalidMaxReconnectAttempts, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		policy, err := NewFixedPolicy(time.Second, 0)
+		require.Nil(t, err)
+		require.False(t, policy.IsInterfaceNil())
+	})
+}
+
+func TestFixedPolicy_NextDelay(t *testing.T) {
+	t.Parallel()
+
+	t.Run("unlimited attempts", func(t *testing.T) {
+		policy, _ := NewFixedPolicy(time.Second, 0)
+		for attempt := 1; attempt < 100; attempt++ {
+			delay, shouldRetry := policy.NextDelay(attempt)
+			require.True(t, shouldRetry)
+			require.Equal(t, time.Second, delay)
+		}
+	})
+	t.Run("capped attempts", func(t *testing.T) {
+		policy, _ := NewFixedPolicy(time.Second, 3)
+		for attempt := 1; attempt < 3; attempt++ {
+			delay, shouldRetry := policy.NextDelay(attempt)
+			require.True(t, shouldRetry)
+			require.Equal(t, time.Second, delay)
+		}
+
+		_, shouldRetry := policy.NextDelay(3)
+		require.False(t, shouldRetry)
+	})
+}
@@ -0,0 +1,31 @@
+package relay
+
+import (
+	"context"
+
+	"github.com/subrahamanyam341/andes-communication/websocket"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+)
+
+// UpstreamHost defines what the host receiving the stream to be relayed should be able to do
+type UpstreamHost interface {
+	SetPayloadHandler(handler websocket.PayloadHandler) error
+	Close() error
+	IsInterfaceNil() bool
+}
+
+// DownstreamHost defines what the host re-serving the stream to the downstream clients should be able to do. The relay
+// also needs it to wait for the acknowledgements of a quorum of clients, as the servers do
+type DownstreamHost interface {
+	Send(payload []byte, topic string) error
+	SendAsync(payload []byte, topic string) (websocket.AckFuture, error)
+	Request(ctx context.Context, topic string, payload []byte) ([]byte, error)
+	SetPayloadHandler(handler websocket.PayloadHandler) error
+	SetRequestHandler(handler websocket.RequestHandler) error
+	Close() error
+	IsInterfaceNil() bool
+}
+
+type quorumSender interface {
+	SendMessageToQuorum(message *data.OutgoingMessage, quorum int) error
+}
@@ -0,0 +1,142 @@
+package relay
+
+import (
+	"context"
+
+	"github.com/subrahamanyam341/andes-communication/websocket"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	"github.com/subrahamanyam341/andes-core-16/core"
+	"github.com/subrahamanyam341/andes-core-16/core/check"
+)
+
+// ArgsRelay holds the arguments needed for creating a relay
+type ArgsRelay struct {
+	Upstream   UpstreamHost
+	Downstream DownstreamHost
+	AckQuorum  int
+	Log        core.Logger
+}
+
+type relay struct {
+	upstream   UpstreamHost
+	downstream DownstreamHost
+}
+
+// NewRelay will create a relay that forwards every payload received by the upstream host to the downstream clients.
+// The payload handler of the upstream host returns only once the acknowledgement quorum of the downstream clients is
+// reached, so the upstream host should acknowledge only the processed payloads
+func NewRelay(args ArgsRelay) (*relay, error) {
+	if check.IfNil(args.Upstream) {
+		return nil, data.ErrNilUpstreamHost
+	}
+	if check.IfNil(args.Downstream) {
+		return nil, data.ErrNilDownstreamHost
+	}
+	downstreamQuorumSender, ok := args.Downstream.(quorumSender)
+	if !ok {
+		return nil, data.ErrQuorumNotSupportedByDownstreamHost
+	}
+	if args.AckQuorum < 0 {
+		return nil, data.ErrInvalidAckQuorum
+	}
+	if check.IfNil(args.Log) {
+		return nil, core.ErrNilLogger
+	}
+
+	err := args.Upstream.SetPayloadHandler(&forwardingHandler{
+		downstream: downstreamQuorumSender,
+		ackQuorum:  args.AckQuorum,
+		log:        args.Log,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &relay{
+		upstream:   args.Upstream,
+		downstream: args.Downstream,
+	}, nil
+}
+
+// Send will send the provided payload to the downstream clients interested in the topic
+func (r *relay) Send(payload []byte, topic string) error {
+	return r.downstream.Send(payload, topic)
+}
+
+// SendAsync will send the provided payload to the downstream clients interested in the topic without waiting for the
+// acknowledgements
+func (r *relay) SendAsync(payload []byte, topic string) (websocket.AckFuture, error) {
+	return r.downstream.SendAsync(payload, topic)
+}
+
+// Request will send the provided payload as a request to the downstream clients interested in the topic
+func (r *relay) Request(ctx context.Context, topic string, payload []byte) ([]byte, error) {
+	return r.downstream.Request(ctx, topic, payload)
+}
+
+// SetPayloadHandler will set the handler of the payloads sent by the downstream clients
+func (r *relay) SetPayloadHandler(handler websocket.PayloadHandler) error {
+	return r.downstream.SetPayloadHandler(handler)
+}
+
+// SetRequestHandler will set the handler of the requests sent by the downstream clients
+func (r *relay) SetRequestHandler(handler websocket.RequestHandler) error {
+	return r.downstream.SetRequestHandler(handler)
+}
+
+// Close will stop receiving the upstream stream and then close the downstream connections
+func (r *relay) Close() error {
+	errUpstream := r.upstream.Close()
+	errDownstream := r.downstream.Close()
+	if errDownstream != nil {
+		return errDownstream
+	}
+
+	return errUpstream
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (r *relay) IsInterfaceNil() bool {
+	return r == nil
+}
+
+type forwardingHandler struct {
+	downstream quorumSender
+	ackQuorum  int
+	log        core.Logger
+}
+
+// ProcessPayloadMessage will forward the payload message to the downstream clients, with the signature of its origin,
+// and will wait for the acknowledgement quorum
+func (fh *forwardingHandler) ProcessPayloadMessage(message *data.WsMessage) error {
+	return fh.forward(&data.OutgoingMessage{
+		Payload:         message.Payload,
+		Topic:           message.Topic,
+		Signature:       message.Signature,
+		SignerPublicKey: message.SignerPublicKey,
+	})
+}
+
+// ProcessPayload will forward the payload to the downstream clients and will wait for the acknowledgement quorum
+func (fh *forwardingHandler) ProcessPayload(payload []byte, topic string, _ uint32) error {
+	return fh.forward(&data.OutgoingMessage{Payload: payload, Topic: topic})
+}
+
+func (fh *forwardingHandler) forward(message *data.OutgoingMessage) error {
+	err := fh.downstream.SendMessageToQuorum(message, fh.ackQuorum)
+	if err != nil {
+		fh.log.Debug("fh.forward: cannot relay payload", "topic", message.Topic, "error", err)
+	}
+
+	return err
+}
+
+// Close does nothing, the hosts are closed by the relay
+func (fh *forwardingHandler) Close() error {
+	return nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (fh *forwardingHandler) IsInterfaceNil() bool {
+	return fh == nil
+}
@@ -0,0 +1,228 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/testscommon"
+	relayStubs "github.com/subrahamanyam341/andes-communication/testscommon/relay"
+	"github.com/subrahamanyam341/andes-communication/websocket"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	"github.com/subrahamanyam341/andes-core-16/core"
+)
+
+func createArgs() ArgsRelay {
+	return ArgsRelay{
+		Upstream:   &relayStubs.HostStub{},
+		Downstream: &relayStubs.HostStub{},
+		AckQuorum:  0,
+		Log:        &testscommon.LoggerMock{},
+	}
+}
+
+func TestNewRelay(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil upstream host, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.Upstream = nil
+		r, err := NewRelay(args)
+		require.Nil(t, r)
+		require.Equal(t, data.ErrNilUpstreamHost, err)
+	})
+
+	t.Run("nil downstream host, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.Downstream = nil
+		r, err := NewRelay(args)
+		require.Nil(t, r)
+		require.Equal(t, data.ErrNilDownstreamHost, err)
+	})
+
+	t.Run("downstream host without acknowledgement quorum, should return error", func(t *testing.T) {
+		args := createArgs()
+		// only the methods of the interface are promoted, so the quorum sending of the stub is hidden
+		args.Downstream = struct{ DownstreamHost }{&relayStubs.HostStub{}}
+		r, err := NewRelay(args)
+		require.Nil(t, r)
+		require.Equal(t, data.ErrQuorumNotSupportedByDownstreamHost, err)
+	})
+
+	t.Run("negative acknowledgement quorum, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.AckQuorum = -1
+		r, err := NewRelay(args)
+		require.Nil(t, r)
+		require.Equal(t, data.ErrInvalidAckQuorum, err)
+	})
+
+	t.Run("nil logger, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.Log = nil
+		r, err := NewRelay(args)
+		require.Nil(t, r)
+		require.Equal(t, core.ErrNilLogger, err)
+	})
+
+	t.Run("cannot set the upstream payload handler, should return error", func(t *testing.T) {
+		expectedErr := errors.New("expected error")
+		args := createArgs()
+		args.Upstream = &relayStubs.HostStub{
+			SetPayloadHandlerCalled: func(_ websocket.PayloadHandler) error {
+				return expectedErr
+			},
+		}
+		r, err := NewRelay(args)
+		require.Nil(t, r)
+		require.Equal(t, expectedErr, err)
+	})
+
+	t.Run("should work", func(t *testing.T) {
+		r, err := NewRelay(createArgs())
+		require.Nil(t, err)
+		require.False(t, r.IsInterfaceNil())
+	})
+}
+
+func TestRelay_ShouldForwardTheUpstreamPayloads(t *testing.T) {
+	t.Parallel()
+
+	var upstreamHandler websocket.PayloadHandler
+	downstreamErr := errors.New("quorum not reached")
+	forwarded := make([]string, 0)
+	args := createArgs()
+	args.AckQuorum = 2
+	args.Upstream = &relayStubs.HostStub{
+		SetPayloadHandlerCalled: func(handler websocket.PayloadHandler) error {
+			upstreamHandler = handler
+			return nil
+		},
+	}
+	args.Downstream = &relayStubs.HostStub{
+		SendMessageToQuorumCalled: func(message *data.OutgoingMessage, quorum int) error {
+			require.Equal(t, 2, quorum)
+			forwarded = append(forwarded, message.Topic+":"+string(message.Payload))
+			if string(message.Payload) == "failing" {
+				return downstreamErr
+			}
+			return nil
+		},
+	}
+	_, _ = NewRelay(args)
+
+	err := upstreamHandler.ProcessPayload([]byte("payload"), "topic", 1)
+	require.Nil(t, err)
+
+	// the error keeps the upstream host from acknowledging the payload
+	err = upstreamHandler.ProcessPayload([]byte("failing"), "topic", 1)
+	require.Equal(t, downstreamErr, err)
+	require.Equal(t, []string{"topic:payload", "topic:failing"}, forwarded)
+
+	// the hosts are closed by the relay
+	require.Nil(t, upstreamHandler.Close())
+	require.False(t, upstreamHandler.IsInterfaceNil())
+}
+
+func TestRelay_ShouldForwardTheSignatureOfTheOrigin(t *testing.T) {
+	t.Parallel()
+
+	var upstreamHandler websocket.PayloadHandler
+	var forwarded *data.OutgoingMessage
+	args := createArgs()
+	args.Upstream = &relayStubs.HostStub{
+		SetPayloadHandlerCalled: func(handler websocket.PayloadHandler) error {
+			upstreamHandler = handler
+			return nil
+		},
+	}
+	args.Downstream = &relayStubs.HostStub{
+		SendMessageToQuorumCalled: func(message *data.OutgoingMessage, _ int) error {
+			forwarded = message
+			return nil
+		},
+	}
+	_, _ = NewRelay(args)
+
+	messageHandler, ok := upstreamHandler.(websocket.PayloadMessageHandler)
+	require.True(t, ok)
+	err := messageHandler.ProcessPayloadMessage(&data.WsMessage{
+		Type:            data.PayloadMessage,
+		Payload:         []byte("payload"),
+		Topic:           "topic",
+		Signature:       []byte("signature"),
+		SignerPublicKey: []byte("public key"),
+	})
+	require.Nil(t, err)
+	require.Equal(t, &data.OutgoingMessage{
+		Payload:         []byte("payload"),
+		Topic:           "topic",
+		Signature:       []byte("signature"),
+		SignerPublicKey: []byte("public key"),
+	}, forwarded)
+}
+
+func TestRelay_ShouldUseTheDownstreamHost(t *testing.T) {
+	t.Parallel()
+
+	calls := make([]string, 0)
+	args := createArgs()
+	args.Downstream = &relayStubs.HostStub{
+		SendCalled: func(_ []byte, _ string) error {
+			calls = append(calls, "send")
+			return nil
+		},
+		SendAsyncCalled: func(_ []byte, _ string) (websocket.AckFuture, error) {
+			calls = append(calls, "send async")
+			return nil, nil
+		},
+		RequestCalled: func(_ context.Context, _ string, _ []byte) ([]byte, error) {
+			calls = append(calls, "request")
+			return []byte("response"), nil
+		},
+		SetPayloadHandlerCalled: func(_ websocket.PayloadHandler) error {
+			calls = append(calls, "set payload handler")
+			return nil
+		},
+		SetRequestHandlerCalled: func(_ websocket.RequestHandler) error {
+			calls = append(calls, "set request handler")
+			return nil
+		},
+	}
+	r, _ := NewRelay(args)
+
+	_ = r.Send([]byte("payload"), "topic")
+	_, _ = r.SendAsync([]byte("payload"), "topic")
+	response, _ := r.Request(context.Background(), "topic", []byte("request"))
+	require.Equal(t, []byte("response"), response)
+	_ = r.SetPayloadHandler(&testscommon.PayloadHandlerStub{})
+	_ = r.SetRequestHandler(&testscommon.RequestHandlerStub{})
+
+	require.Equal(t, []string{"send", "send async", "request", "set payload handler", "set request handler"}, calls)
+}
+
+func TestRelay_Close(t *testing.T) {
+	t.Parallel()
+
+	expectedErr := errors.New("expected error")
+	closed := make([]string, 0)
+	args := createArgs()
+	args.Upstream = &relayStubs.HostStub{
+		CloseCalled: func() error {
+			closed = append(closed, "upstream")
+			return expectedErr
+		},
+	}
+	args.Downstream = &relayStubs.HostStub{
+		CloseCalled: func() error {
+			closed = append(closed, "downstream")
+			return nil
+		},
+	}
+	r, _ := NewRelay(args)
+
+	err := r.Close()
+	require.Equal(t, expectedErr, err)
+	require.Equal(t, []string{"upstream", "downstream"}, closed)
+}
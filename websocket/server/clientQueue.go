@@ -0,0 +1,178 @@
+package server
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+
+	webSocket "github.com/subrahamanyam341/andes-communication/websocket"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	"github.com/subrahamanyam341/andes-communication/websocket/transceiver"
+	"github.com/subrahamanyam341/andes-core-16/core"
+)
+
+type argsClientQueue struct {
+	clientID        string
+	maxSize         int
+	overflowPolicy  string
+	sendHandler     func(message *data.OutgoingMessage) error
+	overflowHandler func(clientID string)
+	log             core.Logger
+}
+
+type queuedPayload struct {
+	message *data.OutgoingMessage
+	future  completableAckFuture
+}
+
+// clientQueue holds the messages waiting to be sent to a single client and sends them, in order, from its own go
+// routine, so a slow client does not delay the others
+type clientQueue struct {
+	mut             sync.Mutex
+	clientID        string
+	maxSize         int
+	overflowPolicy  string
+	sendHandler     func(message *data.OutgoingMessage) error
+	overflowHandler func(clientID string)
+	log             core.Logger
+	items           *list.List
+	closed          bool
+	numDropped      atomic.Uint64
+	chanNewItem     chan struct{}
+	chanClose       chan struct{}
+}
+
+func checkOverflowPolicy(overflowPolicy string) error {
+	switch overflowPolicy {
+	case "", data.DropOldestOverflowPolicy, data.DropNewestOverflowPolicy, data.DisconnectOverflowPolicy:
+		return nil
+	default:
+		return data.ErrUnknownOverflowPolicy
+	}
+}
+
+func newClientQueue(args argsClientQueue) *clientQueue {
+	overflowPolicy := args.overflowPolicy
+	if len(overflowPolicy) == 0 {
+		overflowPolicy = data.DropOldestOverflowPolicy
+	}
+
+	cq := &clientQueue{
+		clientID:        args.clientID,
+		maxSize:         args.maxSize,
+		overflowPolicy:  overflowPolicy,
+		sendHandler:     args.sendHandler,
+		overflowHandler: args.overflowHandler,
+		log:             args.log,
+		items:           list.New(),
+		chanNewItem:     make(chan struct{}, 1),
+		chanClose:       make(chan struct{}),
+	}
+
+	go cq.processLoop()
+
+	return cq
+}
+
+// push will add the message at the end of the queue. The returned future completes once the message was sent or dropped
+func (cq *clientQueue) push(message *data.OutgoingMessage) webSocket.AckFuture {
+	cq.mut.Lock()
+	defer cq.mut.Unlock()
+
+	if cq.closed {
+		return transceiver.NewCompletedAckFuture(data.ErrClientQueueClosed)
+	}
+
+	if cq.items.Len() >= cq.maxSize {
+		switch cq.overflowPolicy {
+		case data.DropNewestOverflowPolicy:
+			cq.markDropped(message.Topic)
+			return transceiver.NewCompletedAckFuture(data.ErrClientQueueFull)
+		case data.DisconnectOverflowPolicy:
+			cq.markDropped(message.Topic)
+			// the handler closes the connection, which closes this queue, so it can not be called under the lock
+			go cq.overflowHandler(cq.clientID)
+			return transceiver.NewCompletedAckFuture(data.ErrClientQueueFull)
+		default:
+			oldest := cq.items.Remove(cq.items.Front()).(*queuedPayload)
+			oldest.future.Complete(data.ErrClientQueueFull)
+			cq.markDropped(oldest.message.Topic)
+		}
+	}
+
+	future := transceiver.NewPendingAckFuture()
+	cq.items.PushBack(&queuedPayload{
+		message: message,
+		future:  future,
+	})
+
+	select {
+	case cq.chanNewItem <- struct{}{}:
+	default:
+	}
+
+	return future
+}
+
+func (cq *clientQueue) markDropped(topic string) {
+	cq.numDropped.Add(1)
+	cq.log.Debug("clientQueue: the queue is full, dropping message", "client id", cq.clientID, "topic", topic, "policy", cq.overflowPolicy)
+}
+
+func (cq *clientQueue) pop() *queuedPayload {
+	cq.mut.Lock()
+	defer cq.mut.Unlock()
+
+	front := cq.items.Front()
+	if front == nil {
+		return nil
+	}
+
+	return cq.items.Remove(front).(*queuedPayload)
+}
+
+func (cq *clientQueue) processLoop() {
+	for {
+		item := cq.pop()
+		if item != nil {
+			item.future.Complete(cq.sendHandler(item.message))
+			continue
+		}
+
+		select {
+		case <-cq.chanNewItem:
+		case <-cq.chanClose:
+			return
+		}
+	}
+}
+
+// len returns the number of messages waiting to be sent
+func (cq *clientQueue) len() int {
+	cq.mut.Lock()
+	defer cq.mut.Unlock()
+
+	return cq.items.Len()
+}
+
+// dropped returns the number of messages dropped because the queue was full
+func (cq *clientQueue) dropped() uint64 {
+	return cq.numDropped.Load()
+}
+
+// close will stop sending the queued messages. The messages still waiting in the queue are dropped
+func (cq *clientQueue) close() {
+	cq.mut.Lock()
+	defer cq.mut.Unlock()
+
+	if cq.closed {
+		return
+	}
+	cq.closed = true
+	close(cq.chanClose)
+
+	for element := cq.items.Front(); element != nil; element = element.Next() {
+		element.Value.(*queuedPayload).future.Complete(data.ErrClientQueueClosed)
+	}
+	cq.items.Init()
+}
@@ -0,0 +1,175 @@
+package server
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/testscommon"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+)
+
+type blockingSender struct {
+	mut       sync.Mutex
+	sent      []string
+	chRelease chan struct{}
+}
+
+func newBlockingSender() *blockingSender {
+	return &blockingSender{
+		chRelease: make(chan struct{}),
+	}
+}
+
+func (bs *blockingSender) send(message *data.OutgoingMessage) error {
+	<-bs.chRelease
+
+	bs.mut.Lock()
+	bs.sent = append(bs.sent, string(message.Payload))
+	bs.mut.Unlock()
+
+	return nil
+}
+
+func (bs *blockingSender) sentPayloads() []string {
+	bs.mut.Lock()
+	defer bs.mut.Unlock()
+
+	return append([]string{}, bs.sent...)
+}
+
+func createClientQueueArgs(sender *blockingSender, overflowPolicy string) argsClientQueue {
+	return argsClientQueue{
+		clientID:        "client",
+		maxSize:         2,
+		overflowPolicy:  overflowPolicy,
+		sendHandler:     sender.send,
+		overflowHandler: func(_ string) {},
+		log:             &testscommon.LoggerMock{},
+	}
+}
+
+// fillQueue pushes one payload that blocks the sending go routine and two payloads that fill the queue
+func fillQueue(t *testing.T, queue *clientQueue) {
+	_ = queue.push(&data.OutgoingMessage{Payload: []byte("in flight"), Topic: "topic"})
+	require.Eventually(t, func() bool {
+		return queue.len() == 0
+	}, time.Second, time.Millisecond)
+
+	_ = queue.push(&data.OutgoingMessage{Payload: []byte("first"), Topic: "topic"})
+	_ = queue.push(&data.OutgoingMessage{Payload: []byte("second"), Topic: "topic"})
+	require.Equal(t, 2, queue.len())
+}
+
+func TestCheckOverflowPolicy(t *testing.T) {
+	t.Parallel()
+
+	for _, policy := range []string{"", data.DropOldestOverflowPolicy, data.DropNewestOverflowPolicy, data.DisconnectOverflowPolicy} {
+		require.Nil(t, checkOverflowPolicy(policy))
+	}
+	require.Equal(t, data.ErrUnknownOverflowPolicy, checkOverflowPolicy("block"))
+}
+
+func TestClientQueue_ShouldSendInOrder(t *testing.T) {
+	t.Parallel()
+
+	sender := newBlockingSender()
+	close(sender.chRelease)
+	args := createClientQueueArgs(sender, data.DropNewestOverflowPolicy)
+	args.maxSize = 100
+	queue := newClientQueue(args)
+	defer queue.close()
+
+	expected := make([]string, 0, 50)
+	for i := 0; i < 50; i++ {
+		payload := fmt.Sprintf("payload %d", i)
+		expected = append(expected, payload)
+		_ = queue.push(&data.OutgoingMessage{Payload: []byte(payload), Topic: "topic"})
+	}
+
+	future := queue.push(&data.OutgoingMessage{Payload: []byte("last"), Topic: "topic"})
+	require.Nil(t, future.Wait())
+	require.Equal(t, append(expected, "last"), sender.sentPayloads())
+}
+
+func TestClientQueue_DropOldest(t *testing.T) {
+	t.Parallel()
+
+	sender := newBlockingSender()
+	queue := newClientQueue(createClientQueueArgs(sender, ""))
+	defer queue.close()
+	fillQueue(t, queue)
+
+	future := queue.push(&data.OutgoingMessage{Payload: []byte("third"), Topic: "topic"})
+	require.Equal(t, 2, queue.len())
+	require.Equal(t, uint64(1), queue.dropped())
+
+	close(sender.chRelease)
+	require.Nil(t, future.Wait())
+	require.Equal(t, []string{"in flight", "second", "third"}, sender.sentPayloads())
+}
+
+func TestClientQueue_DropNewest(t *testing.T) {
+	t.Parallel()
+
+	sender := newBlockingSender()
+	queue := newClientQueue(createClientQueueArgs(sender, data.DropNewestOverflowPolicy))
+	defer queue.close()
+	fillQueue(t, queue)
+
+	future := queue.push(&data.OutgoingMessage{Payload: []byte("third"), Topic: "topic"})
+	require.Equal(t, data.ErrClientQueueFull, future.Wait())
+	require.Equal(t, uint64(1), queue.dropped())
+
+	close(sender.chRelease)
+	require.Eventually(t, func() bool {
+		return queue.len() == 0
+	}, time.Second, time.Millisecond)
+	require.Nil(t, queue.push(&data.OutgoingMessage{Payload: []byte("fourth"), Topic: "topic"}).Wait())
+	require.Equal(t, []string{"in flight", "first", "second", "fourth"}, sender.sentPayloads())
+}
+
+func TestClientQueue_Disconnect(t *testing.T) {
+	t.Parallel()
+
+	sender := newBlockingSender()
+	args := createClientQueueArgs(sender, data.DisconnectOverflowPolicy)
+	chDisconnected := make(chan string, 1)
+	args.overflowHandler = func(clientID string) {
+		chDisconnected <- clientID
+	}
+	queue := newClientQueue(args)
+	defer queue.close()
+	fillQueue(t, queue)
+
+	future := queue.push(&data.OutgoingMessage{Payload: []byte("third"), Topic: "topic"})
+	require.Equal(t, data.ErrClientQueueFull, future.Wait())
+
+	select {
+	case clientID := <-chDisconnected:
+		require.Equal(t, "client", clientID)
+	case <-time.After(time.Second):
+		require.Fail(t, "the overflow handler was not called")
+	}
+}
+
+func TestClientQueue_CloseShouldDropQueuedMessages(t *testing.T) {
+	t.Parallel()
+
+	sender := newBlockingSender()
+	queue := newClientQueue(createClientQueueArgs(sender, data.DropNewestOverflowPolicy))
+	_ = queue.push(&data.OutgoingMessage{Payload: []byte("in flight"), Topic: "topic"})
+	require.Eventually(t, func() bool {
+		return queue.len() == 0
+	}, time.Second, time.Millisecond)
+	future := queue.push(&data.OutgoingMessage{Payload: []byte("queued"), Topic: "topic"})
+
+	queue.close()
+	require.Equal(t, data.ErrClientQueueClosed, future.Wait())
+	require.Equal(t, 0, queue.len())
+	require.Equal(t, data.ErrClientQueueClosed, queue.push(&data.OutgoingMessage{Payload: []byte("after close"), Topic: "topic"}).Wait())
+
+	close(sender.chRelease)
+}
@@ -0,0 +1,314 @@
+package server
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	webSocket "github.com/subrahamanyam341/andes-communication/websocket"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	"github.com/subrahamanyam341/andes-core-16/core"
+)
+
+// errPayloadOverRateLimit is returned for the payloads exceeding the rate limits, which must not be acknowledged
+var errPayloadOverRateLimit = fmt.Errorf("%w: %w", data.ErrPayloadRefused, data.ErrRateLimitExceeded)
+
+type argsClientRateLimiter struct {
+	clientID          string
+	config            data.RateLimitConfig
+	disconnectHandler func(clientID string)
+	log               core.Logger
+}
+
+// tokenBucket allows a number of messages per second, plus a burst of messages sent at once after a quiet period
+type tokenBucket struct {
+	ratePerSecond float64
+	capacity      float64
+	tokens        float64
+	lastRefill    time.Time
+}
+
+// clientRateLimiter limits the rate of the payload and request messages received from a single client, using a token
+// bucket for all the messages of the client and one for every topic with its own limit. It is used by the go routine
+// reading the messages of the client and by the go routines handling its requests
+type clientRateLimiter struct {
+	clientID          string
+	action            string
+	clientBucket      *tokenBucket
+	topicBuckets      map[string]*tokenBucket
+	mutBuckets        sync.Mutex
+	disconnectHandler func(clientID string)
+	log               core.Logger
+	numLimited        atomic.Uint64
+	chanClose         chan struct{}
+	closeOnce         sync.Once
+}
+
+func isRateLimitEnabled(config data.RateLimitConfig) bool {
+	return config.MessagesPerSecond > 0 || len(config.Topics) > 0
+}
+
+func checkRateLimitConfig(config data.RateLimitConfig) error {
+	if config.MessagesPerSecond < 0 || config.Burst < 0 {
+		return data.ErrInvalidRateLimitConfig
+	}
+
+	topics := make(map[string]struct{}, len(config.Topics))
+	for _, topicConfig := range config.Topics {
+		if len(topicConfig.Topic) == 0 || topicConfig.MessagesPerSecond <= 0 || topicConfig.Burst < 0 {
+			return fmt.Errorf("%w for topic %s", data.ErrInvalidRateLimitConfig, topicConfig.Topic)
+		}
+
+		_, found := topics[topicConfig.Topic]
+		if found {
+			return fmt.Errorf("%w: duplicated topic %s", data.ErrInvalidRateLimitConfig, topicConfig.Topic)
+		}
+		topics[topicConfig.Topic] = struct{}{}
+	}
+
+	switch config.Action {
+	case "", data.DropRateLimitAction, data.DelayRateLimitAction, data.DisconnectRateLimitAction:
+		return nil
+	default:
+		return data.ErrUnknownRateLimitAction
+	}
+}
+
+func newTokenBucket(ratePerSecond float64, burst int, now time.Time) *tokenBucket {
+	capacity := float64(burst)
+	if burst == 0 {
+		capacity = math.Max(1, math.Ceil(ratePerSecond))
+	}
+
+	return &tokenBucket{
+		ratePerSecond: ratePerSecond,
+		capacity:      capacity,
+		tokens:        capacity,
+		lastRefill:    now,
+	}
+}
+
+func (tb *tokenBucket) refill(now time.Time) {
+	elapsed := now.Sub(tb.lastRefill).Seconds()
+	if elapsed <= 0 {
+		return
+	}
+
+	tb.tokens = math.Min(tb.capacity, tb.tokens+elapsed*tb.ratePerSecond)
+	tb.lastRefill = now
+}
+
+// timeUntilToken returns the duration until the bucket holds a whole token, 0 if it already does
+func (tb *tokenBucket) timeUntilToken() time.Duration {
+	if tb.tokens >= 1 {
+		return 0
+	}
+
+	seconds := (1 - tb.tokens) / tb.ratePerSecond
+
+	return time.Duration(math.Ceil(seconds * float64(time.Second)))
+}
+
+func newClientRateLimiter(args argsClientRateLimiter) *clientRateLimiter {
+	action := args.config.Action
+	if len(action) == 0 {
+		action = data.DropRateLimitAction
+	}
+
+	now := time.Now()
+	crl := &clientRateLimiter{
+		clientID:          args.clientID,
+		action:            action,
+		topicBuckets:      make(map[string]*tokenBucket, len(args.config.Topics)),
+		disconnectHandler: args.disconnectHandler,
+		log:               args.log,
+		chanClose:         make(chan struct{}),
+	}
+	if args.config.MessagesPerSecond > 0 {
+		crl.clientBucket = newTokenBucket(args.config.MessagesPerSecond, args.config.Burst, now)
+	}
+	for _, topicConfig := range args.config.Topics {
+		crl.topicBuckets[topicConfig.Topic] = newTokenBucket(topicConfig.MessagesPerSecond, topicConfig.Burst, now)
+	}
+
+	return crl
+}
+
+// acquire returns true if a message received on the provided topic can be processed. A message exceeding the limits is
+// dropped or its client is disconnected, depending on the configured action, or acquire waits until the message fits
+// the limits, slowing down the reading from the client
+func (crl *clientRateLimiter) acquire(topic string) bool {
+	buckets := crl.getBuckets(topic)
+	if len(buckets) == 0 {
+		return true
+	}
+
+	isLimited := false
+	for {
+		crl.mutBuckets.Lock()
+		wait := takeToken(buckets, time.Now())
+		crl.mutBuckets.Unlock()
+		if wait == 0 {
+			return true
+		}
+
+		if !isLimited {
+			isLimited = true
+			crl.markLimited(topic)
+		}
+
+		switch crl.action {
+		case data.DelayRateLimitAction:
+			select {
+			case <-time.After(wait):
+			case <-crl.chanClose:
+				return false
+			}
+		case data.DisconnectRateLimitAction:
+			crl.disconnectHandler(crl.clientID)
+			return false
+		default:
+			return false
+		}
+	}
+}
+
+func (crl *clientRateLimiter) getBuckets(topic string) []*tokenBucket {
+	buckets := make([]*tokenBucket, 0, 2)
+	if crl.clientBucket != nil {
+		buckets = append(buckets, crl.clientBucket)
+	}
+
+	topicBucket, found := crl.topicBuckets[topic]
+	if found {
+		buckets = append(buckets, topicBucket)
+	}
+
+	return buckets
+}
+
+// takeToken takes a token from all the provided buckets if each of them holds one. Otherwise, no token is taken and the
+// returned duration is the time until all of them will hold one
+func takeToken(buckets []*tokenBucket, now time.Time) time.Duration {
+	wait := time.Duration(0)
+	for _, bucket := range buckets {
+		bucket.refill(now)
+		wait = maxDuration(wait, bucket.timeUntilToken())
+	}
+	if wait > 0 {
+		return wait
+	}
+
+	for _, bucket := range buckets {
+		bucket.tokens--
+	}
+
+	return 0
+}
+
+func maxDuration(first time.Duration, second time.Duration) time.Duration {
+	if first > second {
+		return first
+	}
+
+	return second
+}
+
+func (crl *clientRateLimiter) markLimited(topic string) {
+	crl.numLimited.Add(1)
+	crl.log.Debug("clientRateLimiter: the rate limit is exceeded", "client id", crl.clientID, "topic", topic, "action", crl.action)
+}
+
+// limited returns the number of received messages that exceeded the limits
+func (crl *clientRateLimiter) limited() uint64 {
+	return crl.numLimited.Load()
+}
+
+// close will stop the wait of a delayed message, the message being dropped
+func (crl *clientRateLimiter) close() {
+	crl.closeOnce.Do(func() {
+		close(crl.chanClose)
+	})
+}
+
+// rateLimitedPayloadHandler passes to the wrapped payload handler only the payloads fitting the rate limits of the
+// client. The other payloads are refused, so they are not acknowledged and the sender can tell they were not processed
+type rateLimitedPayloadHandler struct {
+	payloadHandler webSocket.PayloadHandler
+	rateLimiter    *clientRateLimiter
+}
+
+func newRateLimitedPayloadHandler(payloadHandler webSocket.PayloadHandler, rateLimiter *clientRateLimiter) *rateLimitedPayloadHandler {
+	return &rateLimitedPayloadHandler{
+		payloadHandler: payloadHandler,
+		rateLimiter:    rateLimiter,
+	}
+}
+
+// ProcessPayload will pass the payload to the wrapped handler if it fits the rate limits of the client
+func (handler *rateLimitedPayloadHandler) ProcessPayload(payload []byte, topic string, version uint32) error {
+	if !handler.rateLimiter.acquire(topic) {
+		return errPayloadOverRateLimit
+	}
+
+	return handler.payloadHandler.ProcessPayload(payload, topic, version)
+}
+
+// ProcessPayloadMessage will pass the whole payload message to the wrapped handler if it fits the rate limits of the
+// client, so a wrapped handler needing the signature of the origin still receives it
+func (handler *rateLimitedPayloadHandler) ProcessPayloadMessage(message *data.WsMessage) error {
+	messageHandler, ok := handler.payloadHandler.(webSocket.PayloadMessageHandler)
+	if !ok {
+		return handler.ProcessPayload(message.Payload, message.Topic, message.Version)
+	}
+	if !handler.rateLimiter.acquire(message.Topic) {
+		return errPayloadOverRateLimit
+	}
+
+	return messageHandler.ProcessPayloadMessage(message)
+}
+
+// Close will stop the wait of a delayed payload and will close the wrapped handler
+func (handler *rateLimitedPayloadHandler) Close() error {
+	handler.rateLimiter.close()
+
+	return handler.payloadHandler.Close()
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (handler *rateLimitedPayloadHandler) IsInterfaceNil() bool {
+	return handler == nil
+}
+
+// rateLimitedRequestHandler passes to the wrapped request handler only the requests fitting the rate limits of the
+// client. The other requests are answered with an error
+type rateLimitedRequestHandler struct {
+	requestHandler webSocket.RequestHandler
+	rateLimiter    *clientRateLimiter
+}
+
+func newRateLimitedRequestHandler(requestHandler webSocket.RequestHandler, rateLimiter *clientRateLimiter) *rateLimitedRequestHandler {
+	return &rateLimitedRequestHandler{
+		requestHandler: requestHandler,
+		rateLimiter:    rateLimiter,
+	}
+}
+
+// ProcessRequest will pass the request to the wrapped handler if it fits the rate limits of the client
+func (handler *rateLimitedRequestHandler) ProcessRequest(payload []byte, topic string, version uint32) ([]byte, error) {
+	if !handler.rateLimiter.acquire(topic) {
+		return nil, &data.RequestError{
+			Code:    data.RequestErrorCodeRejected,
+			Message: data.ErrRateLimitExceeded.Error(),
+		}
+	}
+
+	return handler.requestHandler.ProcessRequest(payload, topic, version)
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (handler *rateLimitedRequestHandler) IsInterfaceNil() bool {
+	return handler == nil
+}
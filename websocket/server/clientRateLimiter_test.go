@@ -0,0 +1,311 @@
+package server
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/testscommon"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+)
+
+func createClientRateLimiterArgs(config data.RateLimitConfig) argsClientRateLimiter {
+	return argsClientRateLimiter{
+		clientID:          "client",
+		config:            config,
+		disconnectHandler: func(_ string) {},
+		log:               &testscommon.LoggerMock{},
+	}
+}
+
+func TestCheckRateLimitConfig(t *testing.T) {
+	t.Parallel()
+
+	t.Run("negative rate, should return error", func(t *testing.T) {
+		err := checkRateLimitConfig(data.RateLimitConfig{MessagesPerSecond: -1})
+		require.Equal(t, data.ErrInvalidRateLimitConfig, err)
+	})
+	t.Run("negative burst, should return error", func(t *testing.T) {
+		err := checkRateLimitConfig(data.RateLimitConfig{MessagesPerSecond: 1, Burst: -1})
+		require.Equal(t, data.ErrInvalidRateLimitConfig, err)
+	})
+	t.Run("topic without rate, should return error", func(t *testing.T) {
+		err := checkRateLimitConfig(data.RateLimitConfig{
+			Topics: []data.TopicRateLimitConfig{{Topic: "topic"}},
+		})
+		require.True(t, errors.Is(err, data.ErrInvalidRateLimitConfig))
+		require.ErrorContains(t, err, "topic")
+	})
+	t.Run("empty topic, should return error", func(t *testing.T) {
+		err := checkRateLimitConfig(data.RateLimitConfig{
+			Topics: []data.TopicRateLimitConfig{{MessagesPerSecond: 1}},
+		})
+		require.True(t, errors.Is(err, data.ErrInvalidRateLimitConfig))
+	})
+	t.Run("duplicated topic, should return error", func(t *testing.T) {
+		err := checkRateLimitConfig(data.RateLimitConfig{
+			Topics: []data.TopicRateLimitConfig{
+				{Topic: "topic", MessagesPerSecond: 1},
+				{Topic: "topic", MessagesPerSecond: 2},
+			},
+		})
+		require.True(t, errors.Is(err, data.ErrInvalidRateLimitConfig))
+		require.ErrorContains(t, err, "duplicated topic")
+	})
+	t.Run("unknown action, should return error", func(t *testing.T) {
+		err := checkRateLimitConfig(data.RateLimitConfig{Action: "block"})
+		require.Equal(t, data.ErrUnknownRateLimitAction, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		for _, action := range []string{"", data.DropRateLimitAction, data.DelayRateLimitAction, data.DisconnectRateLimitAction} {
+			err := checkRateLimitConfig(data.RateLimitConfig{
+				MessagesPerSecond: 10,
+				Burst:             5,
+				Topics:            []data.TopicRateLimitConfig{{Topic: "topic", MessagesPerSecond: 1}},
+				Action:            action,
+			})
+			require.Nil(t, err)
+		}
+	})
+}
+
+func TestTokenBucket(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	bucket := newTokenBucket(2, 0, now)
+	require.Equal(t, float64(2), bucket.capacity)
+
+	require.Zero(t, takeToken([]*tokenBucket{bucket}, now))
+	require.Zero(t, takeToken([]*tokenBucket{bucket}, now))
+	require.Equal(t, 500*time.Millisecond, takeToken([]*tokenBucket{bucket}, now))
+
+	require.Equal(t, 250*time.Millisecond, takeToken([]*tokenBucket{bucket}, now.Add(250*time.Millisecond)))
+	require.Zero(t, takeToken([]*tokenBucket{bucket}, now.Add(500*time.Millisecond)))
+
+	// the tokens do not accumulate above the burst
+	later := now.Add(time.Hour)
+	require.Zero(t, takeToken([]*tokenBucket{bucket}, later))
+	require.Zero(t, takeToken([]*tokenBucket{bucket}, later))
+	require.NotZero(t, takeToken([]*tokenBucket{bucket}, later))
+
+	t.Run("a slow rate should still allow one message", func(t *testing.T) {
+		slowBucket := newTokenBucket(0.1, 0, now)
+		require.Equal(t, float64(1), slowBucket.capacity)
+	})
+	t.Run("no token is taken unless all the buckets hold one", func(t *testing.T) {
+		first := newTokenBucket(1, 1, now)
+		second := newTokenBucket(1, 2, now)
+		require.Zero(t, takeToken([]*tokenBucket{first, second}, now))
+		require.Equal(t, time.Second, takeToken([]*tokenBucket{first, second}, now))
+		require.Equal(t, float64(1), second.tokens)
+	})
+}
+
+func TestClientRateLimiter_DropAction(t *testing.T) {
+	t.Parallel()
+
+	limiter := newClientRateLimiter(createClientRateLimiterArgs(data.RateLimitConfig{
+		MessagesPerSecond: 1,
+		Burst:             2,
+	}))
+	defer limiter.close()
+
+	require.True(t, limiter.acquire("topic"))
+	require.True(t, limiter.acquire("other topic"))
+	require.False(t, limiter.acquire("topic"))
+	require.False(t, limiter.acquire("other topic"))
+	require.Equal(t, uint64(2), limiter.limited())
+}
+
+func TestClientRateLimiter_TopicLimits(t *testing.T) {
+	t.Parallel()
+
+	limiter := newClientRateLimiter(createClientRateLimiterArgs(data.RateLimitConfig{
+		Topics: []data.TopicRateLimitConfig{
+			{Topic: "limited", MessagesPerSecond: 1},
+		},
+	}))
+	defer limiter.close()
+
+	require.True(t, limiter.acquire("limited"))
+	require.False(t, limiter.acquire("limited"))
+	for i := 0; i < 10; i++ {
+		require.True(t, limiter.acquire("not limited"))
+	}
+	require.Equal(t, uint64(1), limiter.limited())
+}
+
+func TestClientRateLimiter_DelayAction(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should wait until the message fits the limits", func(t *testing.T) {
+		t.Parallel()
+
+		limiter := newClientRateLimiter(createClientRateLimiterArgs(data.RateLimitConfig{
+			MessagesPerSecond: 10,
+			Burst:             1,
+			Action:            data.DelayRateLimitAction,
+		}))
+		defer limiter.close()
+
+		start := time.Now()
+		for i := 0; i < 3; i++ {
+			require.True(t, limiter.acquire("topic"))
+		}
+		require.GreaterOrEqual(t, time.Since(start), 150*time.Millisecond)
+		require.Equal(t, uint64(2), limiter.limited())
+	})
+	t.Run("close should stop the wait", func(t *testing.T) {
+		t.Parallel()
+
+		limiter := newClientRateLimiter(createClientRateLimiterArgs(data.RateLimitConfig{
+			MessagesPerSecond: 0.01,
+			Action:            data.DelayRateLimitAction,
+		}))
+		require.True(t, limiter.acquire("topic"))
+
+		chAcquired := make(chan bool)
+		go func() {
+			chAcquired <- limiter.acquire("topic")
+		}()
+		limiter.close()
+
+		select {
+		case acquired := <-chAcquired:
+			require.False(t, acquired)
+		case <-time.After(time.Second):
+			require.Fail(t, "timeout waiting for the delayed message")
+		}
+	})
+}
+
+func TestClientRateLimiter_DisconnectAction(t *testing.T) {
+	t.Parallel()
+
+	args := createClientRateLimiterArgs(data.RateLimitConfig{
+		MessagesPerSecond: 1,
+		Action:            data.DisconnectRateLimitAction,
+	})
+	disconnected := ""
+	args.disconnectHandler = func(clientID string) {
+		disconnected = clientID
+	}
+	limiter := newClientRateLimiter(args)
+	defer limiter.close()
+
+	require.True(t, limiter.acquire("topic"))
+	require.Empty(t, disconnected)
+	require.False(t, limiter.acquire("topic"))
+	require.Equal(t, "client", disconnected)
+}
+
+func TestRateLimitedPayloadHandler(t *testing.T) {
+	t.Parallel()
+
+	numProcessed := atomic.Int32{}
+	numClosed := atomic.Int32{}
+	payloadHandler := &testscommon.PayloadHandlerStub{
+		ProcessPayloadCalled: func(_ []byte, _ string, _ uint32) error {
+			numProcessed.Add(1)
+			return nil
+		},
+		CloseCalled: func() error {
+			numClosed.Add(1)
+			return nil
+		},
+	}
+
+	t.Run("dropped payloads return error", func(t *testing.T) {
+		numProcessed.Store(0)
+		limiter := newClientRateLimiter(createClientRateLimiterArgs(data.RateLimitConfig{MessagesPerSecond: 1}))
+		handler := newRateLimitedPayloadHandler(payloadHandler, limiter)
+
+		require.Nil(t, handler.ProcessPayload([]byte("payload"), "topic", 1))
+		err := handler.ProcessPayload([]byte("payload"), "topic", 1)
+		require.ErrorIs(t, err, data.ErrPayloadRefused)
+		require.ErrorIs(t, err, data.ErrRateLimitExceeded)
+		require.Equal(t, int32(1), numProcessed.Load())
+
+		require.Nil(t, handler.Close())
+		require.Equal(t, int32(1), numClosed.Load())
+	})
+	t.Run("payloads of a disconnected client return error", func(t *testing.T) {
+		numProcessed.Store(0)
+		limiter := newClientRateLimiter(createClientRateLimiterArgs(data.RateLimitConfig{
+			MessagesPerSecond: 1,
+			Action:            data.DisconnectRateLimitAction,
+		}))
+		handler := newRateLimitedPayloadHandler(payloadHandler, limiter)
+		defer func() {
+			_ = handler.Close()
+		}()
+
+		require.Nil(t, handler.ProcessPayload([]byte("payload"), "topic", 1))
+		err := handler.ProcessPayload([]byte("payload"), "topic", 1)
+		require.ErrorIs(t, err, data.ErrPayloadRefused)
+		require.ErrorIs(t, err, data.ErrRateLimitExceeded)
+		require.Equal(t, int32(1), numProcessed.Load())
+	})
+	t.Run("payload messages are passed whole to a message handler", func(t *testing.T) {
+		var received []*data.WsMessage
+		messageHandler := &testscommon.PayloadMessageHandlerStub{
+			ProcessPayloadMessageCalled: func(message *data.WsMessage) error {
+				received = append(received, message)
+				return nil
+			},
+		}
+		limiter := newClientRateLimiter(createClientRateLimiterArgs(data.RateLimitConfig{MessagesPerSecond: 1}))
+		handler := newRateLimitedPayloadHandler(messageHandler, limiter)
+		defer func() {
+			_ = handler.Close()
+		}()
+
+		message := &data.WsMessage{Payload: []byte("payload"), Topic: "topic", Signature: []byte("signature")}
+		require.Nil(t, handler.ProcessPayloadMessage(message))
+		err := handler.ProcessPayloadMessage(message)
+		require.ErrorIs(t, err, data.ErrPayloadRefused)
+		require.Equal(t, []*data.WsMessage{message}, received)
+	})
+	t.Run("payload messages are passed as payloads to a payload handler", func(t *testing.T) {
+		numProcessed.Store(0)
+		limiter := newClientRateLimiter(createClientRateLimiterArgs(data.RateLimitConfig{MessagesPerSecond: 1}))
+		handler := newRateLimitedPayloadHandler(payloadHandler, limiter)
+		defer func() {
+			_ = handler.Close()
+		}()
+
+		message := &data.WsMessage{Payload: []byte("payload"), Topic: "topic"}
+		require.Nil(t, handler.ProcessPayloadMessage(message))
+		err := handler.ProcessPayloadMessage(message)
+		require.ErrorIs(t, err, data.ErrPayloadRefused)
+		require.Equal(t, int32(1), numProcessed.Load())
+	})
+}
+
+func TestRateLimitedRequestHandler(t *testing.T) {
+	t.Parallel()
+
+	numProcessed := atomic.Int32{}
+	requestHandler := &testscommon.RequestHandlerStub{
+		ProcessRequestCalled: func(payload []byte, _ string, _ uint32) ([]byte, error) {
+			numProcessed.Add(1)
+			return payload, nil
+		},
+	}
+
+	limiter := newClientRateLimiter(createClientRateLimiterArgs(data.RateLimitConfig{MessagesPerSecond: 1}))
+	handler := newRateLimitedRequestHandler(requestHandler, limiter)
+	require.False(t, handler.IsInterfaceNil())
+
+	response, err := handler.ProcessRequest([]byte("request"), "topic", 1)
+	require.Nil(t, err)
+	require.Equal(t, []byte("request"), response)
+
+	response, err = handler.ProcessRequest([]byte("request"), "topic", 1)
+	require.Nil(t, response)
+	require.Equal(t, &data.RequestError{Code: data.RequestErrorCodeRejected, Message: data.ErrRateLimitExceeded.Error()}, err)
+	require.Equal(t, int32(1), numProcessed.Load())
+}
@@ -0,0 +1,89 @@
+package server
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/subrahamanyam341/andes-communication/websocket"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+)
+
+// connectionWithStatistics wraps a client connection and counts the messages and the bytes going through it
+type connectionWithStatistics struct {
+	websocket.WSConClient
+	remoteAddress    string
+	connectedAt      time.Time
+	bytesSent        atomic.Uint64
+	bytesReceived    atomic.Uint64
+	messagesSent     atomic.Uint64
+	messagesReceived atomic.Uint64
+}
+
+func newConnectionWithStatistics(conn websocket.WSConClient, remoteAddress string) *connectionWithStatistics {
+	return &connectionWithStatistics{
+		WSConClient:   conn,
+		remoteAddress: remoteAddress,
+		connectedAt:   time.Now(),
+	}
+}
+
+// WriteMessage will write the message on the wrapped connection and will count it if the write succeeded
+func (cws *connectionWithStatistics) WriteMessage(messageType int, payload []byte) error {
+	err := cws.WSConClient.WriteMessage(messageType, payload)
+	if err != nil {
+		return err
+	}
+
+	cws.messagesSent.Add(1)
+	cws.bytesSent.Add(uint64(len(payload)))
+
+	return nil
+}
+
+// IsUnresponsive returns true if the wrapped connection was dropped because the client stopped answering the pings
+func (cws *connectionWithStatistics) IsUnresponsive() bool {
+	provider, ok := cws.WSConClient.(unresponsivenessProvider)
+
+	return ok && provider.IsUnresponsive()
+}
+
+// CloseWithReason will close the wrapped connection, the peer reading the provided close code and reason if the wrapped
+// connection supports them
+func (cws *connectionWithStatistics) CloseWithReason(code int, reason string) error {
+	closer, ok := cws.WSConClient.(closerWithReason)
+	if !ok {
+		return cws.WSConClient.Close()
+	}
+
+	return closer.CloseWithReason(code, reason)
+}
+
+// ReadMessage will read a message from the wrapped connection and will count it if the read succeeded
+func (cws *connectionWithStatistics) ReadMessage() (int, []byte, error) {
+	messageType, payload, err := cws.WSConClient.ReadMessage()
+	if err != nil {
+		return messageType, payload, err
+	}
+
+	cws.messagesReceived.Add(1)
+	cws.bytesReceived.Add(uint64(len(payload)))
+
+	return messageType, payload, nil
+}
+
+func (cws *connectionWithStatistics) getClientInfo() data.ClientInfo {
+	return data.ClientInfo{
+		ID:               cws.GetID(),
+		RemoteAddress:    cws.remoteAddress,
+		ConnectedAt:      cws.connectedAt,
+		BytesSent:        cws.bytesSent.Load(),
+		BytesReceived:    cws.bytesReceived.Load(),
+		MessagesSent:     cws.messagesSent.Load(),
+		MessagesReceived: cws.messagesReceived.Load(),
+	}
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (cws *connectionWithStatistics) IsInterfaceNil() bool {
+	return cws == nil
+}
@@ -0,0 +1,55 @@
+package server
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/testscommon"
+)
+
+func TestConnectionWithStatistics(t *testing.T) {
+	t.Parallel()
+
+	expectedErr := errors.New("expected error")
+	failWrite := false
+	failRead := false
+	conn := newConnectionWithStatistics(&testscommon.WebsocketConnectionStub{
+		GetIDCalled: func() string {
+			return "id"
+		},
+		WriteMessageCalled: func(_ int, _ []byte) error {
+			if failWrite {
+				return expectedErr
+			}
+			return nil
+		},
+		ReadMessageCalled: func() (int, []byte, error) {
+			if failRead {
+				return 0, nil, expectedErr
+			}
+			return 0, []byte("message"), nil
+		},
+	}, "remote")
+	require.False(t, conn.IsInterfaceNil())
+
+	require.Nil(t, conn.WriteMessage(0, []byte("abc")))
+	require.Nil(t, conn.WriteMessage(0, []byte("de")))
+	_, _, err := conn.ReadMessage()
+	require.Nil(t, err)
+
+	failWrite = true
+	failRead = true
+	require.Equal(t, expectedErr, conn.WriteMessage(0, []byte("not counted")))
+	_, _, err = conn.ReadMessage()
+	require.Equal(t, expectedErr, err)
+
+	info := conn.getClientInfo()
+	require.Equal(t, "id", info.ID)
+	require.Equal(t, "remote", info.RemoteAddress)
+	require.False(t, info.ConnectedAt.IsZero())
+	require.Equal(t, uint64(2), info.MessagesSent)
+	require.Equal(t, uint64(5), info.BytesSent)
+	require.Equal(t, uint64(1), info.MessagesReceived)
+	require.Equal(t, uint64(7), info.BytesReceived)
+}
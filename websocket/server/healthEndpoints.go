@@ -0,0 +1,53 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+)
+
+// GetHealthStatus returns whether the server is listening, the number of connected clients, the number of messages
+// waiting for their acknowledgement and when a payload of each topic was last sent successfully
+func (s *server) GetHealthStatus() data.HealthStatus {
+	transceiversAndCon := s.transceiversAndConn.getAll()
+
+	pendingAcks := 0
+	for _, tuple := range transceiversAndCon {
+		pendingAcks += tuple.transceiver.GetNumPendingAcks()
+	}
+
+	return data.HealthStatus{
+		Listening:          s.listening.Load(),
+		ConnectedClients:   len(transceiversAndCon),
+		PendingAcks:        pendingAcks,
+		LastSuccessfulSend: s.sendsTracker.getAll(),
+	}
+}
+
+// serveHealth reports the server as alive while it is listening
+func (s *server) serveHealth(writer http.ResponseWriter, _ *http.Request) {
+	status := s.GetHealthStatus()
+	s.writeHealthStatus(writer, status, status.Listening)
+}
+
+// serveReady reports the server as ready only while at least one client is connected, so the traffic is routed to
+// other nodes while the link to the consumers is broken
+func (s *server) serveReady(writer http.ResponseWriter, _ *http.Request) {
+	status := s.GetHealthStatus()
+	s.writeHealthStatus(writer, status, status.Listening && status.ConnectedClients > 0)
+}
+
+func (s *server) writeHealthStatus(writer http.ResponseWriter, status data.HealthStatus, healthy bool) {
+	writer.Header().Set("Content-Type", "application/json")
+	if healthy {
+		writer.WriteHeader(http.StatusOK)
+	} else {
+		writer.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	err := json.NewEncoder(writer).Encode(status)
+	if err != nil {
+		s.log.Debug("s.writeHealthStatus() cannot write the status", "error", err)
+	}
+}
@@ -0,0 +1,77 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/testscommon"
+	"github.com/subrahamanyam341/andes-communication/testscommon/transceiver"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+)
+
+func serveAndDecode(t *testing.T, handler http.HandlerFunc, route string) (int, data.HealthStatus) {
+	recorder := httptest.NewRecorder()
+	handler(recorder, httptest.NewRequest(http.MethodGet, route, nil))
+
+	status := data.HealthStatus{}
+	err := json.NewDecoder(recorder.Body).Decode(&status)
+	require.Nil(t, err)
+	require.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+
+	return recorder.Code, status
+}
+
+func TestServer_HealthEndpoints(t *testing.T) {
+	t.Parallel()
+
+	args := createArgs()
+	args.HealthEndpoints = true
+	wsServer, err := NewWebSocketServer(args)
+	require.Nil(t, err)
+	defer func() {
+		_ = wsServer.Close()
+	}()
+
+	// the url can not be listened on
+	require.Eventually(t, func() bool {
+		code, _ := serveAndDecode(t, wsServer.serveHealth, data.HealthRoute)
+		return code == http.StatusServiceUnavailable
+	}, 5*time.Second, 10*time.Millisecond)
+
+	wsServer.listening.Store(true)
+	code, status := serveAndDecode(t, wsServer.serveHealth, data.HealthRoute)
+	require.Equal(t, http.StatusOK, code)
+	require.True(t, status.Listening)
+
+	code, status = serveAndDecode(t, wsServer.serveReady, data.ReadyRoute)
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.Zero(t, status.ConnectedClients)
+
+	for _, id := range []string{"id1", "id2"} {
+		connID := id
+		conn := &testscommon.WebsocketConnectionStub{
+			GetIDCalled: func() string {
+				return connID
+			},
+		}
+		wsServer.transceiversAndConn.addTransceiverAndConn(&transceiver.WebSocketTransceiverStub{
+			GetNumPendingAcksCalled: func() int {
+				return 2
+			},
+		}, conn, nil, nil)
+	}
+	err = wsServer.SendTo("id1", []byte("payload"), "topic")
+	require.Nil(t, err)
+
+	code, status = serveAndDecode(t, wsServer.serveReady, data.ReadyRoute)
+	require.Equal(t, http.StatusOK, code)
+	require.True(t, status.Listening)
+	require.Equal(t, 2, status.ConnectedClients)
+	require.Equal(t, 4, status.PendingAcks)
+	require.Len(t, status.LastSuccessfulSend, 1)
+	require.False(t, status.LastSuccessfulSend["topic"].IsZero())
+}
@@ -1,19 +1,63 @@
 package server
 
 import (
+	"context"
+	"net"
+
 	"github.com/subrahamanyam341/andes-communication/websocket"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
 )
 
 type transceiversAndConnHandler interface {
-	addTransceiverAndConn(transceiver Transceiver, conn websocket.WSConClient)
+	addTransceiverAndConn(transceiver Transceiver, conn websocket.WSConClient, queue *clientQueue, rateLimiter *clientRateLimiter)
 	remove(id string)
+	get(id string) (tupleTransceiverAndConn, bool)
 	getAll() map[string]tupleTransceiverAndConn
+	getAllForTopic(topic string) map[string]tupleTransceiverAndConn
+	Subscribe(connectionID string, topics []string)
+	Unsubscribe(connectionID string, topics []string)
+	IsInterfaceNil() bool
+}
+
+type clientInfoProvider interface {
+	getClientInfo() data.ClientInfo
+}
+
+type completableAckFuture interface {
+	websocket.AckFuture
+	Complete(err error)
+}
+
+type unresponsivenessProvider interface {
+	IsUnresponsive() bool
+}
+
+type listenerServer interface {
+	Serve(listener net.Listener) error
+	ServeTLS(listener net.Listener, certFile string, keyFile string) error
+}
+
+type closerWithReason interface {
+	CloseWithReason(code int, reason string) error
 }
 
 // Transceiver defines what a WebSocket transceiver should be able to do
 type Transceiver interface {
 	Send(payload []byte, topic string, connection websocket.WSConClient) error
+	SendAsync(payload []byte, topic string, connection websocket.WSConClient) (websocket.AckFuture, error)
+	SendMessage(message *data.OutgoingMessage, connection websocket.WSConClient) error
+	SendMessageAsync(message *data.OutgoingMessage, connection websocket.WSConClient) (websocket.AckFuture, error)
+	Request(ctx context.Context, payload []byte, topic string, connection websocket.WSConClient) ([]byte, error)
 	SetPayloadHandler(handler websocket.PayloadHandler) error
+	SetRequestHandler(handler websocket.RequestHandler) error
 	Listen(connection websocket.WSConClient) (closed bool)
+	GetNumPendingAcks() int
+	Close() error
+}
+
+// QueueSender defines what a component that sends the messages through a persistent queue should be able to do
+type QueueSender interface {
+	Send(payload []byte, topic string) error
 	Close() error
+	IsInterfaceNil() bool
 }
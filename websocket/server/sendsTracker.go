@@ -0,0 +1,45 @@
+package server
+
+import (
+	"sync"
+	"time"
+
+	webSocket "github.com/subrahamanyam341/andes-communication/websocket"
+)
+
+// sendsTracker remembers, for every topic, when a payload was last sent (and acknowledged, if enabled) to a client
+type sendsTracker struct {
+	mut                sync.RWMutex
+	lastSuccessfulSend map[string]time.Time
+}
+
+func newSendsTracker() *sendsTracker {
+	return &sendsTracker{
+		lastSuccessfulSend: make(map[string]time.Time),
+	}
+}
+
+func (st *sendsTracker) sendSucceeded(topic string) {
+	st.mut.Lock()
+	st.lastSuccessfulSend[topic] = time.Now()
+	st.mut.Unlock()
+}
+
+// sendSucceededOnAck waits for the outcome of an asynchronous send and records it, if it succeeded
+func (st *sendsTracker) sendSucceededOnAck(future webSocket.AckFuture, topic string) {
+	if future.Wait() == nil {
+		st.sendSucceeded(topic)
+	}
+}
+
+func (st *sendsTracker) getAll() map[string]time.Time {
+	st.mut.RLock()
+	defer st.mut.RUnlock()
+
+	lastSuccessfulSend := make(map[string]time.Time, len(st.lastSuccessfulSend))
+	for topic, timestamp := range st.lastSuccessfulSend {
+		lastSuccessfulSend[topic] = timestamp
+	}
+
+	return lastSuccessfulSend
+}
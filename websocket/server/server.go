@@ -2,16 +2,29 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
 	"net/http"
+	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/gorilla/websocket"
 	webSocket "github.com/subrahamanyam341/andes-communication/websocket"
+	"github.com/subrahamanyam341/andes-communication/websocket/authentication"
+	"github.com/subrahamanyam341/andes-communication/websocket/codec"
+	"github.com/subrahamanyam341/andes-communication/websocket/compression"
 	"github.com/subrahamanyam341/andes-communication/websocket/connection"
 	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	"github.com/subrahamanyam341/andes-communication/websocket/memory"
+	"github.com/subrahamanyam341/andes-communication/websocket/metrics"
+	"github.com/subrahamanyam341/andes-communication/websocket/queue"
 	"github.com/subrahamanyam341/andes-communication/websocket/transceiver"
+	"github.com/subrahamanyam341/andes-communication/websocket/versioning"
 	"github.com/subrahamanyam341/andes-core-16/core"
 	"github.com/subrahamanyam341/andes-core-16/core/check"
 )
@@ -20,6 +33,7 @@ import (
 type ArgsWebSocketServer struct {
 	RetryDurationInSeconds     int
 	AckTimeoutInSeconds        int
+	AckWindowSize              int
 	BlockingAckOnError         bool
 	WithAcknowledge            bool
 	DropMessagesIfNoConnection bool
@@ -27,6 +41,35 @@ type ArgsWebSocketServer struct {
 	PayloadConverter           webSocket.PayloadConverter
 	Log                        core.Logger
 	PayloadVersion             uint32
+	MinPayloadVersion          uint32
+	TLSConfig                  *tls.Config
+	HandshakeAuthenticator     webSocket.HandshakeAuthenticator
+	OutboundQueue              webSocket.OutboundQueue
+	EnablePerMessageDeflate    bool
+	PayloadCompression         string
+	CompressionThreshold       int
+	PingIntervalInSeconds      int
+	PongTimeoutInSeconds       int
+	ClientQueueSize            int
+	ClientQueueOverflowPolicy  string
+	RoutePath                  string
+	ReadBufferSize             int
+	WriteBufferSize            int
+	MaxMessageSize             int64
+	WriteTimeoutInSeconds      int
+	HandshakeTimeoutInSeconds  int
+	Metrics                    webSocket.MetricsHandler
+	MetricsRoutePath           string
+	HealthEndpoints            bool
+	Transport                  string
+	MemoryTransport            data.MemoryTransportConfig
+	ChunkSizeInBytes           int
+	MaxReassemblySizeInBytes   int
+	ReassemblyTimeoutInSeconds int
+	MessageSigner              webSocket.MessageSigner
+	MessageVerifier            webSocket.MessageVerifier
+	RateLimit                  data.RateLimitConfig
+	MaxConcurrentRequests      int
 }
 
 type server struct {
@@ -34,13 +77,59 @@ type server struct {
 	withAcknowledge            bool
 	dropMessagesIfNoConnection bool
 	ackTimeoutInSec            int
+	ackWindowSize              int
 	payloadConverter           webSocket.PayloadConverter
 	retryDuration              time.Duration
 	log                        core.Logger
 	httpServer                 webSocket.HttpServerHandler
+	listenAddress              string
 	transceiversAndConn        transceiversAndConnHandler
 	payloadHandler             webSocket.PayloadHandler
+	requestHandler             webSocket.RequestHandler
+	mutHandlers                sync.RWMutex
 	payloadVersion             uint32
+	minPayloadVersion          uint32
+	tlsConfig                  *tls.Config
+	handshakeAuthenticator     webSocket.HandshakeAuthenticator
+	queueSender                QueueSender
+	enablePerMessageDeflate    bool
+	payloadCompression         string
+	compressionThreshold       int
+	pingInterval               time.Duration
+	pongTimeout                time.Duration
+	mutStateHandler            sync.RWMutex
+	stateHandler               func(clientID string, state data.ConnectionState)
+	clientQueueSize            int
+	clientQueueOverflowPolicy  string
+	readBufferSize             int
+	writeBufferSize            int
+	maxMessageSize             int64
+	writeTimeout               time.Duration
+	handshakeTimeout           time.Duration
+	sessionsTracker            webSocket.SessionsTracker
+	metrics                    webSocket.MetricsHandler
+	metricsRoutePath           string
+	metricsEndpoint            http.Handler
+	healthEndpoints            bool
+	listening                  atomic.Bool
+	sendsTracker               *sendsTracker
+	chunkSize                  int
+	maxReassemblySize          int
+	reassemblyTimeout          time.Duration
+	messageSigner              webSocket.MessageSigner
+	messageVerifier            webSocket.MessageVerifier
+	rateLimit                  data.RateLimitConfig
+	maxConcurrentRequests      int
+}
+
+// maxTrackedSessions is the number of client sessions remembered for dropping the messages resent after a reconnection
+const maxTrackedSessions = 10000
+
+// negotiatedOptions holds what a client agreed on during the websocket handshake
+type negotiatedOptions struct {
+	compressionAccepted bool
+	codec               string
+	payloadVersion      uint32
 }
 
 // NewWebSocketServer will create a new instance of server
@@ -49,6 +138,11 @@ func NewWebSocketServer(args ArgsWebSocketServer) (*server, error) {
 		return nil, err
 	}
 
+	handshakeAuthenticator := args.HandshakeAuthenticator
+	if check.IfNil(handshakeAuthenticator) {
+		handshakeAuthenticator = authentication.NewDisabledAuthenticator()
+	}
+
 	wsServer := &server{
 		transceiversAndConn:        newTransceiversAndConnHolder(),
 		blockingAckOnError:         args.BlockingAckOnError,
@@ -56,17 +150,134 @@ func NewWebSocketServer(args ArgsWebSocketServer) (*server, error) {
 		retryDuration:              time.Duration(args.RetryDurationInSeconds) * time.Second,
 		payloadConverter:           args.PayloadConverter,
 		payloadHandler:             webSocket.NewNilPayloadHandler(),
+		requestHandler:             webSocket.NewNilRequestHandler(),
 		withAcknowledge:            args.WithAcknowledge,
 		dropMessagesIfNoConnection: args.DropMessagesIfNoConnection,
 		ackTimeoutInSec:            args.AckTimeoutInSeconds,
+		ackWindowSize:              args.AckWindowSize,
 		payloadVersion:             args.PayloadVersion,
+		minPayloadVersion:          args.MinPayloadVersion,
+		tlsConfig:                  args.TLSConfig,
+		handshakeAuthenticator:     handshakeAuthenticator,
+		enablePerMessageDeflate:    args.EnablePerMessageDeflate,
+		payloadCompression:         args.PayloadCompression,
+		compressionThreshold:       args.CompressionThreshold,
+		pingInterval:               time.Duration(args.PingIntervalInSeconds) * time.Second,
+		pongTimeout:                time.Duration(args.PongTimeoutInSeconds) * time.Second,
+		clientQueueSize:            args.ClientQueueSize,
+		clientQueueOverflowPolicy:  args.ClientQueueOverflowPolicy,
+		readBufferSize:             args.ReadBufferSize,
+		writeBufferSize:            args.WriteBufferSize,
+		maxMessageSize:             args.MaxMessageSize,
+		writeTimeout:               time.Duration(args.WriteTimeoutInSeconds) * time.Second,
+		handshakeTimeout:           time.Duration(args.HandshakeTimeoutInSeconds) * time.Second,
+		sessionsTracker:            transceiver.NewSessionsTracker(maxTrackedSessions),
+		metricsRoutePath:           args.MetricsRoutePath,
+		healthEndpoints:            args.HealthEndpoints,
+		sendsTracker:               newSendsTracker(),
+		chunkSize:                  args.ChunkSizeInBytes,
+		maxReassemblySize:          args.MaxReassemblySizeInBytes,
+		reassemblyTimeout:          time.Duration(args.ReassemblyTimeoutInSeconds) * time.Second,
+		messageSigner:              args.MessageSigner,
+		messageVerifier:            args.MessageVerifier,
+		rateLimit:                  args.RateLimit,
+		maxConcurrentRequests:      args.MaxConcurrentRequests,
+	}
+	if wsServer.readBufferSize == 0 {
+		wsServer.readBufferSize = data.DefaultServerBufferSize
+	}
+	if wsServer.writeBufferSize == 0 {
+		wsServer.writeBufferSize = data.DefaultServerBufferSize
+	}
+
+	var err error
+	wsServer.metrics, wsServer.metricsEndpoint, err = createMetrics(args.Metrics, args.MetricsRoutePath)
+	if err != nil {
+		return nil, err
 	}
 
-	wsServer.initializeServer(args.URL, data.WSRoute)
+	if !check.IfNil(args.OutboundQueue) {
+		wsServer.queueSender, err = queue.NewQueueSender(queue.ArgsQueueSender{
+			Queue:              args.OutboundQueue,
+			SendHandler:        wsServer.sendToAllClients,
+			Log:                args.Log,
+			RetryDurationInSec: args.RetryDurationInSeconds,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if memory.IsMemoryTransport(args.Transport) {
+		err = wsServer.initializeMemoryServer(args.URL, getRoutePath(args), args.MemoryTransport)
+		if err != nil {
+			return nil, err
+		}
+
+		return wsServer, nil
+	}
+
+	wsServer.initializeServer(args.URL, getRoutePath(args))
 
 	return wsServer, nil
 }
 
+// createMetrics returns the metrics handler of the server and, if a metrics route is set, the endpoint serving the
+// metrics in the Prometheus text format. Without a provided metrics handler, the metrics are kept in memory only if
+// they are served
+func createMetrics(metricsHandler webSocket.MetricsHandler, metricsRoutePath string) (webSocket.MetricsHandler, http.Handler, error) {
+	if metricsRoutePath == "" {
+		if check.IfNil(metricsHandler) {
+			return webSocket.NewNilMetricsHandler(), nil, nil
+		}
+		return metricsHandler, nil, nil
+	}
+
+	if check.IfNil(metricsHandler) {
+		metricsHandler = metrics.NewInMemoryMetrics()
+	}
+	snapshotProvider, ok := metricsHandler.(webSocket.MetricsSnapshotProvider)
+	if !ok {
+		return nil, nil, data.ErrMetricsSnapshotNotSupported
+	}
+	endpoint, err := metrics.NewPrometheusHandler(snapshotProvider)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return metricsHandler, endpoint, nil
+}
+
+func getRoutePath(args ArgsWebSocketServer) string {
+	if args.RoutePath == "" {
+		return data.WSRoute
+	}
+
+	return args.RoutePath
+}
+
+// checkRoutesConflicts returns an error if two of the routes served by the server have the same path
+func checkRoutesConflicts(args ArgsWebSocketServer) error {
+	extraRoutes := make([]string, 0, 3)
+	if args.MetricsRoutePath != "" {
+		extraRoutes = append(extraRoutes, args.MetricsRoutePath)
+	}
+	if args.HealthEndpoints {
+		extraRoutes = append(extraRoutes, data.HealthRoute, data.ReadyRoute)
+	}
+
+	routes := map[string]struct{}{getRoutePath(args): {}}
+	for _, route := range extraRoutes {
+		_, found := routes[route]
+		if found {
+			return fmt.Errorf("%w: %s", data.ErrRouteConflict, route)
+		}
+		routes[route] = struct{}{}
+	}
+
+	return nil
+}
+
 func checkArgs(args ArgsWebSocketServer) error {
 	if check.IfNil(args.Log) {
 		return core.ErrNilLogger
@@ -80,64 +291,343 @@ func checkArgs(args ArgsWebSocketServer) error {
 	if args.RetryDurationInSeconds == 0 {
 		return data.ErrZeroValueRetryDuration
 	}
+	if (args.PingIntervalInSeconds > 0) != (args.PongTimeoutInSeconds > 0) {
+		return data.ErrInvalidKeepAliveConfig
+	}
+	if args.ClientQueueSize < 0 {
+		return data.ErrInvalidClientQueueSize
+	}
+	err := checkOverflowPolicy(args.ClientQueueOverflowPolicy)
+	if err != nil {
+		return err
+	}
+	if args.RoutePath != "" && !strings.HasPrefix(args.RoutePath, "/") {
+		return data.ErrInvalidRoutePath
+	}
+	if args.MetricsRoutePath != "" && !strings.HasPrefix(args.MetricsRoutePath, "/") {
+		return data.ErrInvalidRoutePath
+	}
+	err = checkRoutesConflicts(args)
+	if err != nil {
+		return err
+	}
+	if args.ReadBufferSize < 0 || args.WriteBufferSize < 0 {
+		return data.ErrInvalidBufferSize
+	}
+	if args.MaxMessageSize < 0 {
+		return data.ErrInvalidMaxMessageSize
+	}
+	if args.WriteTimeoutInSeconds < 0 || args.HandshakeTimeoutInSeconds < 0 {
+		return data.ErrInvalidTimeout
+	}
+	if args.CompressionThreshold < 0 {
+		return data.ErrNegativeCompressionThreshold
+	}
+	if args.ChunkSizeInBytes < 0 || args.MaxReassemblySizeInBytes < 0 || args.ReassemblyTimeoutInSeconds < 0 {
+		return data.ErrInvalidChunkingConfig
+	}
+	if args.MaxConcurrentRequests < 0 {
+		return data.ErrInvalidMaxConcurrentRequests
+	}
+	err = checkRateLimitConfig(args.RateLimit)
+	if err != nil {
+		return err
+	}
+	err = versioning.CheckRange(args.MinPayloadVersion, args.PayloadVersion)
+	if err != nil {
+		return err
+	}
+	err = checkTransport(args)
+	if err != nil {
+		return err
+	}
+	return compression.CheckAlgorithm(args.PayloadCompression)
+}
+
+// checkTransport returns an error if the transport is not known or if an option needing a real network is set for the
+// memory transport
+func checkTransport(args ArgsWebSocketServer) error {
+	err := memory.CheckTransport(args.Transport, args.MemoryTransport)
+	if err != nil {
+		return err
+	}
+	if !memory.IsMemoryTransport(args.Transport) {
+		return nil
+	}
+
+	if args.TLSConfig != nil {
+		return fmt.Errorf("%w: TLS", data.ErrNotSupportedByMemoryTransport)
+	}
+	if args.MetricsRoutePath != "" || args.HealthEndpoints {
+		return fmt.Errorf("%w: http endpoints", data.ErrNotSupportedByMemoryTransport)
+	}
+
 	return nil
 }
 
 func (s *server) connectionHandler(connection webSocket.WSConClient) {
+	s.connectionHandlerWithOptions(connection, negotiatedOptions{
+		codec:          data.ProtobufCodec,
+		payloadVersion: s.payloadVersion,
+	})
+}
+
+func (s *server) connectionHandlerWithOptions(connection webSocket.WSConClient, options negotiatedOptions) {
+	codecPayloadConverter, err := codec.NewPayloadConverter(options.codec, s.payloadConverter)
+	if err != nil {
+		s.log.Warn("s.connectionHandler cannot create codec payload converter", "codec", options.codec, "error", err)
+		return
+	}
+
+	// every connection negotiates the codec and the payload compression on its own, so each one needs its own converter
+	payloadConverter, err := compression.NewCompressingPayloadConverter(compression.ArgsCompressingPayloadConverter{
+		PayloadConverter: codecPayloadConverter,
+		Algorithm:        s.payloadCompression,
+		ThresholdInBytes: s.compressionThreshold,
+	})
+	if err != nil {
+		s.log.Warn("s.connectionHandler cannot create payload converter", "error", err)
+		return
+	}
+	payloadConverter.SetCompressionEnabled(options.compressionAccepted)
+
 	webSocketTransceiver, err := transceiver.NewTransceiver(transceiver.ArgsTransceiver{
-		PayloadConverter:   s.payloadConverter,
-		Log:                s.log,
-		RetryDurationInSec: int(s.retryDuration.Seconds()),
-		AckTimeoutInSec:    s.ackTimeoutInSec,
-		BlockingAckOnError: s.blockingAckOnError,
-		WithAcknowledge:    s.withAcknowledge,
-		PayloadVersion:     s.payloadVersion,
+		PayloadConverter:      payloadConverter,
+		Log:                   s.log,
+		RetryDurationInSec:    int(s.retryDuration.Seconds()),
+		AckTimeoutInSec:       s.ackTimeoutInSec,
+		BlockingAckOnError:    s.blockingAckOnError,
+		WithAcknowledge:       s.withAcknowledge,
+		PayloadVersion:        s.payloadVersion,
+		MinPayloadVersion:     s.minPayloadVersion,
+		AckWindowSize:         s.ackWindowSize,
+		SubscriptionsHandler:  s.transceiversAndConn,
+		TextFrames:            codec.UsesTextFrames(options.codec),
+		SessionsTracker:       s.sessionsTracker,
+		Metrics:               s.metrics,
+		ChunkSizeInBytes:      s.chunkSize,
+		MaxReassemblySize:     s.maxReassemblySize,
+		ReassemblyTimeout:     s.reassemblyTimeout,
+		MessageSigner:         s.messageSigner,
+		MessageVerifier:       s.messageVerifier,
+		MaxConcurrentRequests: s.maxConcurrentRequests,
 	})
 	if err != nil {
 		s.log.Warn("s.connectionHandler cannot create transceiver", "error", err)
 		return
 	}
-	err = webSocketTransceiver.SetPayloadHandler(s.payloadHandler)
+	webSocketTransceiver.SetPayloadVersion(options.payloadVersion)
+	rateLimiter := s.createClientRateLimiter(connection)
+	err = webSocketTransceiver.SetPayloadHandler(s.wrapPayloadHandler(rateLimiter))
 	if err != nil {
 		s.log.Warn("s.SetPayloadHandler cannot set payload handler", "error", err)
 	}
+	err = webSocketTransceiver.SetRequestHandler(s.wrapRequestHandler(rateLimiter))
+	if err != nil {
+		s.log.Warn("s.SetRequestHandler cannot set request handler", "error", err)
+	}
+
+	queue := s.createClientQueue(webSocketTransceiver, connection)
 
 	go func() {
-		s.transceiversAndConn.addTransceiverAndConn(webSocketTransceiver, connection)
+		s.transceiversAndConn.addTransceiverAndConn(webSocketTransceiver, connection, queue, rateLimiter)
+		s.metrics.ClientConnected()
+		s.notifyClientStateChange(connection.GetID(), data.ConnectionOpen)
 		// this method is blocking
 		_ = webSocketTransceiver.Listen(connection)
 		s.log.Info("connection closed", "client id", connection.GetID())
 		// if method listen will end, the client was disconnected, and we should remove the listener from the list
 		s.transceiversAndConn.remove(connection.GetID())
+		s.metrics.ClientDisconnected()
+		if queue != nil {
+			queue.close()
+		}
+		if rateLimiter != nil {
+			rateLimiter.close()
+		}
+		// the connection might be still open if the client stopped answering the pings
+		_ = connection.Close()
+		s.notifyClientStateChange(connection.GetID(), getClosedState(connection))
 	}()
 }
 
+func (s *server) createClientQueue(webSocketTransceiver Transceiver, connection webSocket.WSConClient) *clientQueue {
+	if s.clientQueueSize == 0 {
+		return nil
+	}
+
+	return newClientQueue(argsClientQueue{
+		clientID:       connection.GetID(),
+		maxSize:        s.clientQueueSize,
+		overflowPolicy: s.clientQueueOverflowPolicy,
+		sendHandler: func(message *data.OutgoingMessage) error {
+			err := webSocketTransceiver.SendMessage(message, connection)
+			if err == nil {
+				s.sendsTracker.sendSucceeded(message.Topic)
+			}
+			return err
+		},
+		overflowHandler: s.disconnectSlowClient,
+		log:             s.log,
+	})
+}
+
+func (s *server) disconnectSlowClient(clientID string) {
+	err := s.Disconnect(clientID)
+	if err != nil {
+		s.log.Debug("s.disconnectSlowClient() cannot disconnect client", "client id", clientID, "error", err)
+	}
+}
+
+func (s *server) createClientRateLimiter(connection webSocket.WSConClient) *clientRateLimiter {
+	if !isRateLimitEnabled(s.rateLimit) {
+		return nil
+	}
+
+	return newClientRateLimiter(argsClientRateLimiter{
+		clientID:          connection.GetID(),
+		config:            s.rateLimit,
+		disconnectHandler: s.disconnectRateLimitedClient,
+		log:               s.log,
+	})
+}
+
+// wrapPayloadHandler returns the payload handler of the server, wrapped so that it processes only the payloads fitting
+// the rate limits of the client, if any
+func (s *server) wrapPayloadHandler(rateLimiter *clientRateLimiter) webSocket.PayloadHandler {
+	s.mutHandlers.RLock()
+	payloadHandler := s.payloadHandler
+	s.mutHandlers.RUnlock()
+
+	if rateLimiter == nil {
+		return payloadHandler
+	}
+
+	return newRateLimitedPayloadHandler(payloadHandler, rateLimiter)
+}
+
+// wrapRequestHandler returns the request handler of the server, wrapped so that it processes only the requests fitting
+// the rate limits of the client, if any
+func (s *server) wrapRequestHandler(rateLimiter *clientRateLimiter) webSocket.RequestHandler {
+	s.mutHandlers.RLock()
+	requestHandler := s.requestHandler
+	s.mutHandlers.RUnlock()
+
+	if rateLimiter == nil {
+		return requestHandler
+	}
+
+	return newRateLimitedRequestHandler(requestHandler, rateLimiter)
+}
+
+// disconnectRateLimitedClient closes the connection of the client with the policy violation close code, so the client
+// can tell why it was disconnected
+func (s *server) disconnectRateLimitedClient(clientID string) {
+	tuple, found := s.transceiversAndConn.get(clientID)
+	if !found {
+		return
+	}
+
+	s.log.Info("disconnecting client exceeding the rate limits", "client id", clientID)
+	s.transceiversAndConn.remove(clientID)
+
+	err := closeConnectionWithReason(tuple.conn, websocket.ClosePolicyViolation, data.ErrRateLimitExceeded.Error())
+	if err != nil {
+		s.log.Debug("s.disconnectRateLimitedClient() cannot close connection", "client id", clientID, "error", err)
+	}
+}
+
+// closeConnectionWithReason sends the close message with the provided code and reason to the peer before closing the
+// connection. A connection that can not send them is just closed
+func closeConnectionWithReason(connection webSocket.WSConClient, code int, reason string) error {
+	closer, ok := connection.(closerWithReason)
+	if !ok {
+		return connection.Close()
+	}
+
+	return closer.CloseWithReason(code, reason)
+}
+
+// OnClientStateChange will set the handler called every time a client connects or disconnects
+func (s *server) OnClientStateChange(handler func(clientID string, state data.ConnectionState)) {
+	s.mutStateHandler.Lock()
+	s.stateHandler = handler
+	s.mutStateHandler.Unlock()
+}
+
+func (s *server) notifyClientStateChange(clientID string, state data.ConnectionState) {
+	s.log.Debug("client connection state changed", "client id", clientID, "state", state.String())
+
+	s.mutStateHandler.RLock()
+	handler := s.stateHandler
+	s.mutStateHandler.RUnlock()
+
+	if handler != nil {
+		handler(clientID, state)
+	}
+}
+
+func getClosedState(connection webSocket.WSConClient) data.ConnectionState {
+	provider, ok := connection.(unresponsivenessProvider)
+	if ok && provider.IsUnresponsive() {
+		return data.ConnectionUnresponsive
+	}
+
+	return data.ConnectionClosed
+}
+
 func (s *server) initializeServer(wsURL string, wsPath string) {
 	router := mux.NewRouter()
 	httpServer := &http.Server{
-		Addr:    wsURL,
-		Handler: router,
+		Addr:      wsURL,
+		Handler:   router,
+		TLSConfig: s.tlsConfig,
 	}
 
 	upgrader := websocket.Upgrader{
-		ReadBufferSize:  1024,
-		WriteBufferSize: 1024,
+		HandshakeTimeout:  s.handshakeTimeout,
+		ReadBufferSize:    s.readBufferSize,
+		WriteBufferSize:   s.writeBufferSize,
+		EnableCompression: s.enablePerMessageDeflate,
+		Subprotocols:      codec.SupportedSubprotocols(),
+		CheckOrigin:       func(r *http.Request) bool { return true },
 	}
 
-	s.log.Info("wsServer.initializeServer(): initializing WebSocket server", "url", wsURL, "path", wsPath)
+	s.log.Info("wsServer.initializeServer(): initializing WebSocket server", "url", wsURL, "path", wsPath, "TLS", s.tlsConfig != nil)
 
 	addClientFunc := func(writer http.ResponseWriter, r *http.Request) {
 		s.log.Info("new connection", "route", wsPath, "remote address", r.RemoteAddr)
 
-		upgrader.CheckOrigin = func(r *http.Request) bool { return true }
+		errAuthenticate := s.handshakeAuthenticator.Authenticate(r)
+		if errAuthenticate != nil {
+			s.log.Warn("rejected websocket handshake", "remote address", r.RemoteAddr, "error", errAuthenticate)
+			http.Error(writer, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
 
-		ws, errUpgrade := upgrader.Upgrade(writer, r, nil)
+		payloadVersion, errNegotiate := versioning.Negotiate(s.minPayloadVersion, s.payloadVersion, r.Header)
+		ws, errUpgrade := upgrader.Upgrade(writer, r, s.createHandshakeResponseHeader())
 		if errUpgrade != nil {
 			s.log.Warn("could not update websocket connection", "remote address", r.RemoteAddr, "error", errUpgrade)
 			return
 		}
-		client := connection.NewWSConnClientWithConn(ws)
-		s.connectionHandler(client)
+		wsConn := connection.NewWSConnClientWithConnAndArgs(ws, connection.ArgsWSConnClient{
+			PingInterval:   s.pingInterval,
+			PongTimeout:    s.pongTimeout,
+			MaxMessageSize: s.maxMessageSize,
+			WriteTimeout:   s.writeTimeout,
+		})
+		if errNegotiate != nil {
+			s.log.Warn("closing websocket connection", "remote address", r.RemoteAddr, "error", errNegotiate)
+			_ = closeConnectionWithReason(wsConn, websocket.ClosePolicyViolation, errNegotiate.Error())
+			return
+		}
+		client := newConnectionWithStatistics(wsConn, r.RemoteAddr)
+		s.connectionHandlerWithOptions(client, negotiatedOptions{
+			compressionAccepted: compression.IsAlgorithmSupportedByPeer(r.Header, s.payloadCompression),
+			codec:               codec.SubprotocolToCodec(ws.Subprotocol()),
+			payloadVersion:      payloadVersion,
+		})
 	}
 
 	routeSendData := router.HandleFunc(wsPath, addClientFunc)
@@ -146,33 +636,322 @@ func (s *server) initializeServer(wsURL string, wsPath string) {
 			"route", routeSendData.GetName(),
 			"error", routeSendData.GetError())
 	}
+	if s.metricsEndpoint != nil {
+		router.Handle(s.metricsRoutePath, s.metricsEndpoint).Methods(http.MethodGet)
+	}
+	if s.healthEndpoints {
+		router.HandleFunc(data.HealthRoute, s.serveHealth).Methods(http.MethodGet)
+		router.HandleFunc(data.ReadyRoute, s.serveReady).Methods(http.MethodGet)
+	}
 
 	s.httpServer = httpServer
+	s.listenAddress = wsURL
+
+	s.start()
+}
+
+// initializeMemoryServer replaces the http server with an in-memory listener, accepting the connections dialed by the
+// clients of the same process that use the memory transport
+func (s *server) initializeMemoryServer(wsURL string, wsPath string, config data.MemoryTransportConfig) error {
+	listener, err := memory.NewListener(memory.ArgsListener{
+		URL:          wsURL,
+		Path:         wsPath,
+		Subprotocols: codec.SupportedSubprotocols(),
+		Handler:      s.acceptMemoryConnection,
+		Config:       config,
+	})
+	if err != nil {
+		return err
+	}
 
+	s.log.Info("wsServer.initializeMemoryServer(): initializing in-memory WebSocket server", "url", wsURL, "path", wsPath)
+
+	s.httpServer = listener
 	s.start()
+
+	return nil
 }
 
-// Send will send the provided payload from args
+// acceptMemoryConnection runs the same handshake as the websocket route, for a connection of the memory transport
+func (s *server) acceptMemoryConnection(r *http.Request, upgrade memory.UpgradeFunc) error {
+	s.log.Info("new in-memory connection", "remote address", r.RemoteAddr)
+
+	err := s.handshakeAuthenticator.Authenticate(r)
+	if err != nil {
+		s.log.Warn("rejected in-memory handshake", "remote address", r.RemoteAddr, "error", err)
+		return err
+	}
+
+	payloadVersion, errNegotiate := versioning.Negotiate(s.minPayloadVersion, s.payloadVersion, r.Header)
+	memoryConn := upgrade(s.createHandshakeResponseHeader())
+	if errNegotiate != nil {
+		s.log.Warn("closing in-memory connection", "remote address", r.RemoteAddr, "error", errNegotiate)
+		_ = memoryConn.CloseWithReason(websocket.ClosePolicyViolation, errNegotiate.Error())
+		return nil
+	}
+
+	s.connectionHandlerWithOptions(newConnectionWithStatistics(memoryConn, r.RemoteAddr), negotiatedOptions{
+		compressionAccepted: compression.IsAlgorithmSupportedByPeer(r.Header, s.payloadCompression),
+		codec:               codec.SubprotocolToCodec(memoryConn.Subprotocol()),
+		payloadVersion:      payloadVersion,
+	})
+
+	return nil
+}
+
+// createHandshakeResponseHeader returns the header advertising the payload compression algorithms and the payload
+// versions supported by this server
+func (s *server) createHandshakeResponseHeader() http.Header {
+	header := compression.CreateHandshakeHeader()
+	for key, values := range versioning.CreateHandshakeHeader(s.minPayloadVersion, s.payloadVersion) {
+		header[key] = values
+	}
+
+	return header
+}
+
+// Send will send the provided payload from args to the clients interested in the topic. If an outbound queue is set, the
+// payload is persisted and sent asynchronously. If the per client queues are enabled, the payload is only added in the
+// queues of the clients, without waiting for the acknowledgements
 func (s *server) Send(payload []byte, topic string) error {
-	transceiversAndCon := s.transceiversAndConn.getAll()
-	noClients := len(transceiversAndCon) == 0
+	if s.queueSender != nil {
+		return s.queueSender.Send(payload, topic)
+	}
+
+	noClients := len(s.transceiversAndConn.getAll()) == 0
+	if noClients && !s.dropMessagesIfNoConnection {
+		return data.ErrNoClientsConnected
+	}
+
+	message := &data.OutgoingMessage{Payload: payload, Topic: topic}
+	transceiversAndCon := s.transceiversAndConn.getAllForTopic(topic)
+	for _, tuple := range transceiversAndCon {
+		_ = s.enqueueOrSend(tuple, message)
+	}
+
+	return nil
+}
+
+// enqueueOrSend will add the message in the queue of the client, if the per client queues are enabled, otherwise it
+// will send the message right away and wait for its acknowledgement
+func (s *server) enqueueOrSend(tuple tupleTransceiverAndConn, message *data.OutgoingMessage) webSocket.AckFuture {
+	if tuple.queue != nil {
+		return tuple.queue.push(message)
+	}
+
+	err := tuple.transceiver.SendMessage(message, tuple.conn)
+	if err != nil {
+		s.log.Debug("s.enqueueOrSend() cannot send message", "id", tuple.conn.GetID(), "error", err.Error())
+	} else {
+		s.sendsTracker.sendSucceeded(message.Topic)
+	}
+
+	return transceiver.NewCompletedAckFuture(err)
+}
+
+// SendAsync will send the provided payload to the clients interested in the topic without waiting for the
+// acknowledgements. The returned future completes once all the clients answered and holds nil if at least one of them
+// acknowledged the payload
+func (s *server) SendAsync(payload []byte, topic string) (webSocket.AckFuture, error) {
+	if s.queueSender != nil {
+		return transceiver.NewCompletedAckFuture(nil), s.queueSender.Send(payload, topic)
+	}
+
+	noClients := len(s.transceiversAndConn.getAll()) == 0
+	if noClients && !s.dropMessagesIfNoConnection {
+		return nil, data.ErrNoClientsConnected
+	}
+
+	message := &data.OutgoingMessage{Payload: payload, Topic: topic}
+	futures := s.sendAsyncToClients(s.transceiversAndConn.getAllForTopic(topic), message)
+
+	return transceiver.NewAckFutureGroup(futures), nil
+}
+
+// SendToQuorum will send the provided payload to the clients interested in the topic and will wait until the provided
+// number of them acknowledged it, 0 meaning all of them. The payload is not sent at all if fewer clients than the
+// quorum are interested in the topic
+func (s *server) SendToQuorum(payload []byte, topic string, quorum int) error {
+	return s.SendMessageToQuorum(&data.OutgoingMessage{Payload: payload, Topic: topic}, quorum)
+}
+
+// SendMessageToQuorum will send the provided message like SendToQuorum does. A message carrying the signature of its
+// origin is sent with it. If an outbound queue is set, only the payload and the topic of the message are persisted
+func (s *server) SendMessageToQuorum(message *data.OutgoingMessage, quorum int) error {
+	if quorum < 0 {
+		return data.ErrInvalidAckQuorum
+	}
+	if s.queueSender != nil {
+		return s.queueSender.Send(message.Payload, message.Topic)
+	}
+
+	noClients := len(s.transceiversAndConn.getAll()) == 0
 	if noClients && !s.dropMessagesIfNoConnection {
 		return data.ErrNoClientsConnected
 	}
 
+	transceiversAndCon := s.transceiversAndConn.getAllForTopic(message.Topic)
+	if quorum == 0 {
+		quorum = len(transceiversAndCon)
+	}
+	if quorum > len(transceiversAndCon) {
+		return fmt.Errorf("%w: %d acknowledgements required, %d clients interested in the topic", data.ErrAckQuorumNotReached, quorum, len(transceiversAndCon))
+	}
+
+	futures := s.sendAsyncToClients(transceiversAndCon, message)
+
+	return transceiver.NewAckFutureQuorum(futures, quorum).Wait()
+}
+
+func (s *server) sendAsyncToClients(transceiversAndCon map[string]tupleTransceiverAndConn, message *data.OutgoingMessage) []webSocket.AckFuture {
+	futures := make([]webSocket.AckFuture, 0, len(transceiversAndCon))
 	for _, tuple := range transceiversAndCon {
-		err := tuple.transceiver.Send(payload, topic, tuple.conn)
+		if tuple.queue != nil {
+			futures = append(futures, tuple.queue.push(message))
+			continue
+		}
+
+		future, err := tuple.transceiver.SendMessageAsync(message, tuple.conn)
 		if err != nil {
-			s.log.Debug("s.Send() cannot send message", "id", tuple.conn.GetID(), "error", err.Error())
+			s.log.Debug("s.sendAsyncToClients() cannot send message", "id", tuple.conn.GetID(), "error", err.Error())
+			futures = append(futures, transceiver.NewCompletedAckFuture(err))
+			continue
 		}
+		futures = append(futures, future)
+		go s.sendsTracker.sendSucceededOnAck(future, message.Topic)
 	}
 
-	return nil
+	return futures
+}
+
+// SendTo will send the provided payload to the client with the provided ID, regardless of its subscriptions
+func (s *server) SendTo(clientID string, payload []byte, topic string) error {
+	tuple, found := s.transceiversAndConn.get(clientID)
+	if !found {
+		return data.ErrClientNotFound
+	}
+
+	return s.enqueueOrSend(tuple, &data.OutgoingMessage{Payload: payload, Topic: topic}).Wait()
+}
+
+// ConnectedClients returns the details of the connected clients, ordered by their connection time
+func (s *server) ConnectedClients() []data.ClientInfo {
+	transceiversAndCon := s.transceiversAndConn.getAll()
+
+	clients := make([]data.ClientInfo, 0, len(transceiversAndCon))
+	for id, tuple := range transceiversAndCon {
+		provider, ok := tuple.conn.(clientInfoProvider)
+		if !ok {
+			clients = append(clients, data.ClientInfo{ID: id})
+			continue
+		}
+
+		info := provider.getClientInfo()
+		if tuple.queue != nil {
+			info.QueuedMessages = tuple.queue.len()
+			info.DroppedMessages = tuple.queue.dropped()
+		}
+		if tuple.rateLimiter != nil {
+			info.RateLimitedMessages = tuple.rateLimiter.limited()
+		}
+		clients = append(clients, info)
+	}
+
+	sort.SliceStable(clients, func(i, j int) bool {
+		if clients[i].ConnectedAt.Equal(clients[j].ConnectedAt) {
+			return clients[i].ID < clients[j].ID
+		}
+		return clients[i].ConnectedAt.Before(clients[j].ConnectedAt)
+	})
+
+	return clients
+}
+
+// Disconnect will close the connection of the client with the provided ID
+func (s *server) Disconnect(clientID string) error {
+	tuple, found := s.transceiversAndConn.get(clientID)
+	if !found {
+		return data.ErrClientNotFound
+	}
+
+	s.log.Info("disconnecting client", "client id", clientID)
+	s.transceiversAndConn.remove(clientID)
+
+	return tuple.conn.Close()
+}
+
+// Request will send the provided payload to the clients interested in the topic and will return the first successful
+// response. The other requests are cancelled once a response is received
+func (s *server) Request(ctx context.Context, topic string, payload []byte) ([]byte, error) {
+	transceiversAndCon := s.transceiversAndConn.getAllForTopic(topic)
+	if len(transceiversAndCon) == 0 {
+		return nil, data.ErrNoClientsConnected
+	}
+
+	ctxRequests, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type requestResult struct {
+		response []byte
+		err      error
+	}
+	chResults := make(chan requestResult, len(transceiversAndCon))
+	for _, tuple := range transceiversAndCon {
+		go func(tuple tupleTransceiverAndConn) {
+			response, err := tuple.transceiver.Request(ctxRequests, payload, topic, tuple.conn)
+			chResults <- requestResult{response: response, err: err}
+		}(tuple)
+	}
+
+	var lastErr error
+	for i := 0; i < len(transceiversAndCon); i++ {
+		result := <-chResults
+		if result.err == nil {
+			return result.response, nil
+		}
+		lastErr = result.err
+	}
+
+	return nil, lastErr
+}
+
+// RequestTo will send the provided payload to the client with the provided ID and will wait for its response until the
+// context is done
+func (s *server) RequestTo(ctx context.Context, clientID string, topic string, payload []byte) ([]byte, error) {
+	tuple, found := s.transceiversAndConn.get(clientID)
+	if !found {
+		return nil, data.ErrClientNotFound
+	}
+
+	return tuple.transceiver.Request(ctx, payload, topic, tuple.conn)
+}
+
+// sendToAllClients will send the queued message to all the connected clients interested in its topic and will return
+// nil if at least one of them received it (and acknowledged it, if the acknowledgement is enabled). The message is
+// dropped if no client is interested in the topic
+func (s *server) sendToAllClients(queuedMessage *data.QueuedMessage) error {
+	if len(s.transceiversAndConn.getAll()) == 0 {
+		return data.ErrNoClientsConnected
+	}
+
+	transceiversAndCon := s.transceiversAndConn.getAllForTopic(queuedMessage.Topic)
+	if len(transceiversAndCon) == 0 {
+		return nil
+	}
+
+	message := queuedMessage.ToOutgoingMessage()
+	futures := make([]webSocket.AckFuture, 0, len(transceiversAndCon))
+	for _, tuple := range transceiversAndCon {
+		futures = append(futures, s.enqueueOrSend(tuple, message))
+	}
+
+	return transceiver.NewAckFutureGroup(futures).Wait()
 }
 
 func (s *server) start() {
 	go func() {
-		err := s.httpServer.ListenAndServe()
+		err := s.listenAndServe()
+		s.listening.Store(false)
 		shouldLogError := err != nil && !strings.Contains(err.Error(), data.ErrServerIsClosed.Error())
 		if shouldLogError {
 			s.log.Error("could not initialize webserver", "error", err)
@@ -183,9 +962,53 @@ func (s *server) start() {
 	}()
 }
 
-// SetPayloadHandler will set the provided payload handler
+// listenAndServe reports the server as listening only once its address is bound, so a server that can not bind its
+// address, already in use for example, is never reported as listening
+func (s *server) listenAndServe() error {
+	httpServer, ok := s.httpServer.(listenerServer)
+	if !ok {
+		// the in-memory listener is registered when created
+		s.listening.Store(true)
+		return s.httpServer.ListenAndServe()
+	}
+
+	listener, err := net.Listen("tcp", s.listenAddress)
+	if err != nil {
+		return err
+	}
+	s.listening.Store(true)
+
+	if s.tlsConfig == nil {
+		return httpServer.Serve(listener)
+	}
+
+	// the certificates are already loaded in the TLS config of the http server
+	return httpServer.ServeTLS(listener, "", "")
+}
+
+// SetPayloadHandler will set the provided payload handler, used by the clients connecting from now on
 func (s *server) SetPayloadHandler(handler webSocket.PayloadHandler) error {
+	if check.IfNil(handler) {
+		return data.ErrNilPayloadProcessor
+	}
+
+	s.mutHandlers.Lock()
 	s.payloadHandler = handler
+	s.mutHandlers.Unlock()
+
+	return nil
+}
+
+// SetRequestHandler will set the provided request handler, used by the clients connecting from now on
+func (s *server) SetRequestHandler(handler webSocket.RequestHandler) error {
+	if check.IfNil(handler) {
+		return data.ErrNilRequestHandler
+	}
+
+	s.mutHandlers.Lock()
+	s.requestHandler = handler
+	s.mutHandlers.Unlock()
+
 	return nil
 }
 
@@ -193,6 +1016,14 @@ func (s *server) SetPayloadHandler(handler webSocket.PayloadHandler) error {
 func (s *server) Close() error {
 	var lastError error
 
+	if s.queueSender != nil {
+		err := s.queueSender.Close()
+		if err != nil {
+			s.log.Debug("server.Close() cannot close queue sender", "error", err)
+			lastError = err
+		}
+	}
+
 	err := s.httpServer.Shutdown(context.Background())
 	if err != nil {
 		s.log.Debug("server.Close() cannot close http server", "error", err)
@@ -1,15 +1,21 @@
 package server
 
 import (
+	"context"
 	"errors"
+	"net"
+	"net/http"
 	"sync"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"github.com/subrahamanyam341/andes-communication/testscommon"
+	"github.com/subrahamanyam341/andes-communication/testscommon/transceiver"
 	"github.com/subrahamanyam341/andes-communication/websocket"
 	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	wsTransceiver "github.com/subrahamanyam341/andes-communication/websocket/transceiver"
 )
 
 func createArgs() ArgsWebSocketServer {
@@ -59,6 +65,221 @@ func TestNewWebSocketsServer(t *testing.T) {
 		require.Nil(t, ws)
 		require.Equal(t, data.ErrZeroValueRetryDuration, err)
 	})
+
+	t.Run("min payload version greater than the payload version, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.MinPayloadVersion = 3
+		args.PayloadVersion = 2
+		ws, err := NewWebSocketServer(args)
+		require.Nil(t, ws)
+		require.True(t, errors.Is(err, data.ErrInvalidPayloadVersionRange))
+	})
+
+	t.Run("metrics route path without leading slash, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.MetricsRoutePath = "metrics"
+		ws, err := NewWebSocketServer(args)
+		require.Nil(t, ws)
+		require.Equal(t, data.ErrInvalidRoutePath, err)
+	})
+
+	t.Run("metrics route path same as the websocket route, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.MetricsRoutePath = data.WSRoute
+		ws, err := NewWebSocketServer(args)
+		require.Nil(t, ws)
+		require.True(t, errors.Is(err, data.ErrRouteConflict))
+	})
+
+	t.Run("health endpoints conflicting with the websocket route, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.HealthEndpoints = true
+		args.RoutePath = data.ReadyRoute
+		ws, err := NewWebSocketServer(args)
+		require.Nil(t, ws)
+		require.True(t, errors.Is(err, data.ErrRouteConflict))
+	})
+
+	t.Run("health endpoints conflicting with the metrics route, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.HealthEndpoints = true
+		args.MetricsRoutePath = data.HealthRoute
+		ws, err := NewWebSocketServer(args)
+		require.Nil(t, ws)
+		require.True(t, errors.Is(err, data.ErrRouteConflict))
+	})
+
+	t.Run("served metrics without snapshots, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.MetricsRoutePath = "/metrics"
+		args.Metrics = websocket.NewNilMetricsHandler()
+		ws, err := NewWebSocketServer(args)
+		require.Nil(t, ws)
+		require.Equal(t, data.ErrMetricsSnapshotNotSupported, err)
+	})
+
+	t.Run("unknown payload compression, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.PayloadCompression = "gzip"
+		ws, err := NewWebSocketServer(args)
+		require.Nil(t, ws)
+		require.Equal(t, data.ErrUnknownCompressionAlgorithm, err)
+	})
+
+	t.Run("negative compression threshold, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.CompressionThreshold = -1
+		ws, err := NewWebSocketServer(args)
+		require.Nil(t, ws)
+		require.Equal(t, data.ErrNegativeCompressionThreshold, err)
+	})
+
+	t.Run("negative chunk size, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.ChunkSizeInBytes = -1
+		ws, err := NewWebSocketServer(args)
+		require.Nil(t, ws)
+		require.Equal(t, data.ErrInvalidChunkingConfig, err)
+	})
+
+	t.Run("negative max concurrent requests, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.MaxConcurrentRequests = -1
+		ws, err := NewWebSocketServer(args)
+		require.Nil(t, ws)
+		require.Equal(t, data.ErrInvalidMaxConcurrentRequests, err)
+	})
+
+	t.Run("ping interval without pong timeout, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.PingIntervalInSeconds = 1
+		ws, err := NewWebSocketServer(args)
+		require.Nil(t, ws)
+		require.Equal(t, data.ErrInvalidKeepAliveConfig, err)
+	})
+
+	t.Run("pong timeout without ping interval, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.PongTimeoutInSeconds = 1
+		ws, err := NewWebSocketServer(args)
+		require.Nil(t, ws)
+		require.Equal(t, data.ErrInvalidKeepAliveConfig, err)
+	})
+
+	t.Run("route path without leading slash, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.RoutePath = "save"
+		ws, err := NewWebSocketServer(args)
+		require.Nil(t, ws)
+		require.Equal(t, data.ErrInvalidRoutePath, err)
+	})
+
+	t.Run("negative read buffer size, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.ReadBufferSize = -1
+		ws, err := NewWebSocketServer(args)
+		require.Nil(t, ws)
+		require.Equal(t, data.ErrInvalidBufferSize, err)
+	})
+
+	t.Run("negative write buffer size, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.WriteBufferSize = -1
+		ws, err := NewWebSocketServer(args)
+		require.Nil(t, ws)
+		require.Equal(t, data.ErrInvalidBufferSize, err)
+	})
+
+	t.Run("negative max message size, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.MaxMessageSize = -1
+		ws, err := NewWebSocketServer(args)
+		require.Nil(t, ws)
+		require.Equal(t, data.ErrInvalidMaxMessageSize, err)
+	})
+
+	t.Run("negative write timeout, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.WriteTimeoutInSeconds = -1
+		ws, err := NewWebSocketServer(args)
+		require.Nil(t, ws)
+		require.Equal(t, data.ErrInvalidTimeout, err)
+	})
+
+	t.Run("negative handshake timeout, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.HandshakeTimeoutInSeconds = -1
+		ws, err := NewWebSocketServer(args)
+		require.Nil(t, ws)
+		require.Equal(t, data.ErrInvalidTimeout, err)
+	})
+
+	t.Run("negative client queue size, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.ClientQueueSize = -1
+		ws, err := NewWebSocketServer(args)
+		require.Nil(t, ws)
+		require.Equal(t, data.ErrInvalidClientQueueSize, err)
+	})
+
+	t.Run("unknown client queue overflow policy, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.ClientQueueOverflowPolicy = "block"
+		ws, err := NewWebSocketServer(args)
+		require.Nil(t, ws)
+		require.Equal(t, data.ErrUnknownOverflowPolicy, err)
+	})
+
+	t.Run("invalid rate limit, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.RateLimit.MessagesPerSecond = -1
+		ws, err := NewWebSocketServer(args)
+		require.Nil(t, ws)
+		require.Equal(t, data.ErrInvalidRateLimitConfig, err)
+	})
+
+	t.Run("unknown rate limit action, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.RateLimit.Action = "block"
+		ws, err := NewWebSocketServer(args)
+		require.Nil(t, ws)
+		require.Equal(t, data.ErrUnknownRateLimitAction, err)
+	})
+
+	t.Run("unknown transport, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.Transport = "quic"
+		ws, err := NewWebSocketServer(args)
+		require.Nil(t, ws)
+		require.Equal(t, data.ErrUnknownTransport, err)
+	})
+
+	t.Run("memory transport with invalid drop percentage, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.Transport = data.MemoryTransport
+		args.MemoryTransport.DropPercentage = 101
+		ws, err := NewWebSocketServer(args)
+		require.Nil(t, ws)
+		require.Equal(t, data.ErrInvalidMemoryTransportConfig, err)
+	})
+
+	t.Run("memory transport with health endpoints, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.Transport = data.MemoryTransport
+		args.HealthEndpoints = true
+		ws, err := NewWebSocketServer(args)
+		require.Nil(t, ws)
+		require.True(t, errors.Is(err, data.ErrNotSupportedByMemoryTransport))
+	})
+
+	t.Run("memory transport, should work", func(t *testing.T) {
+		args := createArgs()
+		args.Transport = data.MemoryTransport
+		args.URL = "memory-server-test"
+		ws, err := NewWebSocketServer(args)
+		require.Nil(t, err)
+		require.Nil(t, ws.Close())
+	})
 }
 
 func TestServer_ListenAndClose(t *testing.T) {
@@ -110,6 +331,20 @@ func TestServer_ListenAndRegisterPayloadHandlerAndClose(t *testing.T) {
 	wg.Wait()
 }
 
+func TestServer_SetHandlers(t *testing.T) {
+	t.Parallel()
+
+	wsServer, _ := NewWebSocketServer(createArgs())
+	defer func() {
+		_ = wsServer.Close()
+	}()
+
+	require.Equal(t, data.ErrNilPayloadProcessor, wsServer.SetPayloadHandler(nil))
+	require.Equal(t, data.ErrNilRequestHandler, wsServer.SetRequestHandler(nil))
+	require.Nil(t, wsServer.SetPayloadHandler(&testscommon.PayloadHandlerStub{}))
+	require.Nil(t, wsServer.SetRequestHandler(&testscommon.RequestHandlerStub{}))
+}
+
 func TestServer_DropMessageInCaseOfNoConnection(t *testing.T) {
 	args := createArgs()
 	args.DropMessagesIfNoConnection = true
@@ -137,3 +372,216 @@ func TestServer_SendReturnsErrorIfNoConnection(t *testing.T) {
 	err := wsServer.Send([]byte("test"), "test")
 	require.Equal(t, data.ErrNoClientsConnected, err)
 }
+
+func TestServer_SendToConnectedClientsAndDisconnect(t *testing.T) {
+	args := createArgs()
+	args.URL = "localhost:9212"
+	wsServer, _ := NewWebSocketServer(args)
+	defer func() {
+		_ = wsServer.Close()
+	}()
+
+	sentTo := ""
+	closed := ""
+	for _, id := range []string{"id1", "id2"} {
+		connID := id
+		conn := newConnectionWithStatistics(&testscommon.WebsocketConnectionStub{
+			GetIDCalled: func() string {
+				return connID
+			},
+			CloseCalled: func() error {
+				closed = connID
+				return nil
+			},
+		}, "127.0.0.1:1234")
+		wsServer.transceiversAndConn.addTransceiverAndConn(&transceiver.WebSocketTransceiverStub{
+			SendCalled: func(payload []byte, topic string, conn websocket.WSConClient) error {
+				sentTo = conn.GetID()
+				return conn.WriteMessage(0, payload)
+			},
+		}, conn, nil, nil)
+	}
+
+	err := wsServer.SendTo("unknown", []byte("test"), "test")
+	require.Equal(t, data.ErrClientNotFound, err)
+
+	err = wsServer.SendTo("id2", []byte("test"), "test")
+	require.Nil(t, err)
+	require.Equal(t, "id2", sentTo)
+
+	clients := wsServer.ConnectedClients()
+	require.Len(t, clients, 2)
+	require.Equal(t, "id1", clients[0].ID)
+	require.Equal(t, uint64(0), clients[0].MessagesSent)
+	require.Equal(t, "id2", clients[1].ID)
+	require.Equal(t, "127.0.0.1:1234", clients[1].RemoteAddress)
+	require.Equal(t, uint64(1), clients[1].MessagesSent)
+	require.Equal(t, uint64(4), clients[1].BytesSent)
+
+	err = wsServer.Disconnect("unknown")
+	require.Equal(t, data.ErrClientNotFound, err)
+
+	err = wsServer.Disconnect("id1")
+	require.Nil(t, err)
+	require.Equal(t, "id1", closed)
+	clients = wsServer.ConnectedClients()
+	require.Len(t, clients, 1)
+	require.Equal(t, "id2", clients[0].ID)
+}
+
+func TestServer_SendToQuorum(t *testing.T) {
+	args := createArgs()
+	args.URL = "localhost:9214"
+	wsServer, _ := NewWebSocketServer(args)
+	defer func() {
+		_ = wsServer.Close()
+	}()
+
+	err := wsServer.SendToQuorum([]byte("test"), "test", 1)
+	require.Equal(t, data.ErrNoClientsConnected, err)
+
+	expectedErr := errors.New("expected error")
+	numSent := atomic.Int32{}
+	for _, id := range []string{"id1", "id2", "id3"} {
+		connID := id
+		wsServer.transceiversAndConn.addTransceiverAndConn(&transceiver.WebSocketTransceiverStub{
+			SendAsyncCalled: func(payload []byte, topic string, conn websocket.WSConClient) (websocket.AckFuture, error) {
+				numSent.Add(1)
+				if connID == "id1" {
+					return wsTransceiver.NewCompletedAckFuture(expectedErr), nil
+				}
+				return wsTransceiver.NewCompletedAckFuture(nil), nil
+			},
+		}, &testscommon.WebsocketConnectionStub{
+			GetIDCalled: func() string {
+				return connID
+			},
+		}, nil, nil)
+	}
+
+	t.Run("negative quorum, should return error", func(t *testing.T) {
+		err = wsServer.SendToQuorum([]byte("test"), "test", -1)
+		require.Equal(t, data.ErrInvalidAckQuorum, err)
+	})
+	t.Run("quorum reached, should work", func(t *testing.T) {
+		numSent.Store(0)
+		err = wsServer.SendToQuorum([]byte("test"), "test", 2)
+		require.Nil(t, err)
+		require.Equal(t, int32(3), numSent.Load())
+	})
+	t.Run("all clients required and one failed, should return error", func(t *testing.T) {
+		err = wsServer.SendToQuorum([]byte("test"), "test", 0)
+		require.True(t, errors.Is(err, data.ErrAckQuorumNotReached))
+		require.ErrorContains(t, err, expectedErr.Error())
+	})
+	t.Run("quorum larger than the number of clients, should return error without sending", func(t *testing.T) {
+		numSent.Store(0)
+		err = wsServer.SendToQuorum([]byte("test"), "test", 4)
+		require.True(t, errors.Is(err, data.ErrAckQuorumNotReached))
+		require.Zero(t, numSent.Load())
+	})
+}
+
+func TestServer_Request(t *testing.T) {
+	args := createArgs()
+	args.URL = "localhost:9213"
+	wsServer, _ := NewWebSocketServer(args)
+	defer func() {
+		_ = wsServer.Close()
+	}()
+
+	err := wsServer.SetRequestHandler(nil)
+	require.Equal(t, data.ErrNilRequestHandler, err)
+
+	response, err := wsServer.Request(context.Background(), "topic", []byte("request"))
+	require.Nil(t, response)
+	require.Equal(t, data.ErrNoClientsConnected, err)
+
+	expectedErr := errors.New("expected error")
+	for _, id := range []string{"id1", "id2"} {
+		connID := id
+		wsServer.transceiversAndConn.addTransceiverAndConn(&transceiver.WebSocketTransceiverStub{
+			RequestCalled: func(ctx context.Context, payload []byte, topic string, conn websocket.WSConClient) ([]byte, error) {
+				if connID == "id1" {
+					return nil, expectedErr
+				}
+				return []byte("response from " + connID), nil
+			},
+		}, &testscommon.WebsocketConnectionStub{
+			GetIDCalled: func() string {
+				return connID
+			},
+		}, nil, nil)
+	}
+
+	response, err = wsServer.Request(context.Background(), "topic", []byte("request"))
+	require.Nil(t, err)
+	require.Equal(t, []byte("response from id2"), response)
+
+	response, err = wsServer.RequestTo(context.Background(), "id1", "topic", []byte("request"))
+	require.Nil(t, response)
+	require.Equal(t, expectedErr, err)
+
+	response, err = wsServer.RequestTo(context.Background(), "unknown", "topic", []byte("request"))
+	require.Nil(t, response)
+	require.Equal(t, data.ErrClientNotFound, err)
+}
+
+func TestServer_ShouldReportListeningOnlyWhileServing(t *testing.T) {
+	t.Parallel()
+
+	t.Run("address in use, should never report listening", func(t *testing.T) {
+		t.Parallel()
+
+		listener, err := net.Listen("tcp", "localhost:0")
+		require.Nil(t, err)
+		defer func() {
+			_ = listener.Close()
+		}()
+
+		served := false
+		wsServer := &server{
+			httpServer: &testscommon.HttpServerStub{
+				ServeCalled: func(_ net.Listener) error {
+					served = true
+					return nil
+				},
+			},
+			listenAddress: listener.Addr().String(),
+		}
+
+		err = wsServer.listenAndServe()
+		require.NotNil(t, err)
+		require.False(t, served)
+		require.False(t, wsServer.listening.Load())
+	})
+	t.Run("should report listening once bound and until serving returns", func(t *testing.T) {
+		t.Parallel()
+
+		chListening := make(chan bool)
+		chStopServing := make(chan struct{})
+		wsServer := &server{
+			log:           &testscommon.LoggerMock{},
+			listenAddress: "localhost:0",
+		}
+		wsServer.httpServer = &testscommon.HttpServerStub{
+			ServeCalled: func(listener net.Listener) error {
+				defer func() {
+					_ = listener.Close()
+				}()
+
+				chListening <- wsServer.listening.Load()
+				<-chStopServing
+				return http.ErrServerClosed
+			},
+		}
+
+		wsServer.start()
+		require.True(t, <-chListening)
+
+		close(chStopServing)
+		require.Eventually(t, func() bool {
+			return !wsServer.listening.Load()
+		}, time.Second, time.Millisecond)
+	})
+}
@@ -9,6 +9,13 @@ import (
 type tupleTransceiverAndConn struct {
 	transceiver Transceiver
 	conn        websocket.WSConClient
+	// queue holds the messages waiting to be sent to the client. It is nil if the per client queues are disabled
+	queue *clientQueue
+	// rateLimiter limits the payload messages received from the client. It is nil if no rate limit is configured
+	rateLimiter *clientRateLimiter
+	// subscriptions holds the topics the client subscribed to. A nil map means the client did not subscribe, so it
+	// receives all the topics
+	subscriptions map[string]struct{}
 }
 
 type transceiversAndConnHolder struct {
@@ -24,13 +31,15 @@ func newTransceiversAndConnHolder() *transceiversAndConnHolder {
 }
 
 // addTransceiverAndConn will add the provided transceiver in the internal map
-func (th *transceiversAndConnHolder) addTransceiverAndConn(transceiver Transceiver, conn websocket.WSConClient) {
+func (th *transceiversAndConnHolder) addTransceiverAndConn(transceiver Transceiver, conn websocket.WSConClient, queue *clientQueue, rateLimiter *clientRateLimiter) {
 	th.mutex.Lock()
 	defer th.mutex.Unlock()
 
 	th.transceiverAndConn[conn.GetID()] = tupleTransceiverAndConn{
 		transceiver: transceiver,
 		conn:        conn,
+		queue:       queue,
+		rateLimiter: rateLimiter,
 	}
 }
 
@@ -42,6 +51,15 @@ func (th *transceiversAndConnHolder) remove(id string) {
 	delete(th.transceiverAndConn, id)
 }
 
+// get will return the transceiver and the connection stored for the provided ID
+func (th *transceiversAndConnHolder) get(id string) (tupleTransceiverAndConn, bool) {
+	th.mutex.RLock()
+	defer th.mutex.RUnlock()
+
+	tuple, found := th.transceiverAndConn[id]
+	return tuple, found
+}
+
 // getAll will return a map with all the stored transceivers
 func (th *transceiversAndConnHolder) getAll() map[string]tupleTransceiverAndConn {
 	th.mutex.RLock()
@@ -54,3 +72,73 @@ func (th *transceiversAndConnHolder) getAll() map[string]tupleTransceiverAndConn
 
 	return transceiversAndConn
 }
+
+// getAllForTopic will return a map with the stored transceivers whose clients are interested in the provided topic
+func (th *transceiversAndConnHolder) getAllForTopic(topic string) map[string]tupleTransceiverAndConn {
+	th.mutex.RLock()
+	defer th.mutex.RUnlock()
+
+	transceiversAndConn := make(map[string]tupleTransceiverAndConn)
+	for id, tuple := range th.transceiverAndConn {
+		if tuple.subscriptions != nil {
+			_, isSubscribed := tuple.subscriptions[topic]
+			if !isSubscribed {
+				continue
+			}
+		}
+
+		transceiversAndConn[id] = tuple
+	}
+
+	return transceiversAndConn
+}
+
+// Subscribe will add the provided topics to the subscriptions of the provided connection
+func (th *transceiversAndConnHolder) Subscribe(connectionID string, topics []string) {
+	th.mutex.Lock()
+	defer th.mutex.Unlock()
+
+	tuple, found := th.transceiverAndConn[connectionID]
+	if !found {
+		return
+	}
+
+	subscriptions := make(map[string]struct{}, len(tuple.subscriptions)+len(topics))
+	for topic := range tuple.subscriptions {
+		subscriptions[topic] = struct{}{}
+	}
+	for _, topic := range topics {
+		subscriptions[topic] = struct{}{}
+	}
+
+	tuple.subscriptions = subscriptions
+	th.transceiverAndConn[connectionID] = tuple
+}
+
+// Unsubscribe will remove the provided topics from the subscriptions of the provided connection. It has no effect on
+// clients that did not subscribe before, as they receive all the topics
+func (th *transceiversAndConnHolder) Unsubscribe(connectionID string, topics []string) {
+	th.mutex.Lock()
+	defer th.mutex.Unlock()
+
+	tuple, found := th.transceiverAndConn[connectionID]
+	if !found || tuple.subscriptions == nil {
+		return
+	}
+
+	subscriptions := make(map[string]struct{}, len(tuple.subscriptions))
+	for topic := range tuple.subscriptions {
+		subscriptions[topic] = struct{}{}
+	}
+	for _, topic := range topics {
+		delete(subscriptions, topic)
+	}
+
+	tuple.subscriptions = subscriptions
+	th.transceiverAndConn[connectionID] = tuple
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (th *transceiversAndConnHolder) IsInterfaceNil() bool {
+	return th == nil
+}
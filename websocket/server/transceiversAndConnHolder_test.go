@@ -17,12 +17,12 @@ func TestTransceiversHolderAddAndRemove(t *testing.T) {
 		GetIDCalled: func() string {
 			return "id1"
 		},
-	})
+	}, nil, nil)
 	recsHolder.addTransceiverAndConn(&transceiver.WebSocketTransceiverStub{}, &testscommon.WebsocketConnectionStub{
 		GetIDCalled: func() string {
 			return "id2"
 		},
-	})
+	}, nil, nil)
 
 	recsHolder.remove("id1")
 
@@ -42,17 +42,17 @@ func TestTransceiversHolderAddAndGetAll(t *testing.T) {
 		GetIDCalled: func() string {
 			return "1"
 		},
-	})
+	}, nil, nil)
 	recsHolder.addTransceiverAndConn(&transceiver.WebSocketTransceiverStub{}, &testscommon.WebsocketConnectionStub{
 		GetIDCalled: func() string {
 			return "2"
 		},
-	})
+	}, nil, nil)
 	recsHolder.addTransceiverAndConn(&transceiver.WebSocketTransceiverStub{}, &testscommon.WebsocketConnectionStub{
 		GetIDCalled: func() string {
 			return "3"
 		},
-	})
+	}, nil, nil)
 
 	allReceivers := recsHolder.getAll()
 	require.Equal(t, 3, len(allReceivers))
@@ -64,3 +64,40 @@ func TestTransceiversHolderAddAndGetAll(t *testing.T) {
 	_, found = allReceivers["3"]
 	require.True(t, found)
 }
+
+func TestTransceiversHolderSubscriptions(t *testing.T) {
+	t.Parallel()
+
+	recsHolder := newTransceiversAndConnHolder()
+	require.False(t, recsHolder.IsInterfaceNil())
+
+	for _, id := range []string{"1", "2"} {
+		connID := id
+		recsHolder.addTransceiverAndConn(&transceiver.WebSocketTransceiverStub{}, &testscommon.WebsocketConnectionStub{
+			GetIDCalled: func() string {
+				return connID
+			},
+		}, nil, nil)
+	}
+
+	// unknown connections and unsubscribing without a subscription should be ignored
+	recsHolder.Subscribe("unknown", []string{"topic1"})
+	recsHolder.Unsubscribe("2", []string{"topic1"})
+	require.Len(t, recsHolder.getAllForTopic("topic1"), 2)
+
+	recsHolder.Subscribe("1", []string{"topic1", "topic2"})
+	require.Len(t, recsHolder.getAllForTopic("topic1"), 2)
+	require.Len(t, recsHolder.getAllForTopic("topic2"), 2)
+	require.Len(t, recsHolder.getAllForTopic("topic3"), 1)
+	_, found := recsHolder.getAllForTopic("topic3")["2"]
+	require.True(t, found)
+
+	recsHolder.Unsubscribe("1", []string{"topic1"})
+	require.Len(t, recsHolder.getAllForTopic("topic1"), 1)
+	require.Len(t, recsHolder.getAllForTopic("topic2"), 2)
+
+	// a client that unsubscribed from all its topics does not receive anything
+	recsHolder.Unsubscribe("1", []string{"topic2"})
+	require.Len(t, recsHolder.getAllForTopic("topic2"), 1)
+	require.Len(t, recsHolder.getAll(), 2)
+}
@@ -0,0 +1,59 @@
+package signature
+
+import (
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	"github.com/subrahamanyam341/andes-core-16/core/check"
+	crypto "github.com/subrahamanyam341/andes-crypto-123"
+)
+
+// ArgsMessageSigner holds the arguments needed for creating a message signer
+type ArgsMessageSigner struct {
+	Signer     crypto.SingleSigner
+	PrivateKey crypto.PrivateKey
+}
+
+type messageSigner struct {
+	signer         crypto.SingleSigner
+	privateKey     crypto.PrivateKey
+	publicKeyBytes []byte
+}
+
+// NewMessageSigner will create a new message signer that signs the topic and the payload of the messages with the
+// node key, so they can be verified even after passing through a relay
+func NewMessageSigner(args ArgsMessageSigner) (*messageSigner, error) {
+	if check.IfNil(args.Signer) {
+		return nil, data.ErrNilSingleSigner
+	}
+	if check.IfNil(args.PrivateKey) {
+		return nil, data.ErrNilPrivateKey
+	}
+
+	publicKeyBytes, err := args.PrivateKey.GeneratePublic().ToByteArray()
+	if err != nil {
+		return nil, err
+	}
+
+	return &messageSigner{
+		signer:         args.Signer,
+		privateKey:     args.PrivateKey,
+		publicKeyBytes: publicKeyBytes,
+	}, nil
+}
+
+// Sign will set the signature and the public key of the signer on the provided message
+func (ms *messageSigner) Sign(message *data.WsMessage) error {
+	signature, err := ms.signer.Sign(ms.privateKey, createMessageToSign(message))
+	if err != nil {
+		return err
+	}
+
+	message.Signature = signature
+	message.SignerPublicKey = ms.publicKeyBytes
+
+	return nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (ms *messageSigner) IsInterfaceNil() bool {
+	return ms == nil
+}
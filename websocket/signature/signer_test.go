@@ -0,0 +1,100 @@
+package signature
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/testscommon"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	crypto "github.com/subrahamanyam341/andes-crypto-123"
+	"github.com/subrahamanyam341/andes-crypto-123/signing"
+	"github.com/subrahamanyam341/andes-crypto-123/signing/secp256k1"
+	"github.com/subrahamanyam341/andes-crypto-123/signing/secp256k1/singlesig"
+)
+
+var keyGen = signing.NewKeyGenerator(secp256k1.NewSecp256k1())
+
+func createSigner(privateKey crypto.PrivateKey) *messageSigner {
+	signer, _ := NewMessageSigner(ArgsMessageSigner{
+		Signer:     &singlesig.Secp256k1Signer{},
+		PrivateKey: privateKey,
+	})
+
+	return signer
+}
+
+func TestNewMessageSigner(t *testing.T) {
+	t.Parallel()
+
+	privateKey, _ := keyGen.GeneratePair()
+
+	t.Run("nil signer, should return error", func(t *testing.T) {
+		signer, err := NewMessageSigner(ArgsMessageSigner{PrivateKey: privateKey})
+		require.Nil(t, signer)
+		require.Equal(t, data.ErrNilSingleSigner, err)
+	})
+
+	t.Run("nil private key, should return error", func(t *testing.T) {
+		signer, err := NewMessageSigner(ArgsMessageSigner{Signer: &singlesig.Secp256k1Signer{}})
+		require.Nil(t, signer)
+		require.Equal(t, data.ErrNilPrivateKey, err)
+	})
+
+	t.Run("should work", func(t *testing.T) {
+		signer, err := NewMessageSigner(ArgsMessageSigner{
+			Signer:     &singlesig.Secp256k1Signer{},
+			PrivateKey: privateKey,
+		})
+		require.Nil(t, err)
+		require.False(t, signer.IsInterfaceNil())
+	})
+}
+
+func TestMessageSigner_Sign(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should set the signature and the public key", func(t *testing.T) {
+		privateKey, publicKey := keyGen.GeneratePair()
+		publicKeyBytes, _ := publicKey.ToByteArray()
+
+		message := &data.WsMessage{Topic: "topic", Payload: []byte("payload")}
+		err := createSigner(privateKey).Sign(message)
+		require.Nil(t, err)
+		require.Equal(t, publicKeyBytes, message.SignerPublicKey)
+
+		err = (&singlesig.Secp256k1Signer{}).Verify(publicKey, createMessageToSign(message), message.Signature)
+		require.Nil(t, err)
+	})
+
+	t.Run("signing error, should return error", func(t *testing.T) {
+		privateKey, _ := keyGen.GeneratePair()
+		expectedErr := errors.New("expected error")
+		signer, _ := NewMessageSigner(ArgsMessageSigner{
+			Signer: &testscommon.SingleSignerStub{
+				SignCalled: func(_ crypto.PrivateKey, _ []byte) ([]byte, error) {
+					return nil, expectedErr
+				},
+			},
+			PrivateKey: privateKey,
+		})
+
+		message := &data.WsMessage{Topic: "topic", Payload: []byte("payload")}
+		err := signer.Sign(message)
+		require.Equal(t, expectedErr, err)
+		require.Nil(t, message.Signature)
+	})
+}
+
+func TestCreateMessageToSign(t *testing.T) {
+	t.Parallel()
+
+	// the topic length keeps apart the messages whose topic and payload concatenate to the same bytes
+	first := createMessageToSign(&data.WsMessage{Topic: "ab", Payload: []byte("c")})
+	second := createMessageToSign(&data.WsMessage{Topic: "a", Payload: []byte("bc")})
+	require.NotEqual(t, first, second)
+
+	// the fields set on every hop are not signed
+	third := createMessageToSign(&data.WsMessage{Topic: "ab", Payload: []byte("c"), Counter: 7, Sequence: 3, Version: 2})
+	require.Equal(t, first, third)
+}
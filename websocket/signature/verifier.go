@@ -0,0 +1,85 @@
+package signature
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	"github.com/subrahamanyam341/andes-core-16/core/check"
+	crypto "github.com/subrahamanyam341/andes-crypto-123"
+)
+
+// ArgsMessageVerifier holds the arguments needed for creating a message verifier
+type ArgsMessageVerifier struct {
+	Signer            crypto.SingleSigner
+	KeyGenerator      crypto.KeyGenerator
+	TrustedPublicKeys [][]byte
+}
+
+type messageVerifier struct {
+	signer            crypto.SingleSigner
+	trustedPublicKeys map[string]crypto.PublicKey
+}
+
+// NewMessageVerifier will create a new message verifier that accepts only the messages signed by one of the trusted
+// node keys
+func NewMessageVerifier(args ArgsMessageVerifier) (*messageVerifier, error) {
+	if check.IfNil(args.Signer) {
+		return nil, data.ErrNilSingleSigner
+	}
+	if check.IfNil(args.KeyGenerator) {
+		return nil, data.ErrNilKeyGenerator
+	}
+	if len(args.TrustedPublicKeys) == 0 {
+		return nil, data.ErrEmptyTrustedPublicKeys
+	}
+
+	trustedPublicKeys := make(map[string]crypto.PublicKey, len(args.TrustedPublicKeys))
+	for index, publicKeyBytes := range args.TrustedPublicKeys {
+		publicKey, err := args.KeyGenerator.PublicKeyFromByteArray(publicKeyBytes)
+		if err != nil {
+			return nil, fmt.Errorf("%w for the trusted public key at index %d", err, index)
+		}
+
+		trustedPublicKeys[string(publicKeyBytes)] = publicKey
+	}
+
+	return &messageVerifier{
+		signer:            args.Signer,
+		trustedPublicKeys: trustedPublicKeys,
+	}, nil
+}
+
+// Verify will check that the provided message was signed by one of the trusted keys
+func (mv *messageVerifier) Verify(message *data.WsMessage) error {
+	if len(message.Signature) == 0 {
+		return data.ErrMissingSignature
+	}
+
+	publicKey, isTrusted := mv.trustedPublicKeys[string(message.SignerPublicKey)]
+	if !isTrusted {
+		return data.ErrUntrustedSigner
+	}
+
+	err := mv.signer.Verify(publicKey, createMessageToSign(message), message.Signature)
+	if err != nil {
+		return fmt.Errorf("%w: %s", data.ErrInvalidSignature, err.Error())
+	}
+
+	return nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (mv *messageVerifier) IsInterfaceNil() bool {
+	return mv == nil
+}
+
+// createMessageToSign returns the signed content of a message: its topic and its payload. The fields set on every
+// hop, like the counter or the sequence, are left out, so the signature stays valid when a relay forwards the message
+func createMessageToSign(message *data.WsMessage) []byte {
+	messageToSign := make([]byte, 0, binary.MaxVarintLen64+len(message.Topic)+len(message.Payload))
+	messageToSign = binary.AppendUvarint(messageToSign, uint64(len(message.Topic)))
+	messageToSign = append(messageToSign, message.Topic...)
+
+	return append(messageToSign, message.Payload...)
+}
@@ -0,0 +1,121 @@
+package signature
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	crypto "github.com/subrahamanyam341/andes-crypto-123"
+	"github.com/subrahamanyam341/andes-crypto-123/signing/secp256k1/singlesig"
+)
+
+func createVerifierArgs(trustedKeys ...crypto.PrivateKey) ArgsMessageVerifier {
+	trustedPublicKeys := make([][]byte, 0, len(trustedKeys))
+	for _, privateKey := range trustedKeys {
+		publicKeyBytes, _ := privateKey.GeneratePublic().ToByteArray()
+		trustedPublicKeys = append(trustedPublicKeys, publicKeyBytes)
+	}
+
+	return ArgsMessageVerifier{
+		Signer:            &singlesig.Secp256k1Signer{},
+		KeyGenerator:      keyGen,
+		TrustedPublicKeys: trustedPublicKeys,
+	}
+}
+
+func TestNewMessageVerifier(t *testing.T) {
+	t.Parallel()
+
+	privateKey, _ := keyGen.GeneratePair()
+
+	t.Run("nil signer, should return error", func(t *testing.T) {
+		args := createVerifierArgs(privateKey)
+		args.Signer = nil
+		verifier, err := NewMessageVerifier(args)
+		require.Nil(t, verifier)
+		require.Equal(t, data.ErrNilSingleSigner, err)
+	})
+
+	t.Run("nil key generator, should return error", func(t *testing.T) {
+		args := createVerifierArgs(privateKey)
+		args.KeyGenerator = nil
+		verifier, err := NewMessageVerifier(args)
+		require.Nil(t, verifier)
+		require.Equal(t, data.ErrNilKeyGenerator, err)
+	})
+
+	t.Run("no trusted public key, should return error", func(t *testing.T) {
+		verifier, err := NewMessageVerifier(createVerifierArgs())
+		require.Nil(t, verifier)
+		require.Equal(t, data.ErrEmptyTrustedPublicKeys, err)
+	})
+
+	t.Run("invalid trusted public key, should return error", func(t *testing.T) {
+		args := createVerifierArgs(privateKey)
+		args.TrustedPublicKeys = append(args.TrustedPublicKeys, []byte("invalid"))
+		verifier, err := NewMessageVerifier(args)
+		require.Nil(t, verifier)
+		require.ErrorContains(t, err, "index 1")
+	})
+
+	t.Run("should work", func(t *testing.T) {
+		verifier, err := NewMessageVerifier(createVerifierArgs(privateKey))
+		require.Nil(t, err)
+		require.False(t, verifier.IsInterfaceNil())
+	})
+}
+
+func TestMessageVerifier_Verify(t *testing.T) {
+	t.Parallel()
+
+	trustedKey, _ := keyGen.GeneratePair()
+	untrustedKey, _ := keyGen.GeneratePair()
+	verifier, _ := NewMessageVerifier(createVerifierArgs(trustedKey))
+
+	createSignedMessage := func(privateKey crypto.PrivateKey) *data.WsMessage {
+		message := &data.WsMessage{Topic: "topic", Payload: []byte("payload")}
+		_ = createSigner(privateKey).Sign(message)
+
+		return message
+	}
+
+	t.Run("signed by a trusted key, should work", func(t *testing.T) {
+		message := createSignedMessage(trustedKey)
+		// a relay sets its own counter and sequence
+		message.Counter = 10
+		message.Sequence = 5
+		require.Nil(t, verifier.Verify(message))
+	})
+
+	t.Run("not signed, should return error", func(t *testing.T) {
+		err := verifier.Verify(&data.WsMessage{Topic: "topic", Payload: []byte("payload")})
+		require.Equal(t, data.ErrMissingSignature, err)
+	})
+
+	t.Run("signed by an untrusted key, should return error", func(t *testing.T) {
+		err := verifier.Verify(createSignedMessage(untrustedKey))
+		require.Equal(t, data.ErrUntrustedSigner, err)
+	})
+
+	t.Run("untrusted key claiming a trusted public key, should return error", func(t *testing.T) {
+		message := createSignedMessage(untrustedKey)
+		message.SignerPublicKey = createSignedMessage(trustedKey).SignerPublicKey
+		err := verifier.Verify(message)
+		require.True(t, errors.Is(err, data.ErrInvalidSignature))
+	})
+
+	t.Run("altered payload, should return error", func(t *testing.T) {
+		message := createSignedMessage(trustedKey)
+		message.Payload = []byte("altered payload")
+		err := verifier.Verify(message)
+		require.True(t, errors.Is(err, data.ErrInvalidSignature))
+	})
+
+	t.Run("altered topic, should return error", func(t *testing.T) {
+		message := createSignedMessage(trustedKey)
+		message.Topic = "other topic"
+		err := verifier.Verify(message)
+		require.True(t, errors.Is(err, data.ErrInvalidSignature))
+	})
+}
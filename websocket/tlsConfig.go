@@ -0,0 +1,101 @@
+package websocket
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+)
+
+// ArgsTLSConfig holds the arguments needed for creating a TLS configuration
+type ArgsTLSConfig struct {
+	CertificateFile      string
+	KeyFile              string
+	CACertificateFile    string
+	MutualAuthentication bool
+}
+
+// NewServerTLSConfig will create the TLS configuration used by a WebSocket server. When mutual authentication
+// is enabled, every client has to present a certificate signed by one of the provided CA certificates
+func NewServerTLSConfig(args ArgsTLSConfig) (*tls.Config, error) {
+	if args.CertificateFile == "" {
+		return nil, data.ErrEmptyTLSCertificateFile
+	}
+	if args.KeyFile == "" {
+		return nil, data.ErrEmptyTLSKeyFile
+	}
+	if args.MutualAuthentication && args.CACertificateFile == "" {
+		return nil, data.ErrEmptyTLSCACertificateFile
+	}
+
+	certificate, err := tls.LoadX509KeyPair(args.CertificateFile, args.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if !args.MutualAuthentication {
+		return tlsConfig, nil
+	}
+
+	tlsConfig.ClientCAs, err = loadCertPool(args.CACertificateFile)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+
+	return tlsConfig, nil
+}
+
+// NewClientTLSConfig will create the TLS configuration used by a WebSocket client. If no CA certificate file is
+// provided, the server certificate is verified against the system roots
+func NewClientTLSConfig(args ArgsTLSConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	var err error
+	if args.CACertificateFile != "" {
+		tlsConfig.RootCAs, err = loadCertPool(args.CACertificateFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !args.MutualAuthentication {
+		return tlsConfig, nil
+	}
+
+	if args.CertificateFile == "" {
+		return nil, data.ErrEmptyTLSCertificateFile
+	}
+	if args.KeyFile == "" {
+		return nil, data.ErrEmptyTLSKeyFile
+	}
+
+	certificate, err := tls.LoadX509KeyPair(args.CertificateFile, args.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig.Certificates = []tls.Certificate{certificate}
+
+	return tlsConfig, nil
+}
+
+func loadCertPool(caCertificateFile string) (*x509.CertPool, error) {
+	caCertificate, err := os.ReadFile(caCertificateFile)
+	if err != nil {
+		return nil, err
+	}
+
+	certPool := x509.NewCertPool()
+	if !certPool.AppendCertsFromPEM(caCertificate) {
+		return nil, data.ErrInvalidTLSCACertificate
+	}
+
+	return certPool, nil
+}
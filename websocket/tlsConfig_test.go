@@ -0,0 +1,117 @@
+package websocket
+
+import (
+	"crypto/tls"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/testscommon"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+)
+
+func TestNewServerTLSConfig(t *testing.T) {
+	t.Parallel()
+
+	files, err := testscommon.GenerateTLSCertificateFiles(t.TempDir())
+	require.Nil(t, err)
+
+	t.Run("empty certificate file, should return error", func(t *testing.T) {
+		tlsConfig, err := NewServerTLSConfig(ArgsTLSConfig{KeyFile: files.ServerKeyFile})
+		require.Nil(t, tlsConfig)
+		require.Equal(t, data.ErrEmptyTLSCertificateFile, err)
+	})
+
+	t.Run("empty key file, should return error", func(t *testing.T) {
+		tlsConfig, err := NewServerTLSConfig(ArgsTLSConfig{CertificateFile: files.ServerCertificateFile})
+		require.Nil(t, tlsConfig)
+		require.Equal(t, data.ErrEmptyTLSKeyFile, err)
+	})
+
+	t.Run("mutual authentication without CA file, should return error", func(t *testing.T) {
+		tlsConfig, err := NewServerTLSConfig(ArgsTLSConfig{
+			CertificateFile:      files.ServerCertificateFile,
+			KeyFile:              files.ServerKeyFile,
+			MutualAuthentication: true,
+		})
+		require.Nil(t, tlsConfig)
+		require.Equal(t, data.ErrEmptyTLSCACertificateFile, err)
+	})
+
+	t.Run("invalid CA file, should return error", func(t *testing.T) {
+		invalidCAFile := filepath.Join(t.TempDir(), "invalid.pem")
+		_ = os.WriteFile(invalidCAFile, []byte("not a certificate"), 0600)
+
+		tlsConfig, err := NewServerTLSConfig(ArgsTLSConfig{
+			CertificateFile:      files.ServerCertificateFile,
+			KeyFile:              files.ServerKeyFile,
+			CACertificateFile:    invalidCAFile,
+			MutualAuthentication: true,
+		})
+		require.Nil(t, tlsConfig)
+		require.Equal(t, data.ErrInvalidTLSCACertificate, err)
+	})
+
+	t.Run("should work", func(t *testing.T) {
+		tlsConfig, err := NewServerTLSConfig(ArgsTLSConfig{
+			CertificateFile: files.ServerCertificateFile,
+			KeyFile:         files.ServerKeyFile,
+		})
+		require.Nil(t, err)
+		require.Len(t, tlsConfig.Certificates, 1)
+		require.Equal(t, tls.NoClientCert, tlsConfig.ClientAuth)
+	})
+
+	t.Run("with mutual authentication should work", func(t *testing.T) {
+		tlsConfig, err := NewServerTLSConfig(ArgsTLSConfig{
+			CertificateFile:      files.ServerCertificateFile,
+			KeyFile:              files.ServerKeyFile,
+			CACertificateFile:    files.CACertificateFile,
+			MutualAuthentication: true,
+		})
+		require.Nil(t, err)
+		require.NotNil(t, tlsConfig.ClientCAs)
+		require.Equal(t, tls.RequireAndVerifyClientCert, tlsConfig.ClientAuth)
+	})
+}
+
+func TestNewClientTLSConfig(t *testing.T) {
+	t.Parallel()
+
+	files, err := testscommon.GenerateTLSCertificateFiles(t.TempDir())
+	require.Nil(t, err)
+
+	t.Run("missing CA file, should return error", func(t *testing.T) {
+		tlsConfig, err := NewClientTLSConfig(ArgsTLSConfig{CACertificateFile: filepath.Join(t.TempDir(), "missing.pem")})
+		require.Nil(t, tlsConfig)
+		require.NotNil(t, err)
+	})
+
+	t.Run("mutual authentication without certificate, should return error", func(t *testing.T) {
+		tlsConfig, err := NewClientTLSConfig(ArgsTLSConfig{
+			CACertificateFile:    files.CACertificateFile,
+			MutualAuthentication: true,
+		})
+		require.Nil(t, tlsConfig)
+		require.Equal(t, data.ErrEmptyTLSCertificateFile, err)
+	})
+
+	t.Run("without CA file should use the system roots", func(t *testing.T) {
+		tlsConfig, err := NewClientTLSConfig(ArgsTLSConfig{})
+		require.Nil(t, err)
+		require.Nil(t, tlsConfig.RootCAs)
+	})
+
+	t.Run("with mutual authentication should work", func(t *testing.T) {
+		tlsConfig, err := NewClientTLSConfig(ArgsTLSConfig{
+			CertificateFile:      files.ClientCertificateFile,
+			KeyFile:              files.ClientKeyFile,
+			CACertificateFile:    files.CACertificateFile,
+			MutualAuthentication: true,
+		})
+		require.Nil(t, err)
+		require.NotNil(t, tlsConfig.RootCAs)
+		require.Len(t, tlsConfig.Certificates, 1)
+	})
+}
@@ -0,0 +1,214 @@
+package websocket
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	"github.com/subrahamanyam341/andes-core-16/core"
+	"github.com/subrahamanyam341/andes-core-16/core/check"
+)
+
+const wildcardCharacters = "*?["
+
+type route struct {
+	pattern     string
+	handler     PayloadHandler
+	errorPolicy data.ErrorPolicy
+}
+
+// topicRouter dispatches every payload to the handler registered for its topic. The handler is chosen, in this
+// order, by the exact topic, by the longest matching prefix, by the first matching wildcard pattern and, at last, the
+// fallback handler
+type topicRouter struct {
+	mut           sync.RWMutex
+	log           core.Logger
+	exactRoutes   map[string]*route
+	prefixRoutes  []*route
+	patternRoutes []*route
+	fallback      *route
+}
+
+// NewTopicRouter will create a new payload handler that routes the payloads to the handlers registered per topic
+func NewTopicRouter(log core.Logger) (*topicRouter, error) {
+	if check.IfNil(log) {
+		return nil, core.ErrNilLogger
+	}
+
+	return &topicRouter{
+		log:         log,
+		exactRoutes: make(map[string]*route),
+	}, nil
+}
+
+// RegisterHandler will register the handler for the provided topic pattern. A pattern can be an exact topic, a prefix
+// ending in a single "*" or a wildcard pattern in the path.Match syntax
+func (tr *topicRouter) RegisterHandler(pattern string, handler PayloadHandler, errorPolicy data.ErrorPolicy) error {
+	newRoute, err := createRoute(pattern, handler, errorPolicy)
+	if err != nil {
+		return err
+	}
+
+	tr.mut.Lock()
+	defer tr.mut.Unlock()
+
+	if tr.isRegistered(pattern) {
+		return fmt.Errorf("%w: %s", data.ErrTopicPatternAlreadyRegistered, pattern)
+	}
+
+	prefix, isPrefix := extractPrefix(pattern)
+	switch {
+	case isPrefix:
+		newRoute.pattern = prefix
+		tr.prefixRoutes = append(tr.prefixRoutes, newRoute)
+	case strings.ContainsAny(pattern, wildcardCharacters):
+		tr.patternRoutes = append(tr.patternRoutes, newRoute)
+	default:
+		tr.exactRoutes[pattern] = newRoute
+	}
+
+	return nil
+}
+
+// SetFallbackHandler will set the handler used for the topics that do not match any of the registered patterns
+func (tr *topicRouter) SetFallbackHandler(handler PayloadHandler, errorPolicy data.ErrorPolicy) error {
+	fallback, err := createRoute("*", handler, errorPolicy)
+	if err != nil {
+		return err
+	}
+
+	tr.mut.Lock()
+	tr.fallback = fallback
+	tr.mut.Unlock()
+
+	return nil
+}
+
+func createRoute(pattern string, handler PayloadHandler, errorPolicy data.ErrorPolicy) (*route, error) {
+	if len(pattern) == 0 {
+		return nil, data.ErrEmptyTopicPattern
+	}
+	if check.IfNil(handler) {
+		return nil, data.ErrNilPayloadProcessor
+	}
+	if errorPolicy != data.PropagateErrorPolicy && errorPolicy != data.IgnoreErrorPolicy {
+		return nil, data.ErrUnknownErrorPolicy
+	}
+	_, err := path.Match(pattern, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return &route{
+		pattern:     pattern,
+		handler:     handler,
+		errorPolicy: errorPolicy,
+	}, nil
+}
+
+// extractPrefix returns the prefix of the patterns that end in a single "*" and have no other wildcard characters
+func extractPrefix(pattern string) (string, bool) {
+	prefix := strings.TrimSuffix(pattern, "*")
+	if len(prefix) == len(pattern) || len(prefix) == 0 || strings.ContainsAny(prefix, wildcardCharacters) {
+		return "", false
+	}
+
+	return prefix, true
+}
+
+func (tr *topicRouter) isRegistered(pattern string) bool {
+	_, found := tr.exactRoutes[pattern]
+	if found {
+		return true
+	}
+	for _, prefixRoute := range tr.prefixRoutes {
+		if prefixRoute.pattern+"*" == pattern {
+			return true
+		}
+	}
+	for _, patternRoute := range tr.patternRoutes {
+		if patternRoute.pattern == pattern {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ProcessPayload will send the payload to the handler registered for its topic and will apply the handler's error policy
+func (tr *topicRouter) ProcessPayload(payload []byte, topic string, version uint32) error {
+	matchedRoute := tr.findRoute(topic)
+	if matchedRoute == nil {
+		return fmt.Errorf("%w: %s", data.ErrNoHandlerForTopic, topic)
+	}
+
+	err := matchedRoute.handler.ProcessPayload(payload, topic, version)
+	if err == nil || matchedRoute.errorPolicy == data.PropagateErrorPolicy {
+		return err
+	}
+
+	tr.log.Debug("topicRouter.ProcessPayload: ignoring the handler error", "topic", topic, "pattern", matchedRoute.pattern, "error", err)
+
+	return nil
+}
+
+func (tr *topicRouter) findRoute(topic string) *route {
+	tr.mut.RLock()
+	defer tr.mut.RUnlock()
+
+	exactRoute, found := tr.exactRoutes[topic]
+	if found {
+		return exactRoute
+	}
+
+	var longestPrefixRoute *route
+	for _, prefixRoute := range tr.prefixRoutes {
+		if !strings.HasPrefix(topic, prefixRoute.pattern) {
+			continue
+		}
+		if longestPrefixRoute == nil || len(prefixRoute.pattern) > len(longestPrefixRoute.pattern) {
+			longestPrefixRoute = prefixRoute
+		}
+	}
+	if longestPrefixRoute != nil {
+		return longestPrefixRoute
+	}
+
+	for _, patternRoute := range tr.patternRoutes {
+		matched, _ := path.Match(patternRoute.pattern, topic)
+		if matched {
+			return patternRoute
+		}
+	}
+
+	return tr.fallback
+}
+
+// Close will close all the registered handlers, each one only once
+func (tr *topicRouter) Close() error {
+	tr.mut.RLock()
+	defer tr.mut.RUnlock()
+
+	handlers := make([]PayloadHandler, 0, len(tr.exactRoutes)+len(tr.prefixRoutes)+len(tr.patternRoutes)+1)
+	for _, exactRoute := range tr.exactRoutes {
+		handlers = append(handlers, exactRoute.handler)
+	}
+	for _, prefixRoute := range tr.prefixRoutes {
+		handlers = append(handlers, prefixRoute.handler)
+	}
+	for _, patternRoute := range tr.patternRoutes {
+		handlers = append(handlers, patternRoute.handler)
+	}
+	if tr.fallback != nil {
+		handlers = append(handlers, tr.fallback.handler)
+	}
+
+	return closeHandlersOnce(handlers, tr.log)
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (tr *topicRouter) IsInterfaceNil() bool {
+	return tr == nil
+}
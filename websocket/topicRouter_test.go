@@ -0,0 +1,152 @@
+package websocket
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/testscommon"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	"github.com/subrahamanyam341/andes-core-16/core"
+)
+
+func createNamedHandler(name string, calls *[]string) *testscommon.PayloadHandlerStub {
+	return &testscommon.PayloadHandlerStub{
+		ProcessPayloadCalled: func(_ []byte, topic string, _ uint32) error {
+			*calls = append(*calls, name+":"+topic)
+			return nil
+		},
+	}
+}
+
+func TestNewTopicRouter(t *testing.T) {
+	t.Parallel()
+
+	router, err := NewTopicRouter(nil)
+	require.Nil(t, router)
+	require.Equal(t, core.ErrNilLogger, err)
+
+	router, err = NewTopicRouter(&testscommon.LoggerMock{})
+	require.Nil(t, err)
+	require.False(t, router.IsInterfaceNil())
+}
+
+func TestTopicRouter_RegisterHandler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty pattern, should return error", func(t *testing.T) {
+		router, _ := NewTopicRouter(&testscommon.LoggerMock{})
+		err := router.RegisterHandler("", &testscommon.PayloadHandlerStub{}, data.PropagateErrorPolicy)
+		require.Equal(t, data.ErrEmptyTopicPattern, err)
+	})
+	t.Run("nil handler, should return error", func(t *testing.T) {
+		router, _ := NewTopicRouter(&testscommon.LoggerMock{})
+		err := router.RegisterHandler("topic", nil, data.PropagateErrorPolicy)
+		require.Equal(t, data.ErrNilPayloadProcessor, err)
+	})
+	t.Run("unknown error policy, should return error", func(t *testing.T) {
+		router, _ := NewTopicRouter(&testscommon.LoggerMock{})
+		err := router.RegisterHandler("topic", &testscommon.PayloadHandlerStub{}, data.ErrorPolicy(10))
+		require.Equal(t, data.ErrUnknownErrorPolicy, err)
+	})
+	t.Run("malformed pattern, should return error", func(t *testing.T) {
+		router, _ := NewTopicRouter(&testscommon.LoggerMock{})
+		err := router.RegisterHandler("topic[", &testscommon.PayloadHandlerStub{}, data.PropagateErrorPolicy)
+		require.NotNil(t, err)
+	})
+	t.Run("already registered pattern, should return error", func(t *testing.T) {
+		router, _ := NewTopicRouter(&testscommon.LoggerMock{})
+		for _, pattern := range []string{"topic", "prefix*", "*Block"} {
+			err := router.RegisterHandler(pattern, &testscommon.PayloadHandlerStub{}, data.PropagateErrorPolicy)
+			require.Nil(t, err)
+			err = router.RegisterHandler(pattern, &testscommon.PayloadHandlerStub{}, data.PropagateErrorPolicy)
+			require.True(t, errors.Is(err, data.ErrTopicPatternAlreadyRegistered))
+		}
+	})
+}
+
+func TestTopicRouter_ProcessPayloadShouldRouteByPriority(t *testing.T) {
+	t.Parallel()
+
+	calls := make([]string, 0)
+	router, _ := NewTopicRouter(&testscommon.LoggerMock{})
+	_ = router.RegisterHandler("SaveBlock", createNamedHandler("exact", &calls), data.PropagateErrorPolicy)
+	_ = router.RegisterHandler("Save*", createNamedHandler("short prefix", &calls), data.PropagateErrorPolicy)
+	_ = router.RegisterHandler("SaveAcc*", createNamedHandler("long prefix", &calls), data.PropagateErrorPolicy)
+	_ = router.RegisterHandler("*Block", createNamedHandler("wildcard", &calls), data.PropagateErrorPolicy)
+	_ = router.SetFallbackHandler(createNamedHandler("fallback", &calls), data.PropagateErrorPolicy)
+
+	for _, topic := range []string{"SaveBlock", "SaveRounds", "SaveAccounts", "FinalizedBlock", "RevertIndexedBlock", "Unknown"} {
+		err := router.ProcessPayload([]byte("payload"), topic, 1)
+		require.Nil(t, err)
+	}
+
+	expectedCalls := []string{
+		"exact:SaveBlock",
+		"short prefix:SaveRounds",
+		"long prefix:SaveAccounts",
+		"wildcard:FinalizedBlock",
+		"wildcard:RevertIndexedBlock",
+		"fallback:Unknown",
+	}
+	require.Equal(t, expectedCalls, calls)
+}
+
+func TestTopicRouter_ProcessPayloadWithoutMatchingHandlerShouldError(t *testing.T) {
+	t.Parallel()
+
+	router, _ := NewTopicRouter(&testscommon.LoggerMock{})
+	_ = router.RegisterHandler("SaveBlock", &testscommon.PayloadHandlerStub{}, data.PropagateErrorPolicy)
+
+	err := router.ProcessPayload([]byte("payload"), "Unknown", 1)
+	require.True(t, errors.Is(err, data.ErrNoHandlerForTopic))
+}
+
+func TestTopicRouter_ProcessPayloadShouldApplyTheErrorPolicy(t *testing.T) {
+	t.Parallel()
+
+	expectedErr := errors.New("expected error")
+	failingHandler := &testscommon.PayloadHandlerStub{
+		ProcessPayloadCalled: func(_ []byte, _ string, _ uint32) error {
+			return expectedErr
+		},
+	}
+
+	router, _ := NewTopicRouter(&testscommon.LoggerMock{})
+	_ = router.RegisterHandler("propagated", failingHandler, data.PropagateErrorPolicy)
+	_ = router.RegisterHandler("ignored", failingHandler, data.IgnoreErrorPolicy)
+
+	err := router.ProcessPayload([]byte("payload"), "propagated", 1)
+	require.Equal(t, expectedErr, err)
+
+	err = router.ProcessPayload([]byte("payload"), "ignored", 1)
+	require.Nil(t, err)
+}
+
+func TestTopicRouter_CloseShouldCloseEveryHandlerOnce(t *testing.T) {
+	t.Parallel()
+
+	numSharedCloses := 0
+	sharedHandler := &testscommon.PayloadHandlerStub{
+		CloseCalled: func() error {
+			numSharedCloses++
+			return nil
+		},
+	}
+	expectedErr := errors.New("expected error")
+	failingHandler := &testscommon.PayloadHandlerStub{
+		CloseCalled: func() error {
+			return expectedErr
+		},
+	}
+
+	router, _ := NewTopicRouter(&testscommon.LoggerMock{})
+	_ = router.RegisterHandler("SaveBlock", sharedHandler, data.PropagateErrorPolicy)
+	_ = router.RegisterHandler("Save*", sharedHandler, data.PropagateErrorPolicy)
+	_ = router.RegisterHandler("*Block", failingHandler, data.PropagateErrorPolicy)
+	_ = router.SetFallbackHandler(sharedHandler, data.IgnoreErrorPolicy)
+
+	err := router.Close()
+	require.Equal(t, expectedErr, err)
+	require.Equal(t, 1, numSharedCloses)
+}
@@ -0,0 +1,133 @@
+package transceiver
+
+import (
+	"fmt"
+	"sync"
+
+	webSocket "github.com/subrahamanyam341/andes-communication/websocket"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+)
+
+// ackFuture holds the outcome of an asynchronous send: it completes when the acknowledgement is received, when the
+// acknowledgement timeout is reached or when the transceiver is closed
+type ackFuture struct {
+	once   sync.Once
+	chDone chan struct{}
+	err    error
+}
+
+func newAckFuture() *ackFuture {
+	return &ackFuture{
+		chDone: make(chan struct{}),
+	}
+}
+
+// NewCompletedAckFuture will create a future that already holds the provided outcome
+func NewCompletedAckFuture(err error) *ackFuture {
+	future := newAckFuture()
+	future.complete(err)
+
+	return future
+}
+
+// NewPendingAckFuture will create a future that holds the outcome provided later through Complete
+func NewPendingAckFuture() *ackFuture {
+	return newAckFuture()
+}
+
+// NewAckFutureGroup will create a future that completes once all the provided futures completed. It holds nil if at
+// least one of the futures succeeded, otherwise the last error
+func NewAckFutureGroup(futures []webSocket.AckFuture) *ackFuture {
+	group := newAckFuture()
+	go func() {
+		var lastErr error
+		numSucceeded := 0
+		for _, future := range futures {
+			err := future.Wait()
+			if err != nil {
+				lastErr = err
+				continue
+			}
+			numSucceeded++
+		}
+
+		if numSucceeded > 0 || len(futures) == 0 {
+			lastErr = nil
+		}
+		group.complete(lastErr)
+	}()
+
+	return group
+}
+
+// NewAckFutureQuorum will create a future that holds nil as soon as the provided number of futures succeeded, or an
+// error as soon as too many of them failed for the quorum to be reached
+func NewAckFutureQuorum(futures []webSocket.AckFuture, quorum int) *ackFuture {
+	if quorum > len(futures) {
+		return NewCompletedAckFuture(fmt.Errorf("%w: %d acknowledgements required, %d receivers", data.ErrAckQuorumNotReached, quorum, len(futures)))
+	}
+
+	chResults := make(chan error, len(futures))
+	for _, future := range futures {
+		go func(future webSocket.AckFuture) {
+			chResults <- future.Wait()
+		}(future)
+	}
+
+	group := newAckFuture()
+	go func() {
+		numSucceeded := 0
+		numFailed := 0
+		for numSucceeded < quorum {
+			err := <-chResults
+			if err == nil {
+				numSucceeded++
+				continue
+			}
+
+			numFailed++
+			if len(futures)-numFailed < quorum {
+				group.complete(fmt.Errorf("%w: %s", data.ErrAckQuorumNotReached, err.Error()))
+				return
+			}
+		}
+
+		group.complete(nil)
+	}()
+
+	return group
+}
+
+func (af *ackFuture) complete(err error) {
+	af.once.Do(func() {
+		af.err = err
+		close(af.chDone)
+	})
+}
+
+// Complete will set the outcome of the future. Only the first provided outcome is kept
+func (af *ackFuture) Complete(err error) {
+	af.complete(err)
+}
+
+// Done returns a channel that is closed once the outcome of the send is known
+func (af *ackFuture) Done() <-chan struct{} {
+	return af.chDone
+}
+
+// Err returns the outcome of the send. It returns nil while the send is still in progress
+func (af *ackFuture) Err() error {
+	select {
+	case <-af.chDone:
+		return af.err
+	default:
+		return nil
+	}
+}
+
+// Wait blocks until the outcome of the send is known and returns it
+func (af *ackFuture) Wait() error {
+	<-af.chDone
+
+	return af.err
+}
@@ -0,0 +1,107 @@
+package transceiver
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	webSocket "github.com/subrahamanyam341/andes-communication/websocket"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+)
+
+func TestAckFuture(t *testing.T) {
+	t.Parallel()
+
+	future := newAckFuture()
+	require.Nil(t, future.Err())
+
+	expectedErr := errors.New("expected error")
+	future.complete(expectedErr)
+	future.complete(nil)
+
+	<-future.Done()
+	require.Equal(t, expectedErr, future.Err())
+	require.Equal(t, expectedErr, future.Wait())
+}
+
+func TestNewAckFutureGroup(t *testing.T) {
+	t.Parallel()
+
+	expectedErr := errors.New("expected error")
+
+	t.Run("no futures, should complete without error", func(t *testing.T) {
+		t.Parallel()
+
+		group := NewAckFutureGroup(nil)
+		require.Nil(t, group.Wait())
+	})
+	t.Run("all futures failed, should return the error", func(t *testing.T) {
+		t.Parallel()
+
+		group := NewAckFutureGroup([]webSocket.AckFuture{
+			NewCompletedAckFuture(expectedErr),
+			NewCompletedAckFuture(expectedErr),
+		})
+		require.Equal(t, expectedErr, group.Wait())
+	})
+	t.Run("one future succeeded, should wait for all and work", func(t *testing.T) {
+		t.Parallel()
+
+		pending := newAckFuture()
+		group := NewAckFutureGroup([]webSocket.AckFuture{
+			NewCompletedAckFuture(expectedErr),
+			pending,
+		})
+
+		select {
+		case <-group.Done():
+			require.Fail(t, "should have waited for all the futures")
+		default:
+		}
+
+		pending.complete(nil)
+		require.Nil(t, group.Wait())
+	})
+}
+
+func TestNewAckFutureQuorum(t *testing.T) {
+	t.Parallel()
+
+	expectedErr := errors.New("expected error")
+
+	t.Run("no quorum, should complete without error", func(t *testing.T) {
+		t.Parallel()
+
+		quorum := NewAckFutureQuorum(nil, 0)
+		require.Nil(t, quorum.Wait())
+	})
+	t.Run("quorum larger than the number of futures, should return error", func(t *testing.T) {
+		t.Parallel()
+
+		quorum := NewAckFutureQuorum([]webSocket.AckFuture{NewCompletedAckFuture(nil)}, 2)
+		require.True(t, errors.Is(quorum.Wait(), data.ErrAckQuorumNotReached))
+	})
+	t.Run("quorum reached, should complete without waiting for the other futures", func(t *testing.T) {
+		t.Parallel()
+
+		quorum := NewAckFutureQuorum([]webSocket.AckFuture{
+			NewCompletedAckFuture(nil),
+			NewCompletedAckFuture(expectedErr),
+			newAckFuture(),
+			NewCompletedAckFuture(nil),
+		}, 2)
+		require.Nil(t, quorum.Wait())
+	})
+	t.Run("too many futures failed, should return error without waiting for the other futures", func(t *testing.T) {
+		t.Parallel()
+
+		quorum := NewAckFutureQuorum([]webSocket.AckFuture{
+			NewCompletedAckFuture(expectedErr),
+			newAckFuture(),
+			NewCompletedAckFuture(expectedErr),
+		}, 2)
+		err := quorum.Wait()
+		require.True(t, errors.Is(err, data.ErrAckQuorumNotReached))
+		require.ErrorContains(t, err, expectedErr.Error())
+	})
+}
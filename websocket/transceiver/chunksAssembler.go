@@ -0,0 +1,149 @@
+package transceiver
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+)
+
+const (
+	// defaultMaxReassemblySize is the maximum accumulated size of the chunks waiting for the rest of their messages,
+	// when none is configured
+	defaultMaxReassemblySize = 256 * 1024 * 1024
+	// defaultReassemblyTimeout is the duration a message waits for its next chunk, when none is configured
+	defaultReassemblyTimeout = 30 * time.Second
+	// maxPartialMessages is the maximum number of messages waiting for the rest of their chunks at the same time
+	maxPartialMessages = 128
+	// chunkOverheadSize is the memory kept for every received chunk besides its payload, counted against the maximum
+	// reassembly size so that empty chunks can not grow the buffer without limits
+	chunkOverheadSize = 64
+)
+
+type partialMessage struct {
+	chunks        map[uint32][]byte
+	total         uint32
+	size          int
+	lastReceiveAt time.Time
+}
+
+// chunksAssembler rebuilds the messages written in chunks, keeping the received chunks within a memory cap and
+// dropping the messages whose next chunk takes too long
+type chunksAssembler struct {
+	mut      sync.Mutex
+	maxSize  int
+	timeout  time.Duration
+	size     int
+	partials map[uint64]*partialMessage
+}
+
+func newChunksAssembler(maxSize int, timeout time.Duration) *chunksAssembler {
+	if maxSize == 0 {
+		maxSize = defaultMaxReassemblySize
+	}
+	if timeout == 0 {
+		timeout = defaultReassemblyTimeout
+	}
+
+	return &chunksAssembler{
+		maxSize:  maxSize,
+		timeout:  timeout,
+		partials: make(map[uint64]*partialMessage),
+	}
+}
+
+// add stores the chunk and returns the whole message once all its chunks were received
+func (ca *chunksAssembler) add(chunk *data.WsMessage) ([]byte, error) {
+	ca.mut.Lock()
+	defer ca.mut.Unlock()
+
+	now := time.Now()
+	ca.removeExpired(now)
+
+	if chunk.ChunkTotal < 2 || chunk.ChunkIndex >= chunk.ChunkTotal {
+		return nil, fmt.Errorf("%w: index %d, total %d", data.ErrInvalidChunk, chunk.ChunkIndex, chunk.ChunkTotal)
+	}
+	if uint64(chunk.ChunkTotal)*chunkOverheadSize > uint64(ca.maxSize) {
+		return nil, fmt.Errorf("%w: total %d can not fit in %d bytes", data.ErrInvalidChunk, chunk.ChunkTotal, ca.maxSize)
+	}
+
+	partial, found := ca.partials[chunk.Counter]
+	if !found {
+		if len(ca.partials) >= maxPartialMessages {
+			return nil, fmt.Errorf("%w: limit %d", data.ErrTooManyPartialMessages, maxPartialMessages)
+		}
+		partial = &partialMessage{
+			chunks: make(map[uint32][]byte),
+			total:  chunk.ChunkTotal,
+		}
+		ca.partials[chunk.Counter] = partial
+	}
+	if partial.total != chunk.ChunkTotal {
+		ca.remove(chunk.Counter)
+		return nil, fmt.Errorf("%w: total %d, previous chunks total %d", data.ErrInvalidChunk, chunk.ChunkTotal, partial.total)
+	}
+	_, duplicated := partial.chunks[chunk.ChunkIndex]
+	if duplicated {
+		return nil, fmt.Errorf("%w: duplicated index %d", data.ErrInvalidChunk, chunk.ChunkIndex)
+	}
+	chunkSize := len(chunk.Payload) + chunkOverheadSize
+	if ca.size+chunkSize > ca.maxSize {
+		ca.remove(chunk.Counter)
+		return nil, fmt.Errorf("%w: limit %d bytes", data.ErrReassemblyBufferFull, ca.maxSize)
+	}
+
+	partial.chunks[chunk.ChunkIndex] = chunk.Payload
+	partial.size += chunkSize
+	partial.lastReceiveAt = now
+	ca.size += chunkSize
+	if uint32(len(partial.chunks)) < partial.total {
+		return nil, nil
+	}
+
+	message := bytes.NewBuffer(make([]byte, 0, partial.size-len(partial.chunks)*chunkOverheadSize))
+	for index := uint32(0); index < partial.total; index++ {
+		message.Write(partial.chunks[index])
+	}
+	ca.remove(chunk.Counter)
+
+	return message.Bytes(), nil
+}
+
+func (ca *chunksAssembler) removeExpired(now time.Time) {
+	for counter, partial := range ca.partials {
+		if now.Sub(partial.lastReceiveAt) > ca.timeout {
+			ca.remove(counter)
+		}
+	}
+}
+
+func (ca *chunksAssembler) remove(counter uint64) {
+	partial, found := ca.partials[counter]
+	if !found {
+		return
+	}
+
+	ca.size -= partial.size
+	delete(ca.partials, counter)
+}
+
+// reset drops the partial messages, as their missing chunks will never arrive on a new connection
+func (ca *chunksAssembler) reset() {
+	ca.mut.Lock()
+	ca.partials = make(map[uint64]*partialMessage)
+	ca.size = 0
+	ca.mut.Unlock()
+}
+
+// splitInChunks returns the parts of the message, each one at most chunkSize long
+func splitInChunks(message []byte, chunkSize int) [][]byte {
+	chunks := make([][]byte, 0, (len(message)+chunkSize-1)/chunkSize)
+	for len(message) > chunkSize {
+		chunks = append(chunks, message[:chunkSize])
+		message = message[chunkSize:]
+	}
+
+	return append(chunks, message)
+}
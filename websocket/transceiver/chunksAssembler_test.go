@@ -0,0 +1,153 @@
+package transceiver
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+)
+
+func createChunk(counter uint64, index uint32, total uint32, payload string) *data.WsMessage {
+	return &data.WsMessage{
+		Type:       data.ChunkMessage,
+		Counter:    counter,
+		ChunkIndex: index,
+		ChunkTotal: total,
+		Payload:    []byte(payload),
+	}
+}
+
+func TestChunksAssembler_Add(t *testing.T) {
+	t.Parallel()
+
+	t.Run("chunks in any order, should reassemble the message", func(t *testing.T) {
+		assembler := newChunksAssembler(0, 0)
+
+		message, err := assembler.add(createChunk(1, 2, 3, "c"))
+		require.Nil(t, err)
+		require.Nil(t, message)
+		message, err = assembler.add(createChunk(2, 0, 2, "x"))
+		require.Nil(t, err)
+		require.Nil(t, message)
+		message, err = assembler.add(createChunk(1, 0, 3, "a"))
+		require.Nil(t, err)
+		require.Nil(t, message)
+		message, err = assembler.add(createChunk(1, 1, 3, "b"))
+		require.Nil(t, err)
+		require.Equal(t, []byte("abc"), message)
+		require.Equal(t, 1+chunkOverheadSize, assembler.size)
+		require.Len(t, assembler.partials, 1)
+	})
+	t.Run("index out of range, should return error", func(t *testing.T) {
+		assembler := newChunksAssembler(0, 0)
+
+		_, err := assembler.add(createChunk(1, 2, 2, "a"))
+		require.True(t, errors.Is(err, data.ErrInvalidChunk))
+		_, err = assembler.add(createChunk(1, 0, 1, "a"))
+		require.True(t, errors.Is(err, data.ErrInvalidChunk))
+		require.Empty(t, assembler.partials)
+	})
+	t.Run("total not matching the previous chunks, should return error and drop the message", func(t *testing.T) {
+		assembler := newChunksAssembler(0, 0)
+
+		_, _ = assembler.add(createChunk(1, 0, 2, "a"))
+		_, err := assembler.add(createChunk(1, 1, 3, "b"))
+		require.True(t, errors.Is(err, data.ErrInvalidChunk))
+		require.Empty(t, assembler.partials)
+		require.Zero(t, assembler.size)
+	})
+	t.Run("duplicated chunk, should return error", func(t *testing.T) {
+		assembler := newChunksAssembler(0, 0)
+
+		_, _ = assembler.add(createChunk(1, 0, 2, "a"))
+		_, err := assembler.add(createChunk(1, 0, 2, "a"))
+		require.True(t, errors.Is(err, data.ErrInvalidChunk))
+
+		message, err := assembler.add(createChunk(1, 1, 2, "b"))
+		require.Nil(t, err)
+		require.Equal(t, []byte("ab"), message)
+	})
+	t.Run("reassembly size exceeded, should return error and drop the message", func(t *testing.T) {
+		assembler := newChunksAssembler(3*chunkOverheadSize+5, 0)
+
+		_, _ = assembler.add(createChunk(1, 0, 3, "aa"))
+		_, _ = assembler.add(createChunk(2, 0, 2, "xx"))
+		_, err := assembler.add(createChunk(1, 1, 3, "bb"))
+		require.True(t, errors.Is(err, data.ErrReassemblyBufferFull))
+		require.Equal(t, 2+chunkOverheadSize, assembler.size)
+
+		message, err := assembler.add(createChunk(2, 1, 2, "yy"))
+		require.Nil(t, err)
+		require.Equal(t, []byte("xxyy"), message)
+	})
+	t.Run("next chunk too late, should drop the message", func(t *testing.T) {
+		assembler := newChunksAssembler(0, 10*time.Millisecond)
+
+		_, _ = assembler.add(createChunk(1, 0, 2, "a"))
+		time.Sleep(20 * time.Millisecond)
+		message, err := assembler.add(createChunk(2, 0, 2, "x"))
+		require.Nil(t, err)
+		require.Nil(t, message)
+		require.Len(t, assembler.partials, 1)
+		require.Equal(t, 1+chunkOverheadSize, assembler.size)
+	})
+	t.Run("total not fitting the reassembly size, should return error", func(t *testing.T) {
+		assembler := newChunksAssembler(0, 0)
+
+		_, err := assembler.add(createChunk(1, 0, math.MaxUint32, ""))
+		require.True(t, errors.Is(err, data.ErrInvalidChunk))
+		require.Empty(t, assembler.partials)
+	})
+	t.Run("empty chunks, should count against the reassembly size", func(t *testing.T) {
+		assembler := newChunksAssembler(4*chunkOverheadSize, 0)
+
+		for counter := uint64(0); counter < 4; counter++ {
+			_, err := assembler.add(createChunk(counter, 0, 2, ""))
+			require.Nil(t, err)
+		}
+		_, err := assembler.add(createChunk(4, 0, 2, ""))
+		require.True(t, errors.Is(err, data.ErrReassemblyBufferFull))
+		require.Len(t, assembler.partials, 4)
+		require.Equal(t, 4*chunkOverheadSize, assembler.size)
+	})
+	t.Run("too many partial messages, should return error", func(t *testing.T) {
+		assembler := newChunksAssembler(0, 0)
+
+		for counter := uint64(0); counter < maxPartialMessages; counter++ {
+			_, err := assembler.add(createChunk(counter, 0, 2, "a"))
+			require.Nil(t, err)
+		}
+		_, err := assembler.add(createChunk(maxPartialMessages, 0, 2, "a"))
+		require.True(t, errors.Is(err, data.ErrTooManyPartialMessages))
+		require.Len(t, assembler.partials, maxPartialMessages)
+
+		// the chunks of the messages already waiting are still accepted
+		message, err := assembler.add(createChunk(0, 1, 2, "b"))
+		require.Nil(t, err)
+		require.Equal(t, []byte("ab"), message)
+	})
+}
+
+func TestChunksAssembler_Reset(t *testing.T) {
+	t.Parallel()
+
+	assembler := newChunksAssembler(0, 0)
+	_, _ = assembler.add(createChunk(1, 0, 2, "a"))
+	assembler.reset()
+	require.Empty(t, assembler.partials)
+	require.Zero(t, assembler.size)
+
+	message, err := assembler.add(createChunk(1, 1, 2, "b"))
+	require.Nil(t, err)
+	require.Nil(t, message)
+}
+
+func TestSplitInChunks(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, [][]byte{[]byte("ab"), []byte("cd"), []byte("e")}, splitInChunks([]byte("abcde"), 2))
+	require.Equal(t, [][]byte{[]byte("ab"), []byte("cd")}, splitInChunks([]byte("abcd"), 2))
+}
@@ -0,0 +1,77 @@
+package transceiver
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+)
+
+type inDoubtMessage struct {
+	sequence  uint64
+	messageID string
+}
+
+// outgoingSequencer assigns the session sequences of the outgoing payload messages. The sequence of an identified
+// message that might not have reached the peer is kept aside, so the resend of the message reuses it and the peer can
+// drop it if it was already processed. A message is never matched by its content, as the same content might be sent
+// again on purpose
+type outgoingSequencer struct {
+	mut          sync.Mutex
+	sessionID    string
+	lastSequence uint64
+	inDoubt      []inDoubtMessage
+	maxInDoubt   int
+}
+
+func newOutgoingSequencer(maxInDoubt int) *outgoingSequencer {
+	if maxInDoubt < 1 {
+		maxInDoubt = 1
+	}
+
+	return &outgoingSequencer{
+		sessionID:  createSessionID(),
+		maxInDoubt: maxInDoubt,
+	}
+}
+
+func createSessionID() string {
+	buff := make([]byte, 16)
+	_, _ = rand.Read(buff)
+
+	return hex.EncodeToString(buff)
+}
+
+// next returns the sequence of the message with the provided ID, empty for a message sent only once
+func (sequencer *outgoingSequencer) next(messageID string) uint64 {
+	sequencer.mut.Lock()
+	defer sequencer.mut.Unlock()
+
+	for i, message := range sequencer.inDoubt {
+		if len(messageID) > 0 && message.messageID == messageID {
+			sequencer.inDoubt = append(sequencer.inDoubt[:i], sequencer.inDoubt[i+1:]...)
+			return message.sequence
+		}
+	}
+
+	sequencer.lastSequence++
+	return sequencer.lastSequence
+}
+
+// markInDoubt should be called when it is not known if the message reached the peer. The messages without ID are not
+// remembered, as they are never resent
+func (sequencer *outgoingSequencer) markInDoubt(sequence uint64, messageID string) {
+	if len(messageID) == 0 {
+		return
+	}
+
+	sequencer.mut.Lock()
+	defer sequencer.mut.Unlock()
+
+	if len(sequencer.inDoubt) == sequencer.maxInDoubt {
+		sequencer.inDoubt = sequencer.inDoubt[1:]
+	}
+	sequencer.inDoubt = append(sequencer.inDoubt, inDoubtMessage{
+		sequence:  sequence,
+		messageID: messageID,
+	})
+}
@@ -0,0 +1,49 @@
+package transceiver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutgoingSequencer(t *testing.T) {
+	t.Parallel()
+
+	t.Run("every sequencer has its own session", func(t *testing.T) {
+		first := newOutgoingSequencer(1)
+		second := newOutgoingSequencer(1)
+		require.Len(t, first.sessionID, 32)
+		require.NotEqual(t, first.sessionID, second.sessionID)
+	})
+
+	t.Run("message in doubt resent should reuse its sequence", func(t *testing.T) {
+		sequencer := newOutgoingSequencer(1)
+		require.Equal(t, uint64(1), sequencer.next("a"))
+		require.Equal(t, uint64(2), sequencer.next("b"))
+
+		sequencer.markInDoubt(2, "b")
+		require.Equal(t, uint64(3), sequencer.next("c"))
+		require.Equal(t, uint64(2), sequencer.next("b"))
+		// only once
+		require.Equal(t, uint64(4), sequencer.next("b"))
+	})
+
+	t.Run("messages without ID should never reuse a sequence", func(t *testing.T) {
+		sequencer := newOutgoingSequencer(1)
+		require.Equal(t, uint64(1), sequencer.next(""))
+		sequencer.markInDoubt(1, "")
+		require.Equal(t, uint64(2), sequencer.next(""))
+	})
+
+	t.Run("should keep the most recent messages in doubt", func(t *testing.T) {
+		sequencer := newOutgoingSequencer(2)
+		for _, messageID := range []string{"a", "b", "c"} {
+			sequence := sequencer.next(messageID)
+			sequencer.markInDoubt(sequence, messageID)
+		}
+
+		require.Equal(t, uint64(3), sequencer.next("c"))
+		require.Equal(t, uint64(2), sequencer.next("b"))
+		require.Equal(t, uint64(4), sequencer.next("a"))
+	})
+}
@@ -0,0 +1,138 @@
+package transceiver
+
+import (
+	"sync"
+	"time"
+)
+
+// sequencesWindowSize is the number of sequences, below the highest one processed, remembered for each session. Older
+// sequences can not be checked, so they are processed again rather than risking to drop a message never processed
+const sequencesWindowSize = 1024
+
+type sessionState struct {
+	highestSequence uint64
+	processed       [sequencesWindowSize / 64]uint64
+	lastSeen        time.Time
+}
+
+// sessionsTracker remembers, for each sender session, the highest processed sequence and which of the sequences
+// below it were processed, so the resent messages are recognized even if they arrive on another connection
+type sessionsTracker struct {
+	mut         sync.Mutex
+	sessions    map[string]*sessionState
+	maxSessions int
+}
+
+// NewSessionsTracker will create a sessions tracker that remembers at most the provided number of sessions, the
+// least recently seen one being forgotten first
+func NewSessionsTracker(maxSessions int) *sessionsTracker {
+	if maxSessions < 1 {
+		maxSessions = 1
+	}
+
+	return &sessionsTracker{
+		sessions:    make(map[string]*sessionState),
+		maxSessions: maxSessions,
+	}
+}
+
+// IsDuplicate returns true if the provided sequence was already processed in the provided session. Messages without
+// session, sent by peers that do not know about sessions, are never duplicates
+func (st *sessionsTracker) IsDuplicate(sessionID string, sequence uint64) bool {
+	if len(sessionID) == 0 || sequence == 0 {
+		return false
+	}
+
+	st.mut.Lock()
+	defer st.mut.Unlock()
+
+	state, found := st.sessions[sessionID]
+	if !found || sequence > state.highestSequence {
+		return false
+	}
+	if state.highestSequence-sequence >= sequencesWindowSize {
+		return false
+	}
+
+	return state.isProcessed(sequence)
+}
+
+// MarkProcessed will remember the provided sequence as processed and returns the number of sequences skipped
+// between the previous highest sequence and the provided one
+func (st *sessionsTracker) MarkProcessed(sessionID string, sequence uint64) uint64 {
+	if len(sessionID) == 0 || sequence == 0 {
+		return 0
+	}
+
+	st.mut.Lock()
+	defer st.mut.Unlock()
+
+	state, found := st.sessions[sessionID]
+	if !found {
+		st.evictIfNeeded()
+		// the receiver might have joined an already started session, so there is no gap to report
+		state = &sessionState{highestSequence: sequence}
+		st.sessions[sessionID] = state
+	}
+	state.lastSeen = time.Now()
+
+	numMissing := uint64(0)
+	if sequence > state.highestSequence {
+		numMissing = sequence - state.highestSequence - 1
+		state.advanceTo(sequence)
+	}
+	if state.highestSequence-sequence < sequencesWindowSize {
+		state.setProcessed(sequence)
+	}
+
+	return numMissing
+}
+
+func (st *sessionsTracker) evictIfNeeded() {
+	if len(st.sessions) < st.maxSessions {
+		return
+	}
+
+	oldestSessionID := ""
+	var oldestTime time.Time
+	for sessionID, state := range st.sessions {
+		if oldestSessionID == "" || state.lastSeen.Before(oldestTime) {
+			oldestSessionID = sessionID
+			oldestTime = state.lastSeen
+		}
+	}
+	delete(st.sessions, oldestSessionID)
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (st *sessionsTracker) IsInterfaceNil() bool {
+	return st == nil
+}
+
+// advanceTo forgets the sequences that fall out of the window, the bit of a sequence being reused by the sequence
+// found sequencesWindowSize positions later
+func (state *sessionState) advanceTo(sequence uint64) {
+	if sequence-state.highestSequence >= sequencesWindowSize {
+		state.processed = [sequencesWindowSize / 64]uint64{}
+	} else {
+		for seq := state.highestSequence + 1; seq <= sequence; seq++ {
+			state.clearProcessed(seq)
+		}
+	}
+	state.highestSequence = sequence
+}
+
+func (state *sessionState) isProcessed(sequence uint64) bool {
+	bit := sequence % sequencesWindowSize
+	return state.processed[bit/64]&(1<<(bit%64)) != 0
+}
+
+func (state *sessionState) setProcessed(sequence uint64) {
+	bit := sequence % sequencesWindowSize
+	state.processed[bit/64] |= 1 << (bit % 64)
+}
+
+func (state *sessionState) clearProcessed(sequence uint64) {
+	bit := sequence % sequencesWindowSize
+	state.processed[bit/64] &^= 1 << (bit % 64)
+}
@@ -0,0 +1,82 @@
+package transceiver
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionsTracker_MessagesWithoutSessionAreNeverDuplicates(t *testing.T) {
+	t.Parallel()
+
+	tracker := NewSessionsTracker(10)
+	require.Zero(t, tracker.MarkProcessed("", 1))
+	require.False(t, tracker.IsDuplicate("", 1))
+	require.Zero(t, tracker.MarkProcessed("session", 0))
+	require.False(t, tracker.IsDuplicate("session", 0))
+}
+
+func TestSessionsTracker_ShouldDetectDuplicatesAndGaps(t *testing.T) {
+	t.Parallel()
+
+	tracker := NewSessionsTracker(10)
+	require.False(t, tracker.IsDuplicate("session", 5))
+	// the first sequence seen in a session does not report a gap
+	require.Zero(t, tracker.MarkProcessed("session", 5))
+	require.True(t, tracker.IsDuplicate("session", 5))
+
+	require.Zero(t, tracker.MarkProcessed("session", 6))
+	require.Equal(t, uint64(2), tracker.MarkProcessed("session", 9))
+	require.True(t, tracker.IsDuplicate("session", 6))
+	require.True(t, tracker.IsDuplicate("session", 9))
+
+	// the missing sequences arrive later
+	require.False(t, tracker.IsDuplicate("session", 7))
+	require.Zero(t, tracker.MarkProcessed("session", 7))
+	require.True(t, tracker.IsDuplicate("session", 7))
+	require.False(t, tracker.IsDuplicate("session", 8))
+	require.False(t, tracker.IsDuplicate("session", 10))
+
+	// the sessions are independent
+	require.False(t, tracker.IsDuplicate("other session", 6))
+}
+
+func TestSessionsTracker_SequencesOutOfTheWindowAreNotDuplicates(t *testing.T) {
+	t.Parallel()
+
+	tracker := NewSessionsTracker(10)
+	tracker.MarkProcessed("session", 1)
+	tracker.MarkProcessed("session", 3)
+	require.False(t, tracker.IsDuplicate("session", 2))
+
+	tracker.MarkProcessed("session", 3+sequencesWindowSize-1)
+	require.False(t, tracker.IsDuplicate("session", 4))
+	require.True(t, tracker.IsDuplicate("session", 3))
+	require.False(t, tracker.IsDuplicate("session", 2))
+
+	tracker.MarkProcessed("session", 10*sequencesWindowSize)
+	require.False(t, tracker.IsDuplicate("session", 3+sequencesWindowSize-1))
+	require.False(t, tracker.IsDuplicate("session", 10*sequencesWindowSize-1))
+
+	// a sequence out of the window must not be remembered, its bit belongs to a sequence in the window
+	tracker.MarkProcessed("session", 5)
+	require.False(t, tracker.IsDuplicate("session", 9*sequencesWindowSize+5))
+}
+
+func TestSessionsTracker_ShouldForgetTheLeastRecentlySeenSession(t *testing.T) {
+	t.Parallel()
+
+	tracker := NewSessionsTracker(3)
+	for i := 0; i < 3; i++ {
+		tracker.MarkProcessed(fmt.Sprintf("session%d", i), 1)
+	}
+	tracker.MarkProcessed("session0", 2)
+
+	tracker.MarkProcessed("session3", 1)
+	require.Len(t, tracker.sessions, 3)
+	require.True(t, tracker.IsDuplicate("session0", 1))
+	require.False(t, tracker.IsDuplicate("session1", 1))
+	require.True(t, tracker.IsDuplicate("session2", 1))
+	require.True(t, tracker.IsDuplicate("session3", 1))
+}
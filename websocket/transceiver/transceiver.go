@@ -1,14 +1,18 @@
 package transceiver
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
 	webSocket "github.com/subrahamanyam341/andes-communication/websocket"
 	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	"github.com/subrahamanyam341/andes-communication/websocket/versioning"
 	"github.com/subrahamanyam341/andes-core-16/core"
 	"github.com/subrahamanyam341/andes-core-16/core/check"
 	"github.com/subrahamanyam341/andes-core-16/core/closing"
@@ -16,29 +20,79 @@ import (
 
 // ArgsTransceiver holds the arguments that are needed for a transceiver
 type ArgsTransceiver struct {
-	PayloadConverter   webSocket.PayloadConverter
-	Log                core.Logger
-	RetryDurationInSec int
-	AckTimeoutInSec    int
-	BlockingAckOnError bool
-	WithAcknowledge    bool
-	PayloadVersion     uint32
+	PayloadConverter      webSocket.PayloadConverter
+	Log                   core.Logger
+	RetryDurationInSec    int
+	AckTimeoutInSec       int
+	BlockingAckOnError    bool
+	WithAcknowledge       bool
+	PayloadVersion        uint32
+	MinPayloadVersion     uint32
+	AckWindowSize         int
+	SubscriptionsHandler  webSocket.SubscriptionsHandler
+	TextFrames            bool
+	SessionsTracker       webSocket.SessionsTracker
+	Metrics               webSocket.MetricsHandler
+	ChunkSizeInBytes      int
+	MaxReassemblySize     int
+	ReassemblyTimeout     time.Duration
+	MessageSigner         webSocket.MessageSigner
+	MessageVerifier       webSocket.MessageVerifier
+	MaxConcurrentRequests int
+}
+
+const (
+	// defaultMaxTrackedSessions is the number of peer sessions remembered when no sessions tracker is provided, as a
+	// client talks to a single peer at a time
+	defaultMaxTrackedSessions = 16
+	// defaultMaxConcurrentRequests is the number of requests handled at the same time, when none is configured
+	defaultMaxConcurrentRequests = 64
+)
+
+type pendingAck struct {
+	future    *ackFuture
+	timer     *time.Timer
+	message   *data.WsMessage
+	messageID string
+	sentAt    time.Time
 }
 
 type wsTransceiver struct {
-	payloadParser      webSocket.PayloadConverter
-	payloadHandler     webSocket.PayloadHandler
-	mutPayloadHandler  sync.RWMutex
-	log                core.Logger
-	safeCloser         core.SafeCloser
-	retryDuration      time.Duration
-	ackTimeout         time.Duration
-	mapAck             map[uint64]chan struct{}
-	mutMapAck          sync.Mutex
-	counter            uint64
-	blockingAckOnError bool
-	withAcknowledge    bool
-	payloadVersion     uint32
+	payloadParser         webSocket.PayloadConverter
+	payloadHandler        webSocket.PayloadHandler
+	mutPayloadHandler     sync.RWMutex
+	log                   core.Logger
+	safeCloser            core.SafeCloser
+	retryDuration         time.Duration
+	ackTimeout            time.Duration
+	mapAck                map[uint64]chan struct{}
+	pendingAcks           map[uint64]*pendingAck
+	mutMapAck             sync.Mutex
+	mutSend               sync.Mutex
+	ackWindow             chan struct{}
+	counter               uint64
+	blockingAckOnError    bool
+	withAcknowledge       bool
+	payloadVersion        atomic.Uint32
+	minPayloadVersion     uint32
+	maxPayloadVersion     uint32
+	cumulativeAckDisabled atomic.Bool
+	subscriptionsHandler  webSocket.SubscriptionsHandler
+	requestHandler        webSocket.RequestHandler
+	mutRequestHandler     sync.RWMutex
+	pendingRequests       map[uint64]chan *data.WsMessage
+	mutPendingRequests    sync.Mutex
+	requestCounter        uint64
+	requestWorkers        chan struct{}
+	messageType           int
+	sequencer             *outgoingSequencer
+	sessionsTracker       webSocket.SessionsTracker
+	metrics               webSocket.MetricsHandler
+	chunkSize             int
+	chunkCounter          atomic.Uint64
+	chunksAssembler       *chunksAssembler
+	messageSigner         webSocket.MessageSigner
+	messageVerifier       webSocket.MessageVerifier
 }
 
 // NewTransceiver will create a new instance of transceiver
@@ -48,18 +102,51 @@ func NewTransceiver(args ArgsTransceiver) (*wsTransceiver, error) {
 		return nil, err
 	}
 
-	return &wsTransceiver{
-		log:                args.Log,
-		retryDuration:      time.Duration(args.RetryDurationInSec) * time.Second,
-		ackTimeout:         time.Duration(args.AckTimeoutInSec) * time.Second,
-		blockingAckOnError: args.BlockingAckOnError,
-		safeCloser:         closing.NewSafeChanCloser(),
-		payloadHandler:     webSocket.NewNilPayloadHandler(),
-		payloadParser:      args.PayloadConverter,
-		withAcknowledge:    args.WithAcknowledge,
-		payloadVersion:     args.PayloadVersion,
-		mapAck:             make(map[uint64]chan struct{}),
-	}, nil
+	wt := &wsTransceiver{
+		log:                  args.Log,
+		retryDuration:        time.Duration(args.RetryDurationInSec) * time.Second,
+		ackTimeout:           time.Duration(args.AckTimeoutInSec) * time.Second,
+		blockingAckOnError:   args.BlockingAckOnError,
+		safeCloser:           closing.NewSafeChanCloser(),
+		payloadHandler:       webSocket.NewNilPayloadHandler(),
+		payloadParser:        args.PayloadConverter,
+		withAcknowledge:      args.WithAcknowledge,
+		minPayloadVersion:    args.MinPayloadVersion,
+		maxPayloadVersion:    args.PayloadVersion,
+		mapAck:               make(map[uint64]chan struct{}),
+		pendingAcks:          make(map[uint64]*pendingAck),
+		subscriptionsHandler: args.SubscriptionsHandler,
+		requestHandler:       webSocket.NewNilRequestHandler(),
+		pendingRequests:      make(map[uint64]chan *data.WsMessage),
+		messageType:          websocket.BinaryMessage,
+		sequencer:            newOutgoingSequencer(args.AckWindowSize),
+		sessionsTracker:      args.SessionsTracker,
+		metrics:              args.Metrics,
+		chunkSize:            args.ChunkSizeInBytes,
+		chunksAssembler:      newChunksAssembler(args.MaxReassemblySize, args.ReassemblyTimeout),
+		messageSigner:        args.MessageSigner,
+		messageVerifier:      args.MessageVerifier,
+	}
+	wt.payloadVersion.Store(args.PayloadVersion)
+	if check.IfNil(wt.sessionsTracker) {
+		wt.sessionsTracker = NewSessionsTracker(defaultMaxTrackedSessions)
+	}
+	if check.IfNil(wt.metrics) {
+		wt.metrics = webSocket.NewNilMetricsHandler()
+	}
+	if args.TextFrames {
+		wt.messageType = websocket.TextMessage
+	}
+	if args.AckWindowSize > 0 {
+		wt.ackWindow = make(chan struct{}, args.AckWindowSize)
+	}
+	maxConcurrentRequests := args.MaxConcurrentRequests
+	if maxConcurrentRequests == 0 {
+		maxConcurrentRequests = defaultMaxConcurrentRequests
+	}
+	wt.requestWorkers = make(chan struct{}, maxConcurrentRequests)
+
+	return wt, nil
 }
 
 func checkArgs(args ArgsTransceiver) error {
@@ -75,6 +162,41 @@ func checkArgs(args ArgsTransceiver) error {
 	if args.WithAcknowledge && args.AckTimeoutInSec == 0 {
 		return data.ErrZeroValueAckTimeout
 	}
+	if args.AckWindowSize < 0 {
+		return data.ErrInvalidAckWindowSize
+	}
+	if args.ChunkSizeInBytes < 0 || args.MaxReassemblySize < 0 || args.ReassemblyTimeout < 0 {
+		return data.ErrInvalidChunkingConfig
+	}
+	if args.MaxConcurrentRequests < 0 {
+		return data.ErrInvalidMaxConcurrentRequests
+	}
+	return versioning.CheckRange(args.MinPayloadVersion, args.PayloadVersion)
+}
+
+// GetNumPendingAcks returns the number of sent messages still waiting for their acknowledgement
+func (wt *wsTransceiver) GetNumPendingAcks() int {
+	wt.mutMapAck.Lock()
+	defer wt.mutMapAck.Unlock()
+
+	return len(wt.mapAck) + len(wt.pendingAcks)
+}
+
+// SetPayloadVersion will set the payload version stamped on the outgoing messages, as negotiated with the peer
+func (wt *wsTransceiver) SetPayloadVersion(version uint32) {
+	wt.payloadVersion.Store(version)
+}
+
+// checkPayloadVersion returns an error if the version of a received message is outside the supported range. Nothing
+// is checked if the negotiation is disabled or the peer did not stamp any version
+func (wt *wsTransceiver) checkPayloadVersion(version uint32) error {
+	if wt.minPayloadVersion == 0 || version == 0 {
+		return nil
+	}
+	if version < wt.minPayloadVersion || version > wt.maxPayloadVersion {
+		return fmt.Errorf("%w: %d, supported %d-%d", data.ErrUnsupportedPayloadVersion, version, wt.minPayloadVersion, wt.maxPayloadVersion)
+	}
+
 	return nil
 }
 
@@ -91,8 +213,26 @@ func (wt *wsTransceiver) SetPayloadHandler(handler webSocket.PayloadHandler) err
 	return nil
 }
 
+// SetRequestHandler will set the request handler
+func (wt *wsTransceiver) SetRequestHandler(handler webSocket.RequestHandler) error {
+	if check.IfNil(handler) {
+		return data.ErrNilRequestHandler
+	}
+
+	wt.mutRequestHandler.Lock()
+	defer wt.mutRequestHandler.Unlock()
+
+	wt.requestHandler = handler
+	return nil
+}
+
 // Listen will listen for messages from the provided connection
 func (wt *wsTransceiver) Listen(connection webSocket.WSConClient) bool {
+	// a new connection means a new stream of messages, so cumulative acknowledgements can be used again
+	wt.cumulativeAckDisabled.Store(false)
+	// the missing chunks of the messages started on a previous connection will never arrive
+	wt.chunksAssembler.reset()
+
 	for {
 		_, message, err := connection.ReadMessage()
 		if err == nil {
@@ -106,9 +246,13 @@ func (wt *wsTransceiver) Listen(connection webSocket.WSConClient) bool {
 			wt.log.Warn("wt.Listen()-> connection problem", "error", err.Error())
 		}
 		if isConnectionClosed {
-			wt.log.Info("received connection close")
+			wt.log.Info("received connection close", "code", closeError.Code, "reason", closeError.Text)
 		}
 
+		// the acknowledgements and the responses of the messages written on this connection will never arrive
+		wt.completeAllPendingAcks(data.ErrConnectionClosedBeforeAck)
+		wt.failAllPendingRequests()
+
 		select {
 		case <-wt.safeCloser.ChanClose():
 			return false
@@ -130,37 +274,338 @@ func (wt *wsTransceiver) verifyPayloadAndSendAckIfNeeded(connection webSocket.WS
 		return
 	}
 
+	if wsMessage.Type == data.ChunkMessage {
+		wt.handleChunkMessage(connection, wsMessage)
+		return
+	}
+
+	wt.handleWsMessage(connection, wsMessage, len(payload))
+}
+
+// handleWsMessage handles a received message that is not a chunk. The size of the message is its size on the wire
+func (wt *wsTransceiver) handleWsMessage(connection webSocket.WSConClient, wsMessage *data.WsMessage, size int) {
 	if wsMessage.Type == data.AckMessage {
 		wt.handleAckMessage(wsMessage.Counter)
 		return
 	}
+	if wsMessage.Type == data.CumulativeAckMessage {
+		wt.handleCumulativeAckMessage(wsMessage.Counter)
+		return
+	}
+
+	if wsMessage.Type == data.SubscribeMessage || wsMessage.Type == data.UnsubscribeMessage {
+		wt.handleSubscriptionMessage(connection, wsMessage)
+		return
+	}
+
+	if wsMessage.Type == data.RequestMessage {
+		wt.metrics.MessageReceived(wsMessage.Topic, size)
+		wt.handleRequestMessage(connection, wsMessage)
+		return
+	}
+	if wsMessage.Type == data.ResponseMessage {
+		wt.handleResponseMessage(wsMessage)
+		return
+	}
 
 	if wsMessage.Type != data.PayloadMessage {
 		wt.log.Debug("received an unknown message type", "message type received", wsMessage.Type)
 		return
 	}
 
-	err = wt.payloadHandler.ProcessPayload(wsMessage.Payload, wsMessage.Topic, wsMessage.Version)
+	wt.metrics.MessageReceived(wsMessage.Topic, size)
+
+	if wt.sessionsTracker.IsDuplicate(wsMessage.SessionID, wsMessage.Sequence) {
+		// the acknowledgement of the first delivery was lost, so the peer still waits for it
+		wt.log.Debug("dropped duplicated message", "session", wsMessage.SessionID, "sequence", wsMessage.Sequence)
+		wt.sendAckIfNeeded(connection, wsMessage)
+		return
+	}
+
+	err := wt.checkPayloadVersion(wsMessage.Version)
+	if err == nil {
+		err = wt.verifySignature(wsMessage)
+	}
+	if err != nil {
+		wt.log.Warn("wt.verifyPayloadAndSendAckIfNeeded: dropped payload", "topic", wsMessage.Topic, "error", err)
+	} else {
+		err = wt.processPayload(wsMessage)
+	}
+	if err != nil {
+		wt.metrics.HandlerFailed(wsMessage.Topic)
+	}
+	if errors.Is(err, data.ErrPayloadRefused) {
+		// the payload was refused, whatever the acknowledgement policy is. The connection might also be already closed,
+		// so an acknowledgement would be retried until the transceiver is closed
+		wt.log.Debug("wt.payloadHandler.ProcessPayload: payload refused", "topic", wsMessage.Topic, "error", err)
+		wt.cumulativeAckDisabled.Store(true)
+		return
+	}
 	if err != nil && wt.blockingAckOnError {
 		wt.log.Warn("wt.payloadHandler.ProcessPayload: cannot handle payload", "error", err)
+		// a later cumulative acknowledgement would also acknowledge this message
+		wt.cumulativeAckDisabled.Store(true)
 		return
 	}
 
+	numMissing := wt.sessionsTracker.MarkProcessed(wsMessage.SessionID, wsMessage.Sequence)
+	if numMissing > 0 {
+		wt.log.Warn("gap detected in the received messages", "session", wsMessage.SessionID,
+			"sequence", wsMessage.Sequence, "missing", numMissing)
+	}
+
 	wt.sendAckIfNeeded(connection, wsMessage)
 }
 
+// processPayload passes the received payload to the payload handler, or the whole message if the handler needs it
+func (wt *wsTransceiver) processPayload(wsMessage *data.WsMessage) error {
+	wt.mutPayloadHandler.RLock()
+	payloadHandler := wt.payloadHandler
+	wt.mutPayloadHandler.RUnlock()
+
+	messageHandler, ok := payloadHandler.(webSocket.PayloadMessageHandler)
+	if ok {
+		return messageHandler.ProcessPayloadMessage(wsMessage)
+	}
+
+	return payloadHandler.ProcessPayload(wsMessage.Payload, wsMessage.Topic, wsMessage.Version)
+}
+
+// verifySignature returns an error if the signature of a received payload or request message is missing or invalid.
+// Nothing is checked if no verifier is set
+func (wt *wsTransceiver) verifySignature(wsMessage *data.WsMessage) error {
+	if check.IfNil(wt.messageVerifier) {
+		return nil
+	}
+
+	err := wt.messageVerifier.Verify(wsMessage)
+	if err != nil {
+		wt.metrics.InvalidSignature(wsMessage.Topic)
+	}
+
+	return err
+}
+
+// handleRequestMessage checks the version and the signature of a received request, like for a payload, then passes it
+// to a free request worker. The request handler might take a while, so the next messages are read in the meantime. A
+// request received while all the workers are busy is answered with an error
+func (wt *wsTransceiver) handleRequestMessage(connection webSocket.WSConClient, wsMessage *data.WsMessage) {
+	err := wt.checkPayloadVersion(wsMessage.Version)
+	if err == nil {
+		err = wt.verifySignature(wsMessage)
+	}
+	if err != nil {
+		wt.log.Warn("wt.handleRequestMessage: rejected request", "topic", wsMessage.Topic, "error", err)
+		wt.sendResponse(connection, wsMessage, nil, &data.RequestError{
+			Code:    data.RequestErrorCodeRejected,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	select {
+	case wt.requestWorkers <- struct{}{}:
+	default:
+		wt.log.Debug("wt.handleRequestMessage: rejected request", "topic", wsMessage.Topic, "error", data.ErrTooManyRequests)
+		wt.sendResponse(connection, wsMessage, nil, &data.RequestError{
+			Code:    data.RequestErrorCodeBusy,
+			Message: data.ErrTooManyRequests.Error(),
+		})
+		return
+	}
+
+	go func() {
+		defer func() {
+			<-wt.requestWorkers
+		}()
+
+		wt.processRequest(connection, wsMessage)
+	}()
+}
+
+func (wt *wsTransceiver) processRequest(connection webSocket.WSConClient, wsMessage *data.WsMessage) {
+	wt.mutRequestHandler.RLock()
+	handler := wt.requestHandler
+	wt.mutRequestHandler.RUnlock()
+
+	payload, err := handler.ProcessRequest(wsMessage.Payload, wsMessage.Topic, wsMessage.Version)
+	wt.sendResponse(connection, wsMessage, payload, err)
+}
+
+// sendResponse answers the provided request with the payload, or with the error if any
+func (wt *wsTransceiver) sendResponse(connection webSocket.WSConClient, wsMessage *data.WsMessage, payload []byte, err error) {
+	response := &data.WsMessage{
+		Type:          data.ResponseMessage,
+		CorrelationID: wsMessage.CorrelationID,
+		Topic:         wsMessage.Topic,
+		Version:       wt.payloadVersion.Load(),
+	}
+	if err != nil {
+		wt.metrics.HandlerFailed(wsMessage.Topic)
+		requestErr := &data.RequestError{
+			Code:    data.RequestErrorCodeInternal,
+			Message: err.Error(),
+		}
+		errors.As(err, &requestErr)
+
+		response.ErrorCode = requestErr.Code
+		response.ErrorMessage = requestErr.Message
+	} else {
+		response.Payload = payload
+	}
+
+	responsePayload, err := wt.payloadParser.ConstructPayload(response)
+	if err != nil {
+		wt.log.Warn("wt.sendResponse: cannot construct the response", "error", err)
+		return
+	}
+
+	err = wt.writeInChunksIfNeeded(connection, responsePayload)
+	if err != nil {
+		wt.log.Debug("wt.sendResponse: cannot send the response", "correlation ID", wsMessage.CorrelationID, "error", err)
+	}
+}
+
+func (wt *wsTransceiver) handleChunkMessage(connection webSocket.WSConClient, wsMessage *data.WsMessage) {
+	payload, err := wt.chunksAssembler.add(wsMessage)
+	if err != nil {
+		wt.log.Warn("wt.handleChunkMessage: dropped chunk", "counter", wsMessage.Counter, "error", err)
+		return
+	}
+	if payload == nil {
+		return
+	}
+
+	message, err := wt.payloadParser.ExtractWsMessage(payload)
+	if err != nil {
+		wt.log.Warn("wt.handleChunkMessage: cannot extract the reassembled message", "counter", wsMessage.Counter, "error", err)
+		return
+	}
+	if message.Type == data.ChunkMessage {
+		// a chunk is never split again, so a reassembled chunk comes from a misbehaving peer
+		wt.log.Warn("wt.handleChunkMessage: dropped the reassembled message", "counter", wsMessage.Counter, "error", data.ErrInvalidChunk)
+		return
+	}
+
+	// the reassembled message is processed and acknowledged as if it was received whole
+	wt.handleWsMessage(connection, message, len(payload))
+}
+
+func (wt *wsTransceiver) handleResponseMessage(wsMessage *data.WsMessage) {
+	wt.mutPendingRequests.Lock()
+	defer wt.mutPendingRequests.Unlock()
+
+	chResponse, found := wt.pendingRequests[wsMessage.CorrelationID]
+	if !found {
+		// the requester might have given up on waiting
+		wt.log.Debug("wsTransceiver.handleResponseMessage unknown correlation ID received", "received", wsMessage.CorrelationID)
+		return
+	}
+
+	chResponse <- wsMessage
+	delete(wt.pendingRequests, wsMessage.CorrelationID)
+}
+
+func (wt *wsTransceiver) failAllPendingRequests() {
+	wt.mutPendingRequests.Lock()
+	defer wt.mutPendingRequests.Unlock()
+
+	for correlationID, chResponse := range wt.pendingRequests {
+		close(chResponse)
+		delete(wt.pendingRequests, correlationID)
+	}
+}
+
+func (wt *wsTransceiver) handleSubscriptionMessage(connection webSocket.WSConClient, wsMessage *data.WsMessage) {
+	if check.IfNil(wt.subscriptionsHandler) {
+		wt.log.Debug("received a subscription message, but subscriptions are not handled", "message type received", wsMessage.Type)
+		return
+	}
+
+	if wsMessage.Type == data.SubscribeMessage {
+		wt.subscriptionsHandler.Subscribe(connection.GetID(), wsMessage.Topics)
+		return
+	}
+
+	wt.subscriptionsHandler.Unsubscribe(connection.GetID(), wsMessage.Topics)
+}
+
 func (wt *wsTransceiver) handleAckMessage(counter uint64) {
 	wt.mutMapAck.Lock()
-	defer wt.mutMapAck.Unlock()
-
 	ch, found := wt.mapAck[counter]
+	if found {
+		close(ch)
+		delete(wt.mapAck, counter)
+		wt.mutMapAck.Unlock()
+		return
+	}
+	wt.mutMapAck.Unlock()
+
+	found = wt.completePendingAck(counter, nil)
 	if !found {
 		wt.log.Warn("wsTransceiver.handleAckMessage invalid counter received", "received", counter)
-		return
 	}
+}
 
-	close(ch)
-	delete(wt.mapAck, counter)
+func (wt *wsTransceiver) handleCumulativeAckMessage(counter uint64) {
+	wt.mutMapAck.Lock()
+	acknowledged := make([]*pendingAck, 0, len(wt.pendingAcks))
+	for pendingCounter, pending := range wt.pendingAcks {
+		if pendingCounter <= counter {
+			acknowledged = append(acknowledged, pending)
+			delete(wt.pendingAcks, pendingCounter)
+		}
+	}
+	wt.mutMapAck.Unlock()
+
+	for _, pending := range acknowledged {
+		wt.finishPendingAck(pending, nil)
+	}
+}
+
+func (wt *wsTransceiver) completePendingAck(counter uint64, err error) bool {
+	wt.mutMapAck.Lock()
+	pending, found := wt.pendingAcks[counter]
+	delete(wt.pendingAcks, counter)
+	wt.mutMapAck.Unlock()
+
+	if !found {
+		return false
+	}
+
+	wt.finishPendingAck(pending, err)
+	return true
+}
+
+func (wt *wsTransceiver) completeAllPendingAcks(err error) {
+	wt.mutMapAck.Lock()
+	pendingAcks := wt.pendingAcks
+	wt.pendingAcks = make(map[uint64]*pendingAck)
+	wt.mutMapAck.Unlock()
+
+	for _, pending := range pendingAcks {
+		wt.finishPendingAck(pending, err)
+	}
+}
+
+func (wt *wsTransceiver) finishPendingAck(pending *pendingAck, err error) {
+	pending.timer.Stop()
+	wt.releaseAckWindowSlot()
+	wt.recordAckOutcome(pending.sentAt, err)
+	if err != nil {
+		wt.sequencer.markInDoubt(pending.message.Sequence, pending.messageID)
+	}
+	pending.future.complete(err)
+}
+
+func (wt *wsTransceiver) recordAckOutcome(sentAt time.Time, err error) {
+	if err == nil {
+		wt.metrics.AckReceived(time.Since(sentAt))
+		return
+	}
+	if errors.Is(err, data.ErrAckTimeout) {
+		wt.metrics.AckTimedOut()
+	}
 }
 
 func (wt *wsTransceiver) sendAckIfNeeded(connection webSocket.WSConClient, wsMessage *data.WsMessage) {
@@ -171,9 +616,14 @@ func (wt *wsTransceiver) sendAckIfNeeded(connection webSocket.WSConClient, wsMes
 	timer := time.NewTimer(wt.retryDuration)
 	defer timer.Stop()
 
+	ackType := data.AckMessage
+	if wsMessage.WithCumulativeAck && !wt.cumulativeAckDisabled.Load() {
+		ackType = data.CumulativeAckMessage
+	}
+
 	ackWsMessage := &data.WsMessage{
 		Counter: wsMessage.Counter,
-		Type:    data.AckMessage,
+		Type:    int32(ackType),
 	}
 	wsMessageBytes, errConstruct := wt.payloadParser.ConstructPayload(ackWsMessage)
 	if errConstruct != nil {
@@ -184,7 +634,7 @@ func (wt *wsTransceiver) sendAckIfNeeded(connection webSocket.WSConClient, wsMes
 	for {
 		timer.Reset(wt.retryDuration)
 
-		err := connection.WriteMessage(websocket.BinaryMessage, wsMessageBytes)
+		err := connection.WriteMessage(wt.messageType, wsMessageBytes)
 		if err == nil {
 			return
 		}
@@ -205,21 +655,273 @@ func (wt *wsTransceiver) sendAckIfNeeded(connection webSocket.WSConClient, wsMes
 
 // Send will prepare and send the provided WsSendArgs
 func (wt *wsTransceiver) Send(payload []byte, topic string, connection webSocket.WSConClient) error {
+	return wt.SendMessage(&data.OutgoingMessage{Payload: payload, Topic: topic}, connection)
+}
+
+// SendMessage will prepare and send the provided message like Send does. A message resent with the ID of a previous
+// attempt that might have reached the peer reuses the sequence of that attempt
+func (wt *wsTransceiver) SendMessage(message *data.OutgoingMessage, connection webSocket.WSConClient) error {
+	if wt.ackWindow != nil {
+		future, err := wt.sendInWindow(message, connection)
+		if err != nil {
+			return err
+		}
+
+		return future.Wait()
+	}
+
 	ch, localCounter := wt.prepareChanAndCounter()
+	wsMessage, err := wt.createPayloadMessage(message, localCounter)
+	if err != nil {
+		wt.removeAckChan(localCounter)
+		return err
+	}
+	newPayload, err := wt.payloadParser.ConstructPayload(wsMessage)
+	if err != nil {
+		wt.removeAckChan(localCounter)
+		wt.sequencer.markInDoubt(wsMessage.Sequence, message.ID)
+		return err
+	}
+
+	err = wt.sendPayload(newPayload, message.Topic, connection, localCounter, ch)
+	if err != nil {
+		wt.sequencer.markInDoubt(wsMessage.Sequence, message.ID)
+	}
+
+	return err
+}
+
+func (wt *wsTransceiver) createPayloadMessage(message *data.OutgoingMessage, counter uint64) (*data.WsMessage, error) {
 	wsMessage := &data.WsMessage{
 		WithAcknowledge: wt.withAcknowledge,
-		Counter:         localCounter,
+		Counter:         counter,
 		Type:            data.PayloadMessage,
-		Payload:         payload,
-		Topic:           topic,
-		Version:         wt.payloadVersion,
+		Payload:         message.Payload,
+		Topic:           message.Topic,
+		Version:         wt.payloadVersion.Load(),
+		SessionID:       wt.sequencer.sessionID,
+	}
+	if len(message.Signature) > 0 {
+		wsMessage.Signature = message.Signature
+		wsMessage.SignerPublicKey = message.SignerPublicKey
+	} else if !check.IfNil(wt.messageSigner) {
+		err := wt.messageSigner.Sign(wsMessage)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// the sequence is taken last, so a message that could not be created does not leave a gap
+	wsMessage.Sequence = wt.sequencer.next(message.ID)
+
+	return wsMessage, nil
+}
+
+// Request will send the provided payload as a request and will wait for the response until the context is done
+func (wt *wsTransceiver) Request(ctx context.Context, payload []byte, topic string, connection webSocket.WSConClient) ([]byte, error) {
+	correlationID := atomic.AddUint64(&wt.requestCounter, 1)
+	chResponse := make(chan *data.WsMessage, 1)
+
+	wt.mutPendingRequests.Lock()
+	wt.pendingRequests[correlationID] = chResponse
+	wt.mutPendingRequests.Unlock()
+
+	defer func() {
+		wt.mutPendingRequests.Lock()
+		delete(wt.pendingRequests, correlationID)
+		wt.mutPendingRequests.Unlock()
+	}()
+
+	wsMessage := &data.WsMessage{
+		Type:          data.RequestMessage,
+		CorrelationID: correlationID,
+		Payload:       payload,
+		Topic:         topic,
+		Version:       wt.payloadVersion.Load(),
+	}
+	// the peer verifies the signature of the requests like for the payloads
+	if !check.IfNil(wt.messageSigner) {
+		err := wt.messageSigner.Sign(wsMessage)
+		if err != nil {
+			return nil, err
+		}
 	}
 	newPayload, err := wt.payloadParser.ConstructPayload(wsMessage)
+	if err != nil {
+		return nil, err
+	}
+
+	err = wt.writeMessage(connection, newPayload, topic)
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case response, ok := <-chResponse:
+		if !ok {
+			return nil, data.ErrConnectionClosedBeforeResponse
+		}
+		if response.ErrorCode != 0 {
+			return nil, &data.RequestError{
+				Code:    response.ErrorCode,
+				Message: response.ErrorMessage,
+			}
+		}
+
+		return response.Payload, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-wt.safeCloser.ChanClose():
+		return nil, data.ErrClosedBeforeResponse
+	}
+}
+
+// Subscribe will ask the peer to send only the payloads of the subscribed topics
+func (wt *wsTransceiver) Subscribe(topics []string, connection webSocket.WSConClient) error {
+	return wt.sendSubscriptionMessage(data.SubscribeMessage, topics, connection)
+}
+
+// Unsubscribe will ask the peer to stop sending the payloads of the provided topics
+func (wt *wsTransceiver) Unsubscribe(topics []string, connection webSocket.WSConClient) error {
+	return wt.sendSubscriptionMessage(data.UnsubscribeMessage, topics, connection)
+}
+
+func (wt *wsTransceiver) sendSubscriptionMessage(messageType int32, topics []string, connection webSocket.WSConClient) error {
+	wsMessage := &data.WsMessage{
+		Type:   messageType,
+		Topics: topics,
+	}
+	payload, err := wt.payloadParser.ConstructPayload(wsMessage)
 	if err != nil {
 		return err
 	}
 
-	return wt.sendPayload(newPayload, connection, ch)
+	return connection.WriteMessage(wt.messageType, payload)
+}
+
+// SendAsync will prepare and send the provided payload without waiting for the acknowledgement. The returned future
+// completes once the acknowledgement is received, or with an error if it is not received in time
+func (wt *wsTransceiver) SendAsync(payload []byte, topic string, connection webSocket.WSConClient) (webSocket.AckFuture, error) {
+	return wt.SendMessageAsync(&data.OutgoingMessage{Payload: payload, Topic: topic}, connection)
+}
+
+// SendMessageAsync will prepare and send the provided message like SendAsync does. A message resent with the ID of a
+// previous attempt that might have reached the peer reuses the sequence of that attempt
+func (wt *wsTransceiver) SendMessageAsync(message *data.OutgoingMessage, connection webSocket.WSConClient) (webSocket.AckFuture, error) {
+	if wt.ackWindow != nil {
+		return wt.sendInWindow(message, connection)
+	}
+
+	ch, localCounter := wt.prepareChanAndCounter()
+	wsMessage, err := wt.createPayloadMessage(message, localCounter)
+	if err != nil {
+		wt.removeAckChan(localCounter)
+		return nil, err
+	}
+	newPayload, err := wt.payloadParser.ConstructPayload(wsMessage)
+	if err != nil {
+		wt.removeAckChan(localCounter)
+		wt.sequencer.markInDoubt(wsMessage.Sequence, message.ID)
+		return nil, err
+	}
+
+	err = wt.writeMessage(connection, newPayload, message.Topic)
+	if err != nil {
+		wt.removeAckChan(localCounter)
+		wt.sequencer.markInDoubt(wsMessage.Sequence, message.ID)
+		return nil, err
+	}
+	if !wt.withAcknowledge {
+		return NewCompletedAckFuture(nil), nil
+	}
+
+	future := newAckFuture()
+	go func() {
+		errAck := wt.waitForAck(localCounter, ch)
+		if errAck != nil {
+			wt.sequencer.markInDoubt(wsMessage.Sequence, message.ID)
+		}
+		future.complete(errAck)
+	}()
+
+	return future, nil
+}
+
+// sendInWindow writes the message as soon as there is a free slot in the acknowledgement window. The counter is
+// assigned and the message is written under the same lock, so the peer receives the messages in the counter order
+// and can acknowledge them cumulatively
+func (wt *wsTransceiver) sendInWindow(message *data.OutgoingMessage, connection webSocket.WSConClient) (*ackFuture, error) {
+	select {
+	case wt.ackWindow <- struct{}{}:
+	case <-wt.safeCloser.ChanClose():
+		return nil, data.ErrExpectedAckWasNotReceivedOnClose
+	}
+
+	wt.mutSend.Lock()
+	defer wt.mutSend.Unlock()
+
+	wt.mutMapAck.Lock()
+	wt.counter++
+	localCounter := wt.counter
+	wt.mutMapAck.Unlock()
+
+	wsMessage, err := wt.createPayloadMessage(message, localCounter)
+	if err != nil {
+		wt.releaseAckWindowSlot()
+		return nil, err
+	}
+	wsMessage.WithCumulativeAck = wt.withAcknowledge
+	newPayload, err := wt.payloadParser.ConstructPayload(wsMessage)
+	if err != nil {
+		wt.releaseAckWindowSlot()
+		wt.sequencer.markInDoubt(wsMessage.Sequence, message.ID)
+		return nil, err
+	}
+
+	if !wt.withAcknowledge {
+		defer wt.releaseAckWindowSlot()
+
+		err = wt.writeMessage(connection, newPayload, message.Topic)
+		if err != nil {
+			wt.sequencer.markInDoubt(wsMessage.Sequence, message.ID)
+			return nil, err
+		}
+
+		return NewCompletedAckFuture(nil), nil
+	}
+
+	// the pending acknowledgement is registered before writing, as the acknowledgement might arrive before the write returns
+	future := newAckFuture()
+	wt.mutMapAck.Lock()
+	wt.pendingAcks[localCounter] = &pendingAck{
+		future:    future,
+		message:   wsMessage,
+		messageID: message.ID,
+		sentAt:    time.Now(),
+		timer: time.AfterFunc(wt.ackTimeout, func() {
+			wt.completePendingAck(localCounter, data.ErrAckTimeout)
+		}),
+	}
+	wt.mutMapAck.Unlock()
+
+	err = wt.writeMessage(connection, newPayload, message.Topic)
+	if err != nil {
+		wt.completePendingAck(localCounter, err)
+		return nil, err
+	}
+
+	return future, nil
+}
+
+func (wt *wsTransceiver) releaseAckWindowSlot() {
+	if wt.ackWindow == nil {
+		return
+	}
+
+	select {
+	case <-wt.ackWindow:
+	default:
+	}
 }
 
 func (wt *wsTransceiver) prepareChanAndCounter() (chan struct{}, uint64) {
@@ -236,9 +938,10 @@ func (wt *wsTransceiver) prepareChanAndCounter() (chan struct{}, uint64) {
 	return ch, localCounter
 }
 
-func (wt *wsTransceiver) sendPayload(payload []byte, connection webSocket.WSConClient, ch chan struct{}) error {
-	errSend := connection.WriteMessage(websocket.BinaryMessage, payload)
+func (wt *wsTransceiver) sendPayload(payload []byte, topic string, connection webSocket.WSConClient, counter uint64, ch chan struct{}) error {
+	errSend := wt.writeMessage(connection, payload, topic)
 	if errSend != nil {
+		wt.removeAckChan(counter)
 		return errSend
 	}
 
@@ -246,19 +949,74 @@ func (wt *wsTransceiver) sendPayload(payload []byte, connection webSocket.WSConC
 		return nil
 	}
 
-	return wt.waitForAck(ch)
+	return wt.waitForAck(counter, ch)
+}
+
+// removeAckChan forgets the acknowledgement of a message that will not be waited for anymore
+func (wt *wsTransceiver) removeAckChan(counter uint64) {
+	wt.mutMapAck.Lock()
+	delete(wt.mapAck, counter)
+	wt.mutMapAck.Unlock()
+}
+
+// writeMessage writes a payload or a request message and counts it as sent on its topic
+func (wt *wsTransceiver) writeMessage(connection webSocket.WSConClient, payload []byte, topic string) error {
+	err := wt.writeInChunksIfNeeded(connection, payload)
+	if err != nil {
+		return err
+	}
+
+	wt.metrics.MessageSent(topic, len(payload))
+
+	return nil
+}
+
+// writeInChunksIfNeeded writes a message larger than the chunk size as separate chunk messages, so the connection is
+// not held by a single large write and the acknowledgements can be written in between
+func (wt *wsTransceiver) writeInChunksIfNeeded(connection webSocket.WSConClient, payload []byte) error {
+	if wt.chunkSize == 0 || len(payload) <= wt.chunkSize {
+		return connection.WriteMessage(wt.messageType, payload)
+	}
+
+	parts := splitInChunks(payload, wt.chunkSize)
+	chunk := &data.WsMessage{
+		Type:       data.ChunkMessage,
+		Counter:    wt.chunkCounter.Add(1),
+		ChunkTotal: uint32(len(parts)),
+	}
+	for index, part := range parts {
+		chunk.ChunkIndex = uint32(index)
+		chunk.Payload = part
+
+		chunkPayload, err := wt.payloadParser.ConstructPayload(chunk)
+		if err != nil {
+			return err
+		}
+
+		err = connection.WriteMessage(wt.messageType, chunkPayload)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
-func (wt *wsTransceiver) waitForAck(ch chan struct{}) error {
+func (wt *wsTransceiver) waitForAck(counter uint64, ch chan struct{}) error {
 	timer := time.NewTimer(wt.ackTimeout)
 	defer timer.Stop()
 
+	sentAt := time.Now()
 	select {
 	case <-ch:
+		wt.recordAckOutcome(sentAt, nil)
 		return nil
 	case <-timer.C:
+		wt.removeAckChan(counter)
+		wt.recordAckOutcome(sentAt, data.ErrAckTimeout)
 		return data.ErrAckTimeout
 	case <-wt.safeCloser.ChanClose():
+		wt.removeAckChan(counter)
 		return data.ErrExpectedAckWasNotReceivedOnClose
 	}
 }
@@ -267,7 +1025,13 @@ func (wt *wsTransceiver) waitForAck(ch chan struct{}) error {
 func (wt *wsTransceiver) Close() error {
 	defer wt.safeCloser.Close()
 
-	err := wt.payloadHandler.Close()
+	wt.completeAllPendingAcks(data.ErrExpectedAckWasNotReceivedOnClose)
+
+	wt.mutPayloadHandler.RLock()
+	payloadHandler := wt.payloadHandler
+	wt.mutPayloadHandler.RUnlock()
+
+	err := payloadHandler.Close()
 	if err != nil {
 		wt.log.Debug("cannot close the payload handler", "error", err)
 	}
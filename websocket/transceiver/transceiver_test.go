@@ -1,7 +1,10 @@
 package transceiver
 
 import (
+	"bytes"
+	"context"
 	"errors"
+	"fmt"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -12,8 +15,13 @@ import (
 	"github.com/subrahamanyam341/andes-communication/testscommon"
 	webSocket "github.com/subrahamanyam341/andes-communication/websocket"
 	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	"github.com/subrahamanyam341/andes-communication/websocket/metrics"
+	"github.com/subrahamanyam341/andes-communication/websocket/signature"
 	"github.com/subrahamanyam341/andes-core-16/core"
 	"github.com/subrahamanyam341/andes-core-16/data/outport"
+	"github.com/subrahamanyam341/andes-crypto-123/signing"
+	"github.com/subrahamanyam341/andes-crypto-123/signing/secp256k1"
+	"github.com/subrahamanyam341/andes-crypto-123/signing/secp256k1/singlesig"
 )
 
 func createArgs() ArgsTransceiver {
@@ -69,6 +77,42 @@ func TestNewReceiver(t *testing.T) {
 		require.Nil(t, ws)
 		require.Equal(t, data.ErrZeroValueAckTimeout, err)
 	})
+	t.Run("negative acknowledge window size, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.AckWindowSize = -1
+		ws, err := NewTransceiver(args)
+		require.Nil(t, ws)
+		require.Equal(t, data.ErrInvalidAckWindowSize, err)
+	})
+	t.Run("min payload version greater than the payload version, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.MinPayloadVersion = 2
+		args.PayloadVersion = 1
+		ws, err := NewTransceiver(args)
+		require.Nil(t, ws)
+		require.True(t, errors.Is(err, data.ErrInvalidPayloadVersionRange))
+	})
+	t.Run("negative chunk size, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.ChunkSizeInBytes = -1
+		ws, err := NewTransceiver(args)
+		require.Nil(t, ws)
+		require.Equal(t, data.ErrInvalidChunkingConfig, err)
+	})
+	t.Run("negative reassembly timeout, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.ReassemblyTimeout = -time.Second
+		ws, err := NewTransceiver(args)
+		require.Nil(t, ws)
+		require.Equal(t, data.ErrInvalidChunkingConfig, err)
+	})
+	t.Run("negative max concurrent requests, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.MaxConcurrentRequests = -1
+		ws, err := NewTransceiver(args)
+		require.Nil(t, ws)
+		require.Equal(t, data.ErrInvalidMaxConcurrentRequests, err)
+	})
 }
 
 func TestReceiver_ListenAndClose(t *testing.T) {
@@ -249,7 +293,7 @@ func TestWsTransceiverWaitForAck(t *testing.T) {
 	wg := &sync.WaitGroup{}
 	wg.Add(1)
 	go func() {
-		err := webSocketTransceiver.waitForAck(ch)
+		err := webSocketTransceiver.waitForAck(1, ch)
 		require.Equal(t, data.ErrExpectedAckWasNotReceivedOnClose, err)
 		wg.Done()
 	}()
@@ -303,3 +347,1205 @@ func TestWsTransceiver_ListenReturnsTrue(t *testing.T) {
 	closed := webSocketTransceiver.Listen(conn)
 	require.True(t, closed)
 }
+
+func createConnWithAcks(converter webSocket.PayloadConverter, chAcks chan *data.WsMessage, chWritten chan *data.WsMessage) *testscommon.WebsocketConnectionStub {
+	return &testscommon.WebsocketConnectionStub{
+		WriteMessageCalled: func(_ int, payload []byte) error {
+			wsMessage, err := converter.ExtractWsMessage(payload)
+			if err != nil {
+				return err
+			}
+			chWritten <- wsMessage
+			return nil
+		},
+		ReadMessageCalled: func() (int, []byte, error) {
+			ack, ok := <-chAcks
+			if !ok {
+				return 0, nil, errors.New("closed")
+			}
+			payload, _ := converter.ConstructPayload(ack)
+			return websocket.BinaryMessage, payload, nil
+		},
+	}
+}
+
+func TestWsTransceiver_SendAsyncWithCumulativeAck(t *testing.T) {
+	t.Parallel()
+
+	args := createArgs()
+	args.WithAcknowledge = true
+	args.AckWindowSize = 5
+	webSocketTransceiver, _ := NewTransceiver(args)
+
+	chAcks := make(chan *data.WsMessage)
+	chWritten := make(chan *data.WsMessage, 10)
+	conn := createConnWithAcks(args.PayloadConverter, chAcks, chWritten)
+	go webSocketTransceiver.Listen(conn)
+
+	futures := make([]webSocket.AckFuture, 0, 3)
+	for i := 0; i < 3; i++ {
+		future, err := webSocketTransceiver.SendAsync([]byte("message"), outport.TopicSaveBlock, conn)
+		require.Nil(t, err)
+		futures = append(futures, future)
+	}
+
+	for i := 0; i < 3; i++ {
+		written := <-chWritten
+		require.Equal(t, uint64(i+1), written.Counter)
+		require.True(t, written.WithCumulativeAck)
+	}
+	for _, future := range futures {
+		require.Nil(t, future.Err())
+		select {
+		case <-future.Done():
+			require.Fail(t, "should have waited for the acknowledgement")
+		default:
+		}
+	}
+
+	chAcks <- &data.WsMessage{Counter: 3, Type: data.CumulativeAckMessage}
+	for _, future := range futures {
+		require.Nil(t, future.Wait())
+	}
+
+	close(chAcks)
+	_ = webSocketTransceiver.Close()
+}
+
+func TestWsTransceiver_SendAsyncWithIndividualAcksShouldWork(t *testing.T) {
+	t.Parallel()
+
+	// peers that do not know about the cumulative acknowledgements reply with an acknowledgement for every message
+	args := createArgs()
+	args.WithAcknowledge = true
+	args.AckWindowSize = 5
+	webSocketTransceiver, _ := NewTransceiver(args)
+
+	chAcks := make(chan *data.WsMessage)
+	chWritten := make(chan *data.WsMessage, 10)
+	conn := createConnWithAcks(args.PayloadConverter, chAcks, chWritten)
+	go webSocketTransceiver.Listen(conn)
+
+	first, err := webSocketTransceiver.SendAsync([]byte("message"), outport.TopicSaveBlock, conn)
+	require.Nil(t, err)
+	second, err := webSocketTransceiver.SendAsync([]byte("message"), outport.TopicSaveBlock, conn)
+	require.Nil(t, err)
+
+	chAcks <- &data.WsMessage{Counter: 2, Type: data.AckMessage}
+	require.Nil(t, second.Wait())
+	select {
+	case <-first.Done():
+		require.Fail(t, "an individual acknowledgement should not acknowledge the previous messages")
+	default:
+	}
+
+	chAcks <- &data.WsMessage{Counter: 1, Type: data.AckMessage}
+	require.Nil(t, first.Wait())
+
+	close(chAcks)
+	_ = webSocketTransceiver.Close()
+}
+
+func TestWsTransceiver_SendAsyncShouldBlockWhenTheWindowIsFull(t *testing.T) {
+	t.Parallel()
+
+	args := createArgs()
+	args.WithAcknowledge = true
+	args.AckWindowSize = 2
+	webSocketTransceiver, _ := NewTransceiver(args)
+
+	chAcks := make(chan *data.WsMessage)
+	chWritten := make(chan *data.WsMessage, 10)
+	conn := createConnWithAcks(args.PayloadConverter, chAcks, chWritten)
+	go webSocketTransceiver.Listen(conn)
+
+	for i := 0; i < 2; i++ {
+		_, err := webSocketTransceiver.SendAsync([]byte("message"), outport.TopicSaveBlock, conn)
+		require.Nil(t, err)
+	}
+
+	chSent := make(chan struct{})
+	go func() {
+		_, _ = webSocketTransceiver.SendAsync([]byte("message"), outport.TopicSaveBlock, conn)
+		close(chSent)
+	}()
+
+	select {
+	case <-chSent:
+		require.Fail(t, "the third message should wait for a free slot in the window")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	chAcks <- &data.WsMessage{Counter: 1, Type: data.AckMessage}
+	select {
+	case <-chSent:
+	case <-time.After(time.Second):
+		require.Fail(t, "the third message should have been sent after the acknowledgement")
+	}
+	require.Len(t, chWritten, 3)
+
+	close(chAcks)
+	_ = webSocketTransceiver.Close()
+}
+
+func TestWsTransceiver_SendAsyncInWindowAckTimeoutAndClose(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ack timeout, should complete with error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createArgs()
+		args.WithAcknowledge = true
+		args.AckTimeoutInSec = 1
+		args.AckWindowSize = 2
+		webSocketTransceiver, _ := NewTransceiver(args)
+		defer func() {
+			_ = webSocketTransceiver.Close()
+		}()
+
+		err := webSocketTransceiver.Send([]byte("message"), outport.TopicSaveBlock, &testscommon.WebsocketConnectionStub{})
+		require.Equal(t, data.ErrAckTimeout, err)
+		require.Len(t, webSocketTransceiver.ackWindow, 0)
+	})
+	t.Run("close, should complete the pending acknowledgements with error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createArgs()
+		args.WithAcknowledge = true
+		args.AckWindowSize = 2
+		webSocketTransceiver, _ := NewTransceiver(args)
+
+		future, err := webSocketTransceiver.SendAsync([]byte("message"), outport.TopicSaveBlock, &testscommon.WebsocketConnectionStub{})
+		require.Nil(t, err)
+
+		_ = webSocketTransceiver.Close()
+		require.Equal(t, data.ErrExpectedAckWasNotReceivedOnClose, future.Wait())
+	})
+}
+
+func TestWsTransceiver_ReceiverShouldReplyWithCumulativeAck(t *testing.T) {
+	t.Parallel()
+
+	args := createArgs()
+	args.BlockingAckOnError = true
+	webSocketTransceiver, _ := NewTransceiver(args)
+
+	processErr := errors.New("process error")
+	_ = webSocketTransceiver.SetPayloadHandler(&testscommon.PayloadHandlerStub{
+		ProcessPayloadCalled: func(payload []byte, _ string, _ uint32) error {
+			if string(payload) == "failing" {
+				return processErr
+			}
+			return nil
+		},
+	})
+
+	chIncoming := make(chan *data.WsMessage, 10)
+	chWritten := make(chan *data.WsMessage, 10)
+	conn := createConnWithAcks(args.PayloadConverter, chIncoming, chWritten)
+
+	chIncoming <- &data.WsMessage{Counter: 1, Type: data.PayloadMessage, Payload: []byte("message"), WithAcknowledge: true}
+	chIncoming <- &data.WsMessage{Counter: 2, Type: data.PayloadMessage, Payload: []byte("message"), WithAcknowledge: true, WithCumulativeAck: true}
+	chIncoming <- &data.WsMessage{Counter: 3, Type: data.PayloadMessage, Payload: []byte("failing"), WithAcknowledge: true, WithCumulativeAck: true}
+	chIncoming <- &data.WsMessage{Counter: 4, Type: data.PayloadMessage, Payload: []byte("message"), WithAcknowledge: true, WithCumulativeAck: true}
+	close(chIncoming)
+
+	_ = webSocketTransceiver.Listen(conn)
+	_ = webSocketTransceiver.Close()
+
+	require.Len(t, chWritten, 3)
+	require.Equal(t, &data.WsMessage{Counter: 1, Type: data.AckMessage}, <-chWritten)
+	require.Equal(t, &data.WsMessage{Counter: 2, Type: data.CumulativeAckMessage}, <-chWritten)
+	// after a failed message the acknowledgements are individual, so the failed message remains unacknowledged
+	require.Equal(t, &data.WsMessage{Counter: 4, Type: data.AckMessage}, <-chWritten)
+}
+
+func TestWsTransceiver_Subscriptions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("subscribe and unsubscribe should write the messages", func(t *testing.T) {
+		t.Parallel()
+
+		args := createArgs()
+		webSocketTransceiver, _ := NewTransceiver(args)
+
+		chWritten := make(chan *data.WsMessage, 2)
+		conn := createConnWithAcks(args.PayloadConverter, nil, chWritten)
+
+		err := webSocketTransceiver.Subscribe([]string{"topic1", "topic2"}, conn)
+		require.Nil(t, err)
+		err = webSocketTransceiver.Unsubscribe([]string{"topic1"}, conn)
+		require.Nil(t, err)
+
+		require.Equal(t, &data.WsMessage{Type: data.SubscribeMessage, Topics: []string{"topic1", "topic2"}}, <-chWritten)
+		require.Equal(t, &data.WsMessage{Type: data.UnsubscribeMessage, Topics: []string{"topic1"}}, <-chWritten)
+	})
+	t.Run("received subscriptions should be forwarded to the handler", func(t *testing.T) {
+		t.Parallel()
+
+		subscribed := make(map[string][]string)
+		unsubscribed := make(map[string][]string)
+		args := createArgs()
+		args.SubscriptionsHandler = &testscommon.SubscriptionsHandlerStub{
+			SubscribeCalled: func(connectionID string, topics []string) {
+				subscribed[connectionID] = topics
+			},
+			UnsubscribeCalled: func(connectionID string, topics []string) {
+				unsubscribed[connectionID] = topics
+			},
+		}
+		webSocketTransceiver, _ := NewTransceiver(args)
+		_ = webSocketTransceiver.SetPayloadHandler(&testscommon.PayloadHandlerStub{
+			ProcessPayloadCalled: func(_ []byte, _ string, _ uint32) error {
+				require.Fail(t, "subscription messages should not be processed as payloads")
+				return nil
+			},
+		})
+
+		chIncoming := make(chan *data.WsMessage, 2)
+		conn := createConnWithAcks(args.PayloadConverter, chIncoming, nil)
+		conn.GetIDCalled = func() string {
+			return "conn1"
+		}
+
+		chIncoming <- &data.WsMessage{Type: data.SubscribeMessage, Topics: []string{"topic1", "topic2"}}
+		chIncoming <- &data.WsMessage{Type: data.UnsubscribeMessage, Topics: []string{"topic2"}}
+		close(chIncoming)
+
+		_ = webSocketTransceiver.Listen(conn)
+		_ = webSocketTransceiver.Close()
+
+		require.Equal(t, map[string][]string{"conn1": {"topic1", "topic2"}}, subscribed)
+		require.Equal(t, map[string][]string{"conn1": {"topic2"}}, unsubscribed)
+	})
+}
+
+func createConnectedConns() (*testscommon.WebsocketConnectionStub, *testscommon.WebsocketConnectionStub, func()) {
+	chAToB := make(chan []byte, 10)
+	chBToA := make(chan []byte, 10)
+	chClosed := make(chan struct{})
+	createConn := func(chWrite chan []byte, chRead chan []byte) *testscommon.WebsocketConnectionStub {
+		return &testscommon.WebsocketConnectionStub{
+			WriteMessageCalled: func(_ int, payload []byte) error {
+				select {
+				case chWrite <- payload:
+					return nil
+				case <-chClosed:
+					return errors.New("closed")
+				}
+			},
+			ReadMessageCalled: func() (int, []byte, error) {
+				select {
+				case payload := <-chRead:
+					return websocket.BinaryMessage, payload, nil
+				case <-chClosed:
+					return 0, nil, errors.New("closed")
+				}
+			},
+		}
+	}
+	closeConns := func() {
+		close(chClosed)
+	}
+
+	return createConn(chAToB, chBToA), createConn(chBToA, chAToB), closeConns
+}
+
+func TestWsTransceiver_Request(t *testing.T) {
+	t.Parallel()
+
+	expectedErr := errors.New("expected error")
+	requestHandler := &testscommon.RequestHandlerStub{
+		ProcessRequestCalled: func(payload []byte, topic string, version uint32) ([]byte, error) {
+			switch string(payload) {
+			case "structured error":
+				return nil, &data.RequestError{Code: 100, Message: "invalid request"}
+			case "error":
+				return nil, expectedErr
+			case "slow":
+				time.Sleep(time.Second)
+			}
+			return append([]byte("response to "), payload...), nil
+		},
+	}
+
+	args := createArgs()
+	requester, _ := NewTransceiver(args)
+	responder, _ := NewTransceiver(args)
+	err := responder.SetRequestHandler(nil)
+	require.Equal(t, data.ErrNilRequestHandler, err)
+
+	requesterConn, responderConn, closeConns := createConnectedConns()
+	go requester.Listen(requesterConn)
+	go responder.Listen(responderConn)
+	defer func() {
+		closeConns()
+		_ = requester.Close()
+		_ = responder.Close()
+	}()
+
+	t.Run("no request handler, should return error", func(t *testing.T) {
+		response, errRequest := requester.Request(context.Background(), []byte("request"), "topic", requesterConn)
+		require.Nil(t, response)
+		require.Equal(t, &data.RequestError{Code: data.RequestErrorCodeNoHandler, Message: "no request handler set"}, errRequest)
+	})
+
+	err = responder.SetRequestHandler(requestHandler)
+	require.Nil(t, err)
+
+	t.Run("should work", func(t *testing.T) {
+		response, errRequest := requester.Request(context.Background(), []byte("request"), "topic", requesterConn)
+		require.Nil(t, errRequest)
+		require.Equal(t, []byte("response to request"), response)
+	})
+	t.Run("structured error, should be returned as it is", func(t *testing.T) {
+		response, errRequest := requester.Request(context.Background(), []byte("structured error"), "topic", requesterConn)
+		require.Nil(t, response)
+		require.Equal(t, &data.RequestError{Code: 100, Message: "invalid request"}, errRequest)
+	})
+	t.Run("error, should be returned as internal error", func(t *testing.T) {
+		response, errRequest := requester.Request(context.Background(), []byte("error"), "topic", requesterConn)
+		require.Nil(t, response)
+		require.Equal(t, &data.RequestError{Code: data.RequestErrorCodeInternal, Message: expectedErr.Error()}, errRequest)
+	})
+	t.Run("context timeout, should return error", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		response, errRequest := requester.Request(ctx, []byte("slow"), "topic", requesterConn)
+		require.Nil(t, response)
+		require.Equal(t, context.DeadlineExceeded, errRequest)
+	})
+}
+
+func TestWsTransceiver_RequestShouldBeRejectedWhenAllTheWorkersAreBusy(t *testing.T) {
+	t.Parallel()
+
+	chStarted := make(chan struct{}, 1)
+	chRelease := make(chan struct{})
+	requestHandler := &testscommon.RequestHandlerStub{
+		ProcessRequestCalled: func(payload []byte, _ string, _ uint32) ([]byte, error) {
+			chStarted <- struct{}{}
+			<-chRelease
+			return payload, nil
+		},
+	}
+
+	args := createArgs()
+	requester, _ := NewTransceiver(args)
+	args.MaxConcurrentRequests = 1
+	responder, _ := NewTransceiver(args)
+	_ = responder.SetRequestHandler(requestHandler)
+
+	requesterConn, responderConn, closeConns := createConnectedConns()
+	go requester.Listen(requesterConn)
+	go responder.Listen(responderConn)
+	defer func() {
+		closeConns()
+		_ = requester.Close()
+		_ = responder.Close()
+	}()
+
+	chResponse := make(chan []byte)
+	go func() {
+		response, _ := requester.Request(context.Background(), []byte("first"), "topic", requesterConn)
+		chResponse <- response
+	}()
+	<-chStarted
+
+	response, err := requester.Request(context.Background(), []byte("second"), "topic", requesterConn)
+	require.Nil(t, response)
+	require.Equal(t, &data.RequestError{Code: data.RequestErrorCodeBusy, Message: data.ErrTooManyRequests.Error()}, err)
+
+	close(chRelease)
+	require.Equal(t, []byte("first"), <-chResponse)
+
+	// the worker is free again
+	response, err = requester.Request(context.Background(), []byte("third"), "topic", requesterConn)
+	require.Nil(t, err)
+	require.Equal(t, []byte("third"), response)
+}
+
+func TestWsTransceiver_RequestShouldBeSignedAndVerified(t *testing.T) {
+	t.Parallel()
+
+	keyGen := signing.NewKeyGenerator(secp256k1.NewSecp256k1())
+	privateKey, publicKey := keyGen.GeneratePair()
+	publicKeyBytes, _ := publicKey.ToByteArray()
+	messageSigner, _ := signature.NewMessageSigner(signature.ArgsMessageSigner{
+		Signer:     &singlesig.Secp256k1Signer{},
+		PrivateKey: privateKey,
+	})
+	messageVerifier, _ := signature.NewMessageVerifier(signature.ArgsMessageVerifier{
+		Signer:            &singlesig.Secp256k1Signer{},
+		KeyGenerator:      keyGen,
+		TrustedPublicKeys: [][]byte{publicKeyBytes},
+	})
+
+	numProcessed := atomic.Int32{}
+	requestHandler := &testscommon.RequestHandlerStub{
+		ProcessRequestCalled: func(payload []byte, _ string, _ uint32) ([]byte, error) {
+			numProcessed.Add(1)
+			return payload, nil
+		},
+	}
+
+	request := func(signer webSocket.MessageSigner, payload []byte) ([]byte, error) {
+		args := createArgs()
+		args.MessageSigner = signer
+		requester, _ := NewTransceiver(args)
+		args = createArgs()
+		args.MessageVerifier = messageVerifier
+		responder, _ := NewTransceiver(args)
+		_ = responder.SetRequestHandler(requestHandler)
+
+		requesterConn, responderConn, closeConns := createConnectedConns()
+		go requester.Listen(requesterConn)
+		go responder.Listen(responderConn)
+		defer func() {
+			closeConns()
+			_ = requester.Close()
+			_ = responder.Close()
+		}()
+
+		return requester.Request(context.Background(), payload, "topic", requesterConn)
+	}
+
+	response, err := request(messageSigner, []byte("signed"))
+	require.Nil(t, err)
+	require.Equal(t, []byte("signed"), response)
+
+	response, err = request(nil, []byte("unsigned"))
+	require.Nil(t, response)
+	require.Equal(t, &data.RequestError{Code: data.RequestErrorCodeRejected, Message: data.ErrMissingSignature.Error()}, err)
+	require.Equal(t, int32(1), numProcessed.Load())
+}
+
+func TestWsTransceiver_RequestConnectionClosedShouldReturnError(t *testing.T) {
+	t.Parallel()
+
+	args := createArgs()
+	requester, _ := NewTransceiver(args)
+	defer func() {
+		_ = requester.Close()
+	}()
+
+	chWritten := make(chan struct{})
+	conn := &testscommon.WebsocketConnectionStub{
+		WriteMessageCalled: func(_ int, _ []byte) error {
+			close(chWritten)
+			return nil
+		},
+		ReadMessageCalled: func() (int, []byte, error) {
+			<-chWritten
+			return 0, nil, errors.New("closed")
+		},
+	}
+	go requester.Listen(conn)
+
+	response, err := requester.Request(context.Background(), []byte("request"), "topic", conn)
+	require.Nil(t, response)
+	require.Equal(t, data.ErrConnectionClosedBeforeResponse, err)
+}
+
+func TestWsTransceiver_SendShouldUseTheConfiguredFrameType(t *testing.T) {
+	t.Parallel()
+
+	testFrameType := func(textFrames bool, expectedMessageType int) {
+		args := createArgs()
+		args.TextFrames = textFrames
+		webSocketTransceiver, _ := NewTransceiver(args)
+
+		writtenMessageType := 0
+		conn := &testscommon.WebsocketConnectionStub{
+			WriteMessageCalled: func(messageType int, data []byte) error {
+				writtenMessageType = messageType
+				return nil
+			},
+		}
+
+		err := webSocketTransceiver.Send([]byte("something"), outport.TopicSaveBlock, conn)
+		require.Nil(t, err)
+		require.Equal(t, expectedMessageType, writtenMessageType)
+	}
+
+	t.Run("binary frames", func(t *testing.T) {
+		testFrameType(false, websocket.BinaryMessage)
+	})
+	t.Run("text frames", func(t *testing.T) {
+		testFrameType(true, websocket.TextMessage)
+	})
+}
+
+func TestWsTransceiver_ListenShouldDropDuplicatedMessages(t *testing.T) {
+	t.Parallel()
+
+	args := createArgs()
+	webSocketsReceiver, err := NewTransceiver(args)
+	require.Nil(t, err)
+
+	processed := make([]string, 0)
+	_ = webSocketsReceiver.SetPayloadHandler(&testscommon.PayloadHandlerStub{
+		ProcessPayloadCalled: func(payload []byte, _ string, _ uint32) error {
+			processed = append(processed, string(payload))
+			return nil
+		},
+	})
+
+	messages := []*data.WsMessage{
+		{Payload: []byte("first"), Sequence: 1},
+		{Payload: []byte("first"), Sequence: 1},
+		{Payload: []byte("second"), Sequence: 2},
+		{Payload: []byte("without session"), Sequence: 0},
+		{Payload: []byte("without session"), Sequence: 0},
+	}
+	index := 0
+	numAcks := 0
+	conn := &testscommon.WebsocketConnectionStub{
+		ReadMessageCalled: func() (int, []byte, error) {
+			if index == len(messages) {
+				return 0, nil, errors.New("closed")
+			}
+
+			message := messages[index]
+			index++
+			message.Type = data.PayloadMessage
+			message.WithAcknowledge = true
+			message.Counter = uint64(index)
+			if message.Sequence > 0 {
+				message.SessionID = "session"
+			}
+			preparedPayload, _ := args.PayloadConverter.ConstructPayload(message)
+			return websocket.BinaryMessage, preparedPayload, nil
+		},
+		WriteMessageCalled: func(messageType int, d []byte) error {
+			numAcks++
+			return nil
+		},
+	}
+
+	_ = webSocketsReceiver.Listen(conn)
+	_ = webSocketsReceiver.Close()
+
+	require.Equal(t, []string{"first", "second", "without session", "without session"}, processed)
+	// the duplicated message is acknowledged again, as the first acknowledgement might have been lost
+	require.Equal(t, len(messages), numAcks)
+}
+
+func TestWsTransceiver_SendShouldReuseTheSequenceOfAMessageInDoubt(t *testing.T) {
+	t.Parallel()
+
+	args := createArgs()
+	webSocketTransceiver, _ := NewTransceiver(args)
+	defer func() {
+		_ = webSocketTransceiver.Close()
+	}()
+
+	writeErr := errors.New("write error")
+	written := make([]*data.WsMessage, 0)
+	conn := &testscommon.WebsocketConnectionStub{
+		WriteMessageCalled: func(messageType int, payload []byte) error {
+			wsMessage, _ := args.PayloadConverter.ExtractWsMessage(payload)
+			written = append(written, wsMessage)
+			if len(written) == 1 {
+				return writeErr
+			}
+			return nil
+		},
+	}
+
+	message := &data.OutgoingMessage{
+		Payload: []byte("first"),
+		Topic:   outport.TopicSaveBlock,
+		ID:      "1",
+	}
+	err := webSocketTransceiver.SendMessage(message, conn)
+	require.Equal(t, writeErr, err)
+	err = webSocketTransceiver.SendMessage(message, conn)
+	require.Nil(t, err)
+	err = webSocketTransceiver.Send([]byte("second"), outport.TopicSaveBlock, conn)
+	require.Nil(t, err)
+
+	require.Len(t, written, 3)
+	require.Equal(t, uint64(1), written[0].Sequence)
+	require.Equal(t, uint64(1), written[1].Sequence)
+	require.Equal(t, uint64(2), written[2].Sequence)
+	require.NotEmpty(t, written[0].SessionID)
+	require.Equal(t, written[0].SessionID, written[2].SessionID)
+	// the counters are only used for matching the acknowledgements
+	require.NotEqual(t, written[0].Counter, written[1].Counter)
+}
+
+func TestWsTransceiver_SendShouldNotReuseTheSequenceOfTheSameContent(t *testing.T) {
+	t.Parallel()
+
+	args := createArgs()
+	webSocketTransceiver, _ := NewTransceiver(args)
+	defer func() {
+		_ = webSocketTransceiver.Close()
+	}()
+
+	writeErr := errors.New("write error")
+	written := make([]*data.WsMessage, 0)
+	conn := &testscommon.WebsocketConnectionStub{
+		WriteMessageCalled: func(messageType int, payload []byte) error {
+			wsMessage, _ := args.PayloadConverter.ExtractWsMessage(payload)
+			written = append(written, wsMessage)
+			if len(written) == 1 {
+				return writeErr
+			}
+			return nil
+		},
+	}
+
+	// the same content sent again might be a new message, the peer should not drop it as a duplicate
+	err := webSocketTransceiver.Send([]byte("heartbeat"), outport.TopicSaveBlock, conn)
+	require.Equal(t, writeErr, err)
+	err = webSocketTransceiver.Send([]byte("heartbeat"), outport.TopicSaveBlock, conn)
+	require.Nil(t, err)
+
+	require.Len(t, written, 2)
+	require.Equal(t, uint64(1), written[0].Sequence)
+	require.Equal(t, uint64(2), written[1].Sequence)
+}
+
+func TestWsTransceiver_ListenShouldDropUnsupportedPayloadVersions(t *testing.T) {
+	t.Parallel()
+
+	args := createArgs()
+	args.MinPayloadVersion = 2
+	args.PayloadVersion = 3
+	webSocketsReceiver, err := NewTransceiver(args)
+	require.Nil(t, err)
+
+	processedVersions := make([]uint32, 0)
+	_ = webSocketsReceiver.SetPayloadHandler(&testscommon.PayloadHandlerStub{
+		ProcessPayloadCalled: func(_ []byte, _ string, version uint32) error {
+			processedVersions = append(processedVersions, version)
+			return nil
+		},
+	})
+
+	versions := []uint32{1, 2, 3, 4, 0}
+	index := 0
+	conn := &testscommon.WebsocketConnectionStub{
+		ReadMessageCalled: func() (int, []byte, error) {
+			if index == len(versions) {
+				return 0, nil, errors.New("closed")
+			}
+
+			message := &data.WsMessage{
+				Type:    data.PayloadMessage,
+				Payload: []byte("payload"),
+				Version: versions[index],
+			}
+			index++
+			preparedPayload, _ := args.PayloadConverter.ConstructPayload(message)
+			return websocket.BinaryMessage, preparedPayload, nil
+		},
+	}
+
+	_ = webSocketsReceiver.Listen(conn)
+	_ = webSocketsReceiver.Close()
+
+	// the messages without a version come from peers that do not stamp it, so they are not checked
+	require.Equal(t, []uint32{2, 3, 0}, processedVersions)
+}
+
+func TestWsTransceiver_SetPayloadVersion(t *testing.T) {
+	t.Parallel()
+
+	args := createArgs()
+	args.MinPayloadVersion = 1
+	args.PayloadVersion = 3
+	webSocketTransceiver, _ := NewTransceiver(args)
+
+	var sentMessage *data.WsMessage
+	conn := &testscommon.WebsocketConnectionStub{
+		WriteMessageCalled: func(messageType int, payload []byte) error {
+			sentMessage, _ = args.PayloadConverter.ExtractWsMessage(payload)
+			return nil
+		},
+	}
+
+	err := webSocketTransceiver.Send([]byte("something"), outport.TopicSaveBlock, conn)
+	require.Nil(t, err)
+	require.Equal(t, uint32(3), sentMessage.Version)
+
+	webSocketTransceiver.SetPayloadVersion(2)
+	err = webSocketTransceiver.Send([]byte("something"), outport.TopicSaveBlock, conn)
+	require.Nil(t, err)
+	require.Equal(t, uint32(2), sentMessage.Version)
+}
+
+func TestWsTransceiver_ShouldReportTheReceivedMessagesMetrics(t *testing.T) {
+	t.Parallel()
+
+	inMemoryMetrics := metrics.NewInMemoryMetrics()
+	args := createArgs()
+	args.Metrics = inMemoryMetrics
+	webSocketsReceiver, _ := NewTransceiver(args)
+	_ = webSocketsReceiver.SetPayloadHandler(&testscommon.PayloadHandlerStub{
+		ProcessPayloadCalled: func(payload []byte, _ string, _ uint32) error {
+			if string(payload) == "bad" {
+				return errors.New("local error")
+			}
+			return nil
+		},
+	})
+
+	payloads := []string{"good", "bad"}
+	receivedBytes := 0
+	index := 0
+	conn := &testscommon.WebsocketConnectionStub{
+		ReadMessageCalled: func() (int, []byte, error) {
+			if index == len(payloads) {
+				return 0, nil, errors.New("closed")
+			}
+
+			message := &data.WsMessage{
+				Type:    data.PayloadMessage,
+				Payload: []byte(payloads[index]),
+				Topic:   outport.TopicSaveBlock,
+			}
+			index++
+			preparedPayload, _ := args.PayloadConverter.ConstructPayload(message)
+			receivedBytes += len(preparedPayload)
+			return websocket.BinaryMessage, preparedPayload, nil
+		},
+	}
+
+	_ = webSocketsReceiver.Listen(conn)
+	_ = webSocketsReceiver.Close()
+
+	topicMetrics := inMemoryMetrics.GetSnapshot().Topics[outport.TopicSaveBlock]
+	require.Equal(t, uint64(2), topicMetrics.MessagesReceived)
+	require.Equal(t, uint64(receivedBytes), topicMetrics.BytesReceived)
+	require.Equal(t, uint64(1), topicMetrics.HandlerErrors)
+}
+
+func TestWsTransceiver_ShouldReportTheSentMessagesAndAcksMetrics(t *testing.T) {
+	t.Parallel()
+
+	inMemoryMetrics := metrics.NewInMemoryMetrics()
+	args := createArgs()
+	args.WithAcknowledge = true
+	args.AckTimeoutInSec = 1
+	args.Metrics = inMemoryMetrics
+	webSocketTransceiver, _ := NewTransceiver(args)
+
+	acknowledge := atomic.Bool{}
+	acknowledge.Store(true)
+	sentBytes := 0
+	conn := &testscommon.WebsocketConnectionStub{
+		WriteMessageCalled: func(messageType int, payload []byte) error {
+			sentBytes += len(payload)
+			message, _ := args.PayloadConverter.ExtractWsMessage(payload)
+			if acknowledge.Load() {
+				go webSocketTransceiver.handleAckMessage(message.Counter)
+			}
+			return nil
+		},
+	}
+
+	err := webSocketTransceiver.Send([]byte("acknowledged"), outport.TopicSaveBlock, conn)
+	require.Nil(t, err)
+
+	acknowledge.Store(false)
+	err = webSocketTransceiver.Send([]byte("not acknowledged"), outport.TopicSaveBlock, conn)
+	require.Equal(t, data.ErrAckTimeout, err)
+
+	snapshot := inMemoryMetrics.GetSnapshot()
+	require.Equal(t, uint64(2), snapshot.Topics[outport.TopicSaveBlock].MessagesSent)
+	require.Equal(t, uint64(sentBytes), snapshot.Topics[outport.TopicSaveBlock].BytesSent)
+	require.Equal(t, uint64(1), snapshot.AckLatency.Count)
+	require.Equal(t, uint64(1), snapshot.AckTimeouts)
+}
+
+func TestWsTransceiver_GetNumPendingAcks(t *testing.T) {
+	t.Parallel()
+
+	args := createArgs()
+	args.WithAcknowledge = true
+	args.AckTimeoutInSec = 1
+	webSocketTransceiver, _ := NewTransceiver(args)
+	defer func() {
+		_ = webSocketTransceiver.Close()
+	}()
+
+	chWritten := make(chan struct{}, 1)
+	conn := &testscommon.WebsocketConnectionStub{
+		WriteMessageCalled: func(messageType int, payload []byte) error {
+			chWritten <- struct{}{}
+			return nil
+		},
+	}
+
+	chSendErr := make(chan error, 1)
+	go func() {
+		chSendErr <- webSocketTransceiver.Send([]byte("payload"), outport.TopicSaveBlock, conn)
+	}()
+
+	<-chWritten
+	require.Equal(t, 1, webSocketTransceiver.GetNumPendingAcks())
+
+	// the acknowledgement never arrives, so it is not pending anymore after the timeout
+	require.Equal(t, data.ErrAckTimeout, <-chSendErr)
+	require.Zero(t, webSocketTransceiver.GetNumPendingAcks())
+}
+
+func TestWsTransceiver_ShouldWriteLargeMessagesInChunks(t *testing.T) {
+	t.Parallel()
+
+	args := createArgs()
+	args.WithAcknowledge = true
+	args.ChunkSizeInBytes = 300
+	sender, _ := NewTransceiver(args)
+	receiver, _ := NewTransceiver(args)
+
+	chReceived := make(chan []byte, 10)
+	_ = receiver.SetPayloadHandler(&testscommon.PayloadHandlerStub{
+		ProcessPayloadCalled: func(payload []byte, _ string, _ uint32) error {
+			chReceived <- payload
+			return nil
+		},
+	})
+	_ = receiver.SetRequestHandler(&testscommon.RequestHandlerStub{
+		ProcessRequestCalled: func(payload []byte, _ string, _ uint32) ([]byte, error) {
+			return append([]byte("response to "), payload...), nil
+		},
+	})
+
+	senderConn, receiverConn, closeConns := createConnectedConns()
+	numSenderWrites := atomic.Int32{}
+	writeMessage := senderConn.WriteMessageCalled
+	senderConn.WriteMessageCalled = func(messageType int, payload []byte) error {
+		numSenderWrites.Add(1)
+		return writeMessage(messageType, payload)
+	}
+	numReceiverWrites := atomic.Int32{}
+	writeReply := receiverConn.WriteMessageCalled
+	receiverConn.WriteMessageCalled = func(messageType int, payload []byte) error {
+		numReceiverWrites.Add(1)
+		return writeReply(messageType, payload)
+	}
+
+	go sender.Listen(senderConn)
+	go receiver.Listen(receiverConn)
+	defer func() {
+		closeConns()
+		_ = sender.Close()
+		_ = receiver.Close()
+	}()
+
+	t.Run("small payload, should be written whole", func(t *testing.T) {
+		err := sender.Send([]byte("a"), "topic", senderConn)
+		require.Nil(t, err)
+		require.Equal(t, []byte("a"), <-chReceived)
+		require.Equal(t, int32(1), numSenderWrites.Swap(0))
+		require.Equal(t, int32(1), numReceiverWrites.Swap(0))
+	})
+	t.Run("large payload, should be reassembled and acknowledged once", func(t *testing.T) {
+		payload := bytes.Repeat([]byte("a"), 1000)
+		err := sender.Send(payload, "topic", senderConn)
+		require.Nil(t, err)
+		require.Equal(t, payload, <-chReceived)
+		require.Greater(t, numSenderWrites.Swap(0), int32(1))
+		require.Equal(t, int32(1), numReceiverWrites.Swap(0))
+	})
+	t.Run("large request and response, should be reassembled", func(t *testing.T) {
+		payload := bytes.Repeat([]byte("b"), 1000)
+		response, err := sender.Request(context.Background(), payload, "topic", senderConn)
+		require.Nil(t, err)
+		require.Equal(t, append([]byte("response to "), payload...), response)
+		require.Greater(t, numReceiverWrites.Swap(0), int32(1))
+	})
+}
+
+func TestWsTransceiver_ListenShouldDropTheReassembledChunks(t *testing.T) {
+	t.Parallel()
+
+	args := createArgs()
+	receiver, _ := NewTransceiver(args)
+
+	processed := make([][]byte, 0)
+	_ = receiver.SetPayloadHandler(&testscommon.PayloadHandlerStub{
+		ProcessPayloadCalled: func(payload []byte, _ string, _ uint32) error {
+			processed = append(processed, payload)
+			return nil
+		},
+	})
+
+	payloadMessage, _ := args.PayloadConverter.ConstructPayload(&data.WsMessage{
+		Type:    data.PayloadMessage,
+		Payload: []byte("payload"),
+		Topic:   outport.TopicSaveBlock,
+	})
+	half := len(payloadMessage) / 2
+	innerChunk := func(index uint32, payload []byte) []byte {
+		chunk, _ := args.PayloadConverter.ConstructPayload(&data.WsMessage{
+			Type:       data.ChunkMessage,
+			Counter:    2,
+			ChunkIndex: index,
+			ChunkTotal: 2,
+			Payload:    payload,
+		})
+		return chunk
+	}
+	// the first chunk of the payload message is smuggled inside the chunks of another message
+	smuggledChunk := innerChunk(0, payloadMessage[:half])
+	outerChunk := func(index uint32, payload []byte) []byte {
+		chunk, _ := args.PayloadConverter.ConstructPayload(&data.WsMessage{
+			Type:       data.ChunkMessage,
+			Counter:    1,
+			ChunkIndex: index,
+			ChunkTotal: 2,
+			Payload:    payload,
+		})
+		return chunk
+	}
+	messages := [][]byte{
+		outerChunk(0, smuggledChunk[:len(smuggledChunk)/2]),
+		outerChunk(1, smuggledChunk[len(smuggledChunk)/2:]),
+		innerChunk(1, payloadMessage[half:]),
+	}
+
+	index := 0
+	conn := &testscommon.WebsocketConnectionStub{
+		ReadMessageCalled: func() (int, []byte, error) {
+			if index == len(messages) {
+				return 0, nil, errors.New("closed")
+			}
+
+			index++
+			return websocket.BinaryMessage, messages[index-1], nil
+		},
+	}
+
+	_ = receiver.Listen(conn)
+	_ = receiver.Close()
+
+	require.Empty(t, processed)
+}
+
+func TestWsTransceiver_ListenShouldDropPayloadsWithInvalidSignatures(t *testing.T) {
+	t.Parallel()
+
+	keyGen := signing.NewKeyGenerator(secp256k1.NewSecp256k1())
+	privateKey, publicKey := keyGen.GeneratePair()
+	publicKeyBytes, _ := publicKey.ToByteArray()
+	messageSigner, _ := signature.NewMessageSigner(signature.ArgsMessageSigner{
+		Signer:     &singlesig.Secp256k1Signer{},
+		PrivateKey: privateKey,
+	})
+	messageVerifier, _ := signature.NewMessageVerifier(signature.ArgsMessageVerifier{
+		Signer:            &singlesig.Secp256k1Signer{},
+		KeyGenerator:      keyGen,
+		TrustedPublicKeys: [][]byte{publicKeyBytes},
+	})
+
+	inMemoryMetrics := metrics.NewInMemoryMetrics()
+	args := createArgs()
+	args.Metrics = inMemoryMetrics
+	args.MessageVerifier = messageVerifier
+	webSocketsReceiver, _ := NewTransceiver(args)
+
+	processed := make([]string, 0)
+	_ = webSocketsReceiver.SetPayloadHandler(&testscommon.PayloadHandlerStub{
+		ProcessPayloadCalled: func(payload []byte, _ string, _ uint32) error {
+			processed = append(processed, string(payload))
+			return nil
+		},
+	})
+
+	signed := &data.WsMessage{Type: data.PayloadMessage, Payload: []byte("signed"), Topic: outport.TopicSaveBlock}
+	_ = messageSigner.Sign(signed)
+	altered := &data.WsMessage{Type: data.PayloadMessage, Payload: []byte("original"), Topic: outport.TopicSaveBlock}
+	_ = messageSigner.Sign(altered)
+	altered.Payload = []byte("altered")
+	unsigned := &data.WsMessage{Type: data.PayloadMessage, Payload: []byte("unsigned"), Topic: outport.TopicSaveBlock}
+
+	messages := []*data.WsMessage{signed, altered, unsigned}
+	index := 0
+	conn := &testscommon.WebsocketConnectionStub{
+		ReadMessageCalled: func() (int, []byte, error) {
+			if index == len(messages) {
+				return 0, nil, errors.New("closed")
+			}
+
+			preparedPayload, _ := args.PayloadConverter.ConstructPayload(messages[index])
+			index++
+			return websocket.BinaryMessage, preparedPayload, nil
+		},
+	}
+
+	_ = webSocketsReceiver.Listen(conn)
+	_ = webSocketsReceiver.Close()
+
+	require.Equal(t, []string{"signed"}, processed)
+	require.Equal(t, uint64(2), inMemoryMetrics.GetSnapshot().Topics[outport.TopicSaveBlock].InvalidSignatures)
+}
+
+func TestWsTransceiver_SendShouldSignThePayloads(t *testing.T) {
+	t.Parallel()
+
+	expectedErr := errors.New("expected error")
+	signErr := atomic.Pointer[error]{}
+	args := createArgs()
+	args.MessageSigner = &testscommon.MessageSignerStub{
+		SignCalled: func(message *data.WsMessage) error {
+			errPtr := signErr.Load()
+			if errPtr != nil {
+				return *errPtr
+			}
+
+			message.Signature = []byte("signature")
+			return nil
+		},
+	}
+	webSocketTransceiver, _ := NewTransceiver(args)
+	defer func() {
+		_ = webSocketTransceiver.Close()
+	}()
+
+	var written *data.WsMessage
+	conn := &testscommon.WebsocketConnectionStub{
+		WriteMessageCalled: func(_ int, payload []byte) error {
+			written, _ = args.PayloadConverter.ExtractWsMessage(payload)
+			return nil
+		},
+	}
+
+	err := webSocketTransceiver.Send([]byte("payload"), outport.TopicSaveBlock, conn)
+	require.Nil(t, err)
+	require.Equal(t, []byte("signature"), written.Signature)
+	require.Equal(t, uint64(1), written.Sequence)
+
+	signErr.Store(&expectedErr)
+	err = webSocketTransceiver.Send([]byte("payload"), outport.TopicSaveBlock, conn)
+	require.Equal(t, expectedErr, err)
+
+	// the message that could not be signed did not take a sequence
+	signErr.Store(nil)
+	err = webSocketTransceiver.Send([]byte("payload"), outport.TopicSaveBlock, conn)
+	require.Nil(t, err)
+	require.Equal(t, uint64(2), written.Sequence)
+
+	// a forwarded message keeps the signature of its origin
+	signErr.Store(&expectedErr)
+	err = webSocketTransceiver.SendMessage(&data.OutgoingMessage{
+		Payload:         []byte("payload"),
+		Topic:           outport.TopicSaveBlock,
+		Signature:       []byte("origin signature"),
+		SignerPublicKey: []byte("origin public key"),
+	}, conn)
+	require.Nil(t, err)
+	require.Equal(t, []byte("origin signature"), written.Signature)
+	require.Equal(t, []byte("origin public key"), written.SignerPublicKey)
+}
+
+func TestWsTransceiver_ListenShouldNotAcknowledgeTheRefusedPayloads(t *testing.T) {
+	t.Parallel()
+
+	args := createArgs()
+	webSocketsReceiver, _ := NewTransceiver(args)
+	_ = webSocketsReceiver.SetPayloadHandler(&testscommon.PayloadHandlerStub{
+		ProcessPayloadCalled: func(payload []byte, _ string, _ uint32) error {
+			if string(payload) == "refused" {
+				return fmt.Errorf("%w by the handler", data.ErrPayloadRefused)
+			}
+			return nil
+		},
+	})
+
+	messages := []*data.WsMessage{
+		{WithAcknowledge: true, Counter: 1, Type: data.PayloadMessage, Payload: []byte("refused"), Topic: outport.TopicSaveBlock},
+		{WithAcknowledge: true, Counter: 2, Type: data.PayloadMessage, Payload: []byte("processed"), Topic: outport.TopicSaveBlock},
+	}
+	index := 0
+	acknowledged := make([]uint64, 0)
+	conn := &testscommon.WebsocketConnectionStub{
+		ReadMessageCalled: func() (int, []byte, error) {
+			if index == len(messages) {
+				return 0, nil, errors.New("closed")
+			}
+
+			preparedPayload, _ := args.PayloadConverter.ConstructPayload(messages[index])
+			index++
+			return websocket.BinaryMessage, preparedPayload, nil
+		},
+		WriteMessageCalled: func(_ int, payload []byte) error {
+			ack, _ := args.PayloadConverter.ExtractWsMessage(payload)
+			acknowledged = append(acknowledged, ack.Counter)
+			return nil
+		},
+	}
+
+	_ = webSocketsReceiver.Listen(conn)
+	_ = webSocketsReceiver.Close()
+
+	require.Equal(t, []uint64{2}, acknowledged)
+}
+
+func TestWsTransceiver_SendShouldCleanUpWhenTheMessageCanNotBeConstructed(t *testing.T) {
+	t.Parallel()
+
+	testCleanUp := func(t *testing.T, ackWindowSize int, send func(wt *wsTransceiver, message *data.OutgoingMessage, conn webSocket.WSConClient) error) {
+		marshaller := &testscommon.MarshallerMock{Fail: true}
+		args := createArgs()
+		args.PayloadConverter, _ = webSocket.NewWebSocketPayloadConverter(marshaller)
+		args.WithAcknowledge = true
+		args.AckWindowSize = ackWindowSize
+		webSocketTransceiver, _ := NewTransceiver(args)
+		defer func() {
+			_ = webSocketTransceiver.Close()
+		}()
+
+		written := make([]*data.WsMessage, 0)
+		conn := &testscommon.WebsocketConnectionStub{
+			WriteMessageCalled: func(messageType int, payload []byte) error {
+				wsMessage, _ := args.PayloadConverter.ExtractWsMessage(payload)
+				written = append(written, wsMessage)
+				return nil
+			},
+		}
+
+		message := &data.OutgoingMessage{
+			Payload: []byte("payload"),
+			Topic:   outport.TopicSaveBlock,
+			ID:      "1",
+		}
+		err := send(webSocketTransceiver, message, conn)
+		require.Equal(t, testscommon.ErrMockMarshaller, err)
+		require.Empty(t, webSocketTransceiver.mapAck)
+		require.Zero(t, webSocketTransceiver.GetNumPendingAcks())
+		if webSocketTransceiver.ackWindow != nil {
+			require.Zero(t, len(webSocketTransceiver.ackWindow))
+		}
+
+		// the resend fills the sequence taken by the message that could not be constructed
+		marshaller.Fail = false
+		_, err = webSocketTransceiver.SendMessageAsync(message, conn)
+		require.Nil(t, err)
+		require.Len(t, written, 1)
+		require.Equal(t, uint64(1), written[0].Sequence)
+	}
+
+	t.Run("send", func(t *testing.T) {
+		testCleanUp(t, 0, func(wt *wsTransceiver, message *data.OutgoingMessage, conn webSocket.WSConClient) error {
+			return wt.SendMessage(message, conn)
+		})
+	})
+	t.Run("send async", func(t *testing.T) {
+		testCleanUp(t, 0, func(wt *wsTransceiver, message *data.OutgoingMessage, conn webSocket.WSConClient) error {
+			_, err := wt.SendMessageAsync(message, conn)
+			return err
+		})
+	})
+	t.Run("send in window", func(t *testing.T) {
+		testCleanUp(t, 1, func(wt *wsTransceiver, message *data.OutgoingMessage, conn webSocket.WSConClient) error {
+			_, err := wt.SendMessageAsync(message, conn)
+			return err
+		})
+	})
+}
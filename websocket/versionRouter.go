@@ -0,0 +1,90 @@
+package websocket
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	"github.com/subrahamanyam341/andes-core-16/core"
+	"github.com/subrahamanyam341/andes-core-16/core/check"
+)
+
+// versionRouter dispatches every payload to the handler registered for its payload version, so the payloads of the
+// old and the new versions can be decoded side by side during a migration
+type versionRouter struct {
+	mut      sync.RWMutex
+	log      core.Logger
+	handlers map[uint32]PayloadHandler
+}
+
+// NewVersionRouter will create a new payload handler that routes the payloads to the handlers registered per version
+func NewVersionRouter(log core.Logger) (*versionRouter, error) {
+	if check.IfNil(log) {
+		return nil, core.ErrNilLogger
+	}
+
+	return &versionRouter{
+		log:      log,
+		handlers: make(map[uint32]PayloadHandler),
+	}, nil
+}
+
+// RegisterHandler will register the handler for the provided payload version. The same handler can be registered for
+// several versions
+func (vr *versionRouter) RegisterHandler(version uint32, handler PayloadHandler) error {
+	if check.IfNil(handler) {
+		return data.ErrNilPayloadProcessor
+	}
+
+	vr.mut.Lock()
+	defer vr.mut.Unlock()
+
+	_, found := vr.handlers[version]
+	if found {
+		return fmt.Errorf("%w: %d", data.ErrPayloadVersionAlreadyRegistered, version)
+	}
+
+	vr.handlers[version] = handler
+
+	return nil
+}
+
+// ProcessPayload will send the payload to the handler registered for its version
+func (vr *versionRouter) ProcessPayload(payload []byte, topic string, version uint32) error {
+	vr.mut.RLock()
+	handler, found := vr.handlers[version]
+	vr.mut.RUnlock()
+
+	if !found {
+		return fmt.Errorf("%w: %d", data.ErrNoHandlerForPayloadVersion, version)
+	}
+
+	return handler.ProcessPayload(payload, topic, version)
+}
+
+// Close will close all the registered handlers, each one only once
+func (vr *versionRouter) Close() error {
+	vr.mut.RLock()
+	defer vr.mut.RUnlock()
+
+	versions := make([]uint32, 0, len(vr.handlers))
+	for version := range vr.handlers {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i] < versions[j]
+	})
+
+	handlers := make([]PayloadHandler, 0, len(versions))
+	for _, version := range versions {
+		handlers = append(handlers, vr.handlers[version])
+	}
+
+	return closeHandlersOnce(handlers, vr.log)
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (vr *versionRouter) IsInterfaceNil() bool {
+	return vr == nil
+}
@@ -0,0 +1,78 @@
+package websocket
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/testscommon"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+	"github.com/subrahamanyam341/andes-core-16/core"
+)
+
+func TestNewVersionRouter(t *testing.T) {
+	t.Parallel()
+
+	router, err := NewVersionRouter(nil)
+	require.Nil(t, router)
+	require.Equal(t, core.ErrNilLogger, err)
+
+	router, err = NewVersionRouter(&testscommon.LoggerMock{})
+	require.Nil(t, err)
+	require.False(t, router.IsInterfaceNil())
+}
+
+func TestVersionRouter_RegisterHandler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil handler, should return error", func(t *testing.T) {
+		router, _ := NewVersionRouter(&testscommon.LoggerMock{})
+		err := router.RegisterHandler(1, nil)
+		require.Equal(t, data.ErrNilPayloadProcessor, err)
+	})
+	t.Run("already registered version, should return error", func(t *testing.T) {
+		router, _ := NewVersionRouter(&testscommon.LoggerMock{})
+		err := router.RegisterHandler(1, &testscommon.PayloadHandlerStub{})
+		require.Nil(t, err)
+
+		err = router.RegisterHandler(1, &testscommon.PayloadHandlerStub{})
+		require.True(t, errors.Is(err, data.ErrPayloadVersionAlreadyRegistered))
+	})
+}
+
+func TestVersionRouter_ProcessPayload(t *testing.T) {
+	t.Parallel()
+
+	calls := make([]string, 0)
+	router, _ := NewVersionRouter(&testscommon.LoggerMock{})
+	_ = router.RegisterHandler(1, createNamedHandler("v1", &calls))
+	_ = router.RegisterHandler(2, createNamedHandler("v2", &calls))
+
+	require.Nil(t, router.ProcessPayload([]byte("payload"), "topic", 1))
+	require.Nil(t, router.ProcessPayload([]byte("payload"), "topic", 2))
+	err := router.ProcessPayload([]byte("payload"), "topic", 3)
+	require.True(t, errors.Is(err, data.ErrNoHandlerForPayloadVersion))
+
+	require.Equal(t, []string{"v1:topic", "v2:topic"}, calls)
+}
+
+func TestVersionRouter_CloseShouldCloseEveryHandlerOnce(t *testing.T) {
+	t.Parallel()
+
+	numCloses := 0
+	expectedErr := errors.New("expected error")
+	sharedHandler := &testscommon.PayloadHandlerStub{
+		CloseCalled: func() error {
+			numCloses++
+			return expectedErr
+		},
+	}
+
+	router, _ := NewVersionRouter(&testscommon.LoggerMock{})
+	_ = router.RegisterHandler(1, sharedHandler)
+	_ = router.RegisterHandler(2, sharedHandler)
+
+	err := router.Close()
+	require.Equal(t, expectedErr, err)
+	require.Equal(t, 1, numCloses)
+}
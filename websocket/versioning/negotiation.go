@@ -0,0 +1,82 @@
+package versioning
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+)
+
+// CheckRange returns an error if the provided range of supported payload versions is invalid. A zero minimum version
+// means that the negotiation is disabled
+func CheckRange(minVersion uint32, maxVersion uint32) error {
+	if minVersion > maxVersion {
+		return fmt.Errorf("%w: min %d, max %d", data.ErrInvalidPayloadVersionRange, minVersion, maxVersion)
+	}
+
+	return nil
+}
+
+// CreateHandshakeHeader returns the header advertising the range of payload versions this host supports. The header
+// is empty if the negotiation is disabled
+func CreateHandshakeHeader(minVersion uint32, maxVersion uint32) http.Header {
+	header := http.Header{}
+	if minVersion == 0 {
+		return header
+	}
+
+	header.Set(data.PayloadVersionsHeader, fmt.Sprintf("%d-%d", minVersion, maxVersion))
+
+	return header
+}
+
+// Negotiate returns the highest payload version supported by both this host and the peer that sent the provided
+// handshake header. Peers that do not know about the negotiation do not send the header, so the payload version of
+// this host is kept
+func Negotiate(minVersion uint32, maxVersion uint32, header http.Header) (uint32, error) {
+	value := header.Get(data.PayloadVersionsHeader)
+	if len(value) == 0 {
+		return maxVersion, nil
+	}
+
+	peerMinVersion, peerMaxVersion, err := parseRange(value)
+	if err != nil {
+		return 0, err
+	}
+
+	negotiated := maxVersion
+	if peerMaxVersion < negotiated {
+		negotiated = peerMaxVersion
+	}
+	if negotiated < minVersion || negotiated < peerMinVersion {
+		return 0, fmt.Errorf("%w: supported %d-%d, peer supports %d-%d",
+			data.ErrNoCommonPayloadVersion, minVersion, maxVersion, peerMinVersion, peerMaxVersion)
+	}
+
+	return negotiated, nil
+}
+
+func parseRange(value string) (uint32, uint32, error) {
+	minValue, maxValue, found := strings.Cut(value, "-")
+	if !found {
+		return 0, 0, fmt.Errorf("%w: %s", data.ErrInvalidPayloadVersionRange, value)
+	}
+
+	minVersion, err := strconv.ParseUint(strings.TrimSpace(minValue), 10, 32)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%w: %s", data.ErrInvalidPayloadVersionRange, value)
+	}
+	maxVersion, err := strconv.ParseUint(strings.TrimSpace(maxValue), 10, 32)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%w: %s", data.ErrInvalidPayloadVersionRange, value)
+	}
+
+	err = CheckRange(uint32(minVersion), uint32(maxVersion))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return uint32(minVersion), uint32(maxVersion), nil
+}
@@ -0,0 +1,74 @@
+package versioning
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/subrahamanyam341/andes-communication/websocket/data"
+)
+
+func TestCheckRange(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, CheckRange(0, 1))
+	require.Nil(t, CheckRange(1, 1))
+	require.Nil(t, CheckRange(1, 3))
+	require.True(t, errors.Is(CheckRange(3, 1), data.ErrInvalidPayloadVersionRange))
+}
+
+func TestCreateHandshakeHeader(t *testing.T) {
+	t.Parallel()
+
+	require.Empty(t, CreateHandshakeHeader(0, 2))
+	require.Equal(t, "1-3", CreateHandshakeHeader(1, 3).Get(data.PayloadVersionsHeader))
+}
+
+func TestNegotiate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("peer without negotiation, should keep the own version", func(t *testing.T) {
+		t.Parallel()
+
+		version, err := Negotiate(1, 3, http.Header{})
+		require.Nil(t, err)
+		require.Equal(t, uint32(3), version)
+	})
+	t.Run("invalid peer range, should return error", func(t *testing.T) {
+		t.Parallel()
+
+		for _, value := range []string{"3", "a-3", "1-b", "3-1"} {
+			header := http.Header{}
+			header.Set(data.PayloadVersionsHeader, value)
+
+			_, err := Negotiate(1, 3, header)
+			require.True(t, errors.Is(err, data.ErrInvalidPayloadVersionRange), value)
+		}
+	})
+	t.Run("no overlap, should return error", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Negotiate(1, 2, CreateHandshakeHeader(3, 4))
+		require.True(t, errors.Is(err, data.ErrNoCommonPayloadVersion))
+		require.Contains(t, err.Error(), "supported 1-2, peer supports 3-4")
+
+		_, err = Negotiate(3, 4, CreateHandshakeHeader(1, 2))
+		require.True(t, errors.Is(err, data.ErrNoCommonPayloadVersion))
+	})
+	t.Run("should pick the highest common version", func(t *testing.T) {
+		t.Parallel()
+
+		version, err := Negotiate(1, 3, CreateHandshakeHeader(2, 5))
+		require.Nil(t, err)
+		require.Equal(t, uint32(3), version)
+
+		version, err = Negotiate(2, 5, CreateHandshakeHeader(1, 3))
+		require.Nil(t, err)
+		require.Equal(t, uint32(3), version)
+
+		version, err = Negotiate(1, 1, CreateHandshakeHeader(1, 1))
+		require.Nil(t, err)
+		require.Equal(t, uint32(1), version)
+	})
+}